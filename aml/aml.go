@@ -0,0 +1,205 @@
+package aml
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Tipologi aktivitas mencurigakan yang dideteksi job harian
+const (
+	TipologiStructuring = "structuring_below_threshold" // tunai dipecah agar total harian sedikit di bawah ambang LTKT
+	TipologiPassThrough = "pass_through"                // dana tunai masuk lalu hampir seluruhnya keluar di hari yang sama
+	TipologiManual      = "manual"
+)
+
+// DateLayout adalah format tanggal yang dipakai API dan file laporan
+const DateLayout = "2006-01-02"
+
+// passThroughRatio adalah porsi setoran yang ditarik kembali di hari yang sama
+const passThroughRatio = 0.9
+
+// Thresholds mengatur ambang batas pelaporan dan deteksi
+type Thresholds struct {
+	LTKT             float64 // total tunai harian per arah yang wajib dilaporkan (Rp500 juta)
+	StructuringFloor float64 // porsi dari LTKT; total di [floor x LTKT, LTKT) dianggap structuring
+	PassThroughMin   float64 // setoran harian minimal untuk tipologi pass-through
+}
+
+// Service menjalankan agregasi harian, pembuatan laporan LTKT, deteksi kasus mencurigakan
+// dan ekspor file laporan untuk PPATK
+type Service struct {
+	DB         *sql.DB
+	Thresholds Thresholds
+	ExportDir  string
+	EntityID   string        // ID pelapor (PJK) di PPATK
+	RunAt      time.Duration // jam job harian sejak tengah malam, memproses tanggal kemarin
+}
+
+// NewService membuat Service AML
+func NewService(db *sql.DB, thresholds Thresholds, exportDir, entityID string, runAt time.Duration) *Service {
+	return &Service{DB: db, Thresholds: thresholds, ExportDir: exportDir, EntityID: entityID, RunAt: runAt}
+}
+
+// Run menjalankan job harian sampai ctx dibatalkan. Setiap menit job memeriksa apakah
+// tanggal kemarin sudah diproses; jika belum dan jam RunAt sudah lewat, job dijalankan.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		s.runDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) runDue(ctx context.Context, now time.Time) {
+	today := truncateDay(now)
+	if now.Sub(today) < s.RunAt {
+		return
+	}
+	tanggal := today.AddDate(0, 0, -1)
+
+	run, err := repositories.GetAMLRun(s.DB, tanggal)
+	if err != nil && err != sql.ErrNoRows {
+		log.WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to check AML run")
+		return
+	}
+	if run != nil && run.FinishedAt != nil {
+		return
+	}
+
+	if _, err := s.RunFor(ctx, tanggal); err != nil {
+		log.WithFields(log.Fields{
+			"error":   err,
+			"tanggal": tanggal.Format(DateLayout),
+		}).Error("AML daily job failed")
+	}
+}
+
+// RunFor memproses satu tanggal: menghitung agregat harian, membuat laporan LTKT,
+// membuka kasus untuk pola mencurigakan dan mengekspor file laporan. Aman dijalankan
+// ulang; kasus yang sama tidak dibuka dua kali.
+func (s *Service) RunFor(ctx context.Context, tanggal time.Time) (*models.AMLRun, error) {
+	tanggal = truncateDay(tanggal)
+	run := &models.AMLRun{Tanggal: tanggal, StartedAt: time.Now()}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := repositories.LockAML(tx); err != nil {
+		return nil, err
+	}
+	if err := repositories.ComputeAMLDailyAggregates(tx, tanggal); err != nil {
+		return nil, fmt.Errorf("agregasi harian: %w", err)
+	}
+	if err := repositories.UpsertLTKTReports(tx, tanggal, s.Thresholds.LTKT); err != nil {
+		return nil, fmt.Errorf("laporan LTKT: %w", err)
+	}
+
+	aggregates, err := repositories.ListAMLDailyAggregates(tx, tanggal)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range s.detect(aggregates) {
+		created, err := repositories.InsertAMLCase(tx, &c)
+		if err != nil {
+			return nil, fmt.Errorf("kasus AML: %w", err)
+		}
+		if !created {
+			continue
+		}
+		note := &models.AMLCaseNote{CaseID: c.ID, ToStatus: models.AMLCaseOpen, Catatan: "Dibuka otomatis oleh job AML harian"}
+		if err := repositories.InsertAMLCaseNote(tx, note); err != nil {
+			return nil, err
+		}
+		run.CaseCount++
+	}
+
+	ltkt, err := s.exportLTKT(tx, tanggal)
+	if err != nil {
+		return nil, fmt.Errorf("ekspor LTKT: %w", err)
+	}
+	run.LTKTCount = ltkt
+	if _, err := s.exportLTKM(tx, tanggal); err != nil {
+		return nil, fmt.Errorf("ekspor LTKM: %w", err)
+	}
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	if err := repositories.SaveAMLRun(tx, run); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"tanggal":   tanggal.Format(DateLayout),
+		"LTKT":      run.LTKTCount,
+		"NewCases":  run.CaseCount,
+		"aggregate": len(aggregates),
+	}).Info("AML daily job finished")
+
+	return run, nil
+}
+
+func (s *Service) detect(aggregates []models.AMLDailyAggregate) []models.AMLCase {
+	var cases []models.AMLCase
+	floor := s.Thresholds.StructuringFloor * s.Thresholds.LTKT
+
+	for _, a := range aggregates {
+		for _, side := range []struct {
+			arah   string
+			total  float64
+			jumlah int
+		}{
+			{models.JenisSetor, a.TotalSetor, a.JumlahSetor},
+			{models.JenisTarik, a.TotalTarik, a.JumlahTarik},
+		} {
+			// Satu transaksi besar di bawah ambang bukan pola pemecahan
+			if side.jumlah >= 2 && side.total >= floor && side.total < s.Thresholds.LTKT {
+				cases = append(cases, newCase(a, TipologiStructuring, map[string]interface{}{
+					"arah":             side.arah,
+					"total":            side.total,
+					"jumlah_transaksi": side.jumlah,
+					"ambang_ltkt":      s.Thresholds.LTKT,
+				}))
+			}
+		}
+
+		if a.TotalSetor >= s.Thresholds.PassThroughMin && a.TotalTarik >= passThroughRatio*a.TotalSetor {
+			cases = append(cases, newCase(a, TipologiPassThrough, map[string]interface{}{
+				"total_setor": a.TotalSetor,
+				"total_tarik": a.TotalTarik,
+			}))
+		}
+	}
+	return cases
+}
+
+func newCase(a models.AMLDailyAggregate, tipologi string, detail map[string]interface{}) models.AMLCase {
+	b, _ := json.Marshal(detail)
+	return models.AMLCase{NasabahID: a.NasabahID, NoRekening: a.NoRekening, Tanggal: a.Tanggal, Tipologi: tipologi, Detail: b}
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
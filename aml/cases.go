@@ -0,0 +1,180 @@
+package aml
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrCaseNotFound      = errors.New("kasus AML tidak ditemukan")
+	ErrCaseExists        = errors.New("kasus AML dengan tipologi yang sama sudah ada hari ini")
+	ErrInvalidTransition = errors.New("perpindahan status kasus tidak diizinkan")
+	ErrNoteRequired      = errors.New("catatan wajib diisi")
+	ErrRekeningNotFound  = errors.New("rekening tidak ditemukan")
+)
+
+// transitions adalah alur kerja kasus: open -> investigating -> filed -> closed.
+// Kasus bisa ditutup tanpa dilaporkan jika hasil investigasi tidak mencurigakan.
+var transitions = map[string][]string{
+	models.AMLCaseOpen:          {models.AMLCaseInvestigating, models.AMLCaseClosed},
+	models.AMLCaseInvestigating: {models.AMLCaseFiled, models.AMLCaseClosed},
+	models.AMLCaseFiled:         {models.AMLCaseClosed},
+}
+
+// Cases mengambil daftar kasus, opsional difilter status dan rekening
+func (s *Service) Cases(status, noRekening string, limit int) ([]models.AMLCase, error) {
+	return repositories.ListAMLCases(s.DB, status, noRekening, limit)
+}
+
+// Case mengambil satu kasus beserta riwayat catatannya
+func (s *Service) Case(id int) (*models.AMLCase, error) {
+	c, err := repositories.GetAMLCase(s.DB, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrCaseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.Notes, err = repositories.ListAMLCaseNotes(s.DB, id)
+	return c, err
+}
+
+// OpenCase membuka kasus secara manual, misalnya dari laporan petugas cabang
+func (s *Service) OpenCase(actor *auth.Principal, req models.OpenAMLCaseRequest) (*models.AMLCase, error) {
+	if req.Catatan == "" {
+		return nil, ErrNoteRequired
+	}
+	if req.Tipologi == "" {
+		req.Tipologi = TipologiManual
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	nasabah, err := repositories.GetNasabahByNoRekening(tx, req.NoRekening)
+	if err == sql.ErrNoRows {
+		return nil, ErrRekeningNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	detail, _ := json.Marshal(map[string]interface{}{"dibuka_oleh": actor.ID})
+	c := &models.AMLCase{
+		NasabahID:  nasabah.ID,
+		NoRekening: nasabah.NoRekening,
+		Tanggal:    truncateDay(time.Now()),
+		Tipologi:   req.Tipologi,
+		Detail:     detail,
+	}
+	created, err := repositories.InsertAMLCase(tx, c)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrCaseExists
+	}
+
+	staffID := actor.ID
+	if err := repositories.InsertAMLCaseNote(tx, &models.AMLCaseNote{CaseID: c.ID, StaffID: &staffID, ToStatus: models.AMLCaseOpen, Catatan: req.Catatan}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"CaseID":     c.ID,
+		"NoRekening": c.NoRekening,
+		"tipologi":   c.Tipologi,
+	}).Info("AML case opened")
+
+	return s.Case(c.ID)
+}
+
+// Transition memindahkan kasus ke status berikutnya dan mencatat alasannya. Kasus yang
+// masuk status filed akan disertakan di file LTKM pada ekspor berikutnya.
+func (s *Service) Transition(actor *auth.Principal, id int, req models.AMLCaseTransitionRequest) (*models.AMLCase, error) {
+	if req.Catatan == "" {
+		return nil, ErrNoteRequired
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	c, err := repositories.GetAMLCaseForUpdate(tx, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrCaseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !allowedTransition(c.Status, req.Status) {
+		return nil, ErrInvalidTransition
+	}
+
+	now := time.Now()
+	from := c.Status
+	c.Status = req.Status
+	c.UpdatedAt = now
+	staffID := actor.ID
+	switch req.Status {
+	case models.AMLCaseInvestigating:
+		c.AssignedTo = &staffID
+	case models.AMLCaseFiled:
+		c.FiledAt = &now
+		c.ReferensiPPATK = req.ReferensiPPATK
+	}
+
+	if err := repositories.UpdateAMLCaseStatus(tx, c); err != nil {
+		return nil, err
+	}
+	note := &models.AMLCaseNote{CaseID: c.ID, StaffID: &staffID, FromStatus: from, ToStatus: c.Status, Catatan: req.Catatan}
+	if err := repositories.InsertAMLCaseNote(tx, note); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"CaseID": c.ID,
+		"from":   from,
+		"to":     c.Status,
+		"actor":  actor.ID,
+	}).Info("AML case transitioned")
+
+	return s.Case(c.ID)
+}
+
+// LTKT mengambil laporan transaksi tunai untuk satu tanggal
+func (s *Service) LTKT(tanggal time.Time) ([]models.LTKTReport, error) {
+	return repositories.ListLTKTReports(s.DB, truncateDay(tanggal))
+}
+
+// Exports mengambil file laporan terbaru
+func (s *Service) Exports(limit int) ([]models.AMLExport, error) {
+	return repositories.ListAMLExports(s.DB, limit)
+}
+
+func allowedTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
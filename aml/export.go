@@ -0,0 +1,204 @@
+package aml
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Format file laporan. XML mengikuti struktur ringkas ala goAML PPATK; CSV untuk
+// pemeriksaan internal dan rekonsiliasi.
+const (
+	FormatCSV = "csv"
+	FormatXML = "xml"
+)
+
+type xmlReport struct {
+	XMLName        xml.Name         `xml:"report"`
+	RentityID      string           `xml:"rentity_id"`
+	ReportCode     string           `xml:"report_code"`
+	ReportDate     string           `xml:"report_date"`
+	SubmissionDate string           `xml:"submission_date"`
+	Currency       string           `xml:"currency_code_local"`
+	Transactions   []xmlTransaction `xml:"transactions>transaction,omitempty"`
+	Activities     []xmlActivity    `xml:"activities>activity,omitempty"`
+}
+
+type xmlPerson struct {
+	Nama       string `xml:"full_name"`
+	NIK        string `xml:"id_number"`
+	NoRekening string `xml:"account"`
+}
+
+type xmlTransaction struct {
+	Reference       string    `xml:"transactionnumber"`
+	Date            string    `xml:"date_transaction"`
+	Direction       string    `xml:"funds_code"`
+	Amount          string    `xml:"amount_local"`
+	JumlahTransaksi int       `xml:"transaction_count"`
+	Person          xmlPerson `xml:"t_person"`
+}
+
+type xmlActivity struct {
+	CaseID         int       `xml:"case_id"`
+	Date           string    `xml:"date_activity"`
+	Tipologi       string    `xml:"indicator"`
+	ReferensiPPATK string    `xml:"reference,omitempty"`
+	Reason         string    `xml:"reason"`
+	Person         xmlPerson `xml:"t_person"`
+}
+
+// exportLTKT menulis semua laporan LTKT satu tanggal ke file CSV dan XML
+func (s *Service) exportLTKT(tx *sql.Tx, tanggal time.Time) (int, error) {
+	reports, err := repositories.ListLTKTReports(tx, tanggal)
+	if err != nil || len(reports) == 0 {
+		return 0, err
+	}
+
+	base := fmt.Sprintf("ltkt_%s", tanggal.Format("20060102"))
+	report := s.newXMLReport("LTKT", tanggal)
+	rows := [][]string{{"id", "tanggal", "no_rekening", "nama", "nik", "arah", "total", "jumlah_transaksi"}}
+	for _, r := range reports {
+		rows = append(rows, []string{
+			strconv.Itoa(r.ID), r.Tanggal.Format(DateLayout), r.NoRekening, r.Nama, r.NIK, r.Arah,
+			formatAmount(r.Total), strconv.Itoa(r.JumlahTransaksi),
+		})
+		report.Transactions = append(report.Transactions, xmlTransaction{
+			Reference:       fmt.Sprintf("LTKT-%d", r.ID),
+			Date:            r.Tanggal.Format(DateLayout),
+			Direction:       r.Arah,
+			Amount:          formatAmount(r.Total),
+			JumlahTransaksi: r.JumlahTransaksi,
+			Person:          xmlPerson{Nama: r.Nama, NIK: r.NIK, NoRekening: r.NoRekening},
+		})
+	}
+
+	exportID, err := s.writeExports(tx, models.LaporanLTKT, tanggal, base, rows, report)
+	if err != nil {
+		return 0, err
+	}
+	return len(reports), repositories.MarkLTKTReportsExported(tx, tanggal, exportID)
+}
+
+// exportLTKM menulis kasus yang sudah dilaporkan (filed) tetapi belum pernah diekspor
+func (s *Service) exportLTKM(tx *sql.Tx, tanggal time.Time) (int, error) {
+	cases, err := repositories.ListUnexportedFiledAMLCases(tx)
+	if err != nil || len(cases) == 0 {
+		return 0, err
+	}
+
+	// Nama file memuat jam ekspor agar file sebelumnya pada tanggal yang sama tidak tertimpa
+	base := fmt.Sprintf("ltkm_%s_%s", tanggal.Format("20060102"), time.Now().Format("150405"))
+	report := s.newXMLReport("LTKM", tanggal)
+	rows := [][]string{{"case_id", "tanggal", "no_rekening", "nama", "nik", "tipologi", "referensi_ppatk", "filed_at", "detail"}}
+	ids := make([]int, 0, len(cases))
+	for _, c := range cases {
+		nasabah, err := repositories.GetNasabahByNoRekening(tx, c.NoRekening)
+		if err != nil {
+			return 0, fmt.Errorf("nasabah kasus %d: %w", c.ID, err)
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(c.ID), c.Tanggal.Format(DateLayout), c.NoRekening, nasabah.Nama, nasabah.NIK, c.Tipologi,
+			c.ReferensiPPATK, c.FiledAt.Format(time.RFC3339), string(c.Detail),
+		})
+		report.Activities = append(report.Activities, xmlActivity{
+			CaseID:         c.ID,
+			Date:           c.Tanggal.Format(DateLayout),
+			Tipologi:       c.Tipologi,
+			ReferensiPPATK: c.ReferensiPPATK,
+			Reason:         string(c.Detail),
+			Person:         xmlPerson{Nama: nasabah.Nama, NIK: nasabah.NIK, NoRekening: c.NoRekening},
+		})
+		ids = append(ids, c.ID)
+	}
+
+	exportID, err := s.writeExports(tx, models.LaporanLTKM, tanggal, base, rows, report)
+	if err != nil {
+		return 0, err
+	}
+	return len(cases), repositories.MarkAMLCasesExported(tx, ids, exportID)
+}
+
+func (s *Service) newXMLReport(code string, tanggal time.Time) *xmlReport {
+	return &xmlReport{
+		RentityID:      s.EntityID,
+		ReportCode:     code,
+		ReportDate:     tanggal.Format(DateLayout),
+		SubmissionDate: time.Now().Format(time.RFC3339),
+		Currency:       "IDR",
+	}
+}
+
+// writeExports menulis file CSV dan XML lalu mencatatnya di aml_exports.
+// Id ekspor XML dikembalikan sebagai acuan laporan yang dikirim ke PPATK.
+func (s *Service) writeExports(tx *sql.Tx, jenis string, tanggal time.Time, base string, rows [][]string, report *xmlReport) (int, error) {
+	if err := os.MkdirAll(s.ExportDir, 0o750); err != nil {
+		return 0, err
+	}
+
+	csvPath := filepath.Join(s.ExportDir, base+"."+FormatCSV)
+	err := writeAtomic(csvPath, func(w io.Writer) error {
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	xmlPath := filepath.Join(s.ExportDir, base+"."+FormatXML)
+	err = writeAtomic(xmlPath, func(w io.Writer) error {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		return enc.Encode(report)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	count := len(rows) - 1
+	if err := repositories.InsertAMLExport(tx, &models.AMLExport{JenisLaporan: jenis, Tanggal: tanggal, Format: FormatCSV, FilePath: csvPath, JumlahRecord: count}); err != nil {
+		return 0, err
+	}
+	export := &models.AMLExport{JenisLaporan: jenis, Tanggal: tanggal, Format: FormatXML, FilePath: xmlPath, JumlahRecord: count}
+	if err := repositories.InsertAMLExport(tx, export); err != nil {
+		return 0, err
+	}
+	return export.ID, nil
+}
+
+// writeAtomic menulis ke file sementara lalu me-rename, sehingga pembaca tidak pernah
+// melihat file laporan yang setengah jadi
+func writeAtomic(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
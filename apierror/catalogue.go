@@ -0,0 +1,211 @@
+// Package apierror berisi katalog error API: kode stabil yang bisa dibaca mesin, status
+// HTTP, penanda apakah request aman diulang, dan pesan dalam bahasa Indonesia dan Inggris.
+// Handler dan middleware mengembalikan *Error; Handler (HTTPErrorHandler Echo) mengubahnya
+// menjadi utils.Response dengan bahasa sesuai header Accept-Language.
+//
+// Kode tidak boleh diganti atau dipakai ulang untuk arti lain karena klien bergantung
+// padanya. Pesan boleh diperbaiki kapan saja.
+package apierror
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Code adalah kode error yang stabil, misalnya REKENING_NOT_FOUND
+type Code string
+
+// Kode umum
+const (
+	InvalidPayload       Code = "INVALID_PAYLOAD"
+	InvalidParameter     Code = "INVALID_PARAMETER"
+	Unauthorized         Code = "UNAUTHORIZED"
+	InvalidCredentials   Code = "INVALID_CREDENTIALS"
+	InvalidRefreshToken  Code = "INVALID_REFRESH_TOKEN"
+	Forbidden            Code = "FORBIDDEN"
+	RouteNotFound        Code = "ROUTE_NOT_FOUND"
+	MethodNotAllowed     Code = "METHOD_NOT_ALLOWED"
+	PayloadTooLarge      Code = "PAYLOAD_TOO_LARGE"
+	UnsupportedMediaType Code = "UNSUPPORTED_MEDIA_TYPE"
+	InternalError        Code = "INTERNAL_ERROR"
+	AuditUnavailable     Code = "AUDIT_UNAVAILABLE"
+	ServiceUnavailable   Code = "SERVICE_UNAVAILABLE"
+	DatabaseUnavailable  Code = "DATABASE_UNAVAILABLE"
+	Timeout              Code = "TIMEOUT"
+	RateLimited          Code = "RATE_LIMITED"
+)
+
+// Kode request partner bertanda tangan
+const (
+	SignatureExpired          Code = "SIGNATURE_EXPIRED"
+	ReplayedRequest           Code = "REPLAYED_REQUEST"
+	PartnerNotFound           Code = "PARTNER_NOT_FOUND"
+	PartnerSigningUnavailable Code = "PARTNER_SIGNING_UNAVAILABLE"
+)
+
+// Kode nasabah dan transaksi
+const (
+	InvalidIdentity      Code = "INVALID_IDENTITY"
+	InvalidPIN           Code = "INVALID_PIN"
+	Duplicate            Code = "DUPLICATE"
+	InvalidAmount        Code = "INVALID_AMOUNT"
+	SameRekening         Code = "SAME_REKENING"
+	RekeningNotFound     Code = "REKENING_NOT_FOUND"
+	DestinationNotFound  Code = "DESTINATION_NOT_FOUND"
+	RekeningFrozen       Code = "REKENING_FROZEN"
+	RekeningUnderReview  Code = "REKENING_UNDER_REVIEW"
+	DestinationInactive  Code = "DESTINATION_INACTIVE"
+	DestinationHeld      Code = "DESTINATION_HELD"
+	InsufficientBalance  Code = "INSUFFICIENT_BALANCE"
+	FraudBlocked         Code = "FRAUD_BLOCKED"
+	StepUpRequired       Code = "STEP_UP_REQUIRED"
+	ApprovalUnavailable  Code = "APPROVAL_UNAVAILABLE"
+	EODInProgress        Code = "EOD_IN_PROGRESS"
+	TransactionNotFound  Code = "TRANSACTION_NOT_FOUND"
+	AlreadyReversed      Code = "ALREADY_REVERSED"
+	NotReversible        Code = "NOT_REVERSIBLE"
+	BalanceAlreadyMatch  Code = "BALANCE_ALREADY_MATCHES"
+	OperationNotFound    Code = "OPERATION_NOT_FOUND"
+	OperationDecided     Code = "OPERATION_ALREADY_DECIDED"
+	OperationExpired     Code = "OPERATION_EXPIRED"
+	SelfApproval         Code = "SELF_APPROVAL"
+	SubscriptionNotFound Code = "SUBSCRIPTION_NOT_FOUND"
+	DeliveryNotFound     Code = "DELIVERY_NOT_FOUND"
+	WebhookUnavailable   Code = "WEBHOOK_SIGNING_UNAVAILABLE"
+	RekeningNotLinked    Code = "REKENING_NOT_LINKED"
+)
+
+// Kode kepatuhan dan operasional back-office
+const (
+	FraudDecisionNotFound   Code = "FRAUD_DECISION_NOT_FOUND"
+	FraudDecisionClosed     Code = "FRAUD_DECISION_CLOSED"
+	AMLCaseNotFound         Code = "AML_CASE_NOT_FOUND"
+	AMLCaseExists           Code = "AML_CASE_EXISTS"
+	AMLTransitionNotAllowed Code = "AML_TRANSITION_NOT_ALLOWED"
+	HitNotFound             Code = "SCREENING_HIT_NOT_FOUND"
+	HitAlreadyReviewed      Code = "SCREENING_HIT_ALREADY_REVIEWED"
+	InvalidWatchlist        Code = "INVALID_WATCHLIST_FILE"
+	EODRunNotFound          Code = "EOD_RUN_NOT_FOUND"
+	EODAlreadyRunning       Code = "EOD_ALREADY_RUNNING"
+	BusinessDateClosed      Code = "BUSINESS_DATE_CLOSED"
+	BusinessDateNotEnded    Code = "BUSINESS_DATE_NOT_ENDED"
+	BusinessDateNotClosed   Code = "BUSINESS_DATE_NOT_CLOSED"
+	PreviousDateOpen        Code = "PREVIOUS_DATE_OPEN"
+	MismatchNotFound        Code = "MISMATCH_NOT_FOUND"
+	MismatchNotOpen         Code = "MISMATCH_NOT_OPEN"
+	GLMappingUnavailable    Code = "GL_MAPPING_UNAVAILABLE"
+)
+
+// Entry adalah satu baris katalog
+type Entry struct {
+	Status    int
+	Retryable bool   // request yang sama boleh diulang nanti tanpa perubahan
+	EN        string // pesan bahasa Inggris, juga bahasa bawaan
+	ID        string // pesan bahasa Indonesia
+}
+
+// Message mengembalikan pesan untuk bahasa hasil Language
+func (e Entry) Message(lang string) string {
+	if lang == LangID {
+		return e.ID
+	}
+	return e.EN
+}
+
+var catalogue = map[Code]Entry{
+	InvalidPayload:       {http.StatusBadRequest, false, "Invalid request payload", "Payload request tidak valid"},
+	InvalidParameter:     {http.StatusBadRequest, false, "Invalid request parameter", "Parameter request tidak valid"},
+	Unauthorized:         {http.StatusUnauthorized, false, "Unauthorized", "Autentikasi diperlukan"},
+	InvalidCredentials:   {http.StatusUnauthorized, false, "Invalid credentials", "Kredensial salah"},
+	InvalidRefreshToken:  {http.StatusUnauthorized, false, "Invalid refresh token", "Refresh token tidak valid"},
+	Forbidden:            {http.StatusForbidden, false, "Forbidden", "Akses ditolak"},
+	RouteNotFound:        {http.StatusNotFound, false, "Route not found", "Route tidak ditemukan"},
+	MethodNotAllowed:     {http.StatusMethodNotAllowed, false, "Method not allowed", "Method tidak diizinkan"},
+	PayloadTooLarge:      {http.StatusRequestEntityTooLarge, false, "Request body is too large", "Body request terlalu besar"},
+	UnsupportedMediaType: {http.StatusUnsupportedMediaType, false, "Unsupported content type", "Content type tidak didukung"},
+	InternalError:        {http.StatusInternalServerError, false, "Internal server error", "Terjadi kesalahan pada server"},
+	AuditUnavailable:     {http.StatusInternalServerError, false, "Request may have been processed but could not be audited; check its status before retrying", "Request mungkin sudah diproses tetapi gagal dicatat di log audit; periksa statusnya sebelum mengulang"},
+	ServiceUnavailable:   {http.StatusServiceUnavailable, true, "Service unavailable, try again later", "Layanan tidak tersedia, coba lagi nanti"},
+	DatabaseUnavailable:  {http.StatusServiceUnavailable, true, "Database unavailable, try again later", "Database tidak tersedia, coba lagi nanti"},
+	Timeout:              {http.StatusGatewayTimeout, true, "Request timed out", "Request melewati batas waktu"},
+	RateLimited:          {http.StatusTooManyRequests, true, "Too many requests, retry after the Retry-After header", "Terlalu banyak request, coba lagi setelah waktu di header Retry-After"},
+
+	SignatureExpired:          {http.StatusUnauthorized, false, "Request timestamp expired", "Timestamp request kedaluwarsa"},
+	ReplayedRequest:           {http.StatusUnauthorized, false, "Replayed request", "Request sudah pernah dikirim"},
+	PartnerNotFound:           {http.StatusNotFound, false, "Partner not found", "Partner tidak ditemukan"},
+	PartnerSigningUnavailable: {http.StatusServiceUnavailable, false, "Partner signing is not configured", "Penandatanganan partner belum dikonfigurasi"},
+
+	InvalidIdentity:      {http.StatusBadRequest, false, "Invalid NIK or No HP format", "Format NIK atau No HP tidak valid"},
+	InvalidPIN:           {http.StatusBadRequest, false, "PIN must be 6 digits", "PIN harus 6 digit"},
+	Duplicate:            {http.StatusBadRequest, false, "Duplicate detected", "Data sudah terdaftar"},
+	InvalidAmount:        {http.StatusBadRequest, false, "Amount must be greater than zero", "Nominal harus lebih dari nol"},
+	SameRekening:         {http.StatusBadRequest, false, "Source and destination rekening must be different", "Rekening asal dan tujuan harus berbeda"},
+	RekeningNotFound:     {http.StatusNotFound, false, "No rekening not found", "Nomor rekening tidak ditemukan"},
+	DestinationNotFound:  {http.StatusNotFound, false, "Destination rekening not found", "Rekening tujuan tidak ditemukan"},
+	RekeningFrozen:       {http.StatusConflict, false, "Rekening is frozen", "Rekening dibekukan"},
+	RekeningUnderReview:  {http.StatusConflict, false, "Rekening is pending compliance review", "Rekening sedang ditinjau kepatuhan"},
+	DestinationInactive:  {http.StatusConflict, false, "Destination rekening cannot receive funds", "Rekening tujuan tidak dapat menerima dana"},
+	DestinationHeld:      {http.StatusConflict, false, "Destination rekening is held for compliance review", "Rekening tujuan ditahan untuk tinjauan kepatuhan"},
+	InsufficientBalance:  {http.StatusBadRequest, false, "Insufficient balance", "Saldo tidak cukup"},
+	FraudBlocked:         {http.StatusForbidden, false, "Transaction blocked by fraud screening", "Transaksi diblokir oleh pemeriksaan fraud"},
+	StepUpRequired:       {http.StatusPreconditionRequired, false, "Step-up verification required, resend the request with the X-Step-Up-Pin header", "Verifikasi tambahan diperlukan, kirim ulang request dengan header X-Step-Up-Pin"},
+	ApprovalUnavailable:  {http.StatusServiceUnavailable, false, "Approval queue is not available", "Antrean persetujuan tidak tersedia"},
+	EODInProgress:        {http.StatusServiceUnavailable, true, "End-of-day processing in progress, try again later", "Proses tutup hari sedang berjalan, coba lagi nanti"},
+	TransactionNotFound:  {http.StatusNotFound, false, "Transaction not found", "Transaksi tidak ditemukan"},
+	AlreadyReversed:      {http.StatusConflict, false, "Transaction already reversed", "Transaksi sudah dibalik"},
+	NotReversible:        {http.StatusBadRequest, false, "Transaction cannot be reversed", "Transaksi tidak dapat dibalik"},
+	BalanceAlreadyMatch:  {http.StatusConflict, false, "Balance already matches transaction history", "Saldo sudah sesuai dengan riwayat transaksi"},
+	OperationNotFound:    {http.StatusNotFound, false, "Operation not found", "Operasi tidak ditemukan"},
+	OperationDecided:     {http.StatusConflict, false, "Operation already decided", "Operasi sudah diputuskan"},
+	OperationExpired:     {http.StatusConflict, false, "Operation expired", "Operasi sudah kedaluwarsa"},
+	SelfApproval:         {http.StatusForbidden, false, "Approver cannot be the maker", "Penyetuju tidak boleh sama dengan pembuat"},
+	SubscriptionNotFound: {http.StatusNotFound, false, "Webhook subscription not found", "Subscription webhook tidak ditemukan"},
+	DeliveryNotFound:     {http.StatusNotFound, false, "Webhook delivery not found", "Pengiriman webhook tidak ditemukan"},
+	WebhookUnavailable:   {http.StatusServiceUnavailable, false, "Webhook signing is not configured", "Penandatanganan webhook belum dikonfigurasi"},
+	RekeningNotLinked:    {http.StatusForbidden, false, "Rekening is not linked to the partner", "Rekening tidak terhubung dengan partner"},
+
+	FraudDecisionNotFound:   {http.StatusNotFound, false, "Fraud decision not found", "Keputusan fraud tidak ditemukan"},
+	FraudDecisionClosed:     {http.StatusConflict, false, "Fraud decision is not open for review", "Keputusan fraud tidak terbuka untuk ditinjau"},
+	AMLCaseNotFound:         {http.StatusNotFound, false, "AML case not found", "Kasus AML tidak ditemukan"},
+	AMLCaseExists:           {http.StatusConflict, false, "AML case already exists for today", "Kasus AML untuk hari ini sudah ada"},
+	AMLTransitionNotAllowed: {http.StatusConflict, false, "Case status transition is not allowed", "Perubahan status kasus tidak diizinkan"},
+	HitNotFound:             {http.StatusNotFound, false, "Screening hit not found", "Hit screening tidak ditemukan"},
+	HitAlreadyReviewed:      {http.StatusConflict, false, "Screening hit has already been reviewed", "Hit screening sudah ditinjau"},
+	InvalidWatchlist:        {http.StatusBadRequest, false, "Invalid watchlist file", "Berkas watchlist tidak valid"},
+	EODRunNotFound:          {http.StatusNotFound, false, "EOD run not found", "Run EOD tidak ditemukan"},
+	EODAlreadyRunning:       {http.StatusConflict, true, "EOD is already running", "EOD sedang berjalan"},
+	BusinessDateClosed:      {http.StatusConflict, false, "Business date is already closed", "Tanggal bisnis sudah ditutup"},
+	BusinessDateNotEnded:    {http.StatusConflict, true, "Business date has not ended yet", "Tanggal bisnis belum berakhir"},
+	BusinessDateNotClosed:   {http.StatusConflict, true, "Business date is not closed yet", "Tanggal bisnis belum ditutup"},
+	PreviousDateOpen:        {http.StatusConflict, false, "Previous business date is not closed yet", "Tanggal bisnis sebelumnya belum ditutup"},
+	MismatchNotFound:        {http.StatusNotFound, false, "Reconciliation mismatch not found", "Selisih rekonsiliasi tidak ditemukan"},
+	MismatchNotOpen:         {http.StatusConflict, false, "Reconciliation mismatch is not open", "Selisih rekonsiliasi tidak terbuka"},
+	GLMappingUnavailable:    {http.StatusServiceUnavailable, false, "GL mapping is not configured", "Pemetaan akun GL belum dikonfigurasi"},
+}
+
+// Lookup mengembalikan entri katalog untuk code. Kode yang tidak dikenal diperlakukan
+// sebagai INTERNAL_ERROR.
+func Lookup(code Code) Entry {
+	if e, ok := catalogue[code]; ok {
+		return e
+	}
+	return catalogue[InternalError]
+}
+
+// Documented adalah entri katalog dalam bentuk JSON untuk GET /errors
+type Documented struct {
+	Code      Code   `json:"code"`
+	Status    int    `json:"status"`
+	Retryable bool   `json:"retryable"`
+	Message   string `json:"message"`
+}
+
+// Catalogue mengembalikan seluruh katalog dalam bahasa lang, terurut menurut kode
+func Catalogue(lang string) []Documented {
+	list := make([]Documented, 0, len(catalogue))
+	for code, e := range catalogue {
+		list = append(list, Documented{Code: code, Status: e.Status, Retryable: e.Retryable, Message: e.Message(lang)})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
@@ -0,0 +1,99 @@
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Error adalah kegagalan request yang dikembalikan handler atau middleware
+type Error struct {
+	Code Code
+
+	// Errors berisi detail per field, misalnya "nominal: must be greater than 0".
+	// Detail tidak diterjemahkan.
+	Errors []string
+
+	// Metadata berisi nilai tambahan yang bisa dibaca mesin, misalnya decision_id
+	Metadata map[string]string
+
+	// Err adalah penyebab internal; hanya dicatat di log, tidak pernah dikirim ke klien
+	Err error
+}
+
+// New membentuk error dengan detail per field opsional
+func New(code Code, details ...string) *Error {
+	return &Error{Code: code, Errors: details}
+}
+
+// Wrap membentuk error dengan penyebab internal untuk log
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+// With menambahkan satu nilai metadata
+func (e *Error) With(key, value string) *Error {
+	if e.Metadata == nil {
+		e.Metadata = map[string]string{}
+	}
+	e.Metadata[key] = value
+	return e
+}
+
+func (e *Error) Error() string {
+	msg := string(e.Code) + ": " + Lookup(e.Code).EN
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Status mengembalikan status HTTP error
+func (e *Error) Status() int {
+	return Lookup(e.Code).Status
+}
+
+// statusCodes memetakan *echo.HTTPError (router, Bind, middleware bawaan Echo) ke katalog
+var statusCodes = map[int]Code{
+	http.StatusBadRequest:            InvalidPayload,
+	http.StatusUnauthorized:          Unauthorized,
+	http.StatusForbidden:             Forbidden,
+	http.StatusNotFound:              RouteNotFound,
+	http.StatusMethodNotAllowed:      MethodNotAllowed,
+	http.StatusRequestEntityTooLarge: PayloadTooLarge,
+	http.StatusUnsupportedMediaType:  UnsupportedMediaType,
+	http.StatusTooManyRequests:       RateLimited,
+	http.StatusServiceUnavailable:    ServiceUnavailable,
+	http.StatusGatewayTimeout:        Timeout,
+}
+
+// From mengubah error apa pun menjadi *Error. Error yang tidak dikenal menjadi
+// INTERNAL_ERROR dengan error aslinya sebagai penyebab.
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		if code, ok := statusCodes[httpErr.Code]; ok {
+			apiErr := Wrap(code, err)
+			if code == InvalidPayload {
+				// Pesan Bind (misalnya syntax error JSON) membantu klien memperbaiki body
+				apiErr.Errors = []string{"body: " + fmt.Sprint(httpErr.Message)}
+			}
+			return apiErr
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Wrap(Timeout, err)
+	}
+	return Wrap(InternalError, err)
+}
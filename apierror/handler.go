@@ -0,0 +1,100 @@
+package apierror
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang-echo-postgresql/utils"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// Bahasa pesan yang didukung. LangEN adalah bawaan jika Accept-Language tidak cocok.
+const (
+	LangEN = "en"
+	LangID = "id"
+)
+
+// Language memilih bahasa pesan dari header Accept-Language, misalnya
+// "id-ID,id;q=0.9,en;q=0.8". Tag dengan q tertinggi yang didukung menang.
+func Language(acceptLanguage string) string {
+	best, bestQ := LangEN, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if base == "*" {
+			base = LangEN
+		}
+		if (base == LangEN || base == LangID) && q > bestQ {
+			best, bestQ = base, q
+		}
+	}
+	return best
+}
+
+// Response membentuk envelope utils.Response untuk err dalam bahasa lang
+func Response(err *Error, lang string) utils.Response {
+	entry := Lookup(err.Code)
+	return utils.Response{
+		Code:      string(err.Code),
+		Remark:    entry.Message(lang),
+		Errors:    err.Errors,
+		Retryable: entry.Retryable,
+		Metadata:  err.Metadata,
+	}
+}
+
+// Handler adalah HTTPErrorHandler Echo. Semua jalur gagal (error dari handler, Bind,
+// router, middleware, dan panic yang ditangkap Recover) berakhir di sini sehingga klien
+// selalu menerima envelope yang sama.
+func Handler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	apiErr := From(err)
+	entry := Lookup(apiErr.Code)
+	if entry.Status >= http.StatusInternalServerError && apiErr.Err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"Code":      apiErr.Code,
+			"Method":    c.Request().Method,
+			"Path":      c.Path(),
+			"RequestID": c.Response().Header().Get(echo.HeaderXRequestID),
+		}).Errorf("Request failed: %v", apiErr.Err)
+	}
+
+	lang := Language(c.Request().Header.Get("Accept-Language"))
+	c.Response().Header().Set("Content-Language", lang)
+
+	var writeErr error
+	if c.Request().Method == http.MethodHead {
+		writeErr = c.NoContent(entry.Status)
+	} else {
+		body := Response(apiErr, lang)
+		body.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+		writeErr = c.JSON(entry.Status, body)
+	}
+	if writeErr != nil {
+		log.WithContext(c.Request().Context()).Errorf("Failed to write error response: %v", writeErr)
+	}
+}
+
+// CatalogueHandler menyajikan seluruh katalog error dalam bahasa dari Accept-Language,
+// supaya klien bisa memetakan kode tanpa menyalin tabel dari dokumentasi
+func CatalogueHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		lang := Language(c.Request().Header.Get("Accept-Language"))
+		c.Response().Header().Set("Content-Language", lang)
+		return c.JSON(http.StatusOK, Catalogue(lang))
+	}
+}
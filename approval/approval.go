@@ -0,0 +1,254 @@
+package approval
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/repositories"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrNotFound      = errors.New("operasi tidak ditemukan")
+	ErrNotPending    = errors.New("operasi sudah diputuskan")
+	ErrExpired       = errors.New("operasi sudah kedaluwarsa")
+	ErrSelfApproval  = errors.New("checker tidak boleh sama dengan maker")
+	ErrUnknownAction = errors.New("aksi tidak dikenal")
+)
+
+// ExecuteFunc menjalankan satu aksi di dalam transaksi database. Fungsi yang sama
+// dipakai oleh jalur langsung dan oleh Approve agar hasilnya identik.
+type ExecuteFunc func(tx *sql.Tx, payload json.RawMessage) (interface{}, error)
+
+// ExecutionError membungkus kegagalan aksi saat dieksekusi pada waktu persetujuan
+type ExecutionError struct {
+	Err error
+}
+
+func (e *ExecutionError) Error() string { return e.Err.Error() }
+func (e *ExecutionError) Unwrap() error { return e.Err }
+
+// Queue adalah antrean maker-checker untuk operasi berisiko tinggi
+type Queue struct {
+	DB        *sql.DB
+	TTL       time.Duration
+	executors map[policy.Action]ExecuteFunc
+}
+
+// NewQueue membuat antrean persetujuan dengan masa berlaku ttl untuk setiap operasi
+func NewQueue(db *sql.DB, ttl time.Duration) *Queue {
+	return &Queue{DB: db, TTL: ttl, executors: map[policy.Action]ExecuteFunc{}}
+}
+
+// Register mendaftarkan fungsi eksekusi untuk sebuah aksi
+func (q *Queue) Register(action policy.Action, fn ExecuteFunc) {
+	q.executors[action] = fn
+}
+
+// Execute menjalankan aksi secara langsung di dalam tx (jalur tanpa persetujuan)
+func (q *Queue) Execute(tx *sql.Tx, action policy.Action, payload interface{}) (interface{}, error) {
+	fn, ok := q.executors[action]
+	if !ok {
+		return nil, ErrUnknownAction
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return fn(tx, raw)
+}
+
+// Submit memasukkan aksi ke antrean persetujuan atas nama maker
+func (q *Queue) Submit(maker *auth.Principal, action policy.Action, noRekening string, nominal float64, alasan string, payload interface{}) (*models.PendingOperation, error) {
+	if _, ok := q.executors[action]; !ok {
+		return nil, ErrUnknownAction
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	op := &models.PendingOperation{
+		Action:     string(action),
+		NoRekening: noRekening,
+		Nominal:    nominal,
+		Payload:    raw,
+		MakerType:  maker.Type,
+		MakerID:    maker.ID,
+		Alasan:     alasan,
+		ExpiresAt:  time.Now().Add(q.TTL),
+	}
+	if err := repositories.InsertPendingOperation(q.DB, op); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"OperationID": op.ID,
+		"action":      op.Action,
+		"NoRekening":  noRekening,
+		"maker":       fmt.Sprintf("%s:%d", maker.Type, maker.ID),
+	}).Info("Operation submitted for approval")
+
+	return op, nil
+}
+
+// Approve menyetujui operasi dan menjalankannya secara atomik dalam satu transaksi.
+// Jika eksekusi gagal, operasi ditandai failed dan error dibungkus ExecutionError.
+func (q *Queue) Approve(checker *auth.Principal, id int, catatan string) (*models.PendingOperation, interface{}, error) {
+	tx, err := q.DB.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	op, err := q.lockForDecision(tx, checker, id)
+	if err != nil {
+		return op, nil, err
+	}
+
+	fn, ok := q.executors[policy.Action(op.Action)]
+	if !ok {
+		return op, nil, ErrUnknownAction
+	}
+
+	result, execErr := fn(tx, op.Payload)
+	if execErr != nil {
+		tx.Rollback()
+		return op, nil, q.markFailed(op, checker, catatan, execErr)
+	}
+
+	now := time.Now()
+	op.Status = models.OperasiApproved
+	op.CheckerID = &checker.ID
+	op.CatatanChecker = catatan
+	op.DecidedAt = &now
+	if err := repositories.UpdatePendingOperationDecision(tx, op); err != nil {
+		return op, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return op, nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"OperationID": op.ID,
+		"action":      op.Action,
+		"checker":     checker.ID,
+	}).Info("Operation approved and executed")
+
+	return op, result, nil
+}
+
+// Reject menolak operasi yang masih menunggu persetujuan
+func (q *Queue) Reject(checker *auth.Principal, id int, catatan string) (*models.PendingOperation, error) {
+	tx, err := q.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	op, err := q.lockForDecision(tx, checker, id)
+	if err != nil {
+		return op, err
+	}
+
+	now := time.Now()
+	op.Status = models.OperasiRejected
+	op.CheckerID = &checker.ID
+	op.CatatanChecker = catatan
+	op.DecidedAt = &now
+	if err := repositories.UpdatePendingOperationDecision(tx, op); err != nil {
+		return op, err
+	}
+	if err := tx.Commit(); err != nil {
+		return op, err
+	}
+
+	log.WithFields(log.Fields{
+		"OperationID": op.ID,
+		"action":      op.Action,
+		"checker":     checker.ID,
+	}).Info("Operation rejected")
+
+	return op, nil
+}
+
+// List mengambil operasi berdasarkan status (kosong berarti semua status)
+func (q *Queue) List(status string, limit int) ([]models.PendingOperation, error) {
+	return repositories.ListPendingOperations(q.DB, status, limit)
+}
+
+// Run menandai operasi yang melewati batas waktu sebagai expired secara berkala
+func (q *Queue) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := repositories.ExpirePendingOperations(q.DB, time.Now())
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error": err,
+				}).Error("Failed to expire pending operations")
+				continue
+			}
+			if expired > 0 {
+				log.WithFields(log.Fields{
+					"expired": expired,
+				}).Info("Pending operations expired")
+			}
+		}
+	}
+}
+
+func (q *Queue) lockForDecision(tx *sql.Tx, checker *auth.Principal, id int) (*models.PendingOperation, error) {
+	op, err := repositories.GetPendingOperationForUpdate(tx, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if op.Status != models.OperasiPending {
+		return op, ErrNotPending
+	}
+	if time.Now().After(op.ExpiresAt) {
+		return op, ErrExpired
+	}
+	if op.MakerType == checker.Type && op.MakerID == checker.ID {
+		return op, ErrSelfApproval
+	}
+	return op, nil
+}
+
+func (q *Queue) markFailed(op *models.PendingOperation, checker *auth.Principal, catatan string, execErr error) error {
+	now := time.Now()
+	op.Status = models.OperasiFailed
+	op.CheckerID = &checker.ID
+	op.CatatanChecker = catatan
+	op.Error = execErr.Error()
+	op.DecidedAt = &now
+	if err := repositories.UpdatePendingOperationDecision(q.DB, op); err != nil {
+		log.WithFields(log.Fields{
+			"error":       err,
+			"OperationID": op.ID,
+		}).Error("Failed to mark operation as failed")
+	}
+
+	log.WithFields(log.Fields{
+		"error":       execErr,
+		"OperationID": op.ID,
+		"action":      op.Action,
+	}).Warn("Approved operation failed to execute")
+
+	return &ExecutionError{Err: execErr}
+}
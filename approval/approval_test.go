@@ -0,0 +1,148 @@
+package approval
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/policy"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var operationColumns = []string{"id", "action", "no_rekening", "nominal", "payload", "status", "maker_type", "maker_id",
+	"alasan", "checker_id", "catatan_checker", "error", "created_at", "expires_at", "decided_at"}
+
+var (
+	maker   = &auth.Principal{Type: auth.SubjectStaff, ID: 10, Role: auth.RoleTeller}
+	checker = &auth.Principal{Type: auth.SubjectStaff, ID: 20, Role: auth.RoleSupervisor}
+)
+
+// newTestQueue membuat Queue di atas sqlmock dengan executor freeze yang mencatat pemanggilan
+func newTestQueue(t *testing.T) (*Queue, sqlmock.Sqlmock, *int) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	executed := 0
+	q := NewQueue(db, time.Hour)
+	q.Register(policy.ActionFreeze, func(tx *sql.Tx, payload json.RawMessage) (interface{}, error) {
+		executed++
+		return "ok", nil
+	})
+	return q, mock, &executed
+}
+
+// expectOperation mengantrekan SELECT ... FOR UPDATE yang mengembalikan operasi pending milik maker
+func expectOperation(mock sqlmock.Sqlmock, status string, expiresAt time.Time) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM pending_operations WHERE id = \\$1 FOR UPDATE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(operationColumns).AddRow(
+			1, string(policy.ActionFreeze), "1000000001", 0, []byte(`{}`), status, maker.Type, maker.ID,
+			"", nil, "", "", time.Now().Add(-time.Minute), expiresAt, nil))
+}
+
+func TestApproveExecutesOperation(t *testing.T) {
+	q, mock, executed := newTestQueue(t)
+	expectOperation(mock, models.OperasiPending, time.Now().Add(time.Hour))
+	mock.ExpectExec("UPDATE pending_operations").
+		WithArgs(models.OperasiApproved, checker.ID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	op, result, err := q.Approve(checker, 1, "ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Status != models.OperasiApproved || result != "ok" || *executed != 1 {
+		t.Errorf("status = %s, result = %v, executed = %d", op.Status, result, *executed)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestApproveRejectsDecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		checker   *auth.Principal
+		status    string
+		expiresAt time.Time
+		want      error
+	}{
+		{"self approval", maker, models.OperasiPending, time.Now().Add(time.Hour), ErrSelfApproval},
+		{"expired", checker, models.OperasiPending, time.Now().Add(-time.Second), ErrExpired},
+		{"already decided", checker, models.OperasiRejected, time.Now().Add(time.Hour), ErrNotPending},
+		{"same id but different subject type", &auth.Principal{Type: auth.SubjectNasabah, ID: maker.ID}, models.OperasiPending, time.Now().Add(time.Hour), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, mock, executed := newTestQueue(t)
+			expectOperation(mock, tt.status, tt.expiresAt)
+			if tt.want == nil {
+				mock.ExpectExec("UPDATE pending_operations").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			_, _, err := q.Approve(tt.checker, 1, "")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil && *executed != 0 {
+				t.Error("operation executed despite rejected decision")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRejectBySelfIsRefused(t *testing.T) {
+	q, mock, _ := newTestQueue(t)
+	expectOperation(mock, models.OperasiPending, time.Now().Add(time.Hour))
+	mock.ExpectRollback()
+
+	if _, err := q.Reject(maker, 1, ""); !errors.Is(err, ErrSelfApproval) {
+		t.Fatalf("err = %v, want %v", err, ErrSelfApproval)
+	}
+}
+
+func TestApproveUnknownOperation(t *testing.T) {
+	q, mock, _ := newTestQueue(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM pending_operations").WithArgs(99).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	if _, _, err := q.Approve(checker, 99, ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestSubmitSetsExpiry(t *testing.T) {
+	q, mock, _ := newTestQueue(t)
+	mock.ExpectQuery("INSERT INTO pending_operations").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(1, models.OperasiPending, time.Now()))
+
+	before := time.Now()
+	op, err := q.Submit(maker, policy.ActionFreeze, "1000000001", 0, "Pembekuan", struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.ExpiresAt.Before(before.Add(q.TTL)) || op.ExpiresAt.After(time.Now().Add(q.TTL)) {
+		t.Errorf("expires_at = %v, want now + %v", op.ExpiresAt, q.TTL)
+	}
+
+	if _, err := q.Submit(maker, policy.ActionReversal, "1000000001", 0, "", struct{}{}); !errors.Is(err, ErrUnknownAction) {
+		t.Errorf("err = %v, want %v", err, ErrUnknownAction)
+	}
+}
@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"time"
+	"unicode/utf8"
+)
+
+// genesisHash adalah prev_hash untuk baris audit pertama
+var genesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+// Panjang maksimum kolom audit_events yang isinya bisa berasal dari request
+const (
+	MaxRequestIDLength  = 64
+	maxActorIDLength    = 64
+	maxActionLength     = 100
+	maxNoRekeningLength = 20
+)
+
+// Recorder menulis log audit ke tabel audit_events yang membentuk rantai hash
+type Recorder struct {
+	DB *sql.DB
+}
+
+// NewRecorder membuat Recorder baru
+func NewRecorder(db *sql.DB) *Recorder {
+	return &Recorder{DB: db}
+}
+
+// Append menambahkan event ke ujung rantai. Penambahan diserialkan dengan advisory
+// lock supaya dua replika tidak pernah menautkan baris ke hash sebelumnya yang sama.
+func (r *Recorder) Append(e *models.AuditEvent) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := repositories.LockAuditChain(tx); err != nil {
+		return err
+	}
+
+	prev, err := repositories.GetLastAuditHash(tx)
+	if err != nil {
+		return err
+	}
+	if prev == "" {
+		prev = genesisHash
+	}
+
+	fit(e)
+
+	// Presisi TIMESTAMP Postgres adalah mikrodetik; potong dulu agar hash bisa dihitung ulang
+	e.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	e.PrevHash = prev
+	e.Hash = ComputeHash(e)
+
+	if err := repositories.InsertAuditEvent(tx, e); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// fit memastikan nilai dari request muat di kolom audit_events sehingga request dengan
+// header atau path parameter yang terlalu panjang tidak membuat penulisan audit gagal.
+// Rekening yang terlalu panjang pasti bukan rekening yang ada, jadi dikosongkan alih-alih
+// dipotong agar tidak tercatat sebagai rekening lain.
+func fit(e *models.AuditEvent) {
+	e.RequestID = truncate(e.RequestID, MaxRequestIDLength)
+	e.ActorID = truncate(e.ActorID, maxActorIDLength)
+	e.Action = truncate(e.Action, maxActionLength)
+	if len(e.NoRekening) > maxNoRekeningLength {
+		e.NoRekening = ""
+	}
+}
+
+// truncate memotong s menjadi paling banyak n byte tanpa memotong karakter UTF-8
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// ComputeHash menghitung hash sebuah baris audit dari isi baris dan prev_hash-nya
+func ComputeHash(e *models.AuditEvent) string {
+	canonical, _ := json.Marshal(struct {
+		RequestID  string `json:"request_id"`
+		ActorType  string `json:"actor_type"`
+		ActorID    string `json:"actor_id"`
+		Action     string `json:"action"`
+		NoRekening string `json:"no_rekening"`
+		Before     string `json:"before"`
+		After      string `json:"after"`
+		StatusCode int    `json:"status_code"`
+		CreatedAt  string `json:"created_at"`
+		PrevHash   string `json:"prev_hash"`
+	}{
+		RequestID:  e.RequestID,
+		ActorType:  e.ActorType,
+		ActorID:    e.ActorID,
+		Action:     e.Action,
+		NoRekening: e.NoRekening,
+		Before:     e.Before,
+		After:      e.After,
+		StatusCode: e.StatusCode,
+		CreatedAt:  e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:   e.PrevHash,
+	})
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// Search mencari log audit berdasarkan filter
+func (r *Recorder) Search(f models.AuditFilter) ([]models.AuditEvent, error) {
+	return repositories.ListAuditEvents(r.DB, f)
+}
@@ -0,0 +1,172 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const changeKey = "audit_change"
+
+type change struct {
+	noRekening string
+	before     interface{}
+	after      interface{}
+}
+
+// SetChange dipanggil handler untuk melengkapi event audit request saat ini dengan
+// rekening target serta nilai sebelum dan sesudah perubahan
+func SetChange(c echo.Context, noRekening string, before, after interface{}) {
+	ch, ok := c.Get(changeKey).(*change)
+	if !ok {
+		return
+	}
+	ch.noRekening = noRekening
+	ch.before = before
+	ch.after = after
+}
+
+// RequestID memasang header X-Request-ID pada setiap request dan respons. ID dari klien
+// hanya dipakai jika lolos ValidRequestID; selain itu server membuat ID baru.
+func RequestID() echo.MiddlewareFunc {
+	generate := middleware.RequestID()
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		h := generate(next)
+		return func(c echo.Context) error {
+			if !ValidRequestID(c.Request().Header.Get(echo.HeaderXRequestID)) {
+				c.Request().Header.Del(echo.HeaderXRequestID)
+			}
+			return h(c)
+		}
+	}
+}
+
+// ValidRequestID melaporkan apakah request id dari klien muat di log audit dan hanya
+// berisi huruf, angka, '-', '_', '.' atau ':'
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Middleware mencatat setiap request yang mengubah state (selain GET/HEAD/OPTIONS)
+// setelah handler selesai, termasuk request yang gagal atau ditolak. Body request
+// tidak pernah dicatat karena bisa berisi PIN, password atau secret.
+//
+// Respons handler ditahan sampai event audit tertulis. Jika penulisan audit gagal,
+// klien menerima AUDIT_UNAVAILABLE alih-alih respons sukses, sehingga tidak ada
+// perubahan yang dilaporkan berhasil tanpa jejak audit.
+func (r *Recorder) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+
+			ch := &change{}
+			c.Set(changeKey, ch)
+
+			res := c.Response()
+			writer := res.Writer
+			buf := &bufferedWriter{ResponseWriter: writer}
+			res.Writer = buf
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			res.Writer = writer
+
+			event := &models.AuditEvent{
+				RequestID:  res.Header().Get(echo.HeaderXRequestID),
+				Action:     c.Request().Method + " " + c.Path(),
+				NoRekening: ch.noRekening,
+				Before:     encode(ch.before),
+				After:      encode(ch.after),
+				StatusCode: res.Status,
+			}
+			if event.NoRekening == "" {
+				event.NoRekening = c.Param("no_rekening")
+			}
+			event.ActorType, event.ActorID = Actor(auth.PrincipalFrom(c))
+
+			if err := r.Append(event); err != nil {
+				// Respons handler dibuang dan diganti error; header selain milik respons
+				// error tetap dikirim, misalnya X-Request-ID dan RateLimit-*
+				res.Header().Del(echo.HeaderContentLength)
+				c.SetResponse(echo.NewResponse(writer, c.Echo()))
+				c.Error(apierror.Wrap(apierror.AuditUnavailable, err))
+				return nil
+			}
+			buf.flush()
+			return nil
+		}
+	}
+}
+
+// bufferedWriter menahan status dan body respons sampai event audit tertulis. Header
+// langsung ditulis ke ResponseWriter asli karena baru dikirim saat WriteHeader.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) flush() {
+	if w.status == 0 {
+		return
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.body.Bytes())
+}
+
+// Actor mengembalikan jenis dan id pelaku untuk event audit dari principal
+func Actor(p *auth.Principal) (string, string) {
+	if p == nil {
+		return "anonymous", "-"
+	}
+	if p.Type == auth.SubjectPartner {
+		return p.Type, p.ClientID
+	}
+	return p.Type, strconv.Itoa(p.ID)
+}
+
+func encode(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
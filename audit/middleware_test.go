@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/labstack/echo/v4"
+)
+
+func newAuditedEcho(t *testing.T) (*echo.Echo, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.Handler
+	e.Use(RequestID())
+	e.Use(NewRecorder(db).Middleware())
+	e.POST("/rekening/:no_rekening/freeze", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, utils.Response{Code: "00", Remark: "ok"})
+	})
+	return e, mock
+}
+
+func expectAppend(mock sqlmock.Sqlmock, requestID, noRekening interface{}) *sqlmock.ExpectedQuery {
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT hash FROM audit_events").WillReturnError(sql.ErrNoRows)
+	return mock.ExpectQuery("INSERT INTO audit_events").
+		WithArgs(requestID, "anonymous", "-", "POST /rekening/:no_rekening/freeze", noRekening,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), http.StatusOK, sqlmock.AnyArg(), genesisHash, sqlmock.AnyArg())
+}
+
+// serverRequestID mencocokkan request id buatan server, bukan milik klien
+type serverRequestID struct{ client string }
+
+func (m serverRequestID) Match(v driver.Value) bool {
+	id, ok := v.(string)
+	return ok && ValidRequestID(id) && id != m.client
+}
+
+func TestMiddlewareFitsOverlongRequestValues(t *testing.T) {
+	e, mock := newAuditedEcho(t)
+	clientID := strings.Repeat("a", MaxRequestIDLength+1)
+	expectAppend(mock, serverRequestID{clientID}, sql.NullString{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	req := httptest.NewRequest(http.MethodPost, "/rekening/"+strings.Repeat("9", 40)+"/freeze", nil)
+	req.Header.Set(echo.HeaderXRequestID, clientID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get(echo.HeaderXRequestID); got == clientID || !ValidRequestID(got) {
+		t.Errorf("X-Request-ID = %q, want server generated id", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMiddlewareFailsRequestWhenAuditFails(t *testing.T) {
+	e, mock := newAuditedEcho(t)
+	expectAppend(mock, sql.NullString{String: "req-1", Valid: true}, sql.NullString{String: "1000000001", Valid: true}).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest(http.MethodPost, "/rekening/1000000001/freeze", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var body utils.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body %q: %v", rec.Body.String(), err)
+	}
+	if rec.Code != http.StatusInternalServerError || body.Code != string(apierror.AuditUnavailable) {
+		t.Errorf("response = %d %s, want 500 %s", rec.Code, body.Code, apierror.AuditUnavailable)
+	}
+	if got := rec.Header().Get(echo.HeaderXRequestID); got != "req-1" {
+		t.Errorf("X-Request-ID = %q, want client id kept", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
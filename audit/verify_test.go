@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"golang-echo-postgresql/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var auditColumns = []string{"id", "request_id", "actor_type", "actor_id", "action", "no_rekening", "before", "after",
+	"status_code", "created_at", "prev_hash", "hash"}
+
+// chain membuat n event yang tertaut dengan benar mulai dari genesisHash
+func chain(n int) []models.AuditEvent {
+	events := make([]models.AuditEvent, n)
+	prev := genesisHash
+	start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	for i := range events {
+		e := &events[i]
+		e.ID = int64(i + 1)
+		e.ActorType = "staff"
+		e.ActorID = "1"
+		e.Action = "POST /backoffice/rekening/:no_rekening/freeze"
+		e.NoRekening = "1000000001"
+		e.StatusCode = 200
+		e.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		e.PrevHash = prev
+		e.Hash = ComputeHash(e)
+		prev = e.Hash
+	}
+	return events
+}
+
+func verify(t *testing.T, events []models.AuditEvent) *Report {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows(auditColumns)
+	for _, e := range events {
+		rows.AddRow(e.ID, e.RequestID, e.ActorType, e.ActorID, e.Action, e.NoRekening, e.Before, e.After,
+			e.StatusCode, e.CreatedAt, e.PrevHash, e.Hash)
+	}
+	mock.ExpectQuery("FROM audit_events").WithArgs("", "", "", 0, verifyBatchSize).WillReturnRows(rows)
+	if len(events) > 0 {
+		last := events[len(events)-1].ID
+		mock.ExpectQuery("FROM audit_events").WithArgs("", "", "", last, verifyBatchSize).WillReturnRows(sqlmock.NewRows(auditColumns))
+	}
+
+	report, err := NewRecorder(db).Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	return report
+}
+
+func TestVerifyIntactChain(t *testing.T) {
+	report := verify(t, chain(3))
+	if !report.OK() || report.Checked != 3 || report.LastID != 3 {
+		t.Errorf("report = %+v, want 3 rows checked without breaks", report)
+	}
+}
+
+func TestVerifyEmptyChain(t *testing.T) {
+	if report := verify(t, nil); !report.OK() || report.Checked != 0 {
+		t.Errorf("report = %+v, want empty report", report)
+	}
+}
+
+func TestVerifyDetectsModifiedRow(t *testing.T) {
+	events := chain(3)
+	events[1].StatusCode = 500
+
+	report := verify(t, events)
+	if len(report.Breaks) != 1 || report.Breaks[0].ID != 2 || !strings.Contains(report.Breaks[0].Reason, "hash tersimpan") {
+		t.Errorf("breaks = %+v, want hash mismatch on id 2 only", report.Breaks)
+	}
+}
+
+func TestVerifyDetectsDeletedRow(t *testing.T) {
+	events := chain(4)
+	events = append(events[:1], events[2:]...)
+
+	report := verify(t, events)
+	if len(report.Breaks) != 1 || report.Breaks[0].ID != 3 || !strings.Contains(report.Breaks[0].Reason, "prev_hash") {
+		t.Errorf("breaks = %+v, want prev_hash mismatch on id 3 only", report.Breaks)
+	}
+}
+
+func TestVerifyDetectsRewrittenGenesis(t *testing.T) {
+	events := chain(2)
+	events[0].PrevHash = strings.Repeat("f", 64)
+	events[0].Hash = ComputeHash(&events[0])
+
+	report := verify(t, events)
+	if len(report.Breaks) != 2 || report.Breaks[0].ID != 1 || report.Breaks[1].ID != 2 {
+		t.Errorf("breaks = %+v, want prev_hash mismatch on id 1 and 2", report.Breaks)
+	}
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// BootstrapAdmin membuat akun admin pertama jika tabel staff masih kosong dan
+// kredensial bootstrap diberikan lewat konfigurasi
+func BootstrapAdmin(db *sql.DB, username, password string) error {
+	if username == "" || password == "" {
+		return nil
+	}
+
+	total, err := repositories.CountStaff(db)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return nil
+	}
+
+	hash, err := HashSecret(password)
+	if err != nil {
+		return err
+	}
+
+	staff := &models.Staff{Username: username, PasswordHash: hash, Role: RoleAdmin}
+	if err := repositories.CreateStaff(db, staff); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"username": username,
+	}).Info("Bootstrap admin account created")
+	return nil
+}
@@ -0,0 +1,322 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrNoSigningKey dikembalikan jika belum ada kunci aktif untuk menandatangani token
+var ErrNoSigningKey = errors.New("tidak ada kunci penandatangan aktif")
+
+// unknownKidRefreshInterval membatasi refresh dari database karena kid yang tidak dikenal.
+// Tanpa batas ini setiap token dengan kid palsu memicu satu query.
+const unknownKidRefreshInterval = 10 * time.Second
+
+// Sealer mengenkripsi kunci privat sebelum disimpan di database; dipenuhi oleh
+// *partner.SecretBox
+type Sealer interface {
+	Seal(plain string) (string, error)
+	Open(encoded string) (string, error)
+}
+
+type signingKey struct {
+	kid       string
+	private   *rsa.PrivateKey
+	sealed    bool
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+// KeyManager mengelola kunci RSA untuk JWT. Kunci disimpan di database agar semua
+// replika memakai set kunci yang sama, dan kunci lama tetap dipublikasikan lewat
+// JWKS sampai semua token yang ditandatanganinya kedaluwarsa. Jika box diisi, kunci
+// privat dienkripsi sebelum disimpan.
+type KeyManager struct {
+	db               *sql.DB
+	box              Sealer
+	rotationInterval time.Duration
+	verifyWindow     time.Duration
+
+	mu            sync.RWMutex
+	keys          []*signingKey // terbaru lebih dulu
+	missRefreshAt time.Time     // refresh terakhir karena kid tidak dikenal
+}
+
+// JWK adalah representasi publik satu kunci RSA dalam format JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKS adalah kumpulan kunci publik yang dipublikasikan ke klien
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeyManager membuat KeyManager. accessTTL dipakai untuk menentukan berapa lama
+// kunci lama masih harus bisa memverifikasi token setelah dirotasi. box boleh nil;
+// kunci privat kemudian disimpan tanpa enkripsi.
+func NewKeyManager(db *sql.DB, box Sealer, rotationInterval, accessTTL time.Duration) *KeyManager {
+	return &KeyManager{
+		db:               db,
+		box:              box,
+		rotationInterval: rotationInterval,
+		verifyWindow:     rotationInterval + accessTTL,
+	}
+}
+
+// Refresh memuat ulang kunci yang masih berlaku dari database
+func (m *KeyManager) Refresh() error {
+	rows, err := repositories.GetSigningKeys(m.db, time.Now())
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*signingKey, 0, len(rows))
+	for _, row := range rows {
+		// Kunci yang dibuat sebelum enkripsi diaktifkan masih tersimpan sebagai PEM
+		encoded := row.PrivateKey
+		sealed := isSealed(encoded)
+		if sealed {
+			if m.box == nil {
+				return fmt.Errorf("kunci %s terenkripsi tetapi PARTNER_SECRET_KEY tidak diatur", row.KID)
+			}
+			if encoded, err = m.box.Open(encoded); err != nil {
+				return fmt.Errorf("gagal mendekripsi kunci %s: %v", row.KID, err)
+			}
+		}
+
+		block, _ := pem.Decode([]byte(encoded))
+		if block == nil {
+			return fmt.Errorf("kunci %s tidak valid", row.KID)
+		}
+		private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("gagal membaca kunci %s: %v", row.KID, err)
+		}
+		keys = append(keys, &signingKey{kid: row.KID, private: private, sealed: sealed, createdAt: row.CreatedAt, expiresAt: row.ExpiresAt})
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.mu.Unlock()
+	return nil
+}
+
+// isSealed membedakan kunci terenkripsi dari PEM yang disimpan sebelum enkripsi diaktifkan
+func isSealed(encoded string) bool {
+	return !strings.HasPrefix(encoded, "-----BEGIN")
+}
+
+// Rotate membuat kunci baru yang langsung menjadi kunci aktif
+func (m *KeyManager) Rotate() error {
+	return m.rotate(true)
+}
+
+// rotate membuat kunci baru di bawah advisory lock sehingga replika yang merotasi
+// bersamaan diserialkan. Tanpa force kunci terbaru diperiksa ulang di dalam lock, dan
+// rotasi dilewati jika replika lain sudah membuat kunci yang masih berlaku.
+func (m *KeyManager) rotate(force bool) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := repositories.LockSigningKeys(tx); err != nil {
+		return err
+	}
+	if !force {
+		rows, err := repositories.GetSigningKeys(tx, time.Now())
+		if err != nil {
+			return err
+		}
+		if len(rows) > 0 && !m.stale(rows[0].CreatedAt, isSealed(rows[0].PrivateKey)) {
+			tx.Rollback()
+			return m.Refresh()
+		}
+	}
+
+	key, err := m.newKey()
+	if err != nil {
+		return err
+	}
+	if err := repositories.InsertSigningKey(tx, key); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"kid": key.KID,
+	}).Info("JWT signing key rotated")
+
+	return m.Refresh()
+}
+
+// newKey membuat kunci RSA baru dengan kid acak, terenkripsi jika box diisi
+func (m *KeyManager) newKey() (*models.SigningKey, error) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	kidBytes := make([]byte, 8)
+	if _, err := rand.Read(kidBytes); err != nil {
+		return nil, err
+	}
+
+	encoded := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)}))
+	if m.box != nil {
+		if encoded, err = m.box.Seal(encoded); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	return &models.SigningKey{
+		KID:        hex.EncodeToString(kidBytes),
+		PrivateKey: encoded,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(m.verifyWindow),
+	}, nil
+}
+
+// stale melaporkan apakah kunci aktif harus diganti: sudah melewati interval rotasi,
+// atau belum terenkripsi padahal box tersedia
+func (m *KeyManager) stale(createdAt time.Time, sealed bool) bool {
+	return time.Since(createdAt) >= m.rotationInterval || (m.box != nil && !sealed)
+}
+
+// EnsureActive memastikan ada kunci aktif yang belum melewati interval rotasi. Kunci
+// aktif yang belum terenkripsi padahal box tersedia langsung dirotasi; kunci lama tetap
+// dipakai untuk verifikasi sampai kedaluwarsa lalu dihapus. Semua replika memanggilnya,
+// tetapi hanya satu yang membuat kunci baru untuk setiap rotasi.
+func (m *KeyManager) EnsureActive() error {
+	if err := m.Refresh(); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	needRotate := len(m.keys) == 0 || m.stale(m.keys[0].createdAt, m.keys[0].sealed)
+	m.mu.RUnlock()
+
+	if needRotate {
+		return m.rotate(false)
+	}
+	return nil
+}
+
+// Run menjalankan rotasi terjadwal dan pembersihan data autentikasi kedaluwarsa
+func (m *KeyManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.EnsureActive(); err != nil {
+				log.WithFields(log.Fields{
+					"error": err,
+				}).Error("Failed to refresh signing keys")
+			}
+			now := time.Now()
+			if err := repositories.DeleteExpiredSigningKeys(m.db, now); err != nil {
+				log.WithFields(log.Fields{
+					"error": err,
+				}).Warn("Failed to delete expired signing keys")
+			}
+			if err := repositories.DeleteExpiredAuthRecords(m.db, now); err != nil {
+				log.WithFields(log.Fields{
+					"error": err,
+				}).Warn("Failed to delete expired auth records")
+			}
+		}
+	}
+}
+
+func (m *KeyManager) active() (*signingKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.keys) == 0 {
+		return nil, ErrNoSigningKey
+	}
+	return m.keys[0], nil
+}
+
+func (m *KeyManager) publicKey(kid string) (*rsa.PublicKey, bool) {
+	m.mu.RLock()
+	for _, k := range m.keys {
+		if k.kid == kid {
+			m.mu.RUnlock()
+			return &k.private.PublicKey, true
+		}
+	}
+	m.mu.RUnlock()
+
+	// Kunci mungkin baru saja dirotasi oleh replika lain. Refresh paling banyak sekali
+	// per unknownKidRefreshInterval; kid yang tetap tidak dikenal ditolak tanpa query.
+	m.mu.Lock()
+	if time.Since(m.missRefreshAt) < unknownKidRefreshInterval {
+		m.mu.Unlock()
+		return nil, false
+	}
+	m.missRefreshAt = time.Now()
+	m.mu.Unlock()
+
+	if err := m.Refresh(); err != nil {
+		return nil, false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, k := range m.keys {
+		if k.kid == kid {
+			return &k.private.PublicKey, true
+		}
+	}
+	return nil, false
+}
+
+// JWKS mengembalikan semua kunci publik yang masih berlaku
+func (m *KeyManager) JWKS() JWKS {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := JWKS{Keys: make([]JWK, 0, len(m.keys))}
+	for _, k := range m.keys {
+		pub := k.private.PublicKey
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: k.kid,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+	return set
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var keyColumns = []string{"kid", "private_key", "created_at", "expires_at"}
+
+// testBox adalah Sealer tiruan; cukup untuk membedakan kunci terenkripsi dari PEM
+type testBox struct{}
+
+func (testBox) Seal(plain string) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(plain)), nil
+}
+
+func (testBox) Open(encoded string) (string, error) {
+	plain, err := base64.StdEncoding.DecodeString(encoded)
+	return string(plain), err
+}
+
+// captured menyimpan argumen query supaya bisa dikembalikan lagi oleh SELECT berikutnya
+type captured struct{ value string }
+
+func (c *captured) Match(v driver.Value) bool {
+	c.value, _ = v.(string)
+	return true
+}
+
+func newTestKeyManager(t *testing.T, box Sealer) (*KeyManager, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewKeyManager(db, box, time.Hour, 15*time.Minute), mock
+}
+
+func pemKey(t *testing.T) string {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)}))
+}
+
+func TestRotateStoresSealedKey(t *testing.T) {
+	m, mock := newTestKeyManager(t, testBox{})
+	stored := &captured{}
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO auth_signing_keys").
+		WithArgs(sqlmock.AnyArg(), stored, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("FROM auth_signing_keys").WillReturnRows(sqlmock.NewRows(keyColumns))
+
+	if err := m.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stored.value, "PRIVATE KEY") {
+		t.Fatal("private key stored as plain PEM")
+	}
+
+	mock.ExpectQuery("FROM auth_signing_keys").
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow("k1", stored.value, time.Now(), time.Now().Add(time.Hour)))
+	if err := m.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := m.active(); err != nil || key.kid != "k1" || !key.sealed {
+		t.Errorf("active = %+v, %v; want sealed k1", key, err)
+	}
+}
+
+func TestRefreshSealedKeyWithoutBox(t *testing.T) {
+	m, mock := newTestKeyManager(t, nil)
+	sealed, _ := testBox{}.Seal(pemKey(t))
+	mock.ExpectQuery("FROM auth_signing_keys").
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow("k1", sealed, time.Now(), time.Now().Add(time.Hour)))
+
+	if err := m.Refresh(); err == nil || !strings.Contains(err.Error(), "PARTNER_SECRET_KEY") {
+		t.Fatalf("err = %v, want missing key error", err)
+	}
+}
+
+func TestEnsureActiveRotatesPlainKey(t *testing.T) {
+	m, mock := newTestKeyManager(t, testBox{})
+	legacy := pemKey(t)
+	mock.ExpectQuery("FROM auth_signing_keys").
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow("legacy", legacy, time.Now(), time.Now().Add(time.Hour)))
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("FROM auth_signing_keys").
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow("legacy", legacy, time.Now(), time.Now().Add(time.Hour)))
+	mock.ExpectExec("INSERT INTO auth_signing_keys").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("FROM auth_signing_keys").WillReturnRows(sqlmock.NewRows(keyColumns))
+
+	if err := m.EnsureActive(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEnsureActiveSkipsKeyRotatedByAnotherReplica(t *testing.T) {
+	m, mock := newTestKeyManager(t, nil)
+	old, fresh := pemKey(t), pemKey(t)
+	mock.ExpectQuery("FROM auth_signing_keys").
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow("old", old, time.Now().Add(-2*time.Hour), time.Now().Add(time.Hour)))
+	// Replika lain memegang lock lebih dulu dan sudah menyimpan kunci baru
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("FROM auth_signing_keys").
+		WillReturnRows(sqlmock.NewRows(keyColumns).
+			AddRow("fresh", fresh, time.Now(), time.Now().Add(2*time.Hour)).
+			AddRow("old", old, time.Now().Add(-2*time.Hour), time.Now().Add(time.Hour)))
+	mock.ExpectRollback()
+	mock.ExpectQuery("FROM auth_signing_keys").
+		WillReturnRows(sqlmock.NewRows(keyColumns).
+			AddRow("fresh", fresh, time.Now(), time.Now().Add(2*time.Hour)).
+			AddRow("old", old, time.Now().Add(-2*time.Hour), time.Now().Add(time.Hour)))
+
+	if err := m.EnsureActive(); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := m.active(); err != nil || key.kid != "fresh" {
+		t.Errorf("active = %+v, %v; want fresh", key, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUnknownKidRefreshIsThrottled(t *testing.T) {
+	m, mock := newTestKeyManager(t, nil)
+	mock.ExpectQuery("FROM auth_signing_keys").
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow("k1", pemKey(t), time.Now(), time.Now().Add(time.Hour)))
+
+	if _, ok := m.publicKey("k1"); !ok {
+		t.Fatal("key rotated by another replica not found after refresh")
+	}
+	// Kid palsu tidak lagi memicu query selama interval
+	for i := 0; i < 5; i++ {
+		if _, ok := m.publicKey("forged"); ok {
+			t.Fatal("forged kid accepted")
+		}
+	}
+	if _, ok := m.publicKey("k1"); !ok {
+		t.Error("known kid rejected while refresh is throttled")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	m.missRefreshAt = time.Now().Add(-unknownKidRefreshInterval)
+	mock.ExpectQuery("FROM auth_signing_keys").WillReturnError(errors.New("connection refused"))
+	if _, ok := m.publicKey("forged"); ok {
+		t.Fatal("forged kid accepted")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("no refresh after interval elapsed: %v", err)
+	}
+}
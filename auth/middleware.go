@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// Authenticate memverifikasi header "Authorization: Bearer <token>" dan menyimpan
+// principal ke dalam context. Jika principal sudah ada (misalnya dari autentikasi
+// lain yang dipasang lebih dulu), request langsung diteruskan.
+func Authenticate(tokens *TokenService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if PrincipalFrom(c) != nil {
+				return next(c)
+			}
+
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			raw, found := strings.CutPrefix(header, "Bearer ")
+			if !found || raw == "" {
+				return apierror.New(apierror.Unauthorized)
+			}
+
+			p, err := tokens.ParseAccessToken(raw)
+			if err != nil {
+				if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrTokenRevoked) {
+					log.WithContext(c.Request().Context()).WithFields(log.Fields{
+						"error": err,
+						"path":  c.Request().URL.Path,
+					}).Warn("Rejected access token")
+					return apierror.New(apierror.Unauthorized)
+				}
+				log.WithContext(c.Request().Context()).WithFields(log.Fields{
+					"error": err,
+				}).Error("Failed to verify access token")
+				return apierror.New(apierror.InternalError)
+			}
+
+			SetPrincipal(c, p)
+			return next(c)
+		}
+	}
+}
+
+// RequireRole hanya meneruskan request dari petugas dengan salah satu peran yang diberikan
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			p := PrincipalFrom(c)
+			if p.IsStaff() {
+				for _, role := range roles {
+					if p.Role == role {
+						return next(c)
+					}
+				}
+			}
+			return apierror.New(apierror.Forbidden)
+		}
+	}
+}
+
+// RequireRekeningAccess memastikan principal adalah pemilik rekening pada path
+// parameter yang diberikan, atau petugas bank
+func RequireRekeningAccess(param string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !CanAccessRekening(PrincipalFrom(c), c.Param(param)) {
+				log.WithContext(c.Request().Context()).WithFields(log.Fields{
+					"NoRekening": c.Param(param),
+					"path":       c.Request().URL.Path,
+				}).Warn("Principal is not allowed to access rekening")
+				return apierror.New(apierror.Forbidden)
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireSubject hanya meneruskan request dari principal dengan tipe subjek tertentu
+func RequireSubject(subject string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if p := PrincipalFrom(c); p == nil || p.Type != subject {
+				return apierror.New(apierror.Forbidden)
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// dummyHash dipakai saat akun tidak ditemukan agar waktu respons login tetap seragam
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-secret"), bcrypt.DefaultCost)
+
+// HashSecret membuat hash bcrypt dari PIN atau password
+func HashSecret(secret string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckSecret membandingkan PIN atau password dengan hash yang tersimpan.
+// Hash kosong tetap diproses terhadap dummyHash supaya tidak membocorkan keberadaan akun.
+func CheckSecret(hash, secret string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(secret))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(secret)) == nil
+}
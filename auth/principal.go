@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Jenis subjek yang dapat diautentikasi
+const (
+	SubjectNasabah = "nasabah"
+	SubjectStaff   = "staff"
+	SubjectPartner = "partner"
+)
+
+// Peran petugas bank
+const (
+	RoleTeller     = "teller"
+	RoleSupervisor = "supervisor"
+	RoleAuditor    = "auditor"
+	RoleCompliance = "compliance"
+	RoleAdmin      = "admin"
+)
+
+// ValidRole memeriksa apakah peran dikenal
+func ValidRole(role string) bool {
+	switch role {
+	case RoleTeller, RoleSupervisor, RoleAuditor, RoleCompliance, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+const principalKey = "principal"
+
+// Principal adalah identitas yang sudah terautentikasi untuk request saat ini
+type Principal struct {
+	Type       string
+	ID         int
+	NoRekening string // Hanya terisi untuk nasabah
+	Role       string // Hanya terisi untuk petugas
+	ClientID   string // Hanya terisi untuk partner
+	TokenID    string
+	ExpiresAt  time.Time
+}
+
+// IsStaff mengembalikan true jika principal adalah petugas bank
+func (p *Principal) IsStaff() bool {
+	return p != nil && p.Type == SubjectStaff
+}
+
+// SetPrincipal menyimpan principal ke dalam context Echo
+func SetPrincipal(c echo.Context, p *Principal) {
+	c.Set(principalKey, p)
+}
+
+// PrincipalFrom mengambil principal dari context Echo, nil jika request belum terautentikasi
+func PrincipalFrom(c echo.Context) *Principal {
+	p, _ := c.Get(principalKey).(*Principal)
+	return p
+}
+
+// CanAccessRekening memeriksa apakah principal adalah pemilik rekening atau petugas bank
+func CanAccessRekening(p *Principal, noRekening string) bool {
+	if p == nil {
+		return false
+	}
+	if p.IsStaff() {
+		return true
+	}
+	return p.Type == SubjectNasabah && p.NoRekening == noRekening
+}
@@ -0,0 +1,305 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrInvalidToken dikembalikan untuk token yang rusak, kedaluwarsa atau tanda tangannya salah
+	ErrInvalidToken = errors.New("token tidak valid")
+	// ErrTokenRevoked dikembalikan untuk access token yang sudah dicabut (logout)
+	ErrTokenRevoked = errors.New("token sudah dicabut")
+	// ErrRefreshTokenReused dikembalikan jika refresh token yang sudah dirotasi dipakai lagi
+	ErrRefreshTokenReused = errors.New("refresh token sudah pernah dipakai")
+)
+
+// Claims adalah isi access token
+type Claims struct {
+	jwt.RegisteredClaims
+	SubjectType string `json:"sub_type"`
+	Role        string `json:"role,omitempty"`
+	NoRekening  string `json:"no_rekening,omitempty"`
+}
+
+// TokenService menerbitkan dan memverifikasi access token serta merotasi refresh token
+type TokenService struct {
+	DB         *sql.DB
+	Keys       *KeyManager
+	Issuer     string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+
+	// NoRekening mencari nomor rekening nasabah saat rotasi refresh token. Nil berarti
+	// dibaca dari tabel nasabah; diisi saat data nasabah ada di store lain.
+	NoRekening NoRekeningLookup
+}
+
+// NoRekeningLookup mengembalikan nomor rekening nasabah berdasarkan id, atau
+// sql.ErrNoRows jika nasabah tidak ada
+type NoRekeningLookup func(nasabahID int) (string, error)
+
+// NewTokenService membuat TokenService baru
+func NewTokenService(db *sql.DB, keys *KeyManager, issuer string, accessTTL, refreshTTL time.Duration) *TokenService {
+	return &TokenService{DB: db, Keys: keys, Issuer: issuer, AccessTTL: accessTTL, RefreshTTL: refreshTTL}
+}
+
+// IssueTokens menerbitkan access token dan refresh token baru dengan keluarga rotasi baru
+func (s *TokenService) IssueTokens(p *Principal) (*models.TokenResponse, error) {
+	family, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := s.issueRefresh(s.DB, p, family)
+	if err != nil {
+		return nil, err
+	}
+	access, err := s.issueAccess(p)
+	if err != nil {
+		return nil, err
+	}
+	return s.response(access, refresh), nil
+}
+
+// ParseAccessToken memverifikasi access token dan mengembalikan principal-nya
+func (s *TokenService) ParseAccessToken(raw string) (*Principal, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := s.Keys.publicKey(kid)
+		if !ok {
+			return nil, fmt.Errorf("kid %q tidak dikenal", kid)
+		}
+		return key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(s.Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	revoked, err := repositories.IsTokenRevoked(s.DB, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	id, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return &Principal{
+		Type:       claims.SubjectType,
+		ID:         id,
+		NoRekening: claims.NoRekening,
+		Role:       claims.Role,
+		TokenID:    claims.ID,
+		ExpiresAt:  claims.ExpiresAt.Time,
+	}, nil
+}
+
+// RotateRefreshToken menukar refresh token dengan pasangan token baru. Refresh token
+// hanya bisa dipakai sekali; pemakaian ulang dianggap pencurian dan mencabut seluruh keluarganya.
+func (s *TokenService) RotateRefreshToken(raw string) (*models.TokenResponse, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stored, err := repositories.GetRefreshTokenForUpdate(tx, hashToken(raw))
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	if stored.UsedAt != nil {
+		if err := repositories.RevokeRefreshTokenFamily(tx, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		log.WithFields(log.Fields{
+			"FamilyID":  stored.FamilyID,
+			"SubjectID": stored.SubjectID,
+		}).Warn("Refresh token reuse detected, family revoked")
+		return nil, ErrRefreshTokenReused
+	}
+
+	p, err := s.loadPrincipal(tx, stored.SubjectType, stored.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repositories.MarkRefreshTokenUsed(tx, stored.ID); err != nil {
+		return nil, err
+	}
+	refresh, err := s.issueRefresh(tx, p, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	access, err := s.issueAccess(p)
+	if err != nil {
+		return nil, err
+	}
+	return s.response(access, refresh), nil
+}
+
+// Revoke mencabut access token milik principal dan, jika diberikan, seluruh keluarga refresh token-nya
+func (s *TokenService) Revoke(p *Principal, refreshToken string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := repositories.RevokeToken(tx, p.TokenID, p.ExpiresAt); err != nil {
+		return err
+	}
+
+	if refreshToken != "" {
+		stored, err := repositories.GetRefreshTokenForUpdate(tx, hashToken(refreshToken))
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		// Hanya cabut refresh token milik principal yang sama
+		if stored != nil && stored.SubjectType == p.Type && stored.SubjectID == p.ID {
+			if err := repositories.RevokeRefreshTokenFamily(tx, stored.FamilyID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *TokenService) issueAccess(p *Principal) (string, error) {
+	key, err := s.Keys.active()
+	if err != nil {
+		return "", err
+	}
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Issuer:    s.Issuer,
+			Subject:   strconv.Itoa(p.ID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.AccessTTL)),
+		},
+		SubjectType: p.Type,
+		Role:        p.Role,
+		NoRekening:  p.NoRekening,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.private)
+}
+
+func (s *TokenService) issueRefresh(executor repositories.Executor, p *Principal, family string) (string, error) {
+	raw, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	err = repositories.InsertRefreshToken(executor, &models.RefreshToken{
+		TokenHash:   hashToken(raw),
+		FamilyID:    family,
+		SubjectType: p.Type,
+		SubjectID:   p.ID,
+		ExpiresAt:   time.Now().Add(s.RefreshTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// loadPrincipal membangun ulang principal dari database supaya perubahan peran
+// atau penonaktifan petugas langsung berlaku pada rotasi berikutnya
+func (s *TokenService) loadPrincipal(executor repositories.Executor, subjectType string, subjectID int) (*Principal, error) {
+	switch subjectType {
+	case SubjectNasabah:
+		lookup := s.NoRekening
+		if lookup == nil {
+			lookup = func(id int) (string, error) { return repositories.GetNoRekeningByNasabahID(executor, id) }
+		}
+		noRekening, err := lookup(subjectID)
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidToken
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &Principal{Type: SubjectNasabah, ID: subjectID, NoRekening: noRekening}, nil
+	case SubjectStaff:
+		staff, err := repositories.GetStaffByID(executor, subjectID)
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidToken
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !staff.Aktif {
+			return nil, ErrInvalidToken
+		}
+		return &Principal{Type: SubjectStaff, ID: staff.ID, Role: staff.Role}, nil
+	}
+	return nil, ErrInvalidToken
+}
+
+func (s *TokenService) response(access, refresh string) *models.TokenResponse {
+	return &models.TokenResponse{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.AccessTTL.Seconds()),
+	}
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,235 @@
+package backoffice
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/approval"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/repositories"
+	"time"
+)
+
+var (
+	ErrRekeningNotFound = errors.New("rekening tidak ditemukan")
+	ErrRekeningBeku     = errors.New("rekening sedang dibekukan")
+	ErrRekeningReview   = errors.New("rekening menunggu review compliance")
+	ErrTabunganNotFound = errors.New("transaksi tidak ditemukan")
+	ErrAlreadyReversed  = errors.New("transaksi sudah pernah dibatalkan")
+	ErrNotReversible    = errors.New("transaksi koreksi tidak dapat dibatalkan")
+	ErrInvalidRequest   = errors.New("request tidak valid")
+)
+
+// Arah penyesuaian saldo
+const (
+	ArahKredit = "kredit"
+	ArahDebit  = "debit"
+)
+
+// Register mendaftarkan semua operasi back-office ke antrean persetujuan
+func Register(q *approval.Queue) {
+	q.Register(policy.ActionAdjustment, decode(Adjust))
+	q.Register(policy.ActionReversal, decode(Reverse))
+	q.Register(policy.ActionFreeze, decode(Freeze))
+	q.Register(policy.ActionUnfreeze, decode(Unfreeze))
+}
+
+func decode[T any, R any](fn func(tx *sql.Tx, req T) (R, error)) approval.ExecuteFunc {
+	return func(tx *sql.Tx, payload json.RawMessage) (interface{}, error) {
+		var req T
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+		return fn(tx, req)
+	}
+}
+
+// ValidateAdjustment memeriksa request penyesuaian saldo sebelum dieksekusi atau diantrekan
+func ValidateAdjustment(req models.AdjustmentRequest) error {
+	if req.NoRekening == "" || req.Nominal <= 0 || req.Alasan == "" {
+		return ErrInvalidRequest
+	}
+	if req.Arah != ArahKredit && req.Arah != ArahDebit {
+		return ErrInvalidRequest
+	}
+	return nil
+}
+
+// Adjust menambah atau mengurangi saldo dengan transaksi koreksi
+func Adjust(tx *sql.Tx, req models.AdjustmentRequest) (*models.Nasabah, error) {
+	if err := ValidateAdjustment(req); err != nil {
+		return nil, err
+	}
+
+	nasabah, err := lookupRekening(tx, req.NoRekening)
+	if err != nil {
+		return nil, err
+	}
+
+	jenis := models.JenisKoreksiKredit
+	if req.Arah == ArahDebit {
+		jenis = models.JenisKoreksiDebit
+	}
+
+	if err := repositories.UpdateSaldo(tx, nasabah.NoRekening, jenis, req.Nominal); err != nil {
+		return nil, err
+	}
+	err = repositories.InsertTabunganDetail(tx, &models.Tabungan{
+		NasabahID:      nasabah.ID,
+		JenisTransaksi: jenis,
+		Nominal:        req.Nominal,
+		Keterangan:     req.Alasan,
+		Channel:        models.ChannelTeller,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nasabah, err = refreshSaldo(tx, nasabah)
+	if err != nil {
+		return nil, err
+	}
+
+	err = outbox.Enqueue(tx, outbox.BalanceAdjustedV1{
+		NoRekening: nasabah.NoRekening,
+		Arah:       req.Arah,
+		Nominal:    req.Nominal,
+		SaldoAkhir: nasabah.Saldo,
+		Alasan:     req.Alasan,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nasabah, nil
+}
+
+// Reverse membatalkan transaksi setor atau tarik dengan transaksi koreksi berlawanan
+func Reverse(tx *sql.Tx, req models.ReversalRequest) (*models.Tabungan, error) {
+	if req.TabunganID <= 0 || req.Alasan == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	original, err := repositories.GetTabunganByID(tx, req.TabunganID)
+	if err == sql.ErrNoRows {
+		return nil, ErrTabunganNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if original.JenisTransaksi != models.JenisSetor && original.JenisTransaksi != models.JenisTarik {
+		return nil, ErrNotReversible
+	}
+
+	reversed, err := repositories.IsTabunganReversed(tx, original.ID)
+	if err != nil {
+		return nil, err
+	}
+	if reversed {
+		return nil, ErrAlreadyReversed
+	}
+
+	noRekening, err := repositories.GetNoRekeningByNasabahID(tx, original.NasabahID)
+	if err != nil {
+		return nil, err
+	}
+
+	jenis := models.JenisKoreksiDebit
+	if original.JenisTransaksi == models.JenisTarik {
+		jenis = models.JenisKoreksiKredit
+	}
+
+	if err := repositories.UpdateSaldo(tx, noRekening, jenis, original.Nominal); err != nil {
+		return nil, err
+	}
+
+	reversal := &models.Tabungan{
+		NasabahID:      original.NasabahID,
+		JenisTransaksi: jenis,
+		Nominal:        original.Nominal,
+		Keterangan:     req.Alasan,
+		RefTabunganID:  &original.ID,
+		Channel:        models.ChannelTeller,
+	}
+	if err := repositories.InsertTabunganDetail(tx, reversal); err != nil {
+		return nil, err
+	}
+
+	err = outbox.Enqueue(tx, outbox.TransactionReversedV1{
+		NoRekening:         noRekening,
+		TabunganID:         original.ID,
+		ReversalTabunganID: reversal.ID,
+		Nominal:            original.Nominal,
+		OccurredAt:         time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reversal, nil
+}
+
+// Freeze membekukan rekening sehingga tidak bisa bertransaksi
+func Freeze(tx *sql.Tx, req models.FreezeRequest) (*models.Nasabah, error) {
+	return setStatus(tx, req, models.StatusBeku)
+}
+
+// Unfreeze membuka kembali rekening yang dibekukan. Rekening yang menunggu review
+// screening hanya bisa diaktifkan dengan me-review hit-nya.
+func Unfreeze(tx *sql.Tx, req models.FreezeRequest) (*models.Nasabah, error) {
+	if req.NoRekening == "" || req.Alasan == "" {
+		return nil, ErrInvalidRequest
+	}
+	nasabah, err := lookupRekening(tx, req.NoRekening)
+	if err != nil {
+		return nil, err
+	}
+	if nasabah.Status == models.StatusPendingReview {
+		return nil, ErrRekeningReview
+	}
+	return setStatus(tx, req, models.StatusAktif)
+}
+
+func setStatus(tx *sql.Tx, req models.FreezeRequest, status string) (*models.Nasabah, error) {
+	if req.NoRekening == "" || req.Alasan == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	err := repositories.UpdateStatusRekening(tx, req.NoRekening, status)
+	if err == sql.ErrNoRows {
+		return nil, ErrRekeningNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = outbox.Enqueue(tx, outbox.AccountStatusChangedV1{
+		NoRekening: req.NoRekening,
+		Status:     status,
+		Alasan:     req.Alasan,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lookupRekening(tx, req.NoRekening)
+}
+
+func lookupRekening(tx *sql.Tx, noRekening string) (*models.Nasabah, error) {
+	nasabah, err := repositories.GetNasabahByNoRekening(tx, noRekening)
+	if err == sql.ErrNoRows {
+		return nil, ErrRekeningNotFound
+	}
+	return nasabah, err
+}
+
+func refreshSaldo(tx *sql.Tx, nasabah *models.Nasabah) (*models.Nasabah, error) {
+	saldo, err := repositories.GetSaldo(tx, nasabah.NoRekening)
+	if err != nil {
+		return nil, err
+	}
+	nasabah.Saldo = saldo
+	return nasabah, nil
+}
@@ -0,0 +1,49 @@
+// Command amlreport menjalankan job AML untuk satu tanggal (bawaan kemarin): agregasi
+// tunai harian, laporan LTKT, kasus mencurigakan dan ekspor file PPATK. Cocok dipanggil
+// dari cron jika job di dalam aplikasi dimatikan, atau untuk memproses ulang tanggal lama.
+//
+//	go run ./cmd/amlreport -tanggal 2024-01-31
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"golang-echo-postgresql/aml"
+	"golang-echo-postgresql/config"
+	"golang-echo-postgresql/db"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	yesterday := time.Now().AddDate(0, 0, -1).Format(aml.DateLayout)
+	tanggalFlag := flag.String("tanggal", yesterday, "tanggal yang diproses (YYYY-MM-DD)")
+	flag.Parse()
+
+	tanggal, err := time.ParseInLocation(aml.DateLayout, *tanggalFlag, time.Local)
+	if err != nil {
+		logrus.Fatalf("Invalid -tanggal: %v", err)
+	}
+
+	cfg := config.LoadConfig()
+	dbConn := db.InitDB(cfg)
+	defer dbConn.Close()
+
+	service := aml.NewService(dbConn, aml.Thresholds{
+		LTKT:             cfg.AMLLTKTThreshold,
+		StructuringFloor: cfg.AMLStructuringFloor,
+		PassThroughMin:   cfg.AMLPassThroughMin,
+	}, cfg.AMLExportDir, cfg.AMLReportingEntityID, cfg.AMLRunAt)
+
+	run, err := service.RunFor(context.Background(), tanggal)
+	if err != nil {
+		logrus.Fatalf("AML job failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(run)
+}
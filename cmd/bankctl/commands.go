@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"golang-echo-postgresql/eod"
+	"golang-echo-postgresql/gl"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/reconcile"
+	"golang-echo-postgresql/repositories"
+	"io"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+func runLookup(a *app, args []string) error {
+	fs := flag.NewFlagSet("lookup", flag.ExitOnError)
+	nik := fs.String("nik", "", "NIK nasabah")
+	noHP := fs.String("hp", "", "nomor HP nasabah")
+	noRekening := fs.String("rekening", "", "nomor rekening")
+	fs.Parse(args)
+
+	var nasabah *models.Nasabah
+	var err error
+	switch {
+	case *noRekening != "":
+		nasabah, err = repositories.GetNasabahByNoRekening(a.db, *noRekening)
+	case *nik != "":
+		nasabah, err = repositories.GetNasabahByNIK(a.db, *nik)
+	case *noHP != "":
+		nasabah, err = repositories.GetNasabahByNoHP(a.db, *noHP)
+	default:
+		return errors.New("one of -nik, -hp or -rekening is required")
+	}
+	if err == sql.ErrNoRows {
+		return errors.New("nasabah not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.print(nasabah, func(w io.Writer) {
+		fmt.Fprintf(w, "ID\t%d\n", nasabah.ID)
+		fmt.Fprintf(w, "NAMA\t%s\n", nasabah.Nama)
+		fmt.Fprintf(w, "NIK\t%s\n", nasabah.NIK)
+		fmt.Fprintf(w, "NO HP\t%s\n", nasabah.NoHP)
+		fmt.Fprintf(w, "REKENING\t%s\n", nasabah.NoRekening)
+		fmt.Fprintf(w, "SALDO\t%.2f\n", nasabah.Saldo)
+		fmt.Fprintf(w, "STATUS\t%s\n", nasabah.Status)
+	})
+}
+
+func runAdjust(a *app, args []string) error {
+	fs := flag.NewFlagSet("adjust", flag.ExitOnError)
+	as := fs.String("as", "", "username petugas yang menjalankan perintah")
+	noRekening := fs.String("rekening", "", "nomor rekening")
+	arah := fs.String("arah", "", "kredit atau debit")
+	nominal := fs.Float64("nominal", 0, "nominal penyesuaian")
+	alasan := fs.String("alasan", "", "alasan penyesuaian")
+	dryRun := fs.Bool("dry-run", false, "tampilkan hasil tanpa menyimpan")
+	fs.Parse(args)
+
+	p, err := a.operator(*as)
+	if err != nil {
+		return err
+	}
+	req := models.AdjustmentRequest{NoRekening: *noRekening, Arah: *arah, Nominal: *nominal, Alasan: *alasan}
+	return a.mutate("adjust", p, policy.ActionAdjustment, req.NoRekening, req.Nominal, req.Alasan, req, *dryRun)
+}
+
+func runFreeze(a *app, args []string) error {
+	return runStatusChange(a, "freeze", policy.ActionFreeze, args)
+}
+
+func runUnfreeze(a *app, args []string) error {
+	return runStatusChange(a, "unfreeze", policy.ActionUnfreeze, args)
+}
+
+func runStatusChange(a *app, name string, action policy.Action, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	as := fs.String("as", "", "username petugas yang menjalankan perintah")
+	noRekening := fs.String("rekening", "", "nomor rekening")
+	alasan := fs.String("alasan", "", "alasan perubahan status")
+	dryRun := fs.Bool("dry-run", false, "tampilkan hasil tanpa menyimpan")
+	fs.Parse(args)
+
+	p, err := a.operator(*as)
+	if err != nil {
+		return err
+	}
+	req := models.FreezeRequest{NoRekening: *noRekening, Alasan: *alasan}
+	return a.mutate(name, p, action, req.NoRekening, 0, req.Alasan, req, *dryRun)
+}
+
+func runReconcile(a *app, args []string) error {
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	as := fs.String("as", "", "username petugas yang menjalankan perintah")
+	dryRun := fs.Bool("dry-run", false, "tampilkan selisih tanpa mencatat run, selisih maupun checkpoint")
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	service := reconcile.NewService(a.db, a.cfg.ReconBatchSize, a.cfg.ReconInterval)
+	if *dryRun {
+		mismatches, diperiksa, err := service.Preview(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(map[string]interface{}{"dry_run": true, "diperiksa": diperiksa, "mismatches": mismatches}, func(w io.Writer) {
+			fmt.Fprintf(w, "Checked %d accounts, %d mismatches (dry run)\n\n", diperiksa, len(mismatches))
+			printMismatches(w, mismatches)
+		})
+	}
+
+	p, err := a.operator(*as)
+	if err != nil {
+		return err
+	}
+	if !policy.Can(p, policy.PermReconcileRun) {
+		return fmt.Errorf("role %s is not allowed to run reconciliation", p.Role)
+	}
+	if err := a.waitEOD(); err != nil {
+		return err
+	}
+
+	run, err := service.RunOnce(ctx, "cli:"+*as)
+	if err != nil {
+		return err
+	}
+	mismatches, err := service.Mismatches(models.SelisihOpen, 1000)
+	if err != nil {
+		return err
+	}
+	return a.print(map[string]interface{}{"run": run, "mismatches": mismatches}, func(w io.Writer) {
+		fmt.Fprintf(w, "Run %d %s: checked %d accounts, %d mismatches\n\n", run.ID, run.Status, run.Diperiksa, run.Selisih)
+		printMismatches(w, mismatches)
+	})
+}
+
+func printMismatches(w io.Writer, mismatches []models.ReconciliationMismatch) {
+	fmt.Fprintln(w, "ID\tREKENING\tSALDO TERCATAT\tSALDO HITUNG\tSELISIH\tTRANSAKSI PERTAMA\tSTATUS")
+	for _, m := range mismatches {
+		tabungan := "-"
+		if m.TabunganID != nil {
+			tabungan = strconv.Itoa(*m.TabunganID)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%.2f\t%.2f\t%.2f\t%s\t%s\n", m.ID, m.NoRekening, m.SaldoTercatat, m.SaldoHitung, m.Selisih, tabungan, m.Status)
+	}
+}
+
+func runEOD(a *app, args []string) error {
+	fs := flag.NewFlagSet("eod", flag.ExitOnError)
+	as := fs.String("as", "", "username petugas yang menjalankan perintah")
+	tanggalFlag := fs.String("tanggal", "", "tanggal bisnis yang ditutup (YYYY-MM-DD); kosong berarti semua yang tertunda")
+	dryRun := fs.Bool("dry-run", false, "tampilkan step yang akan dijalankan tanpa menjalankan EOD")
+	fs.Parse(args)
+
+	service := a.eod
+	service.Register(eod.DefaultSteps(eod.Settings{
+		SukuBunga:      a.cfg.EODInterestRate,
+		BiayaAdmin:     a.cfg.EODMonthlyFee,
+		DormansiPeriod: a.cfg.EODDormancyDays,
+	})...)
+	ledger, err := gl.NewService(a.db, a.cfg.GLMappingFile)
+	if err != nil {
+		return err
+	}
+	if ledger.Enabled() {
+		service.Register(ledger.Step())
+	}
+
+	var tanggal time.Time
+	if *tanggalFlag != "" {
+		tanggal, err = time.ParseInLocation(eod.DateLayout, *tanggalFlag, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid -tanggal: %v", err)
+		}
+	}
+
+	if *dryRun {
+		if tanggal.IsZero() {
+			status, err := service.Status()
+			if err != nil {
+				return err
+			}
+			tanggal, _ = time.ParseInLocation(eod.DateLayout, status.TanggalBisnis, time.Local)
+		}
+		plan, err := service.Plan(tanggal)
+		if err != nil {
+			return err
+		}
+		return a.print(map[string]interface{}{"dry_run": true, "tanggal": tanggal.Format(eod.DateLayout), "steps": plan}, func(w io.Writer) {
+			fmt.Fprintf(w, "EOD plan for %s (dry run)\n\n", tanggal.Format(eod.DateLayout))
+			printSteps(w, plan)
+		})
+	}
+
+	p, err := a.operator(*as)
+	if err != nil {
+		return err
+	}
+	if !policy.Can(p, policy.PermEODRun) {
+		return fmt.Errorf("role %s is not allowed to run EOD", p.Role)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	triggeredBy := "cli:" + *as
+	if tanggal.IsZero() {
+		err = service.CloseDue(ctx, triggeredBy)
+	} else {
+		_, err = service.Close(ctx, tanggal, triggeredBy)
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := service.Status()
+	if err != nil {
+		return err
+	}
+	return a.print(status, func(w io.Writer) {
+		fmt.Fprintf(w, "Business date %s, last closed %s\n\n", status.TanggalBisnis, status.TanggalTerakhir)
+		if status.LastRun != nil {
+			printSteps(w, status.LastRun.Steps)
+		}
+	})
+}
+
+func printSteps(w io.Writer, steps []models.EODStep) {
+	fmt.Fprintln(w, "#\tSTEP\tSTATUS\tDIPROSES\tERROR")
+	for _, s := range steps {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\n", s.Urutan, s.Step, s.Status, s.Diproses, s.Error)
+	}
+}
+
+// statement adalah rekening koran satu rekening untuk rentang tanggal
+type statement struct {
+	NoRekening string            `json:"no_rekening"`
+	Nama       string            `json:"nama"`
+	Dari       string            `json:"dari"`
+	Sampai     string            `json:"sampai"`
+	SaldoAwal  float64           `json:"saldo_awal"`
+	SaldoAkhir float64           `json:"saldo_akhir"`
+	Transaksi  []models.Tabungan `json:"transaksi"`
+}
+
+func runStatement(a *app, args []string) error {
+	now := time.Now()
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	fs := flag.NewFlagSet("statement", flag.ExitOnError)
+	noRekening := fs.String("rekening", "", "nomor rekening")
+	dariFlag := fs.String("dari", firstOfMonth.Format(eod.DateLayout), "tanggal awal (YYYY-MM-DD)")
+	sampaiFlag := fs.String("sampai", now.Format(eod.DateLayout), "tanggal akhir, inklusif (YYYY-MM-DD)")
+	fs.Parse(args)
+
+	dari, err := time.ParseInLocation(eod.DateLayout, *dariFlag, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid -dari: %v", err)
+	}
+	sampai, err := time.ParseInLocation(eod.DateLayout, *sampaiFlag, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid -sampai: %v", err)
+	}
+	if sampai.Before(dari) {
+		return errors.New("-sampai must not be before -dari")
+	}
+	akhir := sampai.AddDate(0, 0, 1)
+
+	nasabah, err := repositories.GetNasabahByNoRekening(a.db, *noRekening)
+	if err == sql.ErrNoRows {
+		return errors.New("rekening not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	// Saldo awal, transaksi dan saldo akhir dibaca dari snapshot yang sama
+	tx, err := a.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	st := statement{
+		NoRekening: nasabah.NoRekening,
+		Nama:       nasabah.Nama,
+		Dari:       dari.Format(eod.DateLayout),
+		Sampai:     sampai.Format(eod.DateLayout),
+	}
+	if st.SaldoAwal, err = repositories.GetSaldoAsOf(tx, nasabah.ID, dari); err != nil {
+		return err
+	}
+	if st.SaldoAkhir, err = repositories.GetSaldoAsOf(tx, nasabah.ID, akhir); err != nil {
+		return err
+	}
+	if st.Transaksi, err = repositories.ListTabunganBetween(tx, nasabah.ID, dari, akhir); err != nil {
+		return err
+	}
+
+	return a.print(st, func(w io.Writer) {
+		fmt.Fprintf(w, "%s - %s\t%s s/d %s\n\n", st.NoRekening, st.Nama, st.Dari, st.Sampai)
+		fmt.Fprintln(w, "WAKTU\tID\tJENIS\tCHANNEL\tDEBIT\tKREDIT\tSALDO\tKETERANGAN")
+		fmt.Fprintf(w, "\t\tSALDO AWAL\t\t\t\t%.2f\t\n", st.SaldoAwal)
+		saldo := st.SaldoAwal
+		for _, t := range st.Transaksi {
+			debit, kredit := "", fmt.Sprintf("%.2f", t.Nominal)
+			if models.IsKredit(t.JenisTransaksi) {
+				saldo += t.Nominal
+			} else {
+				saldo -= t.Nominal
+				debit, kredit = kredit, ""
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%.2f\t%s\n", t.CreatedAt.Local().Format(timeLayout), t.ID, t.JenisTransaksi,
+				t.Channel, debit, kredit, saldo, t.Keterangan)
+		}
+		fmt.Fprintf(w, "\t\tSALDO AKHIR\t\t\t\t%.2f\t\n", st.SaldoAkhir)
+	})
+}
@@ -0,0 +1,145 @@
+// Command bankctl adalah alat operasional untuk petugas: mencari nasabah, penyesuaian
+// saldo, pembekuan rekening, rekonsiliasi, EOD dan cetak rekening koran tanpa psql.
+// Konfigurasi dibaca sama seperti aplikasi, termasuk flag global -config dan -set.
+//
+// Perintah yang mengubah data wajib menyebutkan petugas dengan -as beserta password-nya
+// (BANKCTL_PASSWORD atau baris pertama stdin), dinilai oleh policy yang sama dengan API
+// (termasuk maker-checker), ditahan atau ditolak selama EOD seperti transaksi API, dicatat
+// di log audit, dan mendukung -dry-run untuk melihat hasilnya tanpa menyimpan apa pun.
+//
+//	go run ./cmd/bankctl lookup -rekening 1234567890
+//	go run ./cmd/bankctl -output json lookup -nik 3171234567890001
+//	go run ./cmd/bankctl adjust -as budi -rekening 1234567890 -arah kredit -nominal 50000 -alasan "Selisih setoran" -dry-run
+//	go run ./cmd/bankctl freeze -as budi -rekening 1234567890 -alasan "Permintaan nasabah"
+//	go run ./cmd/bankctl unfreeze -as budi -rekening 1234567890 -alasan "Verifikasi selesai"
+//	go run ./cmd/bankctl reconcile -dry-run
+//	go run ./cmd/bankctl eod -tanggal 2024-01-31 -dry-run
+//	go run ./cmd/bankctl statement -rekening 1234567890 -dari 2024-01-01 -sampai 2024-01-31
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"golang-echo-postgresql/approval"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/backoffice"
+	"golang-echo-postgresql/config"
+	"golang-echo-postgresql/db"
+	"golang-echo-postgresql/eod"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/reconcile"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Mode keluaran
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+type command struct {
+	usage string
+	run   func(a *app, args []string) error
+}
+
+var commands = map[string]command{
+	"lookup":    {"cari nasabah dengan -nik, -hp atau -rekening", runLookup},
+	"adjust":    {"penyesuaian saldo dengan alasan (maker-checker)", runAdjust},
+	"freeze":    {"bekukan rekening", runFreeze},
+	"unfreeze":  {"buka blokir rekening (maker-checker)", runUnfreeze},
+	"reconcile": {"jalankan rekonsiliasi saldo", runReconcile},
+	"eod":       {"jalankan EOD untuk satu tanggal atau semua yang tertunda", runEOD},
+	"statement": {"ekspor rekening koran satu rekening", runStatement},
+}
+
+// app berisi dependensi yang dipakai bersama oleh semua perintah
+type app struct {
+	cfg       *config.Config
+	db        *sql.DB
+	output    string
+	in        io.Reader
+	out       io.Writer
+	policy    *policy.Policy
+	approvals *approval.Queue
+	recorder  *audit.Recorder
+	eod       *eod.Service
+}
+
+func main() {
+	output := flag.String("output", OutputTable, "mode keluaran: table atau json")
+	src := config.RegisterFlags(flag.CommandLine)
+	flag.Usage = usage
+	flag.Parse()
+
+	if *output != OutputTable && *output != OutputJSON {
+		logrus.Fatalf("Invalid -output %q, expected table or json", *output)
+	}
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoad(*src)
+	dbConn := db.InitDB(cfg)
+	defer dbConn.Close()
+
+	approvals := approval.NewQueue(dbConn, cfg.ApprovalTTL)
+	backoffice.Register(approvals)
+	reconcile.Register(approvals)
+
+	a := &app{
+		cfg:       cfg,
+		db:        dbConn,
+		output:    *output,
+		in:        os.Stdin,
+		out:       os.Stdout,
+		policy:    policy.New(cfg.LargeWithdrawalThreshold),
+		approvals: approvals,
+		recorder:  audit.NewRecorder(dbConn),
+		eod:       eod.NewService(dbConn, cfg.EODBatchSize, cfg.EODRunAt, cfg.EODTransactionMode, cfg.EODQueueTimeout),
+	}
+
+	if err := cmd.run(a, flag.Args()[1:]); err != nil {
+		logrus.Errorf("%s failed: %v", flag.Arg(0), err)
+		dbConn.Close()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: bankctl [-output table|json] [-config file] [-set KEY=VALUE] <command> [flags]\n\nCommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'bankctl <command> -h' for command flags.\n")
+}
+
+// print menulis v sebagai JSON, atau sebagai tabel lewat fungsi table
+func (a *app) print(v interface{}, table func(w io.Writer)) error {
+	if a.output == OutputJSON {
+		enc := json.NewEncoder(a.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/repositories"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// mutation adalah hasil perintah yang mengubah data
+type mutation struct {
+	DryRun           bool                     `json:"dry_run"`
+	RequiresApproval bool                     `json:"requires_approval"`
+	Before           map[string]interface{}   `json:"before,omitempty"`
+	After            map[string]interface{}   `json:"after,omitempty"`
+	Operation        *models.PendingOperation `json:"operation,omitempty"`
+}
+
+// passwordEnv berisi password petugas -as; tanpa variabel ini password dibaca dari
+// baris pertama stdin
+const passwordEnv = "BANKCTL_PASSWORD"
+
+// operator memuat petugas yang menjalankan perintah sebagai principal setelah password-nya
+// diperiksa seperti login petugas di API
+func (a *app) operator(username string) (*auth.Principal, error) {
+	if username == "" {
+		return nil, errors.New("-as is required for commands that change data")
+	}
+	password, err := a.password(username)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := repositories.GetStaffByUsername(a.db, username)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	var passwordHash string
+	if staff != nil && staff.Aktif {
+		passwordHash = staff.PasswordHash
+	}
+	if !auth.CheckSecret(passwordHash, password) {
+		return nil, fmt.Errorf("invalid credentials for staff %q", username)
+	}
+	return &auth.Principal{Type: auth.SubjectStaff, ID: staff.ID, Role: staff.Role}, nil
+}
+
+// password membaca password petugas dari BANKCTL_PASSWORD atau dari stdin
+func (a *app) password(username string) (string, error) {
+	if password, ok := os.LookupEnv(passwordEnv); ok {
+		return password, nil
+	}
+	fmt.Fprintf(os.Stderr, "Password for %s: ", username)
+	line, err := bufio.NewReader(a.in).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("read password from stdin or %s: %v", passwordEnv, err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// waitEOD menahan perintah selama EOD berjalan seperti EODGuard pada route transaksi
+func (a *app) waitEOD() error {
+	ok, err := a.eod.Wait(context.Background())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("EOD is in progress, try again after it finishes")
+	}
+	return nil
+}
+
+// mutate menjalankan aksi back-office seperti BackofficeHandler: policy dinilai lebih
+// dulu, aksi yang butuh persetujuan dimasukkan ke antrean maker-checker, dan aksi
+// langsung dieksekusi dalam satu transaksi. Aksi selalu dicoba di dalam transaksi
+// terlebih dulu agar kesalahan terlihat sebelum diantrekan; dengan dryRun transaksi
+// tersebut selalu di-rollback. Selama EOD perintah ditahan atau ditolak kecuali dryRun.
+func (a *app) mutate(name string, p *auth.Principal, action policy.Action, noRekening string, nominal float64, alasan string, payload interface{}, dryRun bool) error {
+	outcome := a.policy.Evaluate(p, action, nominal)
+	if outcome == policy.Deny {
+		return fmt.Errorf("role %s is not allowed to run %s", p.Role, action)
+	}
+	if !dryRun {
+		if err := a.waitEOD(); err != nil {
+			return err
+		}
+	}
+
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result := &mutation{DryRun: dryRun, RequiresApproval: outcome == policy.RequireApproval}
+	result.Before = snapshot(tx, noRekening)
+	if _, err := a.approvals.Execute(tx, action, payload); err != nil {
+		return err
+	}
+	result.After = snapshot(tx, noRekening)
+
+	switch {
+	case dryRun:
+		tx.Rollback()
+	case outcome == policy.RequireApproval:
+		tx.Rollback()
+		result.After = nil
+		result.Operation, err = a.approvals.Submit(p, action, noRekening, nominal, alasan, payload)
+		if err != nil {
+			return err
+		}
+		a.audit(name, p, noRekening, http.StatusAccepted, nil, map[string]interface{}{"operation_id": result.Operation.ID, "status": result.Operation.Status})
+	default:
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		a.audit(name, p, noRekening, http.StatusOK, result.Before, result.After)
+	}
+
+	return a.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "ACTION\t%s\n", action)
+		fmt.Fprintf(w, "REKENING\t%s\n", noRekening)
+		fmt.Fprintf(w, "DRY RUN\t%t\n", dryRun)
+		fmt.Fprintf(w, "REQUIRES APPROVAL\t%t\n", result.RequiresApproval)
+		fmt.Fprintf(w, "BEFORE\t%s\n", encode(result.Before))
+		if result.After != nil {
+			fmt.Fprintf(w, "AFTER\t%s\n", encode(result.After))
+		}
+		if result.Operation != nil {
+			fmt.Fprintf(w, "OPERATION\t%d (%s, expires %s)\n", result.Operation.ID, result.Operation.Status,
+				result.Operation.ExpiresAt.Format("2006-01-02 15:04"))
+		}
+	})
+}
+
+// audit mencatat perintah ke log audit berantai hash, sama seperti request API
+func (a *app) audit(name string, p *auth.Principal, noRekening string, status int, before, after map[string]interface{}) {
+	hostname, _ := os.Hostname()
+	event := &models.AuditEvent{
+		RequestID:  "bankctl-" + hostname + "-" + strconv.Itoa(os.Getpid()),
+		ActorType:  p.Type,
+		ActorID:    strconv.Itoa(p.ID),
+		Action:     "CLI bankctl " + name,
+		NoRekening: noRekening,
+		Before:     encode(before),
+		After:      encode(after),
+		StatusCode: status,
+	}
+	if err := a.recorder.Append(event); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error":  err,
+			"action": event.Action,
+		}).Error("Failed to write audit event")
+	}
+}
+
+func snapshot(executor repositories.Executor, noRekening string) map[string]interface{} {
+	nasabah, err := repositories.GetNasabahByNoRekening(executor, noRekening)
+	if err != nil {
+		return nil
+	}
+	return map[string]interface{}{"saldo": nasabah.Saldo, "status": nasabah.Status}
+}
+
+func encode(v map[string]interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
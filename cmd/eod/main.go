@@ -0,0 +1,85 @@
+// Command eod menjalankan proses tutup hari. Tanpa flag, semua tanggal bisnis yang
+// belum ditutup sampai kemarin ditutup berurutan; EOD yang pernah gagal dilanjutkan
+// dari checkpoint-nya. Keluar dengan status 1 jika EOD gagal.
+//
+//	go run ./cmd/eod
+//	go run ./cmd/eod -tanggal 2024-01-31
+//	go run ./cmd/eod -status
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"golang-echo-postgresql/config"
+	"golang-echo-postgresql/db"
+	"golang-echo-postgresql/eod"
+	"golang-echo-postgresql/gl"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	tanggalFlag := flag.String("tanggal", "", "tanggal bisnis yang ditutup (YYYY-MM-DD); kosong berarti semua yang tertunda")
+	statusOnly := flag.Bool("status", false, "hanya tampilkan status EOD")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+	dbConn := db.InitDB(cfg)
+	defer dbConn.Close()
+
+	service := eod.NewService(dbConn, cfg.EODBatchSize, cfg.EODRunAt, cfg.EODTransactionMode, cfg.EODQueueTimeout)
+	service.Register(eod.DefaultSteps(eod.Settings{
+		SukuBunga:      cfg.EODInterestRate,
+		BiayaAdmin:     cfg.EODMonthlyFee,
+		DormansiPeriod: cfg.EODDormancyDays,
+	})...)
+
+	// Step yang sama dengan aplikasi, agar EOD dari command line membentuk jurnal GL juga
+	ledger, err := gl.NewService(dbConn, cfg.GLMappingFile)
+	if err != nil {
+		logrus.Fatalf("Failed to load GL mapping: %v", err)
+	}
+	if ledger.Enabled() {
+		service.Register(ledger.Step())
+	}
+
+	// Ctrl+C menghentikan EOD di antara batch; jalankan ulang untuk melanjutkan
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	failed := false
+	if !*statusOnly {
+		var err error
+		if *tanggalFlag == "" {
+			err = service.CloseDue(ctx, "cli")
+		} else {
+			var tanggal time.Time
+			tanggal, err = time.ParseInLocation(eod.DateLayout, *tanggalFlag, time.Local)
+			if err != nil {
+				logrus.Fatalf("Invalid -tanggal: %v", err)
+			}
+			_, err = service.Close(ctx, tanggal, "cli")
+		}
+		if err != nil {
+			logrus.Errorf("EOD failed: %v", err)
+			failed = true
+		}
+	}
+
+	status, err := service.Status()
+	if err != nil {
+		logrus.Fatalf("Failed to read EOD status: %v", err)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(status)
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,81 @@
+// Command glexport mengekspor voucher jurnal GL satu tanggal bisnis yang sudah ditutup
+// (bawaan kemarin) sebagai CSV atau JSON untuk diimpor ke sistem akuntansi, atau
+// menampilkan neraca saldonya.
+//
+//	go run ./cmd/glexport -tanggal 2024-01-31 -format csv -out jurnal-2024-01-31.csv
+//	go run ./cmd/glexport -tanggal 2024-01-31 -trial-balance
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"golang-echo-postgresql/config"
+	"golang-echo-postgresql/db"
+	"golang-echo-postgresql/eod"
+	"golang-echo-postgresql/gl"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	yesterday := time.Now().AddDate(0, 0, -1).Format(eod.DateLayout)
+	tanggalFlag := flag.String("tanggal", yesterday, "tanggal bisnis yang diekspor (YYYY-MM-DD)")
+	format := flag.String("format", gl.FormatCSV, "format ekspor: csv atau json")
+	out := flag.String("out", "", "file tujuan; kosong berarti stdout")
+	trialBalance := flag.Bool("trial-balance", false, "tampilkan neraca saldo, bukan voucher")
+	flag.Parse()
+
+	tanggal, err := time.ParseInLocation(eod.DateLayout, *tanggalFlag, time.Local)
+	if err != nil {
+		logrus.Fatalf("Invalid -tanggal: %v", err)
+	}
+	if *format != gl.FormatCSV && *format != gl.FormatJSON {
+		logrus.Fatalf("Invalid -format %q, expected csv or json", *format)
+	}
+
+	cfg := config.LoadConfig()
+	dbConn := db.InitDB(cfg)
+	defer dbConn.Close()
+
+	ledger, err := gl.NewService(dbConn, cfg.GLMappingFile)
+	if err != nil {
+		logrus.Fatalf("Failed to load GL mapping: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			logrus.Fatalf("Failed to create %s: %v", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *trialBalance {
+		tb, err := ledger.TrialBalance(tanggal)
+		if err != nil {
+			logrus.Fatalf("Failed to build trial balance: %v", err)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(tb)
+		return
+	}
+
+	vouchers, err := ledger.Vouchers(tanggal)
+	if err != nil {
+		logrus.Fatalf("Failed to load GL vouchers: %v", err)
+	}
+	if *format == gl.FormatCSV {
+		err = gl.WriteCSV(w, vouchers)
+	} else {
+		err = gl.WriteJSON(w, vouchers)
+	}
+	if err != nil {
+		logrus.Fatalf("Failed to write GL vouchers: %v", err)
+	}
+}
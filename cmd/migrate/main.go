@@ -0,0 +1,82 @@
+// Command migrate menjalankan migrasi skema yang di-embed di binary dan mencatat versi
+// yang sudah diterapkan di tabel schema_versions.
+//
+//	go run ./cmd/migrate up
+//	go run ./cmd/migrate down -steps 1
+//	go run ./cmd/migrate to -version 11
+//	go run ./cmd/migrate status
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"golang-echo-postgresql/config"
+	"golang-echo-postgresql/db"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate up | down [-steps N] | to -version N | status")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	command := os.Args[1]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	steps := flags.Int("steps", 1, "jumlah migrasi terakhir yang dibatalkan (down)")
+	version := flags.Int("version", -1, "versi tujuan; 0 membatalkan semua migrasi (to)")
+	flags.Parse(os.Args[2:])
+
+	dbConn := db.InitDB(config.LoadConfig())
+	defer dbConn.Close()
+
+	migrator, err := db.NewMigrator(dbConn)
+	if err != nil {
+		logrus.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var changed []int
+	switch command {
+	case "up":
+		changed, err = migrator.Up(ctx)
+	case "down":
+		if *steps <= 0 {
+			logrus.Fatalf("Invalid -steps %d", *steps)
+		}
+		changed, err = migrator.Down(ctx, *steps)
+	case "to":
+		if *version < 0 {
+			logrus.Fatal("Missing -version")
+		}
+		changed, err = migrator.To(ctx, *version)
+	case "status":
+	default:
+		usage()
+	}
+	if err != nil {
+		logrus.Fatalf("Migration failed: %v", err)
+	}
+	if command != "status" {
+		logrus.Infof("%d migration(s) changed", len(changed))
+	}
+
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		logrus.Fatalf("Failed to read migration status: %v", err)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(status)
+}
@@ -0,0 +1,56 @@
+// Command watchlistimport mengimpor file watchlist (CSV atau XML) sebagai versi baru
+// yang langsung aktif. Format ditebak dari ekstensi file jika -format tidak diisi.
+//
+//	go run ./cmd/watchlistimport -list dttot -file dttot-2024-01.csv
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"golang-echo-postgresql/config"
+	"golang-echo-postgresql/db"
+	"golang-echo-postgresql/screening"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	list := flag.String("list", "", "kode watchlist, misalnya dttot atau internal")
+	file := flag.String("file", "", "path file watchlist")
+	format := flag.String("format", "", "csv atau xml (bawaan dari ekstensi file)")
+	flag.Parse()
+
+	if *list == "" || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		logrus.Fatalf("Failed to read watchlist file: %v", err)
+	}
+
+	cfg := config.LoadConfig()
+	dbConn := db.InitDB(cfg)
+	defer dbConn.Close()
+
+	service, err := screening.NewService(dbConn, cfg.ScreeningThreshold, cfg.ScreeningRefreshInterval)
+	if err != nil {
+		logrus.Fatalf("Failed to load watchlists: %v", err)
+	}
+
+	version, err := service.Import(nil, *list, *format, data)
+	if err != nil {
+		logrus.Fatalf("Watchlist import failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(version)
+}
@@ -0,0 +1,40 @@
+// Command webhookreceiver menjalankan penerima webhook lokal untuk pengujian.
+//
+//	go run ./cmd/webhookreceiver -addr :9090 -secret whsec_... -fail 2
+//
+// Daftarkan http://localhost:9090/webhook sebagai URL subscription (dengan
+// WEBHOOK_ALLOW_INSECURE=true). Daftar webhook yang diterima tersedia di GET /received.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"golang-echo-postgresql/webhook"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "alamat listen")
+	secret := flag.String("secret", "", "secret subscription webhook")
+	fail := flag.Int("fail", 0, "jumlah request pertama yang dijawab 503 untuk menguji retry")
+	flag.Parse()
+
+	if *secret == "" {
+		logrus.Fatal("-secret is required")
+	}
+
+	receiver := webhook.NewReceiver(*secret, *fail)
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", receiver)
+	mux.HandleFunc("/received", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(receiver.Received())
+	})
+
+	logrus.Infof("Webhook receiver listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		logrus.Fatalf("Webhook receiver stopped: %v", err)
+	}
+}
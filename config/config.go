@@ -2,34 +2,312 @@ package config
 
 import (
 	"log"
-	"os"
+	"net"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
 )
 
-// Config struct holds the database configuration
+// Config struct holds the database and application configuration
 type Config struct {
 	Host     string
 	Port     string
 	User     string
 	Password string
 	DBName   string
+
+	// Database connection settings
+	DBSSLMode         string
+	DBConnectTimeout  time.Duration
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+
+	// Database startup retry and health probing
+	DBStartupTimeout  time.Duration
+	DBRetryBackoff    time.Duration
+	DBHealthInterval  time.Duration
+	DBHealthTimeout   time.Duration
+	DBBreakerFailures int
+
+	// Menjalankan migrasi skema yang di-embed saat aplikasi start
+	DBMigrateOnStart bool
+
+	// Store backend for nasabah, rekening and transactions. "memory" keeps them in
+	// process for local development and tests; data is lost on restart.
+	StoreBackend string // "postgres" atau "memory"
+
+	// HTTP server settings
+	APIHost            string
+	APIPort            int
+	ServerReadTimeout  time.Duration
+	ServerWriteTimeout time.Duration
+	ShutdownTimeout    time.Duration
+
+	// Graceful draining: after SIGTERM readiness fails for ShutdownDrainDelay before the
+	// listeners close, then background workers get WorkerShutdownTimeout to finish
+	ShutdownDrainDelay    time.Duration
+	WorkerShutdownTimeout time.Duration
+
+	// gRPC server settings; GRPCPort 0 disables the gRPC server
+	GRPCPort int
+
+	// Prometheus metrics listener; MetricsPort 0 disables /metrics
+	MetricsPort int
+
+	// Tracing settings; an empty TracingEndpoint keeps the no-op tracer provider
+	TracingEndpoint    string // OTLP/gRPC collector URL
+	TracingServiceName string
+	TracingSampleRatio float64
+
+	// Logging settings
+	LogLevel  logrus.Level
+	LogFormat string // "text" atau "json"
+
+	// Auth settings
+	AuthIssuer             string
+	AccessTokenTTL         time.Duration
+	RefreshTokenTTL        time.Duration
+	KeyRotationInterval    time.Duration
+	BootstrapAdminUser     string
+	BootstrapAdminPassword string
+
+	// Back-office settings
+	LargeWithdrawalThreshold float64
+	ApprovalTTL              time.Duration
+
+	// Partner signing settings
+	PartnerSecretKey     string
+	PartnerClockSkew     time.Duration
+	PartnerSecretOverlap time.Duration
+
+	// Outbox settings
+	OutboxPublisher    string // "inprocess" atau "filestream"
+	OutboxStreamDir    string
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+
+	// Webhook settings
+	WebhookMaxAttempts   int
+	WebhookTimeout       time.Duration
+	WebhookPollInterval  time.Duration
+	WebhookSecretOverlap time.Duration
+	WebhookAllowInsecure bool
+
+	// Fraud rules settings
+	FraudRulesFile           string
+	FraudRulesReloadInterval time.Duration
+
+	// AML settings
+	AMLLTKTThreshold     float64
+	AMLStructuringFloor  float64
+	AMLPassThroughMin    float64
+	AMLExportDir         string
+	AMLReportingEntityID string
+	AMLRunAt             time.Duration
+
+	// Watchlist screening settings
+	ScreeningThreshold       float64
+	ScreeningRefreshInterval time.Duration
+
+	// End-of-day settings
+	EODRunAt           time.Duration
+	EODBatchSize       int
+	EODTransactionMode string
+	EODQueueTimeout    time.Duration
+	EODInterestRate    float64
+	EODMonthlyFee      float64
+	EODDormancyDays    int
+
+	// Reconciliation settings
+	ReconInterval  time.Duration
+	ReconBatchSize int
+
+	// General ledger settings
+	GLMappingFile string
+
+	// Rate limit settings; TrustedProxies is a comma-separated list of CIDRs whose
+	// X-Forwarded-For header is trusted for the client IP
+	RateLimitFile    string
+	RateLimitBackend string // "memory" atau "postgres"
+	TrustedProxies   string
+
+	// settings mencatat nilai mentah dan asal setiap kunci untuk dump /config
+	settings []Setting
 }
 
-// LoadConfig loads the configuration from the .env file
+// LoadConfig loads the configuration from defaults, the .env file (optional) and the
+// environment, and exits when it is invalid. Commands that accept -config/-set flags
+// use Load with the Source from RegisterFlags instead.
 func LoadConfig() *Config {
-	// Load .env file
-	err := godotenv.Load()
+	return MustLoad(Source{})
+}
+
+// MustLoad is Load that exits the process with all validation problems listed
+func MustLoad(src Source) *Config {
+	cfg, err := Load(src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return cfg
+}
+
+// Load builds the configuration from defaults, then the dotenv file, the environment
+// and finally flag overrides. Every invalid value is reported in one ValidationError.
+func Load(src Source) (*Config, error) {
+	l, err := newLoader(src)
 	if err != nil {
-		log.Fatal("Error loading .env file")
+		return nil, err
+	}
+
+	cfg := &Config{
+		Host:     l.str("DB_HOST", "localhost"),
+		Port:     l.str("DB_PORT", "5432"),
+		User:     l.str("DB_USER", ""),
+		Password: l.secret("DB_PASSWORD"),
+		DBName:   l.str("DB_NAME", ""),
+
+		DBSSLMode:         l.str("DB_SSLMODE", "require"),
+		DBConnectTimeout:  l.duration("DB_CONNECT_TIMEOUT", 5*time.Second),
+		DBMaxOpenConns:    l.int("DB_MAX_OPEN_CONNS", 25),
+		DBMaxIdleConns:    l.int("DB_MAX_IDLE_CONNS", 5),
+		DBConnMaxLifetime: l.duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		DBConnMaxIdleTime: l.duration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+
+		DBStartupTimeout:  l.duration("DB_STARTUP_TIMEOUT", time.Minute),
+		DBRetryBackoff:    l.duration("DB_RETRY_BACKOFF", 500*time.Millisecond),
+		DBHealthInterval:  l.duration("DB_HEALTH_INTERVAL", 5*time.Second),
+		DBHealthTimeout:   l.duration("DB_HEALTH_TIMEOUT", 2*time.Second),
+		DBBreakerFailures: l.int("DB_BREAKER_FAILURES", 2),
+
+		DBMigrateOnStart: l.bool("DB_MIGRATE_ON_START", false),
+		StoreBackend:     l.str("STORE_BACKEND", "postgres"),
+
+		APIHost:            l.str("API_HOST", ""),
+		APIPort:            l.int("API_PORT", 8080),
+		ServerReadTimeout:  l.duration("SERVER_READ_TIMEOUT", 15*time.Second),
+		ServerWriteTimeout: l.duration("SERVER_WRITE_TIMEOUT", 60*time.Second),
+		ShutdownTimeout:    l.duration("SERVER_SHUTDOWN_TIMEOUT", 10*time.Second),
+
+		ShutdownDrainDelay:    l.duration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+		WorkerShutdownTimeout: l.duration("WORKER_SHUTDOWN_TIMEOUT", 30*time.Second),
+
+		GRPCPort: l.int("GRPC_PORT", 9090),
+
+		MetricsPort: l.int("METRICS_PORT", 9091),
+
+		TracingEndpoint:    l.str("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		TracingServiceName: l.str("OTEL_SERVICE_NAME", "golang-echo-postgresql"),
+		TracingSampleRatio: l.float("TRACING_SAMPLE_RATIO", 1),
+
+		LogLevel:  l.level("LOG_LEVEL", logrus.DebugLevel),
+		LogFormat: l.str("LOG_FORMAT", "text"),
+
+		AuthIssuer:             l.str("AUTH_ISSUER", "golang-echo-postgresql"),
+		AccessTokenTTL:         l.duration("AUTH_ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:        l.duration("AUTH_REFRESH_TOKEN_TTL", 7*24*time.Hour),
+		KeyRotationInterval:    l.duration("AUTH_KEY_ROTATION_INTERVAL", 24*time.Hour),
+		BootstrapAdminUser:     l.str("AUTH_BOOTSTRAP_ADMIN_USER", ""),
+		BootstrapAdminPassword: l.secret("AUTH_BOOTSTRAP_ADMIN_PASSWORD"),
+
+		LargeWithdrawalThreshold: l.float("LARGE_WITHDRAWAL_THRESHOLD", 50000000),
+		ApprovalTTL:              l.duration("APPROVAL_TTL", 24*time.Hour),
+
+		PartnerSecretKey:     l.secret("PARTNER_SECRET_KEY"),
+		PartnerClockSkew:     l.duration("PARTNER_CLOCK_SKEW", 5*time.Minute),
+		PartnerSecretOverlap: l.duration("PARTNER_SECRET_OVERLAP", 24*time.Hour),
+
+		OutboxPublisher:    l.str("OUTBOX_PUBLISHER", "inprocess"),
+		OutboxStreamDir:    l.str("OUTBOX_STREAM_DIR", "./data/streams"),
+		OutboxPollInterval: l.duration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:    l.int("OUTBOX_BATCH_SIZE", 100),
+
+		WebhookMaxAttempts:   l.int("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookTimeout:       l.duration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookPollInterval:  l.duration("WEBHOOK_POLL_INTERVAL", 2*time.Second),
+		WebhookSecretOverlap: l.duration("WEBHOOK_SECRET_OVERLAP", 24*time.Hour),
+		WebhookAllowInsecure: l.bool("WEBHOOK_ALLOW_INSECURE", false),
+
+		FraudRulesFile:           l.str("FRAUD_RULES_FILE", "./fraud_rules.json"),
+		FraudRulesReloadInterval: l.duration("FRAUD_RULES_RELOAD_INTERVAL", 10*time.Second),
+
+		AMLLTKTThreshold:     l.float("AML_LTKT_THRESHOLD", 500000000),
+		AMLStructuringFloor:  l.float("AML_STRUCTURING_FLOOR", 0.8),
+		AMLPassThroughMin:    l.float("AML_PASS_THROUGH_MIN", 100000000),
+		AMLExportDir:         l.str("AML_EXPORT_DIR", "./data/aml"),
+		AMLReportingEntityID: l.str("AML_REPORTING_ENTITY_ID", ""),
+		AMLRunAt:             l.duration("AML_RUN_AT", time.Hour),
+
+		ScreeningThreshold:       l.float("SCREENING_THRESHOLD", 0.88),
+		ScreeningRefreshInterval: l.duration("SCREENING_REFRESH_INTERVAL", time.Minute),
+
+		EODRunAt:           l.duration("EOD_RUN_AT", 30*time.Minute),
+		EODBatchSize:       l.int("EOD_BATCH_SIZE", 500),
+		EODTransactionMode: l.str("EOD_TRANSACTION_MODE", "queue"),
+		EODQueueTimeout:    l.duration("EOD_QUEUE_TIMEOUT", 30*time.Second),
+		EODInterestRate:    l.float("EOD_INTEREST_RATE", 0.01),
+		EODMonthlyFee:      l.float("EOD_MONTHLY_FEE", 0),
+		EODDormancyDays:    l.int("EOD_DORMANCY_DAYS", 365),
+
+		ReconInterval:  l.duration("RECON_INTERVAL", time.Hour),
+		ReconBatchSize: l.int("RECON_BATCH_SIZE", 500),
+
+		GLMappingFile: l.str("GL_MAPPING_FILE", "./gl_mapping.json"),
+
+		RateLimitFile:    l.str("RATE_LIMIT_FILE", "./rate_limits.json"),
+		RateLimitBackend: l.str("RATE_LIMIT_BACKEND", "memory"),
+		TrustedProxies:   l.str("TRUSTED_PROXIES", ""),
+	}
+	cfg.settings = l.settings
+
+	l.unknownOverrides()
+	l.problems = append(l.problems, cfg.validate()...)
+	if len(l.problems) > 0 {
+		return nil, &ValidationError{Problems: l.problems}
+	}
+	return cfg, nil
+}
+
+// TrustedProxyRanges parses TrustedProxies; invalid entries are reported by validate
+func (c *Config) TrustedProxyRanges() []*net.IPNet {
+	var ranges []*net.IPNet
+	for _, entry := range strings.Split(c.TrustedProxies, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			ranges = append(ranges, ipNet)
+		}
 	}
+	return ranges
+}
+
+// Addr returns the host:port the HTTP server listens on
+func (c *Config) Addr() string {
+	return net.JoinHostPort(c.APIHost, strconv.Itoa(c.APIPort))
+}
+
+// GRPCAddr returns the host:port the gRPC server listens on, on the same host as the HTTP server
+func (c *Config) GRPCAddr() string {
+	return net.JoinHostPort(c.APIHost, strconv.Itoa(c.GRPCPort))
+}
+
+// MetricsAddr returns the host:port the Prometheus metrics listener listens on
+func (c *Config) MetricsAddr() string {
+	return net.JoinHostPort(c.APIHost, strconv.Itoa(c.MetricsPort))
+}
 
-	// Get environment variables and assign them to the Config struct
-	return &Config{
-		Host:     os.Getenv("DB_HOST"),
-		Port:     os.Getenv("DB_PORT"),
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		DBName:   os.Getenv("DB_NAME"),
+// Redacted returns every setting with its source, with secret values masked
+func (c *Config) Redacted() []Setting {
+	settings := make([]Setting, len(c.settings))
+	for i, s := range c.settings {
+		if s.Secret && s.Value != "" {
+			s.Value = "********"
+		}
+		settings[i] = s
 	}
+	return settings
 }
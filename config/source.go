@@ -0,0 +1,187 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+// Asal nilai konfigurasi, dari prioritas terendah ke tertinggi
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// defaultFile dibaca jika ada; file yang disebut lewat -config atau CONFIG_FILE wajib ada
+const defaultFile = ".env"
+
+// Source menentukan lapisan di atas nilai bawaan: file dotenv, environment, lalu override dari flag
+type Source struct {
+	File      string            // file dotenv; kosong berarti CONFIG_FILE atau .env
+	Overrides map[string]string // nilai dari flag -set KEY=VALUE
+}
+
+// RegisterFlags mendaftarkan flag -config dan -set pada fs. Source yang dikembalikan
+// terisi setelah fs.Parse dipanggil.
+func RegisterFlags(fs *flag.FlagSet) *Source {
+	src := &Source{Overrides: map[string]string{}}
+	fs.StringVar(&src.File, "config", "", "file konfigurasi dotenv (bawaan CONFIG_FILE atau .env)")
+	fs.Var(overrideFlag(src.Overrides), "set", "override konfigurasi KEY=VALUE, boleh diulang")
+	return src
+}
+
+type overrideFlag map[string]string
+
+func (o overrideFlag) String() string { return "" }
+
+func (o overrideFlag) Set(value string) error {
+	key, v, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	o[strings.TrimSpace(key)] = v
+	return nil
+}
+
+// Setting adalah satu nilai konfigurasi beserta asalnya
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// loader membaca setiap kunci dari semua lapisan dan mengumpulkan kesalahan parsing,
+// sehingga semua masalah dilaporkan sekaligus alih-alih berhenti di kunci pertama.
+type loader struct {
+	file      map[string]string
+	overrides map[string]string
+	settings  []Setting
+	problems  []string
+}
+
+func newLoader(src Source) (*loader, error) {
+	path, required := src.File, true
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path == "" {
+		path, required = defaultFile, false
+	}
+
+	file, err := godotenv.Read(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		file, err = map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca file konfigurasi %s: %w", path, err)
+	}
+	return &loader{file: file, overrides: src.Overrides}, nil
+}
+
+func (l *loader) problem(format string, args ...interface{}) {
+	l.problems = append(l.problems, fmt.Sprintf(format, args...))
+}
+
+// lookup mengembalikan nilai dengan prioritas flag, environment, file, lalu bawaan
+func (l *loader) lookup(key, fallback string, secret bool) string {
+	value, source := fallback, SourceDefault
+	if v := l.file[key]; v != "" {
+		value, source = v, SourceFile
+	}
+	if v := os.Getenv(key); v != "" {
+		value, source = v, SourceEnv
+	}
+	if v, ok := l.overrides[key]; ok {
+		value, source = v, SourceFlag
+	}
+	l.settings = append(l.settings, Setting{Key: key, Value: value, Source: source, Secret: secret})
+	return value
+}
+
+func (l *loader) str(key, fallback string) string {
+	return l.lookup(key, fallback, false)
+}
+
+func (l *loader) secret(key string) string {
+	return l.lookup(key, "", true)
+}
+
+func (l *loader) duration(key string, fallback time.Duration) time.Duration {
+	value := l.lookup(key, fallback.String(), false)
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.problem("%s: durasi tidak valid %q", key, value)
+		return fallback
+	}
+	return d
+}
+
+func (l *loader) float(key string, fallback float64) float64 {
+	value := l.lookup(key, strconv.FormatFloat(fallback, 'f', -1, 64), false)
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		l.problem("%s: angka tidak valid %q", key, value)
+		return fallback
+	}
+	return f
+}
+
+func (l *loader) int(key string, fallback int) int {
+	value := l.lookup(key, strconv.Itoa(fallback), false)
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		l.problem("%s: bilangan bulat tidak valid %q", key, value)
+		return fallback
+	}
+	return n
+}
+
+func (l *loader) bool(key string, fallback bool) bool {
+	value := l.lookup(key, strconv.FormatBool(fallback), false)
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.problem("%s: boolean tidak valid %q", key, value)
+		return fallback
+	}
+	return b
+}
+
+func (l *loader) level(key string, fallback logrus.Level) logrus.Level {
+	value := l.lookup(key, fallback.String(), false)
+	level, err := logrus.ParseLevel(value)
+	if err != nil {
+		l.problem("%s: level log tidak valid %q", key, value)
+		return fallback
+	}
+	return level
+}
+
+// unknownOverrides melaporkan -set untuk kunci yang tidak dikenal, biasanya salah ketik
+func (l *loader) unknownOverrides() {
+	known := make(map[string]bool, len(l.settings))
+	for _, s := range l.settings {
+		known[s.Key] = true
+	}
+	var unknown []string
+	for key := range l.overrides {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		l.problem("%s: kunci konfigurasi tidak dikenal", key)
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError berisi semua masalah konfigurasi yang ditemukan dalam satu kali load
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "konfigurasi tidak valid:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// validator mengumpulkan pelanggaran aturan lintas kunci setelah semua nilai berhasil di-parse
+type validator struct {
+	problems []string
+}
+
+func (v *validator) check(ok bool, problem string) {
+	if !ok {
+		v.problems = append(v.problems, problem)
+	}
+}
+
+func (v *validator) positive(key string, d time.Duration) {
+	v.check(d > 0, key+": harus lebih dari 0")
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.problems = append(v.problems, key+": harus salah satu dari "+strings.Join(allowed, ", ")+", bukan "+strconv.Quote(value))
+}
+
+func (c *Config) validate() []string {
+	v := &validator{}
+
+	v.check(c.User != "", "DB_USER: wajib diisi")
+	v.check(c.DBName != "", "DB_NAME: wajib diisi")
+	port, err := strconv.Atoi(c.Port)
+	v.check(err == nil && port > 0 && port <= 65535, "DB_PORT: harus port 1-65535")
+	v.oneOf("DB_SSLMODE", c.DBSSLMode, sslModes...)
+	v.check(c.DBConnectTimeout == 0 || c.DBConnectTimeout >= time.Second, "DB_CONNECT_TIMEOUT: minimal 1s, atau 0 untuk tanpa batas")
+	v.check(c.DBMaxOpenConns >= 0, "DB_MAX_OPEN_CONNS: tidak boleh negatif")
+	v.check(c.DBMaxIdleConns >= 0, "DB_MAX_IDLE_CONNS: tidak boleh negatif")
+	v.check(c.DBMaxOpenConns == 0 || c.DBMaxIdleConns <= c.DBMaxOpenConns, "DB_MAX_IDLE_CONNS: tidak boleh lebih dari DB_MAX_OPEN_CONNS")
+	v.check(c.DBConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME: tidak boleh negatif")
+	v.check(c.DBConnMaxIdleTime >= 0, "DB_CONN_MAX_IDLE_TIME: tidak boleh negatif")
+	v.check(c.DBStartupTimeout >= 0, "DB_STARTUP_TIMEOUT: tidak boleh negatif")
+	v.positive("DB_RETRY_BACKOFF", c.DBRetryBackoff)
+	v.positive("DB_HEALTH_INTERVAL", c.DBHealthInterval)
+	v.positive("DB_HEALTH_TIMEOUT", c.DBHealthTimeout)
+	v.check(c.DBBreakerFailures > 0, "DB_BREAKER_FAILURES: harus lebih dari 0")
+	v.oneOf("STORE_BACKEND", c.StoreBackend, "postgres", "memory")
+
+	v.check(c.APIPort > 0 && c.APIPort <= 65535, "API_PORT: harus port 1-65535")
+	v.positive("SERVER_READ_TIMEOUT", c.ServerReadTimeout)
+	v.positive("SERVER_WRITE_TIMEOUT", c.ServerWriteTimeout)
+	v.positive("SERVER_SHUTDOWN_TIMEOUT", c.ShutdownTimeout)
+	v.check(c.ShutdownDrainDelay >= 0, "SHUTDOWN_DRAIN_DELAY: tidak boleh negatif")
+	v.positive("WORKER_SHUTDOWN_TIMEOUT", c.WorkerShutdownTimeout)
+	v.check(c.GRPCPort >= 0 && c.GRPCPort <= 65535, "GRPC_PORT: harus port 1-65535, atau 0 untuk menonaktifkan gRPC")
+	v.check(c.GRPCPort != c.APIPort, "GRPC_PORT: tidak boleh sama dengan API_PORT")
+	v.check(c.MetricsPort >= 0 && c.MetricsPort <= 65535, "METRICS_PORT: harus port 1-65535, atau 0 untuk menonaktifkan /metrics")
+	v.check(c.MetricsPort == 0 || (c.MetricsPort != c.APIPort && c.MetricsPort != c.GRPCPort), "METRICS_PORT: tidak boleh sama dengan API_PORT atau GRPC_PORT")
+	if c.TracingEndpoint != "" {
+		u, err := url.Parse(c.TracingEndpoint)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"OTEL_EXPORTER_OTLP_ENDPOINT: harus URL http:// atau https:// collector OTLP/gRPC")
+	}
+	v.check(c.TracingServiceName != "", "OTEL_SERVICE_NAME: wajib diisi")
+	v.check(c.TracingSampleRatio >= 0 && c.TracingSampleRatio <= 1, "TRACING_SAMPLE_RATIO: harus antara 0 dan 1")
+	v.oneOf("LOG_FORMAT", c.LogFormat, "text", "json")
+
+	v.positive("AUTH_ACCESS_TOKEN_TTL", c.AccessTokenTTL)
+	v.check(c.RefreshTokenTTL > c.AccessTokenTTL, "AUTH_REFRESH_TOKEN_TTL: harus lebih lama dari AUTH_ACCESS_TOKEN_TTL")
+	v.positive("AUTH_KEY_ROTATION_INTERVAL", c.KeyRotationInterval)
+	v.check((c.BootstrapAdminUser == "") == (c.BootstrapAdminPassword == ""),
+		"AUTH_BOOTSTRAP_ADMIN_USER dan AUTH_BOOTSTRAP_ADMIN_PASSWORD: harus diisi keduanya atau tidak sama sekali")
+
+	v.check(c.LargeWithdrawalThreshold > 0, "LARGE_WITHDRAWAL_THRESHOLD: harus lebih dari 0")
+	v.positive("APPROVAL_TTL", c.ApprovalTTL)
+
+	if c.PartnerSecretKey != "" {
+		key, err := hex.DecodeString(c.PartnerSecretKey)
+		v.check(err == nil && len(key) == 32, "PARTNER_SECRET_KEY: harus 64 karakter hex (32 byte)")
+	}
+	v.positive("PARTNER_CLOCK_SKEW", c.PartnerClockSkew)
+	v.check(c.PartnerSecretOverlap >= 0, "PARTNER_SECRET_OVERLAP: tidak boleh negatif")
+
+	v.oneOf("OUTBOX_PUBLISHER", c.OutboxPublisher, "inprocess", "filestream")
+	v.check(c.OutboxPublisher != "filestream" || c.OutboxStreamDir != "", "OUTBOX_STREAM_DIR: wajib diisi untuk publisher filestream")
+	v.positive("OUTBOX_POLL_INTERVAL", c.OutboxPollInterval)
+	v.check(c.OutboxBatchSize > 0, "OUTBOX_BATCH_SIZE: harus lebih dari 0")
+
+	v.check(c.WebhookMaxAttempts > 0, "WEBHOOK_MAX_ATTEMPTS: harus lebih dari 0")
+	v.positive("WEBHOOK_TIMEOUT", c.WebhookTimeout)
+	v.positive("WEBHOOK_POLL_INTERVAL", c.WebhookPollInterval)
+	v.check(c.WebhookSecretOverlap >= 0, "WEBHOOK_SECRET_OVERLAP: tidak boleh negatif")
+
+	v.positive("FRAUD_RULES_RELOAD_INTERVAL", c.FraudRulesReloadInterval)
+
+	v.check(c.AMLLTKTThreshold > 0, "AML_LTKT_THRESHOLD: harus lebih dari 0")
+	v.check(c.AMLStructuringFloor > 0 && c.AMLStructuringFloor < 1, "AML_STRUCTURING_FLOOR: harus antara 0 dan 1")
+	v.check(c.AMLPassThroughMin > 0, "AML_PASS_THROUGH_MIN: harus lebih dari 0")
+	v.check(c.AMLRunAt >= 0 && c.AMLRunAt < 24*time.Hour, "AML_RUN_AT: harus antara 0 dan 24h")
+
+	v.check(c.ScreeningThreshold > 0 && c.ScreeningThreshold <= 1, "SCREENING_THRESHOLD: harus antara 0 dan 1")
+	v.positive("SCREENING_REFRESH_INTERVAL", c.ScreeningRefreshInterval)
+
+	v.check(c.EODRunAt >= 0 && c.EODRunAt < 24*time.Hour, "EOD_RUN_AT: harus antara 0 dan 24h")
+	v.check(c.EODBatchSize > 0, "EOD_BATCH_SIZE: harus lebih dari 0")
+	v.oneOf("EOD_TRANSACTION_MODE", c.EODTransactionMode, "queue", "reject")
+	v.positive("EOD_QUEUE_TIMEOUT", c.EODQueueTimeout)
+	v.check(c.EODInterestRate >= 0, "EOD_INTEREST_RATE: tidak boleh negatif")
+	v.check(c.EODMonthlyFee >= 0, "EOD_MONTHLY_FEE: tidak boleh negatif")
+	v.check(c.EODDormancyDays > 0, "EOD_DORMANCY_DAYS: harus lebih dari 0")
+
+	v.positive("RECON_INTERVAL", c.ReconInterval)
+	v.check(c.ReconBatchSize > 0, "RECON_BATCH_SIZE: harus lebih dari 0")
+
+	v.oneOf("RATE_LIMIT_BACKEND", c.RateLimitBackend, "memory", "postgres")
+	for _, entry := range strings.Split(c.TrustedProxies, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			_, _, err := net.ParseCIDR(entry)
+			v.check(err == nil, fmt.Sprintf("TRUSTED_PROXIES: %q bukan CIDR yang valid", entry))
+		}
+	}
+
+	return v.problems
+}
@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"golang-echo-postgresql/apierror"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// Status circuit breaker database
+const (
+	BreakerClosed = "closed" // database sehat, request diteruskan
+	BreakerOpen   = "open"   // database tidak terjangkau, request langsung ditolak dengan 503
+)
+
+// Breaker memeriksa database secara berkala. Setelah Threshold kali probe gagal berturut-turut
+// breaker terbuka dan Guard menolak request dengan 503 tanpa menunggu timeout koneksi;
+// probe pertama yang berhasil menutupnya kembali.
+type Breaker struct {
+	DB        *sql.DB
+	Interval  time.Duration
+	Timeout   time.Duration
+	Threshold int
+
+	mu        sync.RWMutex
+	state     string
+	failures  int
+	lastErr   error
+	lastProbe time.Time
+	openedAt  time.Time
+}
+
+// PoolStats berisi status breaker dan statistik pool koneksi untuk monitoring
+type PoolStats struct {
+	Breaker             string     `json:"breaker"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastProbeAt         *time.Time `json:"last_probe_at,omitempty"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+func NewBreaker(db *sql.DB, interval, timeout time.Duration, threshold int) *Breaker {
+	return &Breaker{
+		DB:        db,
+		Interval:  interval,
+		Timeout:   timeout,
+		Threshold: threshold,
+		state:     BreakerClosed,
+	}
+}
+
+// Run menjalankan probe setiap Interval sampai ctx dibatalkan
+func (b *Breaker) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.Probe(ctx)
+		}
+	}
+}
+
+// Probe melakukan ping ke database dan memperbarui status breaker
+func (b *Breaker) Probe(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, b.Timeout)
+	defer cancel()
+	err := b.DB.PingContext(pingCtx)
+	if ctx.Err() != nil {
+		// Shutdown, bukan gangguan database
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastProbe = time.Now()
+	b.lastErr = err
+	if err == nil {
+		if b.state == BreakerOpen {
+			log.WithFields(log.Fields{
+				"down_for": time.Since(b.openedAt).Round(time.Second).String(),
+			}).Info("Database reachable again, circuit breaker closed")
+		}
+		b.state = BreakerClosed
+		b.failures = 0
+		return nil
+	}
+
+	b.failures++
+	if b.state == BreakerClosed && b.failures >= b.Threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.lastProbe
+		log.WithFields(log.Fields{
+			"failures": b.failures,
+			"error":    err,
+		}).Error("Database unreachable, circuit breaker opened")
+	} else {
+		log.WithFields(log.Fields{
+			"failures": b.failures,
+			"error":    err,
+		}).Warn("Database health probe failed")
+	}
+	return err
+}
+
+// Allow melaporkan apakah request boleh diteruskan ke database
+func (b *Breaker) Allow() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.state == BreakerClosed
+}
+
+// Guard menolak request dengan 503 selama breaker terbuka. Route di skip tetap dilayani,
+// misalnya probe liveness yang harus menjawab walaupun database tidak tersedia.
+func (b *Breaker) Guard(skip ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if b.Allow() || slices.Contains(skip, c.Path()) {
+				return next(c)
+			}
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(b.Interval.Seconds())+1))
+			return apierror.New(apierror.DatabaseUnavailable)
+		}
+	}
+}
+
+// Stats mengembalikan status breaker beserta statistik pool koneksi
+func (b *Breaker) Stats() PoolStats {
+	b.mu.RLock()
+	stats := PoolStats{
+		Breaker:             b.state,
+		ConsecutiveFailures: b.failures,
+	}
+	if b.lastErr != nil {
+		stats.LastError = b.lastErr.Error()
+	}
+	if !b.lastProbe.IsZero() {
+		at := b.lastProbe
+		stats.LastProbeAt = &at
+	}
+	if b.state == BreakerOpen {
+		at := b.openedAt
+		stats.OpenedAt = &at
+	}
+	b.mu.RUnlock()
+
+	pool := b.DB.Stats()
+	stats.MaxOpenConnections = pool.MaxOpenConnections
+	stats.OpenConnections = pool.OpenConnections
+	stats.InUse = pool.InUse
+	stats.Idle = pool.Idle
+	stats.WaitCount = pool.WaitCount
+	stats.WaitDurationMs = pool.WaitDuration.Milliseconds()
+	stats.MaxIdleClosed = pool.MaxIdleClosed
+	stats.MaxIdleTimeClosed = pool.MaxIdleTimeClosed
+	stats.MaxLifetimeClosed = pool.MaxLifetimeClosed
+	return stats
+}
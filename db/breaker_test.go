@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/labstack/echo/v4"
+)
+
+var errUnreachable = errors.New("dial tcp: connection refused")
+
+func newTestBreaker(t *testing.T, threshold int) (*Breaker, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewBreaker(conn, 5*time.Second, time.Second, threshold), mock
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	b, mock := newTestBreaker(t, 3)
+	ctx := context.Background()
+
+	// Kegagalan di bawah threshold belum membuka breaker
+	for i := 1; i <= 2; i++ {
+		mock.ExpectPing().WillReturnError(errUnreachable)
+		b.Probe(ctx)
+		if !b.Allow() || b.Stats().ConsecutiveFailures != i {
+			t.Fatalf("after %d failures: state %s, failures %d", i, b.Stats().Breaker, b.Stats().ConsecutiveFailures)
+		}
+	}
+
+	// Probe berhasil mereset hitungan kegagalan
+	mock.ExpectPing()
+	b.Probe(ctx)
+	if stats := b.Stats(); stats.Breaker != BreakerClosed || stats.ConsecutiveFailures != 0 || stats.LastError != "" {
+		t.Fatalf("after success: %+v, want closed with failures reset", stats)
+	}
+
+	for i := 0; i < 3; i++ {
+		mock.ExpectPing().WillReturnError(errUnreachable)
+		b.Probe(ctx)
+	}
+	stats := b.Stats()
+	if b.Allow() || stats.Breaker != BreakerOpen || stats.OpenedAt == nil || stats.LastError != errUnreachable.Error() {
+		t.Fatalf("after threshold: %+v, want open", stats)
+	}
+
+	// Kegagalan berikutnya tidak memindahkan waktu terbuka
+	opened := *stats.OpenedAt
+	mock.ExpectPing().WillReturnError(errUnreachable)
+	b.Probe(ctx)
+	if stats := b.Stats(); !stats.OpenedAt.Equal(opened) || stats.ConsecutiveFailures != 4 {
+		t.Errorf("while open: %+v, want opened_at unchanged", stats)
+	}
+
+	mock.ExpectPing()
+	b.Probe(ctx)
+	if stats := b.Stats(); !b.Allow() || stats.Breaker != BreakerClosed || stats.OpenedAt != nil {
+		t.Errorf("after recovery: %+v, want closed", stats)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBreakerIgnoresShutdown(t *testing.T) {
+	b, mock := newTestBreaker(t, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mock.ExpectPing().WillReturnError(context.Canceled)
+	b.Probe(ctx)
+	if stats := b.Stats(); !b.Allow() || stats.ConsecutiveFailures != 0 || stats.LastProbeAt != nil {
+		t.Errorf("after cancelled probe: %+v, want untouched", stats)
+	}
+}
+
+func TestGuard(t *testing.T) {
+	b, mock := newTestBreaker(t, 1)
+	mock.ExpectPing().WillReturnError(errUnreachable)
+	b.Probe(context.Background())
+
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.Handler
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	e.GET("/saldo", ok, b.Guard("/health/live"))
+	e.GET("/health/live", ok, b.Guard("/health/live"))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/saldo", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "6" {
+		t.Errorf("guarded route: %d Retry-After %q, want 503 and 6", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("skipped route status = %d, want 204", rec.Code)
+	}
+}
@@ -1,34 +1,65 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"golang-echo-postgresql/config"
 	"log"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
-// InitDB initializes and returns a database connection
-func InitDB() *sql.DB {
-	// Load config values from environment
-	cfg := config.LoadConfig()
+// maxRetryBackoff membatasi jeda antar percobaan koneksi saat startup
+const maxRetryBackoff = 10 * time.Second
 
+// InitDB initializes and returns a database connection. While the database is not
+// reachable yet (for example Postgres still starting under docker-compose) it retries
+// with exponential backoff until DBStartupTimeout has passed.
+func InitDB(cfg *config.Config) *sql.DB {
 	// Build the connection string using loaded values
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=require",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName)
+	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s connect_timeout=%d",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.DBSSLMode, int(cfg.DBConnectTimeout.Seconds()))
 
-	// Open a connection to the PostgreSQL database
-	db, err := sql.Open("postgres", connStr)
+	// Open a connection to the PostgreSQL database; every query is timed for /metrics
+	pqConnector, err := pq.NewConnector(connStr)
 	if err != nil {
 		log.Fatal("Error connecting to the database: ", err)
 	}
+	db := sql.OpenDB(connector{pqConnector})
 
-	// Ping the database to check if it's reachable
-	if err = db.Ping(); err != nil {
+	// Connection pool shared by handlers and background workers
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+
+	// Ping the database until it is reachable or the startup timeout has passed
+	if err = waitForDB(db, cfg.DBStartupTimeout, cfg.DBRetryBackoff); err != nil {
 		log.Fatal("Database is not reachable: ", err)
 	}
 
 	fmt.Println("Connected to the database successfully")
 	return db
 }
+
+func waitForDB(db *sql.DB, timeout, backoff time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(context.Background())
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		}
+
+		log.Printf("Database not ready (attempt %d): %v, retrying in %s", attempt, err, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
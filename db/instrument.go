@@ -0,0 +1,235 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"golang-echo-postgresql/metrics"
+	"golang-echo-postgresql/tracing"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// connector membungkus connector lib/pq supaya setiap query dan exec tercatat di
+// metrik bank_db_query_duration_seconds dan sebagai span tanpa mengubah repository.
+// Durasi metrik diukur sampai baris pertama siap; span query berakhir saat rows ditutup
+// dan mencatat jumlah baris yang dibaca.
+type connector struct {
+	driver.Connector
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn}, nil
+}
+
+// instrumentedConn meneruskan semua antarmuka opsional yang diimplementasikan koneksi
+// lib/pq; tanpa ini database/sql akan kembali ke jalur Prepare yang lebih lambat
+type instrumentedConn struct {
+	driver.Conn
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	statement := statementOf(query)
+	ctx, span := startSpan(ctx, statement, query)
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args)
+	observe("query", statement, err, start)
+	if err != nil || !span.IsRecording() {
+		tracing.End(span, skipped(err))
+		return rows, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	statement := statementOf(query)
+	ctx, span := startSpan(ctx, statement, query)
+	start := time.Now()
+	res, err := e.ExecContext(ctx, query, args)
+	observe("exec", statement, err, start)
+	if err == nil && span.IsRecording() {
+		if n, err := res.RowsAffected(); err == nil {
+			span.SetAttributes(attrRowsAffected.Int64(n))
+		}
+	}
+	tracing.End(span, skipped(err))
+	return res, err
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var tx driver.Tx
+	var err error
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = b.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Conn.Begin()
+	}
+	if err != nil || !trace.SpanContextFromContext(ctx).IsValid() {
+		return tx, err
+	}
+	return &tracedTx{Tx: tx, ctx: ctx}, nil
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func observe(operation, statement string, err error, start time.Time) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	metrics.ObserveQuery(operation, statement, err != nil, time.Since(start))
+}
+
+// Atribut jumlah baris pada span SQL
+const (
+	attrRowsReturned = attribute.Key("db.response.returned_rows")
+	attrRowsAffected = attribute.Key("db.response.affected_rows")
+)
+
+// startSpan membuka span klien untuk satu statement. Teks query memakai placeholder
+// ($1, $2, ...), sehingga nilai argumen tidak pernah masuk ke span.
+func startSpan(ctx context.Context, statement, query string) (context.Context, trace.Span) {
+	return tracing.Child(ctx, strings.ToUpper(statement),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(statement), semconv.DBQueryText(query)))
+}
+
+// skipped mengabaikan driver.ErrSkip, yang berarti database/sql mengulang lewat Prepare
+func skipped(err error) error {
+	if errors.Is(err, driver.ErrSkip) {
+		return nil
+	}
+	return err
+}
+
+// tracedRows menutup span query saat rows ditutup dan mencatat jumlah baris yang dibaca
+type tracedRows struct {
+	driver.Rows
+	span trace.Span
+	n    int64
+	err  error
+}
+
+func (r *tracedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	switch {
+	case err == nil:
+		r.n++
+	case !errors.Is(err, io.EOF):
+		r.err = err
+	}
+	return err
+}
+
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+	r.span.SetAttributes(attrRowsReturned.Int64(r.n))
+	tracing.End(r.span, r.err)
+	return err
+}
+
+// Rows lib/pq menyediakan tipe kolom; teruskan supaya sql.Rows.ColumnTypes tetap lengkap
+func (r *tracedRows) ColumnTypeScanType(index int) reflect.Type {
+	if t, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return t.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(interface{})).Elem()
+}
+
+func (r *tracedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if t, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return t.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *tracedRows) ColumnTypeLength(index int) (int64, bool) {
+	if t, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return t.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *tracedRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if t, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return t.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
+
+// tracedTx mencatat COMMIT dan ROLLBACK sebagai span di bawah context BeginTx
+type tracedTx struct {
+	driver.Tx
+	ctx context.Context
+}
+
+func (t *tracedTx) Commit() error {
+	_, span := startSpan(t.ctx, "commit", "COMMIT")
+	err := t.Tx.Commit()
+	tracing.End(span, err)
+	return err
+}
+
+func (t *tracedTx) Rollback() error {
+	_, span := startSpan(t.ctx, "rollback", "ROLLBACK")
+	err := t.Tx.Rollback()
+	tracing.End(span, err)
+	return err
+}
+
+// statementOf mengambil kata kunci pertama query sebagai label; teks query tidak pernah
+// dipakai karena bisa berisi nilai literal
+func statementOf(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "other"
+	}
+	switch kw := strings.ToLower(fields[0]); kw {
+	case "select", "insert", "update", "delete", "with", "begin", "commit", "rollback", "lock", "copy":
+		return kw
+	}
+	return "other"
+}
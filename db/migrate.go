@@ -0,0 +1,415 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey adalah kunci advisory lock selama migrasi berjalan
+const migrationLockKey = 7305
+
+var migrationName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	ErrUnknownVersion = errors.New("versi migrasi tidak dikenal")
+	ErrNoDownScript   = errors.New("migrasi tidak punya skrip down")
+)
+
+// Migration adalah satu versi skema dengan skrip up dan down-nya
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus adalah status satu versi migrasi pada database
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrator menjalankan migrasi yang di-embed di binary dan mencatat versi yang sudah
+// diterapkan di tabel schema_versions. Setiap versi diterapkan dalam transaksinya sendiri,
+// dan seluruh proses memegang advisory lock sehingga replika yang start bersamaan
+// tidak menjalankan migrasi yang sama dua kali.
+type Migrator struct {
+	DB         *sql.DB
+	Migrations []Migration
+}
+
+// NewMigrator membuat Migrator dari migrasi yang di-embed
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{DB: db, Migrations: migrations}, nil
+}
+
+// LoadMigrations membaca file NNN_nama.up.sql dan NNN_nama.down.sql dari direktori
+// migrations, diurutkan berdasarkan versi. Versi ganda atau file tanpa skrip up ditolak.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		m := migrationName.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("nama file migrasi tidak valid: %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		data, err := fs.ReadFile(fsys, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("versi %d dipakai oleh dua migrasi: %s dan %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrasi %03d_%s tidak punya skrip up", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Latest mengembalikan versi migrasi terbaru yang di-embed
+func (m *Migrator) Latest() int {
+	if len(m.Migrations) == 0 {
+		return 0
+	}
+	return m.Migrations[len(m.Migrations)-1].Version
+}
+
+// Up menerapkan semua migrasi yang belum diterapkan
+func (m *Migrator) Up(ctx context.Context) ([]int, error) {
+	return m.To(ctx, m.Latest())
+}
+
+// Down membatalkan sejumlah steps migrasi terakhir yang sudah diterapkan
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	var reverted []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.Migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.Migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration.Version)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// To menerapkan atau membatalkan migrasi sampai versi target. Versi 0 membatalkan semuanya.
+func (m *Migrator) To(ctx context.Context, target int) ([]int, error) {
+	if target != 0 && m.find(target) == nil {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, target)
+	}
+
+	var changed []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.Migrations {
+			if _, ok := applied[migration.Version]; ok || migration.Version > target {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			changed = append(changed, migration.Version)
+		}
+		for i := len(m.Migrations) - 1; i >= 0; i-- {
+			migration := m.Migrations[i]
+			if _, ok := applied[migration.Version]; !ok || migration.Version <= target {
+				continue
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			changed = append(changed, migration.Version)
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// Status mengembalikan status setiap migrasi yang di-embed
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, len(m.Migrations))
+	for i, migration := range m.Migrations {
+		status[i] = MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if at, ok := applied[migration.Version]; ok {
+			status[i].Applied = true
+			status[i].AppliedAt = &at
+		}
+	}
+	return status, nil
+}
+
+// Pending mengembalikan versi migrasi yang di-embed tetapi belum diterapkan. Berbeda
+// dengan Status, Pending hanya membaca dan tidak membuat atau mengadopsi tabel versi,
+// sehingga aman dipanggil berkala oleh pemeriksaan readiness.
+func (m *Migrator) Pending(ctx context.Context) ([]int, error) {
+	var exists, legacy bool
+	err := m.DB.QueryRowContext(ctx, `SELECT to_regclass('schema_versions') IS NOT NULL,
+		to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists, &legacy)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[int]bool{}
+	if exists {
+		rows, err := m.DB.QueryContext(ctx, "SELECT version FROM schema_versions")
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var version int
+			if err := rows.Scan(&version); err != nil {
+				return nil, err
+			}
+			applied[version] = true
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	} else if legacy {
+		// Database dari tool migrate yang belum diadopsi: versi sampai schema_migrations
+		// dianggap sudah diterapkan
+		var version int
+		var dirty bool
+		err := m.DB.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if dirty {
+			return nil, fmt.Errorf("schema_migrations dari tool migrate berstatus dirty pada versi %d", version)
+		}
+		for _, migration := range m.Migrations {
+			if migration.Version <= version {
+				applied[migration.Version] = true
+			}
+		}
+	}
+
+	var pending []int
+	for _, migration := range m.Migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration.Version)
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.Migrations {
+		if m.Migrations[i].Version == version {
+			return &m.Migrations[i]
+		}
+	}
+	return nil
+}
+
+// withLock menjalankan fn pada satu koneksi yang memegang advisory lock migrasi.
+// Replika lain menunggu sampai migrasi selesai, lalu melihat versi yang sudah diterapkan.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+		return fmt.Errorf("migrasi %03d_%s up: %w", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_versions (version, name) VALUES ($1, $2)", migration.Version, migration.Name); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"version": migration.Version,
+		"name":    migration.Name,
+	}).Info("Migration applied")
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("%w: %03d_%s", ErrNoDownScript, migration.Version, migration.Name)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+		return fmt.Errorf("migrasi %03d_%s down: %w", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_versions WHERE version = $1", migration.Version); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"version": migration.Version,
+		"name":    migration.Name,
+	}).Info("Migration reverted")
+	return nil
+}
+
+// ensureVersionTable membuat tabel schema_versions. Database yang sebelumnya dimigrasi
+// dengan tool migrate (tabel schema_migrations) diadopsi: semua versi sampai versi
+// terakhirnya dianggap sudah diterapkan, kecuali jika tool tersebut meninggalkan status dirty.
+func ensureVersionTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_versions (
+		version INT PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		applied_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	var adopt bool
+	err = conn.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM schema_versions)`).Scan(&adopt)
+	if err != nil || !adopt {
+		return err
+	}
+
+	var version int64
+	var dirty bool
+	err = conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("schema_migrations dari tool migrate berstatus dirty pada versi %d, perbaiki secara manual dulu", version)
+	}
+
+	migrations, err := LoadMigrations(migrationFiles)
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		if int64(migration.Version) > version {
+			break
+		}
+		if _, err := conn.ExecContext(ctx, "INSERT INTO schema_versions (version, name) VALUES ($1, $2)", migration.Version, migration.Name); err != nil {
+			return err
+		}
+	}
+	log.WithFields(log.Fields{
+		"version": version,
+	}).Info("Adopted schema version from migrate tool")
+	return nil
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_versions")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
@@ -1,4 +1,4 @@
--- db/migrations/001_create_nasabah_table.sql
+-- db/migrations/001_create_nasabah_table.up.sql
 CREATE TABLE nasabah (
     id SERIAL PRIMARY KEY,
     nama VARCHAR(100) NOT NULL,
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS auth_signing_keys;
+DROP TABLE IF EXISTS staff;
+ALTER TABLE nasabah DROP COLUMN IF EXISTS pin_hash;
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS pending_operations;
+DROP INDEX IF EXISTS idx_tabungan_reversal;
+DELETE FROM tabungan WHERE jenis_transaksi NOT IN ('setor', 'tarik');
+ALTER TABLE tabungan DROP CONSTRAINT IF EXISTS tabungan_jenis_transaksi_check;
+ALTER TABLE tabungan ADD CONSTRAINT tabungan_jenis_transaksi_check
+    CHECK (jenis_transaksi IN ('setor', 'tarik'));
+ALTER TABLE tabungan ALTER COLUMN jenis_transaksi TYPE VARCHAR(10);
+ALTER TABLE tabungan DROP COLUMN IF EXISTS ref_tabungan_id;
+ALTER TABLE tabungan DROP COLUMN IF EXISTS keterangan;
+ALTER TABLE nasabah DROP COLUMN IF EXISTS status;
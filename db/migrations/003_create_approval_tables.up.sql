@@ -0,0 +1,33 @@
+-- db/migrations/003_create_approval_tables.up.sql
+ALTER TABLE nasabah ADD COLUMN status VARCHAR(20) DEFAULT 'aktif' NOT NULL
+    CHECK (status IN ('aktif', 'beku'));
+
+ALTER TABLE tabungan ADD COLUMN keterangan TEXT;
+ALTER TABLE tabungan ADD COLUMN ref_tabungan_id INT REFERENCES tabungan(id);
+-- VARCHAR(10) dari migrasi 001 terlalu pendek untuk jenis koreksi_kredit/koreksi_debit
+ALTER TABLE tabungan ALTER COLUMN jenis_transaksi TYPE VARCHAR(20);
+ALTER TABLE tabungan DROP CONSTRAINT IF EXISTS tabungan_jenis_transaksi_check;
+ALTER TABLE tabungan ADD CONSTRAINT tabungan_jenis_transaksi_check
+    CHECK (jenis_transaksi IN ('setor', 'tarik', 'koreksi_kredit', 'koreksi_debit'));
+CREATE UNIQUE INDEX idx_tabungan_reversal ON tabungan (ref_tabungan_id) WHERE ref_tabungan_id IS NOT NULL;
+
+CREATE TABLE pending_operations (
+    id SERIAL PRIMARY KEY,
+    action VARCHAR(30) NOT NULL,
+    no_rekening VARCHAR(20),
+    nominal DECIMAL(15,2),
+    payload JSONB NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending' NOT NULL
+        CHECK (status IN ('pending', 'approved', 'rejected', 'expired', 'failed')),
+    maker_type VARCHAR(10) NOT NULL,
+    maker_id INT NOT NULL,
+    alasan TEXT,
+    checker_id INT REFERENCES staff(id),
+    catatan_checker TEXT,
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    decided_at TIMESTAMP
+);
+
+CREATE INDEX idx_pending_operations_status ON pending_operations (status, expires_at);
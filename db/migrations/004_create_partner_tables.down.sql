@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS partner_nonces;
+DROP TABLE IF EXISTS partner_secrets;
+DROP TABLE IF EXISTS partners;
@@ -0,0 +1,27 @@
+-- db/migrations/004_create_partner_tables.up.sql
+CREATE TABLE partners (
+    id SERIAL PRIMARY KEY,
+    client_id VARCHAR(64) UNIQUE NOT NULL,
+    nama VARCHAR(100) NOT NULL,
+    allowed_endpoints TEXT[] DEFAULT '{}' NOT NULL,
+    aktif BOOLEAN DEFAULT TRUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE partner_secrets (
+    id SERIAL PRIMARY KEY,
+    partner_id INT NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    secret_enc TEXT NOT NULL,
+    valid_from TIMESTAMP NOT NULL,
+    valid_until TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_partner_secrets_partner ON partner_secrets (partner_id);
+
+CREATE TABLE partner_nonces (
+    partner_id INT NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    nonce VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (partner_id, nonce)
+);
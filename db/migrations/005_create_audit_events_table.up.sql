@@ -0,0 +1,33 @@
+-- db/migrations/005_create_audit_events_table.up.sql
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    request_id VARCHAR(64),
+    actor_type VARCHAR(10) NOT NULL,
+    actor_id VARCHAR(64) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    no_rekening VARCHAR(20),
+    before_value TEXT,
+    after_value TEXT,
+    status_code INT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL
+);
+
+CREATE INDEX idx_audit_events_rekening ON audit_events (no_rekening, id);
+CREATE INDEX idx_audit_events_actor ON audit_events (actor_type, actor_id, id);
+
+-- Tabel audit bersifat append-only: perubahan dan penghapusan ditolak di level database
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
@@ -0,0 +1,17 @@
+-- db/migrations/006_create_outbox_events_table.up.sql
+CREATE TABLE outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_id VARCHAR(20) NOT NULL,
+    aggregate_seq BIGINT NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    event_version INT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    published_at TIMESTAMP,
+    attempts INT DEFAULT 0 NOT NULL,
+    next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    last_error TEXT,
+    UNIQUE (aggregate_id, aggregate_seq)
+);
+
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events (aggregate_id, aggregate_seq) WHERE published_at IS NULL;
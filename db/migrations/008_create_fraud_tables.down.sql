@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS fraud_decisions;
+DROP INDEX IF EXISTS idx_tabungan_nasabah_created;
+ALTER TABLE nasabah DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,34 @@
+-- db/migrations/008_create_fraud_tables.up.sql
+-- Umur rekening dipakai aturan fraud untuk rekening baru
+ALTER TABLE nasabah ADD COLUMN created_at TIMESTAMP;
+UPDATE nasabah n SET created_at = COALESCE(
+    (SELECT MIN(t.created_at) FROM tabungan t WHERE t.nasabah_id = n.id),
+    CURRENT_TIMESTAMP
+);
+ALTER TABLE nasabah ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE nasabah ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX idx_tabungan_nasabah_created ON tabungan (nasabah_id, created_at);
+
+CREATE TABLE fraud_decisions (
+    id BIGSERIAL PRIMARY KEY,
+    no_rekening VARCHAR(20) NOT NULL,
+    jenis_transaksi VARCHAR(20) NOT NULL,
+    nominal DECIMAL(15, 2) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(64) NOT NULL,
+    outcome VARCHAR(20) NOT NULL CHECK (outcome IN ('allow', 'challenge', 'block')),
+    rules JSONB NOT NULL,
+    rules_version VARCHAR(64) NOT NULL,
+    step_up_passed BOOLEAN DEFAULT FALSE NOT NULL,
+    review_status VARCHAR(20)
+        CHECK (review_status IN ('open', 'confirmed_fraud', 'false_positive')),
+    reviewed_by INT REFERENCES staff(id),
+    reviewed_at TIMESTAMP,
+    review_note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_fraud_decisions_review ON fraud_decisions (review_status, id) WHERE review_status IS NOT NULL;
+CREATE INDEX idx_fraud_decisions_rekening ON fraud_decisions (no_rekening, id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS aml_runs;
+DROP TABLE IF EXISTS aml_case_notes;
+DROP TABLE IF EXISTS aml_cases;
+DROP TABLE IF EXISTS aml_ltkt_reports;
+DROP TABLE IF EXISTS aml_exports;
+DROP TABLE IF EXISTS aml_daily_aggregates;
@@ -0,0 +1,79 @@
+-- db/migrations/009_create_aml_tables.up.sql
+CREATE TABLE aml_daily_aggregates (
+    nasabah_id INT NOT NULL REFERENCES nasabah(id) ON DELETE CASCADE,
+    tanggal DATE NOT NULL,
+    no_rekening VARCHAR(20) NOT NULL,
+    total_setor DECIMAL(17, 2) DEFAULT 0 NOT NULL,
+    total_tarik DECIMAL(17, 2) DEFAULT 0 NOT NULL,
+    jumlah_setor INT DEFAULT 0 NOT NULL,
+    jumlah_tarik INT DEFAULT 0 NOT NULL,
+    computed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (nasabah_id, tanggal)
+);
+
+CREATE INDEX idx_aml_daily_aggregates_tanggal ON aml_daily_aggregates (tanggal);
+
+CREATE TABLE aml_exports (
+    id SERIAL PRIMARY KEY,
+    jenis_laporan VARCHAR(10) NOT NULL CHECK (jenis_laporan IN ('ltkt', 'ltkm')),
+    tanggal DATE NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    file_path TEXT NOT NULL,
+    jumlah_record INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Laporan Transaksi Keuangan Tunai: total tunai harian per arah >= ambang batas
+CREATE TABLE aml_ltkt_reports (
+    id SERIAL PRIMARY KEY,
+    nasabah_id INT NOT NULL REFERENCES nasabah(id),
+    tanggal DATE NOT NULL,
+    arah VARCHAR(10) NOT NULL CHECK (arah IN ('setor', 'tarik')),
+    no_rekening VARCHAR(20) NOT NULL,
+    nama VARCHAR(100) NOT NULL,
+    nik VARCHAR(16) NOT NULL,
+    total DECIMAL(17, 2) NOT NULL,
+    jumlah_transaksi INT NOT NULL,
+    export_id INT REFERENCES aml_exports(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (nasabah_id, tanggal, arah)
+);
+
+CREATE TABLE aml_cases (
+    id SERIAL PRIMARY KEY,
+    nasabah_id INT NOT NULL REFERENCES nasabah(id),
+    no_rekening VARCHAR(20) NOT NULL,
+    tanggal DATE NOT NULL,
+    tipologi VARCHAR(50) NOT NULL,
+    detail JSONB NOT NULL,
+    status VARCHAR(20) DEFAULT 'open' NOT NULL
+        CHECK (status IN ('open', 'investigating', 'filed', 'closed')),
+    assigned_to INT REFERENCES staff(id),
+    referensi_ppatk VARCHAR(100),
+    filed_at TIMESTAMP,
+    export_id INT REFERENCES aml_exports(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (nasabah_id, tanggal, tipologi)
+);
+
+CREATE INDEX idx_aml_cases_status ON aml_cases (status, id);
+
+CREATE TABLE aml_case_notes (
+    id SERIAL PRIMARY KEY,
+    case_id INT NOT NULL REFERENCES aml_cases(id) ON DELETE CASCADE,
+    staff_id INT REFERENCES staff(id),
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    catatan TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Satu baris per tanggal yang sudah diproses job harian
+CREATE TABLE aml_runs (
+    tanggal DATE PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP,
+    ltkt_count INT DEFAULT 0 NOT NULL,
+    case_count INT DEFAULT 0 NOT NULL
+);
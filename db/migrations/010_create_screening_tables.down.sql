@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS screening_hits;
+DROP TABLE IF EXISTS watchlist_entries;
+DROP TABLE IF EXISTS watchlist_versions;
+DROP TABLE IF EXISTS watchlists;
+UPDATE nasabah SET status = 'beku' WHERE status = 'pending_review';
+ALTER TABLE nasabah DROP CONSTRAINT IF EXISTS nasabah_status_check;
+ALTER TABLE nasabah ADD CONSTRAINT nasabah_status_check CHECK (status IN ('aktif', 'beku'));
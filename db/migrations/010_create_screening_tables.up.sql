@@ -0,0 +1,59 @@
+-- db/migrations/010_create_screening_tables.up.sql
+ALTER TABLE nasabah DROP CONSTRAINT IF EXISTS nasabah_status_check;
+ALTER TABLE nasabah ADD CONSTRAINT nasabah_status_check
+    CHECK (status IN ('aktif', 'beku', 'pending_review'));
+
+CREATE TABLE watchlists (
+    id SERIAL PRIMARY KEY,
+    kode VARCHAR(30) UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Setiap impor membuat versi baru berisi seluruh entri; hanya versi terbaru yang aktif
+CREATE TABLE watchlist_versions (
+    id SERIAL PRIMARY KEY,
+    watchlist_id INT NOT NULL REFERENCES watchlists(id),
+    versi INT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    jumlah_entri INT NOT NULL,
+    aktif BOOLEAN DEFAULT FALSE NOT NULL,
+    imported_by INT REFERENCES staff(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (watchlist_id, versi)
+);
+
+CREATE UNIQUE INDEX idx_watchlist_versions_aktif ON watchlist_versions (watchlist_id) WHERE aktif;
+
+CREATE TABLE watchlist_entries (
+    id SERIAL PRIMARY KEY,
+    version_id INT NOT NULL REFERENCES watchlist_versions(id) ON DELETE CASCADE,
+    nama VARCHAR(200) NOT NULL,
+    alias TEXT[] DEFAULT '{}' NOT NULL,
+    nik VARCHAR(16),
+    tanggal_lahir VARCHAR(20),
+    keterangan TEXT
+);
+
+CREATE INDEX idx_watchlist_entries_version ON watchlist_entries (version_id);
+
+CREATE TABLE screening_hits (
+    id SERIAL PRIMARY KEY,
+    nasabah_id INT NOT NULL REFERENCES nasabah(id),
+    konteks VARCHAR(20) NOT NULL CHECK (konteks IN ('onboarding', 'transfer')),
+    nama_subjek VARCHAR(200) NOT NULL,
+    entry_id INT NOT NULL REFERENCES watchlist_entries(id),
+    watchlist VARCHAR(30) NOT NULL,
+    versi INT NOT NULL,
+    nama_entri VARCHAR(200) NOT NULL,
+    skor DECIMAL(5, 4) NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending' NOT NULL
+        CHECK (status IN ('pending', 'cleared', 'confirmed')),
+    reviewed_by INT REFERENCES staff(id),
+    reviewed_at TIMESTAMP,
+    catatan TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_screening_hits_status ON screening_hits (status, id);
+CREATE INDEX idx_screening_hits_nasabah ON screening_hits (nasabah_id);
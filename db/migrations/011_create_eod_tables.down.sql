@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS bunga_harian;
+DROP TABLE IF EXISTS saldo_harian;
+DROP TABLE IF EXISTS eod_steps;
+DROP TABLE IF EXISTS eod_runs;
+
+ALTER TABLE nasabah DROP COLUMN IF EXISTS dorman_sejak;
+
+DELETE FROM tabungan WHERE jenis_transaksi IN ('bunga', 'biaya_admin');
+ALTER TABLE tabungan DROP CONSTRAINT IF EXISTS tabungan_jenis_transaksi_check;
+ALTER TABLE tabungan ADD CONSTRAINT tabungan_jenis_transaksi_check
+    CHECK (jenis_transaksi IN ('setor', 'tarik', 'koreksi_kredit', 'koreksi_debit'));
@@ -0,0 +1,51 @@
+-- db/migrations/011_create_eod_tables.up.sql
+ALTER TABLE tabungan DROP CONSTRAINT IF EXISTS tabungan_jenis_transaksi_check;
+ALTER TABLE tabungan ADD CONSTRAINT tabungan_jenis_transaksi_check
+    CHECK (jenis_transaksi IN ('setor', 'tarik', 'koreksi_kredit', 'koreksi_debit', 'bunga', 'biaya_admin'));
+
+ALTER TABLE nasabah ADD COLUMN dorman_sejak DATE;
+
+CREATE TABLE eod_runs (
+    id SERIAL PRIMARY KEY,
+    tanggal DATE UNIQUE NOT NULL,
+    status VARCHAR(20) DEFAULT 'running' NOT NULL
+        CHECK (status IN ('running', 'failed', 'completed')),
+    triggered_by VARCHAR(100) NOT NULL,
+    attempts INT DEFAULT 1 NOT NULL,
+    error TEXT,
+    started_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE TABLE eod_steps (
+    run_id INT NOT NULL REFERENCES eod_runs(id) ON DELETE CASCADE,
+    step VARCHAR(50) NOT NULL,
+    urutan INT NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending' NOT NULL
+        CHECK (status IN ('pending', 'running', 'failed', 'completed', 'skipped')),
+    checkpoint INT DEFAULT 0 NOT NULL,
+    diproses INT DEFAULT 0 NOT NULL,
+    error TEXT,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    PRIMARY KEY (run_id, step)
+);
+
+CREATE TABLE saldo_harian (
+    tanggal DATE NOT NULL,
+    nasabah_id INT NOT NULL REFERENCES nasabah(id) ON DELETE CASCADE,
+    saldo DECIMAL(15,2) NOT NULL,
+    PRIMARY KEY (tanggal, nasabah_id)
+);
+CREATE INDEX idx_saldo_harian_nasabah ON saldo_harian (nasabah_id, tanggal);
+
+CREATE TABLE bunga_harian (
+    tanggal DATE NOT NULL,
+    nasabah_id INT NOT NULL REFERENCES nasabah(id) ON DELETE CASCADE,
+    saldo DECIMAL(15,2) NOT NULL,
+    suku_bunga DECIMAL(7,4) NOT NULL,
+    bunga DECIMAL(15,6) NOT NULL,
+    tabungan_id INT REFERENCES tabungan(id),
+    PRIMARY KEY (tanggal, nasabah_id)
+);
+CREATE INDEX idx_bunga_harian_unposted ON bunga_harian (nasabah_id) WHERE tabungan_id IS NULL;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS rekonsiliasi_selisih;
+DROP TABLE IF EXISTS rekonsiliasi_runs;
+DROP TABLE IF EXISTS rekonsiliasi_checkpoint;
+
+DROP INDEX IF EXISTS idx_tabungan_nasabah_id;
+ALTER TABLE tabungan DROP COLUMN IF EXISTS saldo_setelah;
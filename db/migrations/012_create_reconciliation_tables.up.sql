@@ -0,0 +1,44 @@
+-- db/migrations/012_create_reconciliation_tables.up.sql
+-- Saldo rekening setelah transaksi dicatat, dipakai untuk menemukan transaksi pertama
+-- yang membuat saldo dan riwayat berbeda. Kosong untuk transaksi sebelum migrasi ini.
+ALTER TABLE tabungan ADD COLUMN saldo_setelah DECIMAL(15,2);
+CREATE INDEX idx_tabungan_nasabah_id ON tabungan (nasabah_id, id);
+
+CREATE TABLE rekonsiliasi_checkpoint (
+    nasabah_id INT PRIMARY KEY REFERENCES nasabah(id) ON DELETE CASCADE,
+    last_tabungan_id INT NOT NULL,
+    saldo_hitung DECIMAL(15,2) NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE TABLE rekonsiliasi_runs (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(20) DEFAULT 'running' NOT NULL
+        CHECK (status IN ('running', 'failed', 'completed')),
+    triggered_by VARCHAR(100) NOT NULL,
+    diperiksa INT DEFAULT 0 NOT NULL,
+    selisih INT DEFAULT 0 NOT NULL,
+    error TEXT,
+    started_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE TABLE rekonsiliasi_selisih (
+    id SERIAL PRIMARY KEY,
+    run_id INT NOT NULL REFERENCES rekonsiliasi_runs(id),
+    nasabah_id INT NOT NULL REFERENCES nasabah(id) ON DELETE CASCADE,
+    saldo_tercatat DECIMAL(15,2) NOT NULL,
+    saldo_hitung DECIMAL(15,2) NOT NULL,
+    tabungan_id INT REFERENCES tabungan(id),
+    saldo_setelah_tercatat DECIMAL(15,2),
+    saldo_setelah_hitung DECIMAL(15,2),
+    status VARCHAR(20) DEFAULT 'open' NOT NULL
+        CHECK (status IN ('open', 'koreksi_diajukan', 'dikoreksi', 'selesai')),
+    operation_id INT REFERENCES pending_operations(id),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    resolved_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_rekonsiliasi_selisih_aktif ON rekonsiliasi_selisih (nasabah_id)
+    WHERE status IN ('open', 'koreksi_diajukan');
+CREATE INDEX idx_rekonsiliasi_selisih_status ON rekonsiliasi_selisih (status, id);
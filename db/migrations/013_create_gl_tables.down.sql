@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS gl_vouchers;
+ALTER TABLE tabungan DROP COLUMN IF EXISTS channel;
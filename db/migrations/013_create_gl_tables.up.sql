@@ -0,0 +1,22 @@
+-- db/migrations/013_create_gl_tables.up.sql
+-- Channel asal transaksi untuk pemetaan ke akun buku besar. Kosong untuk transaksi
+-- sebelum migrasi ini; pemetaan dengan channel "*" berlaku untuk transaksi tersebut.
+ALTER TABLE tabungan ADD COLUMN channel VARCHAR(20);
+
+-- Voucher jurnal harian: satu voucher per jenis transaksi dan channel, dengan satu baris
+-- debit dan satu baris kredit sebesar total transaksi hari itu
+CREATE TABLE gl_vouchers (
+    id SERIAL PRIMARY KEY,
+    tanggal DATE NOT NULL,
+    nomor VARCHAR(64) NOT NULL UNIQUE,
+    jenis_transaksi VARCHAR(20) NOT NULL,
+    channel VARCHAR(20) DEFAULT '' NOT NULL,
+    akun_debit VARCHAR(20) NOT NULL,
+    akun_kredit VARCHAR(20) NOT NULL,
+    jumlah_transaksi INT DEFAULT 0 NOT NULL,
+    total DECIMAL(17,2) DEFAULT 0 NOT NULL,
+    mapping_version VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    UNIQUE (tanggal, jenis_transaksi, channel)
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tabungan_referensi;
+ALTER TABLE tabungan DROP COLUMN IF EXISTS referensi;
+
+DELETE FROM tabungan WHERE jenis_transaksi IN ('transfer_keluar', 'transfer_masuk');
+ALTER TABLE tabungan DROP CONSTRAINT IF EXISTS tabungan_jenis_transaksi_check;
+ALTER TABLE tabungan ADD CONSTRAINT tabungan_jenis_transaksi_check
+    CHECK (jenis_transaksi IN ('setor', 'tarik', 'koreksi_kredit', 'koreksi_debit', 'bunga', 'biaya_admin'));
@@ -0,0 +1,9 @@
+-- db/migrations/014_add_transfer_columns.up.sql
+ALTER TABLE tabungan DROP CONSTRAINT IF EXISTS tabungan_jenis_transaksi_check;
+ALTER TABLE tabungan ADD CONSTRAINT tabungan_jenis_transaksi_check
+    CHECK (jenis_transaksi IN ('setor', 'tarik', 'koreksi_kredit', 'koreksi_debit', 'bunga', 'biaya_admin',
+        'transfer_keluar', 'transfer_masuk'));
+
+-- Referensi dari channel pemanggil; kedua sisi transfer memakai referensi yang sama
+ALTER TABLE tabungan ADD COLUMN referensi VARCHAR(64);
+CREATE INDEX idx_tabungan_referensi ON tabungan (referensi) WHERE referensi IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS trace_parent;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS trace_parent;
@@ -0,0 +1,6 @@
+-- db/migrations/015_add_trace_context.up.sql
+-- Header W3C traceparent dari request yang membuat event, diteruskan ke pengiriman
+-- webhook supaya request ke partner masuk ke trace yang sama. Kosong jika request
+-- asal tidak berada di dalam trace.
+ALTER TABLE outbox_events ADD COLUMN trace_parent VARCHAR(55);
+ALTER TABLE webhook_deliveries ADD COLUMN trace_parent VARCHAR(55);
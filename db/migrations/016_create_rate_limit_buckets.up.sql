@@ -0,0 +1,12 @@
+-- db/migrations/016_create_rate_limit_buckets.up.sql
+-- Token bucket rate limit yang dipakai bersama oleh semua replika (RATE_LIMIT_BACKEND=postgres).
+-- bucket_key berisi id policy dan kunci (IP, principal, partner atau rekening). Bucket yang
+-- sudah penuh kembali (expires_at lewat) dihapus berkala karena isinya sama dengan bucket baru.
+CREATE TABLE rate_limit_buckets (
+    bucket_key VARCHAR(200) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_expires ON rate_limit_buckets (expires_at);
@@ -0,0 +1,11 @@
+-- db/migrations/017_create_partner_rekening.up.sql
+-- Rekening yang boleh dilihat partner, dihubungkan oleh back-office. Subscription webhook
+-- hanya menerima event dari rekening yang terhubung dengan partner pemiliknya.
+CREATE TABLE partner_rekening (
+    partner_id INT NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    no_rekening VARCHAR(20) NOT NULL REFERENCES nasabah(no_rekening) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (partner_id, no_rekening)
+);
+
+CREATE INDEX idx_partner_rekening_rekening ON partner_rekening (no_rekening);
@@ -0,0 +1,401 @@
+package eod
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DateLayout adalah format tanggal bisnis yang dipakai API dan command line
+const DateLayout = "2006-01-02"
+
+// Mode penanganan transaksi selama EOD berjalan
+const (
+	ModeQueue  = "queue"  // tahan request sampai EOD selesai atau QueueTimeout habis
+	ModeReject = "reject" // langsung tolak dengan 503
+)
+
+var (
+	ErrAlreadyRunning = errors.New("EOD sedang berjalan")
+	ErrAlreadyClosed  = errors.New("tanggal bisnis sudah ditutup")
+	ErrDateNotOver    = errors.New("tanggal bisnis belum berakhir")
+	ErrOutOfOrder     = errors.New("tanggal bisnis sebelumnya belum ditutup")
+	ErrRunNotFound    = errors.New("run EOD tidak ditemukan")
+)
+
+// Service menjalankan proses tutup hari (EOD): menutup tanggal bisnis, menyimpan
+// saldo penutupan setiap rekening dan menjalankan step terdaftar sesuai dependensinya.
+// Hanya satu EOD yang berjalan sekaligus di semua instance.
+type Service struct {
+	DB           *sql.DB
+	BatchSize    int
+	RunAt        time.Duration // jam EOD otomatis sejak tengah malam, menutup tanggal kemarin
+	Mode         string
+	QueueTimeout time.Duration
+
+	steps []Step
+
+	mu        sync.Mutex
+	checked   time.Time
+	inProcess bool
+}
+
+// NewService membuat Service EOD tanpa step; daftarkan step dengan Register
+func NewService(db *sql.DB, batchSize int, runAt time.Duration, mode string, queueTimeout time.Duration) *Service {
+	return &Service{DB: db, BatchSize: batchSize, RunAt: runAt, Mode: mode, QueueTimeout: queueTimeout}
+}
+
+// Register menambahkan step EOD. Urutan eksekusi ditentukan dari dependensi step,
+// bukan dari urutan pendaftaran.
+func (s *Service) Register(steps ...Step) {
+	s.steps = append(s.steps, steps...)
+}
+
+// Run menjalankan EOD otomatis sampai ctx dibatalkan. Setiap menit, setelah jam RunAt,
+// semua tanggal bisnis yang belum ditutup sampai kemarin ditutup berurutan; EOD yang
+// gagal dilanjutkan dari checkpoint-nya.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		if now.Sub(truncateDay(now)) >= s.RunAt {
+			if err := s.CloseDue(ctx, "scheduler"); err != nil && !errors.Is(err, ErrAlreadyRunning) && ctx.Err() == nil {
+				log.WithFields(log.Fields{
+					"error": err,
+				}).Error("Scheduled EOD failed")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CloseDue menutup semua tanggal bisnis yang belum ditutup sampai kemarin
+func (s *Service) CloseDue(ctx context.Context, triggeredBy string) error {
+	yesterday := truncateDay(time.Now()).AddDate(0, 0, -1)
+
+	lastClosed, err := repositories.GetLastClosedDate(s.DB)
+	if err != nil {
+		return err
+	}
+	tanggal := yesterday
+	if lastClosed != nil {
+		tanggal = truncateDay(*lastClosed).AddDate(0, 0, 1)
+	}
+
+	for ; !tanggal.After(yesterday); tanggal = tanggal.AddDate(0, 0, 1) {
+		if _, err := s.Close(ctx, tanggal, triggeredBy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close menutup satu tanggal bisnis. Tanggal harus sudah berakhir dan tanggal
+// sebelumnya harus sudah ditutup (kecuali EOD pertama). Run yang pernah gagal
+// dilanjutkan dari checkpoint setiap step.
+func (s *Service) Close(ctx context.Context, tanggal time.Time, triggeredBy string) (*models.EODRun, error) {
+	tanggal = truncateDay(tanggal)
+	if err := s.Check(tanggal); err != nil {
+		return nil, err
+	}
+
+	steps, err := Order(s.steps)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := s.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	locked, err := repositories.TryLockEOD(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrAlreadyRunning
+	}
+	defer repositories.UnlockEOD(context.Background(), conn)
+
+	run, err := s.begin(tanggal, triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"tanggal": tanggal.Format(DateLayout),
+		"RunID":   run.ID,
+		"attempt": run.Attempts,
+		"trigger": triggeredBy,
+		"steps":   len(steps),
+	}).Info("EOD started")
+
+	if err := s.runSteps(ctx, run, steps); err != nil {
+		if ferr := repositories.FinishEODRun(s.DB, run.ID, models.EODFailed, err.Error()); ferr != nil {
+			log.WithFields(log.Fields{
+				"error": ferr,
+				"RunID": run.ID,
+			}).Error("Failed to record EOD failure")
+		}
+		log.WithFields(log.Fields{
+			"error":   err,
+			"tanggal": tanggal.Format(DateLayout),
+			"RunID":   run.ID,
+		}).Error("EOD failed, it will resume from the last checkpoint")
+		return nil, err
+	}
+
+	if err := repositories.FinishEODRun(s.DB, run.ID, models.EODCompleted, ""); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"tanggal": tanggal.Format(DateLayout),
+		"RunID":   run.ID,
+	}).Info("EOD completed, business date closed")
+
+	return s.RunDetail(tanggal)
+}
+
+// Check memeriksa apakah tanggal bisnis bisa ditutup sekarang tanpa menjalankan EOD
+func (s *Service) Check(tanggal time.Time) error {
+	tanggal = truncateDay(tanggal)
+	if !tanggal.Before(truncateDay(time.Now())) {
+		return ErrDateNotOver
+	}
+	lastClosed, err := repositories.GetLastClosedDate(s.DB)
+	if err != nil {
+		return err
+	}
+	if lastClosed != nil {
+		next := truncateDay(*lastClosed).AddDate(0, 0, 1)
+		if tanggal.Before(next) {
+			return ErrAlreadyClosed
+		}
+		if tanggal.After(next) {
+			return ErrOutOfOrder
+		}
+	}
+	return nil
+}
+
+// Plan mengembalikan step yang akan dijalankan untuk tanggal beserta urutan dan status
+// awalnya tanpa menjalankan EOD. Run yang pernah gagal menampilkan progres tersimpannya.
+func (s *Service) Plan(tanggal time.Time) ([]models.EODStep, error) {
+	tanggal = truncateDay(tanggal)
+	if err := s.Check(tanggal); err != nil {
+		return nil, err
+	}
+	steps, err := Order(s.steps)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := map[string]models.EODStep{}
+	run, err := repositories.GetEODRun(s.DB, tanggal)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if run != nil {
+		saved, err := repositories.ListEODSteps(s.DB, run.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range saved {
+			progress[p.Step] = p
+		}
+	}
+
+	plan := make([]models.EODStep, len(steps))
+	for i, step := range steps {
+		p, ok := progress[step.Name]
+		if !ok {
+			p = models.EODStep{Step: step.Name, Status: models.EODPending}
+			if step.Due != nil && !step.Due(tanggal) {
+				p.Status = models.EODSkipped
+			}
+		}
+		p.Urutan = i + 1
+		plan[i] = p
+	}
+	return plan, nil
+}
+
+func (s *Service) begin(tanggal time.Time, triggeredBy string) (*models.EODRun, error) {
+	if err := s.Check(tanggal); err != nil {
+		return nil, err
+	}
+
+	run, err := repositories.GetEODRun(s.DB, tanggal)
+	if err == sql.ErrNoRows {
+		run = &models.EODRun{Tanggal: tanggal, TriggeredBy: triggeredBy}
+		return run, repositories.CreateEODRun(s.DB, run)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if run.Status == models.EODCompleted {
+		return nil, ErrAlreadyClosed
+	}
+
+	// Status running tanpa pemegang kunci berarti proses sebelumnya mati di tengah jalan
+	run.TriggeredBy = triggeredBy
+	return run, repositories.ResumeEODRun(s.DB, run)
+}
+
+func (s *Service) runSteps(ctx context.Context, run *models.EODRun, steps []Step) error {
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		names[i] = step.Name
+	}
+	if err := repositories.EnsureEODSteps(s.DB, run.ID, names); err != nil {
+		return err
+	}
+
+	progress, err := repositories.ListEODSteps(s.DB, run.ID)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]models.EODStep, len(progress))
+	for _, p := range progress {
+		byName[p.Step] = p
+	}
+
+	for _, step := range steps {
+		p := byName[step.Name]
+		if p.Status == models.EODCompleted || p.Status == models.EODSkipped {
+			continue
+		}
+		if step.Due != nil && !step.Due(run.Tanggal) {
+			if err := repositories.FinishEODStep(s.DB, run.ID, step.Name, models.EODSkipped, ""); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := repositories.StartEODStep(s.DB, run.ID, step.Name); err != nil {
+			return err
+		}
+		if err := s.runStep(ctx, run, step, p.Checkpoint); err != nil {
+			if ferr := repositories.FinishEODStep(s.DB, run.ID, step.Name, models.EODFailed, err.Error()); ferr != nil {
+				return ferr
+			}
+			return fmt.Errorf("step %s: %w", step.Name, err)
+		}
+		if err := repositories.FinishEODStep(s.DB, run.ID, step.Name, models.EODCompleted, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) runStep(ctx context.Context, run *models.EODRun, step Step, checkpoint int) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ids, err := repositories.ListNasabahIDsAfter(s.DB, checkpoint, s.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := s.runBatch(ctx, run, step, ids); err != nil {
+			return err
+		}
+		checkpoint = ids[len(ids)-1]
+
+		if len(ids) < s.BatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *Service) runBatch(ctx context.Context, run *models.EODRun, step Step, ids []int) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	processed, err := step.Process(tx, run.Tanggal, ids)
+	if err != nil {
+		return err
+	}
+	if err := repositories.SaveEODCheckpoint(tx, run.ID, step.Name, ids[len(ids)-1], processed); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Status mengembalikan tanggal bisnis yang sedang terbuka dan run EOD terakhir
+func (s *Service) Status() (*models.EODStatus, error) {
+	status := &models.EODStatus{TanggalBisnis: truncateDay(time.Now()).Format(DateLayout)}
+
+	lastClosed, err := repositories.GetLastClosedDate(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	if lastClosed != nil {
+		status.TanggalTerakhir = lastClosed.Format(DateLayout)
+		status.TanggalBisnis = truncateDay(*lastClosed).AddDate(0, 0, 1).Format(DateLayout)
+	}
+
+	status.InProgress, err = repositories.IsEODLocked(s.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := repositories.GetLastEODRun(s.DB)
+	if err == sql.ErrNoRows {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	run.Steps, err = repositories.ListEODSteps(s.DB, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	status.LastRun = run
+	return status, nil
+}
+
+// RunDetail mengambil run EOD satu tanggal beserta progres setiap step
+func (s *Service) RunDetail(tanggal time.Time) (*models.EODRun, error) {
+	run, err := repositories.GetEODRun(s.DB, tanggal)
+	if err == sql.ErrNoRows {
+		return nil, ErrRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	run.Steps, err = repositories.ListEODSteps(s.DB, run.ID)
+	return run, err
+}
+
+// truncateDay mengembalikan tengah malam waktu lokal pada tanggal kalender t. Kolom
+// DATE dibaca sebagai tengah malam UTC, sehingga zona waktunya diseragamkan di sini.
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
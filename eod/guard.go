@@ -0,0 +1,85 @@
+package eod
+
+import (
+	"context"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/repositories"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// guardCacheTTL membatasi seberapa sering status EOD diperiksa ke database
+const guardCacheTTL = time.Second
+
+// retryAfter adalah saran jeda untuk klien yang ditolak selama EOD
+const retryAfter = 30 * time.Second
+
+// Guard menahan atau menolak request transaksi selama EOD berjalan. Pada ModeQueue
+// request ditahan sampai EOD selesai atau QueueTimeout habis; pada ModeReject (atau
+// setelah timeout) request ditolak dengan 503 dan header Retry-After.
+func (s *Service) Guard() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ok, err := s.Wait(c.Request().Context())
+			if err != nil {
+				return err
+			}
+			if ok {
+				return next(c)
+			}
+
+			log.WithContext(c.Request().Context()).WithFields(log.Fields{
+				"path": c.Path(),
+				"mode": s.Mode,
+			}).Warn("Transaction rejected during EOD")
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+			return apierror.New(apierror.EODInProgress)
+		}
+	}
+}
+
+// Wait melaporkan apakah transaksi boleh berjalan. Selama EOD pada ModeQueue, Wait
+// menunggu sampai EOD selesai atau QueueTimeout habis; false berarti transaksi harus ditolak.
+func (s *Service) Wait(ctx context.Context) (bool, error) {
+	if !s.InProgress() {
+		return true, nil
+	}
+	if s.Mode != ModeQueue {
+		return false, nil
+	}
+
+	deadline := time.Now().Add(s.QueueTimeout)
+	for s.InProgress() && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(guardCacheTTL):
+		}
+	}
+	return !s.InProgress(), nil
+}
+
+// InProgress melaporkan apakah EOD sedang berjalan di instance mana pun. Hasilnya
+// di-cache sebentar agar setiap request tidak menambah query ke database.
+func (s *Service) InProgress() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.checked) < guardCacheTTL {
+		return s.inProcess
+	}
+	locked, err := repositories.IsEODLocked(s.DB)
+	if err != nil {
+		// Jangan blokir transaksi hanya karena status EOD tidak bisa dibaca
+		log.WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to check EOD status")
+		locked = false
+	}
+	s.checked = time.Now()
+	s.inProcess = locked
+	return locked
+}
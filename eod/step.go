@@ -0,0 +1,74 @@
+package eod
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Step adalah satu langkah EOD. Step diproses per batch nasabah berurutan id; setiap
+// batch berjalan di transaksinya sendiri bersama checkpoint step, sehingga EOD yang
+// gagal dilanjutkan dari batch terakhir yang berhasil tanpa memproses ulang batch lain.
+type Step struct {
+	Name  string
+	After []string // step yang harus selesai lebih dulu
+
+	// Due menentukan apakah step berjalan untuk tanggal bisnis tertentu (misalnya hanya
+	// akhir bulan). Nil berarti setiap hari.
+	Due func(tanggal time.Time) bool
+
+	// Process memproses satu batch nasabah dan mengembalikan jumlah baris yang diproses
+	Process func(tx *sql.Tx, tanggal time.Time, ids []int) (int, error)
+}
+
+// Order mengurutkan step sesuai dependensinya (topological sort). Step tanpa
+// hubungan dependensi diurutkan berdasarkan nama agar urutan selalu sama.
+func Order(steps []Step) ([]Step, error) {
+	byName := make(map[string]Step, len(steps))
+	for _, s := range steps {
+		if _, dup := byName[s.Name]; dup {
+			return nil, fmt.Errorf("step EOD %q terdaftar dua kali", s.Name)
+		}
+		byName[s.Name] = s
+	}
+
+	indegree := make(map[string]int, len(steps))
+	dependents := make(map[string][]string)
+	for _, s := range steps {
+		indegree[s.Name] += 0
+		for _, dep := range s.After {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("step EOD %q bergantung pada step %q yang tidak terdaftar", s.Name, dep)
+			}
+			indegree[s.Name]++
+			dependents[dep] = append(dependents[dep], s.Name)
+		}
+	}
+
+	var ready []string
+	for name, n := range indegree {
+		if n == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	ordered := make([]Step, 0, len(steps))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, byName[name])
+		for _, next := range dependents[name] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	if len(ordered) != len(steps) {
+		return nil, fmt.Errorf("dependensi step EOD membentuk siklus")
+	}
+	return ordered, nil
+}
@@ -0,0 +1,163 @@
+package eod
+
+import (
+	"database/sql"
+	"fmt"
+	"golang-echo-postgresql/backoffice"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/repositories"
+	"math"
+	"time"
+)
+
+// Nama step bawaan
+const (
+	StepSaldoHarian  = "saldo_harian"
+	StepAkrualBunga  = "akrual_bunga"
+	StepKapitalisasi = "kapitalisasi_bunga"
+	StepBiayaAdmin   = "biaya_admin"
+	StepDormansi     = "dormansi"
+)
+
+// Settings mengatur step bawaan
+type Settings struct {
+	SukuBunga      float64 // suku bunga tabungan per tahun, misalnya 0.01 untuk 1%
+	BiayaAdmin     float64 // biaya administrasi bulanan; 0 mematikan step biaya
+	DormansiPeriod int     // jumlah hari tanpa setor, tarik atau transfer sebelum rekening ditandai dorman
+}
+
+// DefaultSteps mengembalikan step bawaan EOD:
+//
+//	saldo_harian -> akrual_bunga -> kapitalisasi_bunga -> biaya_admin
+//	saldo_harian -> dormansi
+//
+// Kapitalisasi bunga dan biaya administrasi hanya berjalan di akhir bulan. Produk
+// berjangka (deposito) belum ada, sehingga belum ada step jatuh tempo; step tersebut
+// didaftarkan lewat Service.Register saat produknya tersedia.
+func DefaultSteps(settings Settings) []Step {
+	return []Step{
+		{
+			Name:    StepSaldoHarian,
+			Process: repositories.SnapshotSaldoHarian,
+		},
+		{
+			Name:  StepAkrualBunga,
+			After: []string{StepSaldoHarian},
+			Due:   func(time.Time) bool { return settings.SukuBunga > 0 },
+			Process: func(tx *sql.Tx, tanggal time.Time, ids []int) (int, error) {
+				return repositories.AccrueBungaHarian(tx, tanggal, ids, settings.SukuBunga)
+			},
+		},
+		{
+			Name:    StepKapitalisasi,
+			After:   []string{StepAkrualBunga},
+			Due:     isMonthEnd,
+			Process: kapitalisasiBunga,
+		},
+		{
+			Name:  StepBiayaAdmin,
+			After: []string{StepKapitalisasi},
+			Due:   func(tanggal time.Time) bool { return settings.BiayaAdmin > 0 && isMonthEnd(tanggal) },
+			Process: func(tx *sql.Tx, tanggal time.Time, ids []int) (int, error) {
+				return biayaAdmin(tx, tanggal, ids, settings.BiayaAdmin)
+			},
+		},
+		{
+			Name:  StepDormansi,
+			After: []string{StepSaldoHarian},
+			Due:   func(time.Time) bool { return settings.DormansiPeriod > 0 },
+			Process: func(tx *sql.Tx, tanggal time.Time, ids []int) (int, error) {
+				batas := tanggal.AddDate(0, 0, 1-settings.DormansiPeriod)
+				return repositories.UpdateDormansi(tx, tanggal, batas, ids)
+			},
+		},
+	}
+}
+
+// kapitalisasiBunga membukukan akumulasi bunga harian bulan berjalan ke saldo nasabah.
+// Bunga di bawah satu sen dibawa ke bulan berikutnya.
+func kapitalisasiBunga(tx *sql.Tx, tanggal time.Time, ids []int) (int, error) {
+	terutang, err := repositories.ListUnpostedBunga(tx, tanggal, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, b := range terutang {
+		if b.Total < 0.01 {
+			continue
+		}
+		alasan := fmt.Sprintf("Bunga tabungan %s", tanggal.Format("2006-01"))
+		t, err := post(tx, b.NasabahID, b.NoRekening, models.JenisBunga, b.Total, alasan)
+		if err != nil {
+			return 0, err
+		}
+		if err := repositories.MarkBungaPosted(tx, b.NasabahID, tanggal, t.ID); err != nil {
+			return 0, err
+		}
+		posted++
+	}
+	return posted, nil
+}
+
+// biayaAdmin membebankan biaya administrasi bulanan. Biaya tidak pernah membuat saldo
+// negatif; rekening dengan saldo kurang dari biaya dibebani sebesar saldonya.
+func biayaAdmin(tx *sql.Tx, tanggal time.Time, ids []int, biaya float64) (int, error) {
+	nasabah, err := repositories.ListNasabahByIDs(tx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	charged := 0
+	for _, n := range nasabah {
+		nominal := math.Min(biaya, n.Saldo)
+		if nominal < 0.01 {
+			continue
+		}
+		alasan := fmt.Sprintf("Biaya administrasi %s", tanggal.Format("2006-01"))
+		if _, err := post(tx, n.ID, n.NoRekening, models.JenisBiayaAdmin, nominal, alasan); err != nil {
+			return 0, err
+		}
+		charged++
+	}
+	return charged, nil
+}
+
+func post(tx *sql.Tx, nasabahID int, noRekening, jenis string, nominal float64, alasan string) (*models.Tabungan, error) {
+	if err := repositories.UpdateSaldo(tx, noRekening, jenis, nominal); err != nil {
+		return nil, err
+	}
+	t := &models.Tabungan{
+		NasabahID:      nasabahID,
+		JenisTransaksi: jenis,
+		Nominal:        nominal,
+		Keterangan:     alasan,
+		Channel:        models.ChannelSistem,
+	}
+	if err := repositories.InsertTabunganDetail(tx, t); err != nil {
+		return nil, err
+	}
+
+	saldo, err := repositories.GetSaldo(tx, noRekening)
+	if err != nil {
+		return nil, err
+	}
+	arah := backoffice.ArahKredit
+	if !models.IsKredit(jenis) {
+		arah = backoffice.ArahDebit
+	}
+	err = outbox.Enqueue(tx, outbox.BalanceAdjustedV1{
+		NoRekening: noRekening,
+		Arah:       arah,
+		Nominal:    nominal,
+		SaldoAkhir: saldo,
+		Alasan:     alasan,
+		OccurredAt: time.Now(),
+	})
+	return t, err
+}
+
+func isMonthEnd(tanggal time.Time) bool {
+	return tanggal.AddDate(0, 0, 1).Day() == 1
+}
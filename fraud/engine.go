@@ -0,0 +1,324 @@
+package fraud
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// HeaderStepUpPIN dipakai klien untuk menjawab challenge dengan PIN nasabah
+const HeaderStepUpPIN = "X-Step-Up-Pin"
+
+// Channel asal transaksi
+const (
+	ChannelApp     = models.ChannelApp
+	ChannelTeller  = models.ChannelTeller
+	ChannelPartner = models.ChannelPartner
+)
+
+// Transaction adalah transaksi yang dinilai mesin aturan
+type Transaction struct {
+	NasabahID  int
+	NoRekening string
+	Jenis      string
+	Nominal    float64
+	Principal  *auth.Principal
+}
+
+// Decision adalah hasil penilaian satu transaksi
+type Decision struct {
+	ID           int64                    `json:"id"`
+	Outcome      string                   `json:"outcome"`
+	Results      []models.FraudRuleResult `json:"rules"`
+	Version      string                   `json:"rules_version"`
+	StepUpPassed bool                     `json:"step_up_passed"`
+}
+
+// Blocked mengembalikan true jika transaksi harus ditolak
+func (d *Decision) Blocked() bool {
+	return d.Outcome == models.FraudBlock
+}
+
+// NeedsStepUp mengembalikan true jika transaksi di-challenge dan PIN belum diverifikasi
+func (d *Decision) NeedsStepUp() bool {
+	return d.Outcome == models.FraudChallenge && !d.StepUpPassed
+}
+
+// Engine menilai transaksi terhadap aturan fraud dari file JSON. File diperiksa ulang
+// secara berkala dan aturan baru langsung dipakai tanpa restart; jika file baru tidak
+// valid, aturan lama tetap berlaku.
+type Engine struct {
+	DB             *sql.DB
+	Path           string
+	ReloadInterval time.Duration
+
+	mu      sync.RWMutex
+	rules   *RuleSet
+	modTime time.Time
+}
+
+// NewEngine membuat Engine dan memuat file aturan. File yang tidak ada menghasilkan
+// rule set kosong (semua transaksi diizinkan) agar lingkungan pengembangan tetap jalan.
+func NewEngine(db *sql.DB, path string, reloadInterval time.Duration) (*Engine, error) {
+	e := &Engine{DB: db, Path: path, ReloadInterval: reloadInterval, rules: &RuleSet{Version: "empty"}}
+	if _, err := e.Reload(); err != nil {
+		if os.IsNotExist(err) {
+			log.WithFields(log.Fields{
+				"path": path,
+			}).Warn("Fraud rules file not found, fraud screening allows everything")
+			return e, nil
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// Rules mengembalikan rule set yang sedang berlaku
+func (e *Engine) Rules() *RuleSet {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.rules
+}
+
+// Reload memuat ulang file aturan jika berubah sejak pemuatan terakhir
+func (e *Engine) Reload() (bool, error) {
+	info, err := os.Stat(e.Path)
+	if err != nil {
+		return false, err
+	}
+
+	e.mu.RLock()
+	unchanged := info.ModTime().Equal(e.modTime)
+	e.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(e.Path)
+	if err != nil {
+		return false, err
+	}
+	set, err := ParseRuleSet(data)
+	if err != nil {
+		return false, err
+	}
+
+	e.mu.Lock()
+	e.rules = set
+	e.modTime = info.ModTime()
+	e.mu.Unlock()
+
+	log.WithFields(log.Fields{
+		"path":    e.Path,
+		"version": set.Version,
+		"rules":   len(set.Rules),
+	}).Info("Fraud rules loaded")
+	return true, nil
+}
+
+// Run memeriksa perubahan file aturan secara berkala sampai ctx dibatalkan
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := e.Reload(); err != nil && !os.IsNotExist(err) {
+				log.WithFields(log.Fields{
+					"error": err,
+					"path":  e.Path,
+				}).Error("Failed to reload fraud rules, keeping previous rules")
+			}
+		}
+	}
+}
+
+// Screen menilai transaksi di dalam transaksi database yang sama dengan perubahan
+// saldo, lalu mencatat keputusannya. Baris nasabah dikunci lebih dulu agar dua
+// transaksi bersamaan tidak sama-sama lolos aturan velocity. Jika transaksi di-challenge
+// dan stepUpPIN cocok dengan PIN nasabah, challenge dianggap terjawab.
+//
+// Keputusan dicatat lewat koneksi terpisah sehingga tetap tersimpan walaupun
+// transaksi yang diblokir di-rollback.
+func (e *Engine) Screen(tx *sql.Tx, txn Transaction, stepUpPIN string) (*Decision, error) {
+	if err := repositories.LockNasabah(tx, txn.NasabahID); err != nil {
+		return nil, err
+	}
+
+	decision, err := e.evaluate(tx, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	if decision.Outcome == models.FraudChallenge && stepUpPIN != "" {
+		_, pinHash, err := repositories.GetNasabahPINHash(tx, txn.NoRekening)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		decision.StepUpPassed = auth.CheckSecret(pinHash, stepUpPIN)
+	}
+
+	if err := e.record(txn, decision); err != nil {
+		return nil, err
+	}
+
+	if decision.Outcome != models.FraudAllow {
+		log.WithFields(log.Fields{
+			"DecisionID":   decision.ID,
+			"NoRekening":   txn.NoRekening,
+			"jenis":        txn.Jenis,
+			"Nominal":      txn.Nominal,
+			"outcome":      decision.Outcome,
+			"StepUpPassed": decision.StepUpPassed,
+		}).Warn("Transaction flagged by fraud rules")
+	}
+	return decision, nil
+}
+
+func (e *Engine) evaluate(tx *sql.Tx, txn Transaction) (*Decision, error) {
+	set := e.Rules()
+	decision := &Decision{Outcome: models.FraudAllow, Version: set.Version, Results: []models.FraudRuleResult{}}
+	now := time.Now()
+
+	for _, rule := range set.Rules {
+		if !rule.appliesTo(txn.Jenis) {
+			continue
+		}
+
+		matched, reason, err := e.check(tx, rule, txn, now)
+		if err != nil {
+			return nil, fmt.Errorf("aturan %s: %w", rule.ID, err)
+		}
+
+		result := models.FraudRuleResult{RuleID: rule.ID, Type: rule.Type, Matched: matched}
+		if matched {
+			result.Outcome = rule.Outcome
+			result.Reason = reason
+			if severity[rule.Outcome] > severity[decision.Outcome] {
+				decision.Outcome = rule.Outcome
+			}
+		}
+		decision.Results = append(decision.Results, result)
+	}
+	return decision, nil
+}
+
+func (e *Engine) check(tx *sql.Tx, rule Rule, txn Transaction, now time.Time) (bool, string, error) {
+	switch rule.Type {
+	case RuleVelocity:
+		count, err := repositories.CountTabunganSince(tx, txn.NasabahID, rule.jenis(txn.Jenis), now.Add(-time.Duration(rule.Window)))
+		if err != nil {
+			return false, "", err
+		}
+		// Transaksi yang sedang dinilai ikut dihitung
+		if count+1 > rule.Count {
+			return true, fmt.Sprintf("%d transaksi dalam %s (maksimal %d)", count+1, time.Duration(rule.Window), rule.Count), nil
+		}
+
+	case RuleAmountAnomaly:
+		if txn.Nominal < rule.MinNominal {
+			return false, "", nil
+		}
+		count, avg, err := repositories.GetTabunganNominalStats(tx, txn.NasabahID, rule.jenis(txn.Jenis), now.Add(-time.Duration(rule.Lookback)))
+		if err != nil {
+			return false, "", err
+		}
+		if count >= rule.MinHistory && avg > 0 && txn.Nominal > rule.Multiplier*avg {
+			return true, fmt.Sprintf("nominal %.2f lebih dari %.1fx rata-rata %.2f", txn.Nominal, rule.Multiplier, avg), nil
+		}
+
+	case RuleNewAccountCashOut:
+		if txn.Nominal < rule.MinNominal {
+			return false, "", nil
+		}
+		createdAt, err := repositories.GetNasabahCreatedAt(tx, txn.NasabahID)
+		if err != nil {
+			return false, "", err
+		}
+		if age := now.Sub(createdAt); age < time.Duration(rule.AccountAge) {
+			return true, fmt.Sprintf("rekening berumur %s menarik %.2f", age.Round(time.Minute), txn.Nominal), nil
+		}
+
+	case RuleRoundAmount:
+		if txn.Nominal < rule.MinNominal || !isMultiple(txn.Nominal, rule.RoundTo) {
+			return false, "", nil
+		}
+		count, err := repositories.CountRoundTabunganSince(tx, txn.NasabahID, rule.jenis(txn.Jenis), now.Add(-time.Duration(rule.Window)), rule.RoundTo, rule.MinNominal)
+		if err != nil {
+			return false, "", err
+		}
+		if count+1 >= rule.Count {
+			return true, fmt.Sprintf("%d transaksi bulat kelipatan %.0f dalam %s", count+1, rule.RoundTo, time.Duration(rule.Window)), nil
+		}
+	}
+	return false, "", nil
+}
+
+func (e *Engine) record(txn Transaction, decision *Decision) error {
+	rules, err := json.Marshal(decision.Results)
+	if err != nil {
+		return err
+	}
+
+	row := &models.FraudDecision{
+		NoRekening:     txn.NoRekening,
+		JenisTransaksi: txn.Jenis,
+		Nominal:        txn.Nominal,
+		Channel:        ChannelOf(txn.Principal),
+		Outcome:        decision.Outcome,
+		Rules:          rules,
+		RulesVersion:   decision.Version,
+		StepUpPassed:   decision.StepUpPassed,
+	}
+	row.ActorType, row.ActorID = actor(txn.Principal)
+	if decision.Outcome != models.FraudAllow {
+		row.ReviewStatus = models.FraudReviewOpen
+	}
+
+	if err := repositories.InsertFraudDecision(e.DB, row); err != nil {
+		return err
+	}
+	decision.ID = row.ID
+	return nil
+}
+
+// ChannelOf menentukan channel transaksi dari principal yang menjalankannya
+func ChannelOf(p *auth.Principal) string {
+	switch {
+	case p == nil:
+		return ChannelApp
+	case p.Type == auth.SubjectPartner:
+		return ChannelPartner
+	case p.IsStaff():
+		return ChannelTeller
+	}
+	return ChannelApp
+}
+
+func actor(p *auth.Principal) (string, string) {
+	if p == nil {
+		return "anonymous", "-"
+	}
+	if p.Type == auth.SubjectPartner {
+		return p.Type, p.ClientID
+	}
+	return p.Type, strconv.Itoa(p.ID)
+}
+
+func isMultiple(nominal, roundTo float64) bool {
+	q := nominal / roundTo
+	return q == float64(int64(q))
+}
@@ -0,0 +1,61 @@
+package fraud
+
+import (
+	"database/sql"
+	"errors"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrDecisionNotFound = errors.New("keputusan fraud tidak ditemukan")
+	ErrAlreadyReviewed  = errors.New("keputusan fraud tidak menunggu review")
+	ErrInvalidReview    = errors.New("status review tidak valid")
+)
+
+// Flagged mengambil transaksi yang ditandai aturan fraud untuk direview analis
+func (e *Engine) Flagged(filter models.FraudDecisionFilter) ([]models.FraudDecision, error) {
+	return repositories.ListFraudDecisions(e.DB, filter)
+}
+
+// Decision mengambil satu keputusan fraud
+func (e *Engine) Decision(id int64) (*models.FraudDecision, error) {
+	d, err := repositories.GetFraudDecision(e.DB, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrDecisionNotFound
+	}
+	return d, err
+}
+
+// Review menyimpan kesimpulan analis atas transaksi yang ditandai
+func (e *Engine) Review(reviewer *auth.Principal, id int64, req models.FraudReviewRequest) (*models.FraudDecision, error) {
+	if req.Status != models.FraudReviewConfirmedFraud && req.Status != models.FraudReviewFalsePositive {
+		return nil, ErrInvalidReview
+	}
+
+	d, err := e.Decision(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := repositories.UpdateFraudReview(e.DB, id, req.Status, reviewer.ID, req.Catatan, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrAlreadyReviewed
+	}
+
+	log.WithFields(log.Fields{
+		"DecisionID": id,
+		"NoRekening": d.NoRekening,
+		"status":     req.Status,
+		"ReviewerID": reviewer.ID,
+	}).Info("Fraud decision reviewed")
+
+	return e.Decision(id)
+}
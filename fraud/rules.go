@@ -0,0 +1,149 @@
+package fraud
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"time"
+)
+
+// Tipe aturan yang didukung
+const (
+	RuleVelocity          = "velocity"            // lebih dari Count transaksi dalam Window
+	RuleAmountAnomaly     = "amount_anomaly"      // nominal > Multiplier x rata-rata riwayat selama Lookback
+	RuleNewAccountCashOut = "new_account_cashout" // rekening lebih muda dari AccountAge menarik >= MinNominal
+	RuleRoundAmount       = "round_amount"        // Count transaksi bernominal bulat (kelipatan RoundTo) dalam Window
+)
+
+var severity = map[string]int{
+	models.FraudAllow:     0,
+	models.FraudChallenge: 1,
+	models.FraudBlock:     2,
+}
+
+// Duration adalah time.Duration yang ditulis sebagai string ("10m", "72h") di file aturan
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Rule adalah satu aturan deklaratif dari file aturan
+type Rule struct {
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	Jenis      []string `json:"jenis"` // kosong berarti semua jenis transaksi
+	Outcome    string   `json:"outcome"`
+	Disabled   bool     `json:"disabled,omitempty"`
+	Count      int      `json:"count,omitempty"`
+	Window     Duration `json:"window,omitempty"`
+	Multiplier float64  `json:"multiplier,omitempty"`
+	Lookback   Duration `json:"lookback,omitempty"`
+	MinHistory int      `json:"min_history,omitempty"`
+	MinNominal float64  `json:"min_nominal,omitempty"`
+	AccountAge Duration `json:"account_age,omitempty"`
+	RoundTo    float64  `json:"round_to,omitempty"`
+}
+
+// RuleSet adalah isi file aturan. Version dihitung dari isi file sehingga setiap
+// keputusan di log bisa ditelusuri ke versi aturan yang dipakai.
+type RuleSet struct {
+	Version string `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+// ParseRuleSet membaca dan memvalidasi file aturan
+func ParseRuleSet(data []byte) (*RuleSet, error) {
+	var set RuleSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("file aturan fraud tidak valid: %v", err)
+	}
+
+	var errs []error
+	seen := map[string]bool{}
+	for i, rule := range set.Rules {
+		if rule.ID == "" {
+			errs = append(errs, fmt.Errorf("aturan #%d: id wajib diisi", i+1))
+			continue
+		}
+		if seen[rule.ID] {
+			errs = append(errs, fmt.Errorf("aturan %s: id duplikat", rule.ID))
+		}
+		seen[rule.ID] = true
+		if err := rule.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("aturan %s: %v", rule.ID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sum := sha256.Sum256(data)
+	set.Version = hex.EncodeToString(sum[:6])
+	return &set, nil
+}
+
+func (r Rule) validate() error {
+	if _, ok := severity[r.Outcome]; !ok || r.Outcome == models.FraudAllow {
+		return fmt.Errorf("outcome harus %q atau %q", models.FraudChallenge, models.FraudBlock)
+	}
+	switch r.Type {
+	case RuleVelocity:
+		if r.Count <= 0 || r.Window <= 0 {
+			return errors.New("count dan window wajib diisi")
+		}
+	case RuleAmountAnomaly:
+		if r.Multiplier <= 1 || r.Lookback <= 0 {
+			return errors.New("multiplier (> 1) dan lookback wajib diisi")
+		}
+	case RuleNewAccountCashOut:
+		if r.AccountAge <= 0 || r.MinNominal <= 0 {
+			return errors.New("account_age dan min_nominal wajib diisi")
+		}
+	case RuleRoundAmount:
+		if r.RoundTo <= 0 || r.Count <= 0 || r.Window <= 0 {
+			return errors.New("round_to, count dan window wajib diisi")
+		}
+	default:
+		return fmt.Errorf("tipe %q tidak dikenal", r.Type)
+	}
+	return nil
+}
+
+func (r Rule) appliesTo(jenis string) bool {
+	if r.Disabled {
+		return false
+	}
+	if len(r.Jenis) == 0 {
+		return true
+	}
+	for _, j := range r.Jenis {
+		if j == jenis {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) jenis(current string) []string {
+	if len(r.Jenis) == 0 {
+		return []string{current}
+	}
+	return r.Jenis
+}
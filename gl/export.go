@@ -0,0 +1,55 @@
+package gl
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"golang-echo-postgresql/eod"
+	"golang-echo-postgresql/models"
+	"io"
+	"strconv"
+)
+
+// Format ekspor voucher jurnal
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// WriteCSV menulis voucher sebagai CSV dengan satu baris per baris jurnal, format yang
+// umum diterima fitur impor jurnal sistem akuntansi
+func WriteCSV(w io.Writer, vouchers []models.GLVoucher) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"tanggal", "nomor", "akun", "nama_akun", "debit", "kredit", "keterangan"}); err != nil {
+		return err
+	}
+	for _, v := range vouchers {
+		keterangan := fmt.Sprintf("%s %d transaksi", v.JenisTransaksi, v.JumlahTransaksi)
+		if v.Channel != "" {
+			keterangan = fmt.Sprintf("%s %s %d transaksi", v.JenisTransaksi, v.Channel, v.JumlahTransaksi)
+		}
+		for _, line := range v.Lines {
+			err := writer.Write([]string{
+				v.Tanggal.Format(eod.DateLayout),
+				v.Nomor,
+				line.Akun,
+				line.NamaAkun,
+				strconv.FormatFloat(line.Debit, 'f', 2, 64),
+				strconv.FormatFloat(line.Kredit, 'f', 2, 64),
+				keterangan,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON menulis voucher beserta baris jurnalnya sebagai JSON
+func WriteJSON(w io.Writer, vouchers []models.GLVoucher) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(vouchers)
+}
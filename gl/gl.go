@@ -0,0 +1,241 @@
+package gl
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/eod"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// StepJurnalGL adalah nama step EOD yang membentuk voucher jurnal harian
+const StepJurnalGL = "jurnal_gl"
+
+var (
+	ErrNoMapping = errors.New("pemetaan GL belum dikonfigurasi")
+	ErrUnmapped  = errors.New("jenis transaksi belum dipetakan ke akun GL")
+	ErrNotClosed = errors.New("tanggal bisnis belum ditutup")
+)
+
+// Service membentuk voucher jurnal harian dari transaksi tabungan sesuai pemetaan GL,
+// mengekspornya untuk sistem akuntansi dan menyusun neraca saldo
+type Service struct {
+	DB      *sql.DB
+	Mapping *Mapping
+}
+
+// NewService memuat file pemetaan GL. Jika file tidak ada, jurnal GL dinonaktifkan.
+func NewService(db *sql.DB, path string) (*Service, error) {
+	s := &Service{DB: db}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.WithFields(log.Fields{
+			"path": path,
+		}).Warn("GL mapping file not found, GL journal is disabled")
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.Mapping, err = ParseMapping(data)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Enabled mengembalikan true jika pemetaan GL tersedia
+func (s *Service) Enabled() bool {
+	return s.Mapping != nil
+}
+
+// Step mengembalikan step EOD jurnal GL. Voucher dijumlahkan per batch nasabah sehingga
+// ikut checkpoint EOD, dan tanggal yang sudah ditutup tidak pernah dijurnal ulang.
+func (s *Service) Step() eod.Step {
+	return eod.Step{
+		Name:    StepJurnalGL,
+		After:   []string{eod.StepSaldoHarian},
+		Process: s.journal,
+	}
+}
+
+func (s *Service) journal(tx *sql.Tx, tanggal time.Time, ids []int) (int, error) {
+	movements, err := repositories.AggregateGLMovements(tx, tanggal, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	// Hari pertama jurnal: saldo tabungan yang sudah ada dibukukan sebagai saldo awal
+	started, err := repositories.HasGLVouchersBefore(tx, tanggal)
+	if err != nil {
+		return 0, err
+	}
+	if !started {
+		jumlah, total, err := repositories.SumSaldoAwal(tx, tanggal, ids)
+		if err != nil {
+			return 0, err
+		}
+		if total != 0 {
+			movements = append(movements, repositories.GLMovement{Jenis: JenisSaldoAwal, Jumlah: jumlah, Total: total})
+		}
+	}
+
+	processed := 0
+	for _, m := range movements {
+		entry, ok := s.Mapping.Lookup(m.Jenis, m.Channel)
+		if !ok {
+			return 0, fmt.Errorf("%w: %s/%s", ErrUnmapped, m.Jenis, m.Channel)
+		}
+		err := repositories.AddGLVoucher(tx, &models.GLVoucher{
+			Tanggal:         tanggal,
+			Nomor:           voucherNumber(tanggal, m.Jenis, m.Channel),
+			JenisTransaksi:  m.Jenis,
+			Channel:         m.Channel,
+			AkunDebit:       entry.Debit,
+			AkunKredit:      entry.Kredit,
+			JumlahTransaksi: m.Jumlah,
+			Total:           m.Total,
+			MappingVersion:  s.Mapping.Version,
+		})
+		if err != nil {
+			return 0, err
+		}
+		if m.Jenis != JenisSaldoAwal {
+			processed += m.Jumlah
+		}
+	}
+	return processed, nil
+}
+
+// Vouchers mengambil voucher jurnal satu tanggal bisnis yang sudah ditutup, lengkap
+// dengan baris debit dan kredit
+func (s *Service) Vouchers(tanggal time.Time) ([]models.GLVoucher, error) {
+	if err := s.checkClosed(tanggal); err != nil {
+		return nil, err
+	}
+	vouchers, err := repositories.ListGLVouchers(s.DB, tanggal)
+	if err != nil {
+		return nil, err
+	}
+	for i := range vouchers {
+		v := &vouchers[i]
+		v.Lines = []models.GLVoucherLine{
+			{Akun: v.AkunDebit, NamaAkun: s.accountName(v.AkunDebit), Debit: v.Total},
+			{Akun: v.AkunKredit, NamaAkun: s.accountName(v.AkunKredit), Kredit: v.Total},
+		}
+	}
+	return vouchers, nil
+}
+
+// TrialBalance menyusun neraca saldo kumulatif sampai tanggal bisnis yang sudah ditutup
+// dan memeriksa bahwa saldo akun tabungan nasabah sama dengan jumlah nasabah.saldo
+// pada akhir tanggal tersebut
+func (s *Service) TrialBalance(tanggal time.Time) (*models.TrialBalance, error) {
+	if !s.Enabled() {
+		return nil, ErrNoMapping
+	}
+	if err := s.checkClosed(tanggal); err != nil {
+		return nil, err
+	}
+
+	totals, err := repositories.SumGLByAccount(s.DB, tanggal)
+	if err != nil {
+		return nil, err
+	}
+	saldoNasabah, err := repositories.SumSaldoNasabahAsOf(s.DB, tanggal)
+	if err != nil {
+		return nil, err
+	}
+
+	tb := &models.TrialBalance{
+		Tanggal: tanggal.Format(eod.DateLayout),
+		Akun:    []models.TrialBalanceLine{},
+	}
+	for _, t := range totals {
+		account, _ := s.Mapping.Account(t.Akun)
+		saldo := t.Kredit - t.Debit
+		if account.NormalDebit() {
+			saldo = t.Debit - t.Kredit
+		}
+		tb.Akun = append(tb.Akun, models.TrialBalanceLine{
+			Kode:   t.Akun,
+			Nama:   account.Nama,
+			Jenis:  account.Jenis,
+			Debit:  round(t.Debit),
+			Kredit: round(t.Kredit),
+			Saldo:  round(saldo),
+		})
+		tb.TotalDebit += t.Debit
+		tb.TotalKredit += t.Kredit
+		if t.Akun == s.Mapping.AkunTabungan {
+			tb.Tabungan.SaldoGL = saldo
+		}
+	}
+	sort.Slice(tb.Akun, func(i, j int) bool { return tb.Akun[i].Kode < tb.Akun[j].Kode })
+
+	tb.TotalDebit = round(tb.TotalDebit)
+	tb.TotalKredit = round(tb.TotalKredit)
+	tb.Seimbang = math.Abs(tb.TotalDebit-tb.TotalKredit) < 0.005
+	tb.Tabungan = models.LiabilityCheck{
+		Akun:         s.Mapping.AkunTabungan,
+		SaldoGL:      round(tb.Tabungan.SaldoGL),
+		SaldoNasabah: round(saldoNasabah),
+		Selisih:      round(tb.Tabungan.SaldoGL - saldoNasabah),
+	}
+	tb.Tabungan.Cocok = math.Abs(tb.Tabungan.Selisih) < 0.005
+
+	if !tb.Seimbang || !tb.Tabungan.Cocok {
+		log.WithFields(log.Fields{
+			"tanggal":      tb.Tanggal,
+			"TotalDebit":   tb.TotalDebit,
+			"TotalKredit":  tb.TotalKredit,
+			"SaldoGL":      tb.Tabungan.SaldoGL,
+			"SaldoNasabah": tb.Tabungan.SaldoNasabah,
+		}).Warn("GL trial balance does not reconcile")
+	}
+	return tb, nil
+}
+
+func (s *Service) checkClosed(tanggal time.Time) error {
+	run, err := repositories.GetEODRun(s.DB, tanggal)
+	if err == sql.ErrNoRows {
+		return ErrNotClosed
+	}
+	if err != nil {
+		return err
+	}
+	if run.Status != models.EODCompleted {
+		return ErrNotClosed
+	}
+	return nil
+}
+
+func (s *Service) accountName(kode string) string {
+	if s.Mapping == nil {
+		return ""
+	}
+	account, _ := s.Mapping.Account(kode)
+	return account.Nama
+}
+
+// voucherNumber membentuk nomor voucher yang tetap untuk tanggal, jenis dan channel,
+// misalnya JV-20240131-SETOR-TELLER
+func voucherNumber(tanggal time.Time, jenis, channel string) string {
+	nomor := "JV-" + tanggal.Format("20060102") + "-" + strings.ToUpper(jenis)
+	if channel != "" {
+		nomor += "-" + strings.ToUpper(channel)
+	}
+	return nomor
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
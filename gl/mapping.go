@@ -0,0 +1,144 @@
+package gl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/models"
+)
+
+// JenisSaldoAwal adalah jenis voucher untuk saldo awal tabungan pada hari pertama jurnal GL
+const JenisSaldoAwal = "saldo_awal"
+
+// AnyChannel pada entri pemetaan berlaku untuk semua channel yang tidak punya entri sendiri
+const AnyChannel = "*"
+
+var jenisTransaksi = map[string]bool{
+	models.JenisSetor:          true,
+	models.JenisTarik:          true,
+	models.JenisKoreksiKredit:  true,
+	models.JenisKoreksiDebit:   true,
+	models.JenisBunga:          true,
+	models.JenisBiayaAdmin:     true,
+	models.JenisTransferKeluar: true,
+	models.JenisTransferMasuk:  true,
+	JenisSaldoAwal:             true,
+}
+
+var jenisAkun = map[string]bool{
+	models.AkunAset:       true,
+	models.AkunLiabilitas: true,
+	models.AkunEkuitas:    true,
+	models.AkunPendapatan: true,
+	models.AkunBeban:      true,
+}
+
+// Entry memetakan satu jenis transaksi dan channel ke akun debit dan kredit
+type Entry struct {
+	Jenis   string `json:"jenis"`
+	Channel string `json:"channel"`
+	Debit   string `json:"debit"`
+	Kredit  string `json:"kredit"`
+}
+
+// Mapping adalah isi file pemetaan GL: bagan akun, pemetaan transaksi ke akun dan akun
+// liabilitas tabungan nasabah. Version dihitung dari isi file dan disimpan di setiap voucher.
+type Mapping struct {
+	Version      string             `json:"version"`
+	AkunTabungan string             `json:"akun_tabungan"`
+	Accounts     []models.GLAccount `json:"accounts"`
+	Entries      []Entry            `json:"entries"`
+}
+
+// ParseMapping membaca dan memvalidasi file pemetaan GL
+func ParseMapping(data []byte) (*Mapping, error) {
+	var m Mapping
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("file pemetaan GL tidak valid: %v", err)
+	}
+
+	var errs []error
+	accounts := map[string]bool{}
+	for i, a := range m.Accounts {
+		if a.Kode == "" {
+			errs = append(errs, fmt.Errorf("akun #%d: kode wajib diisi", i+1))
+			continue
+		}
+		if accounts[a.Kode] {
+			errs = append(errs, fmt.Errorf("akun %s: kode duplikat", a.Kode))
+		}
+		accounts[a.Kode] = true
+		if !jenisAkun[a.Jenis] {
+			errs = append(errs, fmt.Errorf("akun %s: jenis %q tidak dikenal", a.Kode, a.Jenis))
+		}
+	}
+	if !accounts[m.AkunTabungan] {
+		errs = append(errs, fmt.Errorf("akun_tabungan %q tidak ada di daftar akun", m.AkunTabungan))
+	}
+
+	seen := map[string]bool{}
+	for i, e := range m.Entries {
+		name := fmt.Sprintf("pemetaan #%d (%s/%s)", i+1, e.Jenis, e.Channel)
+		if !jenisTransaksi[e.Jenis] {
+			errs = append(errs, fmt.Errorf("%s: jenis transaksi tidak dikenal", name))
+		}
+		if e.Channel == "" {
+			errs = append(errs, fmt.Errorf("%s: channel wajib diisi, gunakan %q untuk semua channel", name, AnyChannel))
+		}
+		key := e.Jenis + "/" + e.Channel
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("%s: pemetaan duplikat", name))
+		}
+		seen[key] = true
+		if !accounts[e.Debit] || !accounts[e.Kredit] {
+			errs = append(errs, fmt.Errorf("%s: akun debit dan kredit harus ada di daftar akun", name))
+		} else if e.Debit == e.Kredit {
+			errs = append(errs, fmt.Errorf("%s: akun debit dan kredit tidak boleh sama", name))
+		}
+	}
+	for jenis := range jenisTransaksi {
+		if !seen[jenis+"/"+AnyChannel] {
+			errs = append(errs, fmt.Errorf("jenis %s: pemetaan untuk channel %q wajib ada", jenis, AnyChannel))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sum := sha256.Sum256(data)
+	m.Version = hex.EncodeToString(sum[:6])
+	return &m, nil
+}
+
+// Lookup mencari pemetaan untuk jenis transaksi dan channel, dengan pemetaan channel
+// "*" sebagai cadangan
+func (m *Mapping) Lookup(jenis, channel string) (Entry, bool) {
+	var fallback *Entry
+	for i, e := range m.Entries {
+		if e.Jenis != jenis {
+			continue
+		}
+		if e.Channel == channel {
+			return e, true
+		}
+		if e.Channel == AnyChannel {
+			fallback = &m.Entries[i]
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return Entry{}, false
+}
+
+// Account mencari akun berdasarkan kode
+func (m *Mapping) Account(kode string) (models.GLAccount, bool) {
+	for _, a := range m.Accounts {
+		if a.Kode == kode {
+			return a, true
+		}
+	}
+	return models.GLAccount{Kode: kode}, false
+}
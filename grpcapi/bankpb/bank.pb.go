@@ -0,0 +1,1065 @@
+// grpcapi/bankpb/bank.proto
+//
+// API gRPC untuk layanan internal. Semantik dan pemetaan error sama dengan route HTTP:
+// setiap RPC memanggil service yang sama (onboarding dan transaction) dengan handler Echo.
+// Kode dibangkitkan ulang dengan: go generate ./grpcapi/bankpb
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.1
+// 	protoc        v5.28.3
+// source: bank.proto
+
+package bankpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RegisterNasabahRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nama          string                 `protobuf:"bytes,1,opt,name=nama,proto3" json:"nama,omitempty"`
+	Nik           string                 `protobuf:"bytes,2,opt,name=nik,proto3" json:"nik,omitempty"`
+	NoHp          string                 `protobuf:"bytes,3,opt,name=no_hp,json=noHp,proto3" json:"no_hp,omitempty"`
+	Pin           string                 `protobuf:"bytes,4,opt,name=pin,proto3" json:"pin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterNasabahRequest) Reset() {
+	*x = RegisterNasabahRequest{}
+	mi := &file_bank_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterNasabahRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterNasabahRequest) ProtoMessage() {}
+
+func (x *RegisterNasabahRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterNasabahRequest.ProtoReflect.Descriptor instead.
+func (*RegisterNasabahRequest) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterNasabahRequest) GetNama() string {
+	if x != nil {
+		return x.Nama
+	}
+	return ""
+}
+
+func (x *RegisterNasabahRequest) GetNik() string {
+	if x != nil {
+		return x.Nik
+	}
+	return ""
+}
+
+func (x *RegisterNasabahRequest) GetNoHp() string {
+	if x != nil {
+		return x.NoHp
+	}
+	return ""
+}
+
+func (x *RegisterNasabahRequest) GetPin() string {
+	if x != nil {
+		return x.Pin
+	}
+	return ""
+}
+
+type RegisterNasabahResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	NoRekening string                 `protobuf:"bytes,1,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	Status     string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// true jika nasabah cocok dengan watchlist dan rekening ditahan untuk review compliance
+	Held          bool `protobuf:"varint,3,opt,name=held,proto3" json:"held,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterNasabahResponse) Reset() {
+	*x = RegisterNasabahResponse{}
+	mi := &file_bank_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterNasabahResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterNasabahResponse) ProtoMessage() {}
+
+func (x *RegisterNasabahResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterNasabahResponse.ProtoReflect.Descriptor instead.
+func (*RegisterNasabahResponse) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RegisterNasabahResponse) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+func (x *RegisterNasabahResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RegisterNasabahResponse) GetHeld() bool {
+	if x != nil {
+		return x.Held
+	}
+	return false
+}
+
+type DepositRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	NoRekening string                 `protobuf:"bytes,1,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	Nominal    float64                `protobuf:"fixed64,2,opt,name=nominal,proto3" json:"nominal,omitempty"`
+	Referensi  string                 `protobuf:"bytes,3,opt,name=referensi,proto3" json:"referensi,omitempty"`
+	Keterangan string                 `protobuf:"bytes,4,opt,name=keterangan,proto3" json:"keterangan,omitempty"`
+	// PIN nasabah untuk step-up jika aturan fraud meminta verifikasi (header X-Step-Up-Pin)
+	StepUpPin     string `protobuf:"bytes,5,opt,name=step_up_pin,json=stepUpPin,proto3" json:"step_up_pin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepositRequest) Reset() {
+	*x = DepositRequest{}
+	mi := &file_bank_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepositRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositRequest) ProtoMessage() {}
+
+func (x *DepositRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositRequest.ProtoReflect.Descriptor instead.
+func (*DepositRequest) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DepositRequest) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+func (x *DepositRequest) GetNominal() float64 {
+	if x != nil {
+		return x.Nominal
+	}
+	return 0
+}
+
+func (x *DepositRequest) GetReferensi() string {
+	if x != nil {
+		return x.Referensi
+	}
+	return ""
+}
+
+func (x *DepositRequest) GetKeterangan() string {
+	if x != nil {
+		return x.Keterangan
+	}
+	return ""
+}
+
+func (x *DepositRequest) GetStepUpPin() string {
+	if x != nil {
+		return x.StepUpPin
+	}
+	return ""
+}
+
+type WithdrawRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoRekening    string                 `protobuf:"bytes,1,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	Nominal       float64                `protobuf:"fixed64,2,opt,name=nominal,proto3" json:"nominal,omitempty"`
+	Referensi     string                 `protobuf:"bytes,3,opt,name=referensi,proto3" json:"referensi,omitempty"`
+	Keterangan    string                 `protobuf:"bytes,4,opt,name=keterangan,proto3" json:"keterangan,omitempty"`
+	StepUpPin     string                 `protobuf:"bytes,5,opt,name=step_up_pin,json=stepUpPin,proto3" json:"step_up_pin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithdrawRequest) Reset() {
+	*x = WithdrawRequest{}
+	mi := &file_bank_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawRequest) ProtoMessage() {}
+
+func (x *WithdrawRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawRequest.ProtoReflect.Descriptor instead.
+func (*WithdrawRequest) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WithdrawRequest) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+func (x *WithdrawRequest) GetNominal() float64 {
+	if x != nil {
+		return x.Nominal
+	}
+	return 0
+}
+
+func (x *WithdrawRequest) GetReferensi() string {
+	if x != nil {
+		return x.Referensi
+	}
+	return ""
+}
+
+func (x *WithdrawRequest) GetKeterangan() string {
+	if x != nil {
+		return x.Keterangan
+	}
+	return ""
+}
+
+func (x *WithdrawRequest) GetStepUpPin() string {
+	if x != nil {
+		return x.StepUpPin
+	}
+	return ""
+}
+
+type TransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DariRekening  string                 `protobuf:"bytes,1,opt,name=dari_rekening,json=dariRekening,proto3" json:"dari_rekening,omitempty"`
+	KeRekening    string                 `protobuf:"bytes,2,opt,name=ke_rekening,json=keRekening,proto3" json:"ke_rekening,omitempty"`
+	Nominal       float64                `protobuf:"fixed64,3,opt,name=nominal,proto3" json:"nominal,omitempty"`
+	Referensi     string                 `protobuf:"bytes,4,opt,name=referensi,proto3" json:"referensi,omitempty"`
+	Keterangan    string                 `protobuf:"bytes,5,opt,name=keterangan,proto3" json:"keterangan,omitempty"`
+	StepUpPin     string                 `protobuf:"bytes,6,opt,name=step_up_pin,json=stepUpPin,proto3" json:"step_up_pin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_bank_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TransferRequest) GetDariRekening() string {
+	if x != nil {
+		return x.DariRekening
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetKeRekening() string {
+	if x != nil {
+		return x.KeRekening
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetNominal() float64 {
+	if x != nil {
+		return x.Nominal
+	}
+	return 0
+}
+
+func (x *TransferRequest) GetReferensi() string {
+	if x != nil {
+		return x.Referensi
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetKeterangan() string {
+	if x != nil {
+		return x.Keterangan
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetStepUpPin() string {
+	if x != nil {
+		return x.StepUpPin
+	}
+	return ""
+}
+
+type TransactionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// completed atau pending_approval
+	Status     string  `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	TabunganId int64   `protobuf:"varint,2,opt,name=tabungan_id,json=tabunganId,proto3" json:"tabungan_id,omitempty"`
+	NoRekening string  `protobuf:"bytes,3,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	Nominal    float64 `protobuf:"fixed64,4,opt,name=nominal,proto3" json:"nominal,omitempty"`
+	SaldoAwal  float64 `protobuf:"fixed64,5,opt,name=saldo_awal,json=saldoAwal,proto3" json:"saldo_awal,omitempty"`
+	SaldoAkhir float64 `protobuf:"fixed64,6,opt,name=saldo_akhir,json=saldoAkhir,proto3" json:"saldo_akhir,omitempty"`
+	Channel    string  `protobuf:"bytes,7,opt,name=channel,proto3" json:"channel,omitempty"`
+	Referensi  string  `protobuf:"bytes,8,opt,name=referensi,proto3" json:"referensi,omitempty"`
+	// Terisi jika status pending_approval
+	OperationId   int64 `protobuf:"varint,9,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionResponse) Reset() {
+	*x = TransactionResponse{}
+	mi := &file_bank_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionResponse) ProtoMessage() {}
+
+func (x *TransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionResponse.ProtoReflect.Descriptor instead.
+func (*TransactionResponse) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TransactionResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetTabunganId() int64 {
+	if x != nil {
+		return x.TabunganId
+	}
+	return 0
+}
+
+func (x *TransactionResponse) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetNominal() float64 {
+	if x != nil {
+		return x.Nominal
+	}
+	return 0
+}
+
+func (x *TransactionResponse) GetSaldoAwal() float64 {
+	if x != nil {
+		return x.SaldoAwal
+	}
+	return 0
+}
+
+func (x *TransactionResponse) GetSaldoAkhir() float64 {
+	if x != nil {
+		return x.SaldoAkhir
+	}
+	return 0
+}
+
+func (x *TransactionResponse) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetReferensi() string {
+	if x != nil {
+		return x.Referensi
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetOperationId() int64 {
+	if x != nil {
+		return x.OperationId
+	}
+	return 0
+}
+
+type TransferResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Debit            *TransactionResponse   `protobuf:"bytes,1,opt,name=debit,proto3" json:"debit,omitempty"`
+	KeRekening       string                 `protobuf:"bytes,2,opt,name=ke_rekening,json=keRekening,proto3" json:"ke_rekening,omitempty"`
+	KreditTabunganId int64                  `protobuf:"varint,3,opt,name=kredit_tabungan_id,json=kreditTabunganId,proto3" json:"kredit_tabungan_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TransferResponse) Reset() {
+	*x = TransferResponse{}
+	mi := &file_bank_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferResponse) ProtoMessage() {}
+
+func (x *TransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferResponse.ProtoReflect.Descriptor instead.
+func (*TransferResponse) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TransferResponse) GetDebit() *TransactionResponse {
+	if x != nil {
+		return x.Debit
+	}
+	return nil
+}
+
+func (x *TransferResponse) GetKeRekening() string {
+	if x != nil {
+		return x.KeRekening
+	}
+	return ""
+}
+
+func (x *TransferResponse) GetKreditTabunganId() int64 {
+	if x != nil {
+		return x.KreditTabunganId
+	}
+	return 0
+}
+
+type GetSaldoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoRekening    string                 `protobuf:"bytes,1,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSaldoRequest) Reset() {
+	*x = GetSaldoRequest{}
+	mi := &file_bank_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSaldoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSaldoRequest) ProtoMessage() {}
+
+func (x *GetSaldoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSaldoRequest.ProtoReflect.Descriptor instead.
+func (*GetSaldoRequest) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetSaldoRequest) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+type GetSaldoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Saldo         float64                `protobuf:"fixed64,1,opt,name=saldo,proto3" json:"saldo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSaldoResponse) Reset() {
+	*x = GetSaldoResponse{}
+	mi := &file_bank_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSaldoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSaldoResponse) ProtoMessage() {}
+
+func (x *GetSaldoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSaldoResponse.ProtoReflect.Descriptor instead.
+func (*GetSaldoResponse) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetSaldoResponse) GetSaldo() float64 {
+	if x != nil {
+		return x.Saldo
+	}
+	return 0
+}
+
+type GetRiwayatTransaksiRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoRekening    string                 `protobuf:"bytes,1,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRiwayatTransaksiRequest) Reset() {
+	*x = GetRiwayatTransaksiRequest{}
+	mi := &file_bank_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRiwayatTransaksiRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRiwayatTransaksiRequest) ProtoMessage() {}
+
+func (x *GetRiwayatTransaksiRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRiwayatTransaksiRequest.ProtoReflect.Descriptor instead.
+func (*GetRiwayatTransaksiRequest) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetRiwayatTransaksiRequest) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+type Tabungan struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	JenisTransaksi string                 `protobuf:"bytes,2,opt,name=jenis_transaksi,json=jenisTransaksi,proto3" json:"jenis_transaksi,omitempty"`
+	Nominal        float64                `protobuf:"fixed64,3,opt,name=nominal,proto3" json:"nominal,omitempty"`
+	Keterangan     string                 `protobuf:"bytes,4,opt,name=keterangan,proto3" json:"keterangan,omitempty"`
+	RefTabunganId  int64                  `protobuf:"varint,5,opt,name=ref_tabungan_id,json=refTabunganId,proto3" json:"ref_tabungan_id,omitempty"`
+	Channel        string                 `protobuf:"bytes,6,opt,name=channel,proto3" json:"channel,omitempty"`
+	Referensi      string                 `protobuf:"bytes,7,opt,name=referensi,proto3" json:"referensi,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Tabungan) Reset() {
+	*x = Tabungan{}
+	mi := &file_bank_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tabungan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tabungan) ProtoMessage() {}
+
+func (x *Tabungan) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tabungan.ProtoReflect.Descriptor instead.
+func (*Tabungan) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Tabungan) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Tabungan) GetJenisTransaksi() string {
+	if x != nil {
+		return x.JenisTransaksi
+	}
+	return ""
+}
+
+func (x *Tabungan) GetNominal() float64 {
+	if x != nil {
+		return x.Nominal
+	}
+	return 0
+}
+
+func (x *Tabungan) GetKeterangan() string {
+	if x != nil {
+		return x.Keterangan
+	}
+	return ""
+}
+
+func (x *Tabungan) GetRefTabunganId() int64 {
+	if x != nil {
+		return x.RefTabunganId
+	}
+	return 0
+}
+
+func (x *Tabungan) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *Tabungan) GetReferensi() string {
+	if x != nil {
+		return x.Referensi
+	}
+	return ""
+}
+
+func (x *Tabungan) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GetRiwayatTransaksiResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transaksi     []*Tabungan            `protobuf:"bytes,1,rep,name=transaksi,proto3" json:"transaksi,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRiwayatTransaksiResponse) Reset() {
+	*x = GetRiwayatTransaksiResponse{}
+	mi := &file_bank_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRiwayatTransaksiResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRiwayatTransaksiResponse) ProtoMessage() {}
+
+func (x *GetRiwayatTransaksiResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bank_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRiwayatTransaksiResponse.ProtoReflect.Descriptor instead.
+func (*GetRiwayatTransaksiResponse) Descriptor() ([]byte, []int) {
+	return file_bank_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetRiwayatTransaksiResponse) GetTransaksi() []*Tabungan {
+	if x != nil {
+		return x.Transaksi
+	}
+	return nil
+}
+
+var File_bank_proto protoreflect.FileDescriptor
+
+var file_bank_proto_rawDesc = []byte{
+	0x0a, 0x0a, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x62, 0x61,
+	0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x65, 0x0a, 0x16, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x4e, 0x61, 0x73, 0x61, 0x62, 0x61, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x69, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6e, 0x69, 0x6b, 0x12, 0x13, 0x0a, 0x05, 0x6e, 0x6f, 0x5f, 0x68, 0x70, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x6f, 0x48, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x70,
+	0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x70, 0x69, 0x6e, 0x22, 0x66, 0x0a,
+	0x17, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x73, 0x61, 0x62, 0x61, 0x68,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x5f, 0x72,
+	0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e,
+	0x6f, 0x52, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x65, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x04, 0x68, 0x65, 0x6c, 0x64, 0x22, 0xa9, 0x01, 0x0a, 0x0e, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x5f, 0x72,
+	0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e,
+	0x6f, 0x52, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x6f, 0x6d,
+	0x69, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x6e, 0x6f, 0x6d, 0x69,
+	0x6e, 0x61, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x73, 0x69,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x73,
+	0x69, 0x12, 0x1e, 0x0a, 0x0a, 0x6b, 0x65, 0x74, 0x65, 0x72, 0x61, 0x6e, 0x67, 0x61, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6b, 0x65, 0x74, 0x65, 0x72, 0x61, 0x6e, 0x67, 0x61,
+	0x6e, 0x12, 0x1e, 0x0a, 0x0b, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x75, 0x70, 0x5f, 0x70, 0x69, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x65, 0x70, 0x55, 0x70, 0x50, 0x69,
+	0x6e, 0x22, 0xaa, 0x01, 0x0a, 0x0f, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x5f, 0x72, 0x65, 0x6b, 0x65,
+	0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x6f, 0x52, 0x65,
+	0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c,
+	0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x73, 0x69, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x73, 0x69, 0x12, 0x1e,
+	0x0a, 0x0a, 0x6b, 0x65, 0x74, 0x65, 0x72, 0x61, 0x6e, 0x67, 0x61, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x6b, 0x65, 0x74, 0x65, 0x72, 0x61, 0x6e, 0x67, 0x61, 0x6e, 0x12, 0x1e,
+	0x0a, 0x0b, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x75, 0x70, 0x5f, 0x70, 0x69, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x65, 0x70, 0x55, 0x70, 0x50, 0x69, 0x6e, 0x22, 0xcf,
+	0x01, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x61, 0x72, 0x69, 0x5f, 0x72, 0x65, 0x6b, 0x65, 0x6e,
+	0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x61, 0x72, 0x69, 0x52,
+	0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x6b, 0x65, 0x5f, 0x72, 0x65,
+	0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6b, 0x65,
+	0x52, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x6f, 0x6d, 0x69,
+	0x6e, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x6e, 0x6f, 0x6d, 0x69, 0x6e,
+	0x61, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x73, 0x69, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x73, 0x69,
+	0x12, 0x1e, 0x0a, 0x0a, 0x6b, 0x65, 0x74, 0x65, 0x72, 0x61, 0x6e, 0x67, 0x61, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6b, 0x65, 0x74, 0x65, 0x72, 0x61, 0x6e, 0x67, 0x61, 0x6e,
+	0x12, 0x1e, 0x0a, 0x0b, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x75, 0x70, 0x5f, 0x70, 0x69, 0x6e, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x65, 0x70, 0x55, 0x70, 0x50, 0x69, 0x6e,
+	0x22, 0xa4, 0x02, 0x0a, 0x13, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x61, 0x62, 0x75, 0x6e, 0x67, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x74, 0x61, 0x62, 0x75, 0x6e, 0x67, 0x61, 0x6e, 0x49,
+	0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x5f, 0x72, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x6f, 0x52, 0x65, 0x6b, 0x65, 0x6e, 0x69,
+	0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x07, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x61, 0x6c, 0x64, 0x6f, 0x5f, 0x61, 0x77, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x09, 0x73, 0x61, 0x6c, 0x64, 0x6f, 0x41, 0x77, 0x61, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x61, 0x6c, 0x64, 0x6f, 0x5f, 0x61, 0x6b, 0x68, 0x69, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0a, 0x73, 0x61, 0x6c, 0x64, 0x6f, 0x41, 0x6b, 0x68, 0x69, 0x72, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x73, 0x69, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x73, 0x69, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x95, 0x01, 0x0a, 0x10, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x05,
+	0x64, 0x65, 0x62, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x62, 0x61,
+	0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x05, 0x64, 0x65, 0x62, 0x69, 0x74,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6b, 0x65, 0x5f, 0x72, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6b, 0x65, 0x52, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e,
+	0x67, 0x12, 0x2c, 0x0a, 0x12, 0x6b, 0x72, 0x65, 0x64, 0x69, 0x74, 0x5f, 0x74, 0x61, 0x62, 0x75,
+	0x6e, 0x67, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6b,
+	0x72, 0x65, 0x64, 0x69, 0x74, 0x54, 0x61, 0x62, 0x75, 0x6e, 0x67, 0x61, 0x6e, 0x49, 0x64, 0x22,
+	0x32, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x61, 0x6c, 0x64, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x5f, 0x72, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e,
+	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x6f, 0x52, 0x65, 0x6b, 0x65, 0x6e,
+	0x69, 0x6e, 0x67, 0x22, 0x28, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x61, 0x6c, 0x64, 0x6f, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x61, 0x6c, 0x64, 0x6f,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x61, 0x6c, 0x64, 0x6f, 0x22, 0x3d, 0x0a,
+	0x1a, 0x47, 0x65, 0x74, 0x52, 0x69, 0x77, 0x61, 0x79, 0x61, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x6b, 0x73, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e,
+	0x6f, 0x5f, 0x72, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x6e, 0x6f, 0x52, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0x98, 0x02, 0x0a,
+	0x08, 0x54, 0x61, 0x62, 0x75, 0x6e, 0x67, 0x61, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x6a, 0x65, 0x6e,
+	0x69, 0x73, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x6b, 0x73, 0x69, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x6a, 0x65, 0x6e, 0x69, 0x73, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x6b,
+	0x73, 0x69, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x07, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x12, 0x1e, 0x0a, 0x0a,
+	0x6b, 0x65, 0x74, 0x65, 0x72, 0x61, 0x6e, 0x67, 0x61, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x6b, 0x65, 0x74, 0x65, 0x72, 0x61, 0x6e, 0x67, 0x61, 0x6e, 0x12, 0x26, 0x0a, 0x0f,
+	0x72, 0x65, 0x66, 0x5f, 0x74, 0x61, 0x62, 0x75, 0x6e, 0x67, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x72, 0x65, 0x66, 0x54, 0x61, 0x62, 0x75, 0x6e, 0x67,
+	0x61, 0x6e, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x73, 0x69, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x73, 0x69, 0x12, 0x39, 0x0a, 0x0a,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x4e, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x52, 0x69,
+	0x77, 0x61, 0x79, 0x61, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x6b, 0x73, 0x69, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x6b, 0x73, 0x69, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x61, 0x6e, 0x6b,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x62, 0x75, 0x6e, 0x67, 0x61, 0x6e, 0x52, 0x09, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x6b, 0x73, 0x69, 0x32, 0xcd, 0x03, 0x0a, 0x0b, 0x42, 0x61, 0x6e, 0x6b,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x54, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x4e, 0x61, 0x73, 0x61, 0x62, 0x61, 0x68, 0x12, 0x1f, 0x2e, 0x62, 0x61, 0x6e,
+	0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x73,
+	0x61, 0x62, 0x61, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x62, 0x61,
+	0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61,
+	0x73, 0x61, 0x62, 0x61, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a,
+	0x07, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x12, 0x17, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1c, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x42, 0x0a, 0x08, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x12, 0x18, 0x2e, 0x62, 0x61,
+	0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12,
+	0x18, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x62, 0x61, 0x6e, 0x6b,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x61, 0x6c, 0x64, 0x6f,
+	0x12, 0x18, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x61,
+	0x6c, 0x64, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x62, 0x61, 0x6e,
+	0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x61, 0x6c, 0x64, 0x6f, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x52, 0x69, 0x77, 0x61,
+	0x79, 0x61, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x6b, 0x73, 0x69, 0x12, 0x23, 0x2e, 0x62,
+	0x61, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x69, 0x77, 0x61, 0x79, 0x61,
+	0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x6b, 0x73, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x24, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52,
+	0x69, 0x77, 0x61, 0x79, 0x61, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x6b, 0x73, 0x69, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x6f, 0x6c, 0x61, 0x6e,
+	0x67, 0x2d, 0x65, 0x63, 0x68, 0x6f, 0x2d, 0x70, 0x6f, 0x73, 0x74, 0x67, 0x72, 0x65, 0x73, 0x71,
+	0x6c, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x61, 0x70, 0x69, 0x2f, 0x62, 0x61, 0x6e, 0x6b, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_bank_proto_rawDescOnce sync.Once
+	file_bank_proto_rawDescData = file_bank_proto_rawDesc
+)
+
+func file_bank_proto_rawDescGZIP() []byte {
+	file_bank_proto_rawDescOnce.Do(func() {
+		file_bank_proto_rawDescData = protoimpl.X.CompressGZIP(file_bank_proto_rawDescData)
+	})
+	return file_bank_proto_rawDescData
+}
+
+var file_bank_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_bank_proto_goTypes = []any{
+	(*RegisterNasabahRequest)(nil),      // 0: bank.v1.RegisterNasabahRequest
+	(*RegisterNasabahResponse)(nil),     // 1: bank.v1.RegisterNasabahResponse
+	(*DepositRequest)(nil),              // 2: bank.v1.DepositRequest
+	(*WithdrawRequest)(nil),             // 3: bank.v1.WithdrawRequest
+	(*TransferRequest)(nil),             // 4: bank.v1.TransferRequest
+	(*TransactionResponse)(nil),         // 5: bank.v1.TransactionResponse
+	(*TransferResponse)(nil),            // 6: bank.v1.TransferResponse
+	(*GetSaldoRequest)(nil),             // 7: bank.v1.GetSaldoRequest
+	(*GetSaldoResponse)(nil),            // 8: bank.v1.GetSaldoResponse
+	(*GetRiwayatTransaksiRequest)(nil),  // 9: bank.v1.GetRiwayatTransaksiRequest
+	(*Tabungan)(nil),                    // 10: bank.v1.Tabungan
+	(*GetRiwayatTransaksiResponse)(nil), // 11: bank.v1.GetRiwayatTransaksiResponse
+	(*timestamppb.Timestamp)(nil),       // 12: google.protobuf.Timestamp
+}
+var file_bank_proto_depIdxs = []int32{
+	5,  // 0: bank.v1.TransferResponse.debit:type_name -> bank.v1.TransactionResponse
+	12, // 1: bank.v1.Tabungan.created_at:type_name -> google.protobuf.Timestamp
+	10, // 2: bank.v1.GetRiwayatTransaksiResponse.transaksi:type_name -> bank.v1.Tabungan
+	0,  // 3: bank.v1.BankService.RegisterNasabah:input_type -> bank.v1.RegisterNasabahRequest
+	2,  // 4: bank.v1.BankService.Deposit:input_type -> bank.v1.DepositRequest
+	3,  // 5: bank.v1.BankService.Withdraw:input_type -> bank.v1.WithdrawRequest
+	4,  // 6: bank.v1.BankService.Transfer:input_type -> bank.v1.TransferRequest
+	7,  // 7: bank.v1.BankService.GetSaldo:input_type -> bank.v1.GetSaldoRequest
+	9,  // 8: bank.v1.BankService.GetRiwayatTransaksi:input_type -> bank.v1.GetRiwayatTransaksiRequest
+	1,  // 9: bank.v1.BankService.RegisterNasabah:output_type -> bank.v1.RegisterNasabahResponse
+	5,  // 10: bank.v1.BankService.Deposit:output_type -> bank.v1.TransactionResponse
+	5,  // 11: bank.v1.BankService.Withdraw:output_type -> bank.v1.TransactionResponse
+	6,  // 12: bank.v1.BankService.Transfer:output_type -> bank.v1.TransferResponse
+	8,  // 13: bank.v1.BankService.GetSaldo:output_type -> bank.v1.GetSaldoResponse
+	11, // 14: bank.v1.BankService.GetRiwayatTransaksi:output_type -> bank.v1.GetRiwayatTransaksiResponse
+	9,  // [9:15] is the sub-list for method output_type
+	3,  // [3:9] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_bank_proto_init() }
+func file_bank_proto_init() {
+	if File_bank_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_bank_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_bank_proto_goTypes,
+		DependencyIndexes: file_bank_proto_depIdxs,
+		MessageInfos:      file_bank_proto_msgTypes,
+	}.Build()
+	File_bank_proto = out.File
+	file_bank_proto_rawDesc = nil
+	file_bank_proto_goTypes = nil
+	file_bank_proto_depIdxs = nil
+}
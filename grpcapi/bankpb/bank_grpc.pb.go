@@ -0,0 +1,329 @@
+// grpcapi/bankpb/bank.proto
+//
+// API gRPC untuk layanan internal. Semantik dan pemetaan error sama dengan route HTTP:
+// setiap RPC memanggil service yang sama (onboarding dan transaction) dengan handler Echo.
+// Kode dibangkitkan ulang dengan: go generate ./grpcapi/bankpb
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: bank.proto
+
+package bankpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BankService_RegisterNasabah_FullMethodName     = "/bank.v1.BankService/RegisterNasabah"
+	BankService_Deposit_FullMethodName             = "/bank.v1.BankService/Deposit"
+	BankService_Withdraw_FullMethodName            = "/bank.v1.BankService/Withdraw"
+	BankService_Transfer_FullMethodName            = "/bank.v1.BankService/Transfer"
+	BankService_GetSaldo_FullMethodName            = "/bank.v1.BankService/GetSaldo"
+	BankService_GetRiwayatTransaksi_FullMethodName = "/bank.v1.BankService/GetRiwayatTransaksi"
+)
+
+// BankServiceClient is the client API for BankService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BankServiceClient interface {
+	// Pendaftaran nasabah baru, tanpa autentikasi (sama dengan POST /daftar)
+	RegisterNasabah(ctx context.Context, in *RegisterNasabahRequest, opts ...grpc.CallOption) (*RegisterNasabahResponse, error)
+	// Setor ke rekening (POST /tabung)
+	Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	// Tarik dari rekening milik principal (POST /tarik)
+	Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	// Transfer antarrekening (POST /transfer)
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error)
+	// Saldo rekening milik principal (GET /saldo/:no_rekening)
+	GetSaldo(ctx context.Context, in *GetSaldoRequest, opts ...grpc.CallOption) (*GetSaldoResponse, error)
+	// Riwayat transaksi rekening milik principal
+	GetRiwayatTransaksi(ctx context.Context, in *GetRiwayatTransaksiRequest, opts ...grpc.CallOption) (*GetRiwayatTransaksiResponse, error)
+}
+
+type bankServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBankServiceClient(cc grpc.ClientConnInterface) BankServiceClient {
+	return &bankServiceClient{cc}
+}
+
+func (c *bankServiceClient) RegisterNasabah(ctx context.Context, in *RegisterNasabahRequest, opts ...grpc.CallOption) (*RegisterNasabahResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterNasabahResponse)
+	err := c.cc.Invoke(ctx, BankService_RegisterNasabah_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankServiceClient) Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionResponse)
+	err := c.cc.Invoke(ctx, BankService_Deposit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankServiceClient) Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionResponse)
+	err := c.cc.Invoke(ctx, BankService_Withdraw_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankServiceClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferResponse)
+	err := c.cc.Invoke(ctx, BankService_Transfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankServiceClient) GetSaldo(ctx context.Context, in *GetSaldoRequest, opts ...grpc.CallOption) (*GetSaldoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSaldoResponse)
+	err := c.cc.Invoke(ctx, BankService_GetSaldo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankServiceClient) GetRiwayatTransaksi(ctx context.Context, in *GetRiwayatTransaksiRequest, opts ...grpc.CallOption) (*GetRiwayatTransaksiResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRiwayatTransaksiResponse)
+	err := c.cc.Invoke(ctx, BankService_GetRiwayatTransaksi_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BankServiceServer is the server API for BankService service.
+// All implementations must embed UnimplementedBankServiceServer
+// for forward compatibility.
+type BankServiceServer interface {
+	// Pendaftaran nasabah baru, tanpa autentikasi (sama dengan POST /daftar)
+	RegisterNasabah(context.Context, *RegisterNasabahRequest) (*RegisterNasabahResponse, error)
+	// Setor ke rekening (POST /tabung)
+	Deposit(context.Context, *DepositRequest) (*TransactionResponse, error)
+	// Tarik dari rekening milik principal (POST /tarik)
+	Withdraw(context.Context, *WithdrawRequest) (*TransactionResponse, error)
+	// Transfer antarrekening (POST /transfer)
+	Transfer(context.Context, *TransferRequest) (*TransferResponse, error)
+	// Saldo rekening milik principal (GET /saldo/:no_rekening)
+	GetSaldo(context.Context, *GetSaldoRequest) (*GetSaldoResponse, error)
+	// Riwayat transaksi rekening milik principal
+	GetRiwayatTransaksi(context.Context, *GetRiwayatTransaksiRequest) (*GetRiwayatTransaksiResponse, error)
+	mustEmbedUnimplementedBankServiceServer()
+}
+
+// UnimplementedBankServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBankServiceServer struct{}
+
+func (UnimplementedBankServiceServer) RegisterNasabah(context.Context, *RegisterNasabahRequest) (*RegisterNasabahResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterNasabah not implemented")
+}
+func (UnimplementedBankServiceServer) Deposit(context.Context, *DepositRequest) (*TransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deposit not implemented")
+}
+func (UnimplementedBankServiceServer) Withdraw(context.Context, *WithdrawRequest) (*TransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Withdraw not implemented")
+}
+func (UnimplementedBankServiceServer) Transfer(context.Context, *TransferRequest) (*TransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
+func (UnimplementedBankServiceServer) GetSaldo(context.Context, *GetSaldoRequest) (*GetSaldoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSaldo not implemented")
+}
+func (UnimplementedBankServiceServer) GetRiwayatTransaksi(context.Context, *GetRiwayatTransaksiRequest) (*GetRiwayatTransaksiResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRiwayatTransaksi not implemented")
+}
+func (UnimplementedBankServiceServer) mustEmbedUnimplementedBankServiceServer() {}
+func (UnimplementedBankServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeBankServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BankServiceServer will
+// result in compilation errors.
+type UnsafeBankServiceServer interface {
+	mustEmbedUnimplementedBankServiceServer()
+}
+
+func RegisterBankServiceServer(s grpc.ServiceRegistrar, srv BankServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBankServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BankService_ServiceDesc, srv)
+}
+
+func _BankService_RegisterNasabah_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterNasabahRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).RegisterNasabah(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_RegisterNasabah_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).RegisterNasabah(ctx, req.(*RegisterNasabahRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankService_Deposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DepositRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).Deposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_Deposit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).Deposit(ctx, req.(*DepositRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankService_Withdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).Withdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_Withdraw_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).Withdraw(ctx, req.(*WithdrawRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankService_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_Transfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankService_GetSaldo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSaldoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).GetSaldo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_GetSaldo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).GetSaldo(ctx, req.(*GetSaldoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankService_GetRiwayatTransaksi_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRiwayatTransaksiRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).GetRiwayatTransaksi(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_GetRiwayatTransaksi_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).GetRiwayatTransaksi(ctx, req.(*GetRiwayatTransaksiRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BankService_ServiceDesc is the grpc.ServiceDesc for BankService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BankService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bank.v1.BankService",
+	HandlerType: (*BankServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterNasabah",
+			Handler:    _BankService_RegisterNasabah_Handler,
+		},
+		{
+			MethodName: "Deposit",
+			Handler:    _BankService_Deposit_Handler,
+		},
+		{
+			MethodName: "Withdraw",
+			Handler:    _BankService_Withdraw_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _BankService_Transfer_Handler,
+		},
+		{
+			MethodName: "GetSaldo",
+			Handler:    _BankService_GetSaldo_Handler,
+		},
+		{
+			MethodName: "GetRiwayatTransaksi",
+			Handler:    _BankService_GetRiwayatTransaksi_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "bank.proto",
+}
@@ -0,0 +1,4 @@
+// Package bankpb berisi pesan dan stub gRPC yang dibangkitkan dari bank.proto
+package bankpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative bank.proto
@@ -0,0 +1,182 @@
+package grpcapi
+
+import (
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/fraud"
+	"golang-echo-postgresql/onboarding"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/transaction"
+	"net/http"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain dipakai pada detail ErrorInfo agar klien bisa membedakan alasan penolakan
+const errorDomain = "bank.v1"
+
+// transactionStatus menerjemahkan error domain transaksi menjadi status gRPC dengan kode
+// katalog yang sama dengan response HTTP
+func transactionStatus(err error) error {
+	var fraudErr *transaction.FraudError
+	switch {
+	case errors.As(err, &fraudErr):
+		return fraudStatus(fraudErr.Decision)
+	case errors.Is(err, transaction.ErrInvalidAmount):
+		return apiStatus(apierror.New(apierror.InvalidAmount))
+	case errors.Is(err, transaction.ErrInvalidRequest):
+		return apiStatus(apierror.New(apierror.InvalidPayload))
+	case errors.Is(err, transaction.ErrSameRekening):
+		return apiStatus(apierror.New(apierror.SameRekening))
+	case errors.Is(err, transaction.ErrForbidden):
+		return apiStatus(apierror.New(apierror.Forbidden))
+	case errors.Is(err, transaction.ErrRekeningNotLinked):
+		return apiStatus(apierror.New(apierror.RekeningNotLinked))
+	case errors.Is(err, transaction.ErrRekeningNotFound), errors.Is(err, store.ErrNotFound):
+		return apiStatus(apierror.New(apierror.RekeningNotFound))
+	case errors.Is(err, transaction.ErrTujuanNotFound):
+		return apiStatus(apierror.New(apierror.DestinationNotFound))
+	case errors.Is(err, transaction.ErrRekeningBeku):
+		return apiStatus(apierror.New(apierror.RekeningFrozen))
+	case errors.Is(err, transaction.ErrRekeningReview):
+		return apiStatus(apierror.New(apierror.RekeningUnderReview))
+	case errors.Is(err, transaction.ErrTujuanTidakAktif):
+		return apiStatus(apierror.New(apierror.DestinationInactive))
+	case errors.Is(err, transaction.ErrTujuanDitahan):
+		return apiStatus(apierror.New(apierror.DestinationHeld))
+	case errors.Is(err, transaction.ErrSaldoTidakCukup):
+		return apiStatus(apierror.New(apierror.InsufficientBalance))
+	case errors.Is(err, transaction.ErrApprovalUnavailable):
+		return apiStatus(apierror.New(apierror.ApprovalUnavailable))
+	}
+	return internalStatus(err, "Failed to process transaction")
+}
+
+// fraudStatus membalas transaksi yang diblokir atau menunggu step-up PIN, dengan id
+// keputusan fraud di detail ErrorInfo
+func fraudStatus(decision *fraud.Decision) error {
+	code := apierror.StepUpRequired
+	if decision.Blocked() {
+		code = apierror.FraudBlocked
+	}
+	return apiStatus(apierror.New(code).With("decision_id", strconv.FormatInt(decision.ID, 10)))
+}
+
+func onboardingStatus(err error) error {
+	var dupErr *onboarding.DuplicateError
+	switch {
+	case errors.Is(err, onboarding.ErrInvalidIdentity):
+		return apiStatus(apierror.New(apierror.InvalidIdentity))
+	case errors.Is(err, onboarding.ErrInvalidPIN):
+		return apiStatus(apierror.New(apierror.InvalidPIN))
+	case errors.As(err, &dupErr):
+		fields := dupErr.Fields
+		if len(fields) == 0 {
+			fields = []string{"NIK or No HP"}
+		}
+		return apiStatus(apierror.New(apierror.Duplicate, strings.Join(fields, " and ")+" already used"))
+	}
+	return internalStatus(err, "Failed to register nasabah")
+}
+
+// internalStatus mencatat error yang tidak dikenal dan menyembunyikan detailnya dari
+// klien. Context yang habis atau dibatalkan diteruskan sebagai status aslinya.
+func internalStatus(err error, msg string) error {
+	if st := status.FromContextError(err); st.Code() == codes.DeadlineExceeded || st.Code() == codes.Canceled {
+		return st.Err()
+	}
+	log.WithFields(log.Fields{
+		"error": err,
+	}).Error(msg)
+	return apiStatus(apierror.New(apierror.InternalError))
+}
+
+// apiStatus membentuk status gRPC dari error katalog. Pesan selalu bahasa Inggris; kode
+// katalog, detail per field, dan metadata dikirim di ErrorInfo.
+func apiStatus(e *apierror.Error) error {
+	code := grpcCode(e.Code)
+	msg := apierror.Lookup(e.Code).EN
+	if e.Code == apierror.StepUpRequired {
+		// Pada gRPC PIN dikirim lewat field request, bukan header
+		msg = "Step-up verification required, resend the request with step_up_pin"
+	}
+	if len(e.Errors) > 0 {
+		msg += ": " + strings.Join(e.Errors, "; ")
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason:   string(e.Code),
+		Domain:   errorDomain,
+		Metadata: e.Metadata,
+	}
+	st, err := status.New(code, msg).WithDetails(info)
+	if err != nil {
+		return status.Error(code, msg)
+	}
+	return st.Err()
+}
+
+// grpcCode memetakan kode katalog ke kode gRPC lewat status HTTP-nya. Penolakan karena
+// keadaan data (409, saldo, step-up) menjadi FailedPrecondition.
+func grpcCode(code apierror.Code) codes.Code {
+	switch code {
+	case apierror.Duplicate:
+		return codes.AlreadyExists
+	case apierror.InsufficientBalance, apierror.StepUpRequired:
+		return codes.FailedPrecondition
+	}
+	switch apierror.Lookup(code).Status {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict, http.StatusPreconditionRequired:
+		return codes.FailedPrecondition
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	return codes.Internal
+}
+
+// httpStatus memetakan kode gRPC ke status HTTP yang setara untuk log audit, sehingga
+// event dari kedua API bisa dicari dengan filter yang sama
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
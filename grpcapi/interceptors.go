@@ -0,0 +1,512 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/grpcapi/bankpb"
+	"golang-echo-postgresql/metrics"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/ratelimit"
+	"golang-echo-postgresql/tracing"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// metadataRequestID sama dengan header X-Request-ID pada API HTTP
+const metadataRequestID = "x-request-id"
+
+// publicMethods dapat dipanggil tanpa token, sama dengan POST /daftar
+var publicMethods = map[string]bool{
+	bankpb.BankService_RegisterNasabah_FullMethodName: true,
+}
+
+// mutatingMethods mengubah state dan selalu dicatat ke log audit
+var mutatingMethods = map[string]bool{
+	bankpb.BankService_RegisterNasabah_FullMethodName: true,
+	bankpb.BankService_Deposit_FullMethodName:         true,
+	bankpb.BankService_Withdraw_FullMethodName:        true,
+	bankpb.BankService_Transfer_FullMethodName:        true,
+}
+
+// eodGuardedMethods ditahan atau ditolak selama EOD, sama dengan route yang memakai EODGuard
+var eodGuardedMethods = map[string]bool{
+	bankpb.BankService_Deposit_FullMethodName:  true,
+	bankpb.BankService_Withdraw_FullMethodName: true,
+	bankpb.BankService_Transfer_FullMethodName: true,
+}
+
+// rateLimitRoutes memetakan RPC ke route HTTP padanannya supaya policy rate limit yang
+// sama berlaku di kedua API. RPC lain dihitung sebagai "POST /<service>/<method>".
+var rateLimitRoutes = map[string]string{
+	bankpb.BankService_RegisterNasabah_FullMethodName: "POST /daftar",
+	bankpb.BankService_Deposit_FullMethodName:         "POST /tabung",
+	bankpb.BankService_Withdraw_FullMethodName:        "POST /tarik",
+	bankpb.BankService_Transfer_FullMethodName:        "POST /transfer",
+	bankpb.BankService_GetSaldo_FullMethodName:        "GET /saldo/:no_rekening",
+}
+
+// call menyimpan state satu RPC yang diisi interceptor dan handler, padanan echo.Context
+type call struct {
+	requestID string
+	principal *auth.Principal
+	limit     *ratelimit.Counter
+
+	noRekening string
+	before     interface{}
+	after      interface{}
+}
+
+type callKey struct{}
+
+func callFrom(ctx context.Context) *call {
+	c, _ := ctx.Value(callKey{}).(*call)
+	return c
+}
+
+// principalFrom mengembalikan principal hasil autentikasi RPC saat ini, atau nil
+func principalFrom(ctx context.Context) *auth.Principal {
+	if c := callFrom(ctx); c != nil {
+		return c.principal
+	}
+	return nil
+}
+
+// setChange melengkapi event audit RPC saat ini, padanan audit.SetChange
+func setChange(ctx context.Context, noRekening string, before, after interface{}) {
+	c := callFrom(ctx)
+	if c == nil {
+		return
+	}
+	c.noRekening = noRekening
+	c.before = before
+	c.after = after
+}
+
+// unaryInterceptors menyusun rantai interceptor dengan urutan yang sama dengan
+// middleware Echo: tracing, request ID dan log, metrik, deadline, breaker, rate limit
+// per IP dan rekening, audit, autentikasi, rate limit per principal lalu guard EOD
+func (s *Server) unaryInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		s.trace,
+		s.logUnary,
+		s.Metrics.unary,
+		s.deadline,
+		s.breaker,
+		s.rateLimit,
+		s.audit,
+		s.authenticate,
+		s.rateLimitIdentified,
+		s.eodGuard,
+	}
+}
+
+// trace membuka span server untuk setiap RPC dengan trace context dari metadata
+// traceparent jika ada, padanan tracing.Middleware pada API HTTP
+func (s *Server) trace(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	ctx, span := tracing.Start(ctx, strings.TrimPrefix(info.FullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.RPCSystemGRPC))
+	defer span.End()
+
+	resp, err := handler(ctx, req)
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded:
+		span.SetStatus(otelcodes.Error, code.String())
+	}
+	return resp, err
+}
+
+// metadataCarrier membaca trace context dari metadata gRPC
+type metadataCarrier metadata.MD
+
+func (m metadataCarrier) Get(key string) string {
+	if v := metadata.MD(m).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (m metadataCarrier) Set(key, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+func (m metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// logUnary menyiapkan state RPC, meneruskan atau membuat request ID dan mencatat hasil setiap RPC
+func (s *Server) logUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	c := &call{requestID: incomingRequestID(ctx)}
+	ctx = context.WithValue(ctx, callKey{}, c)
+	if err := grpc.SetHeader(ctx, metadata.Pairs(metadataRequestID, c.requestID)); err != nil {
+		log.WithContext(ctx).WithFields(log.Fields{
+			"error": err,
+		}).Warn("Failed to set gRPC response header")
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	code := status.Code(err)
+
+	entry := log.WithContext(ctx).WithFields(log.Fields{
+		"method":    info.FullMethod,
+		"code":      code.String(),
+		"duration":  time.Since(start).String(),
+		"RequestID": c.requestID,
+	})
+	switch code {
+	case codes.OK:
+		entry.Info("gRPC request completed")
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		entry.WithField("error", err).Error("gRPC request failed")
+	default:
+		entry.WithField("error", err).Warn("gRPC request rejected")
+	}
+	return resp, err
+}
+
+// logStream mencatat RPC streaming (reflection dan health Watch)
+func (s *Server) logStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	log.WithFields(log.Fields{
+		"method":   info.FullMethod,
+		"code":     status.Code(err).String(),
+		"duration": time.Since(start).String(),
+	}).Debug("gRPC stream closed")
+	return err
+}
+
+// deadline memasang batas waktu bawaan jika klien tidak mengirim deadline. Deadline
+// ikut diteruskan ke transaksi database lewat context.
+func (s *Server) deadline(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if _, ok := ctx.Deadline(); !ok && s.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.opts.Timeout)
+		defer cancel()
+	}
+	return handler(ctx, req)
+}
+
+// breaker menolak RPC selama circuit breaker database terbuka, kecuali health check
+func (s *Server) breaker(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.opts.Breaker != nil && isBankMethod(info.FullMethod) && !s.opts.Breaker.Allow() {
+		return nil, apiStatus(apierror.New(apierror.DatabaseUnavailable))
+	}
+	return handler(ctx, req)
+}
+
+// rateLimit menerapkan policy berkunci IP dan rekening sebelum autentikasi, padanan
+// ratelimit.Limiter.Middleware. Header RateLimit-* dikirim sebagai metadata respons
+// setelah semua tahap dihitung; RPC yang ditolak tidak ditulis ke log audit.
+func (s *Server) rateLimit(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.opts.Limiter == nil || !s.opts.Limiter.Enabled() || !isBankMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	route, ok := rateLimitRoutes[info.FullMethod]
+	if !ok {
+		route = "POST " + info.FullMethod
+	}
+	c := callFrom(ctx)
+	c.limit = s.opts.Limiter.Counter(route)
+
+	var resp interface{}
+	err := c.limit.Count(ctx, limitRequest(ctx, req, route), ratelimit.KeyIP, ratelimit.KeyRekening)
+	if err != nil {
+		err = limitStatus(err)
+	} else {
+		resp, err = handler(ctx, req)
+	}
+
+	// Header respons unary baru dikirim setelah interceptor selesai, jadi hasil tahap
+	// principal di rateLimitIdentified ikut terhitung
+	if headers := c.limit.Headers(); len(headers) > 0 {
+		md := metadata.MD{}
+		for name, value := range headers {
+			md.Set(name, value)
+		}
+		if err := grpc.SetHeader(ctx, md); err != nil {
+			log.WithContext(ctx).WithFields(log.Fields{
+				"error": err,
+			}).Warn("Failed to set gRPC response header")
+		}
+	}
+	return resp, err
+}
+
+// rateLimitIdentified menerapkan policy berkunci principal dan partner setelah
+// autentikasi, padanan ratelimit.Limiter.Identified. RPC publik tanpa token dilewati.
+func (s *Server) rateLimitIdentified(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	c := callFrom(ctx)
+	if c == nil || c.limit == nil || c.principal == nil {
+		return handler(ctx, req)
+	}
+	if err := c.limit.Count(ctx, ratelimit.Request{Principal: c.principal}, ratelimit.KeyPrincipal, ratelimit.KeyPartner); err != nil {
+		return nil, limitStatus(err)
+	}
+	return handler(ctx, req)
+}
+
+// limitStatus mengubah penolakan rate limit menjadi status gRPC ResourceExhausted
+func limitStatus(err error) error {
+	var apiErr *apierror.Error
+	if errors.As(err, &apiErr) {
+		return apiStatus(apiErr)
+	}
+	return err
+}
+
+// audit mencatat RPC yang mengubah state ke log audit berantai hash setelah handler
+// selesai, termasuk yang gagal atau ditolak. Pesan request tidak pernah dicatat. Jika
+// penulisan audit gagal, klien menerima AUDIT_UNAVAILABLE alih-alih respons sukses.
+func (s *Server) audit(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.opts.Recorder == nil || !mutatingMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	resp, err := handler(ctx, req)
+
+	c := callFrom(ctx)
+	event := &models.AuditEvent{
+		RequestID:  c.requestID,
+		Action:     "GRPC " + info.FullMethod,
+		NoRekening: c.noRekening,
+		Before:     encode(c.before),
+		After:      encode(c.after),
+		StatusCode: httpStatus(status.Code(err)),
+	}
+	if event.NoRekening == "" {
+		event.NoRekening = requestRekening(req)
+	}
+	event.ActorType, event.ActorID = audit.Actor(c.principal)
+
+	if aerr := s.opts.Recorder.Append(event); aerr != nil {
+		log.WithContext(ctx).WithFields(log.Fields{
+			"error":     aerr,
+			"action":    event.Action,
+			"RequestID": event.RequestID,
+		}).Error("Failed to write audit event")
+		return nil, apiStatus(apierror.Wrap(apierror.AuditUnavailable, aerr))
+	}
+	return resp, err
+}
+
+// authenticate memverifikasi metadata "authorization: Bearer <token>" untuk semua RPC
+// BankService kecuali publicMethods
+func (s *Server) authenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !isBankMethod(info.FullMethod) || publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	raw, found := strings.CutPrefix(firstMetadata(ctx, "authorization"), "Bearer ")
+	if !found || raw == "" {
+		return nil, apiStatus(apierror.New(apierror.Unauthorized))
+	}
+
+	p, err := s.opts.Tokens.ParseAccessToken(raw)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) {
+			log.WithContext(ctx).WithFields(log.Fields{
+				"error":  err,
+				"method": info.FullMethod,
+			}).Warn("Rejected access token")
+			return nil, apiStatus(apierror.New(apierror.Unauthorized))
+		}
+		log.WithContext(ctx).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to verify access token")
+		return nil, apiStatus(apierror.New(apierror.InternalError))
+	}
+
+	callFrom(ctx).principal = p
+	return handler(ctx, req)
+}
+
+// eodGuard menahan atau menolak transaksi selama EOD berjalan, sama dengan eod.Service.Guard
+func (s *Server) eodGuard(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.opts.EOD == nil || !eodGuardedMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	ok, err := s.opts.EOD.Wait(ctx)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	if !ok {
+		log.WithContext(ctx).WithFields(log.Fields{
+			"method": info.FullMethod,
+			"mode":   s.opts.EOD.Mode,
+		}).Warn("Transaction rejected during EOD")
+		return nil, apiStatus(apierror.New(apierror.EODInProgress))
+	}
+	return handler(ctx, req)
+}
+
+// Metrics mengumpulkan jumlah dan durasi RPC per method dan kode status
+type Metrics struct {
+	mu    sync.Mutex
+	stats map[metricKey]*MethodStats
+}
+
+type metricKey struct {
+	method string
+	code   codes.Code
+}
+
+// MethodStats adalah ringkasan RPC untuk satu method dan kode status
+type MethodStats struct {
+	Method  string  `json:"method"`
+	Code    string  `json:"code"`
+	Count   int64   `json:"count"`
+	TotalMs float64 `json:"total_ms"`
+	MaxMs   float64 `json:"max_ms"`
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{stats: make(map[metricKey]*MethodStats)}
+}
+
+func (m *Metrics) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	code, d := status.Code(err), time.Since(start)
+	m.observe(info.FullMethod, code, d)
+	metrics.ObserveRPC(info.FullMethod, code.String(), d)
+	return resp, err
+}
+
+func (m *Metrics) observe(method string, code codes.Code, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := metricKey{method: method, code: code}
+	st, ok := m.stats[key]
+	if !ok {
+		st = &MethodStats{Method: method, Code: code.String()}
+		m.stats[key] = st
+	}
+	ms := float64(d) / float64(time.Millisecond)
+	st.Count++
+	st.TotalMs += ms
+	if ms > st.MaxMs {
+		st.MaxMs = ms
+	}
+}
+
+// Snapshot mengembalikan salinan metrik yang diurutkan menurut method dan kode
+func (m *Metrics) Snapshot() []MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]MethodStats, 0, len(m.stats))
+	for _, st := range m.stats {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Method != out[j].Method {
+			return out[i].Method < out[j].Method
+		}
+		return out[i].Code < out[j].Code
+	})
+	return out
+}
+
+func isBankMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+bankpb.BankService_ServiceDesc.ServiceName+"/")
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// incomingRequestID memakai x-request-id dari klien jika lolos audit.ValidRequestID,
+// atau membuat yang baru
+func incomingRequestID(ctx context.Context) string {
+	if id := firstMetadata(ctx, metadataRequestID); audit.ValidRequestID(id) {
+		return id
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// limitRequest mengisi nilai kunci rate limit dari RPC: alamat peer sebagai IP dan
+// rekening dari pesan request jika route HTTP padanannya memiliki path parameter tersebut
+func limitRequest(ctx context.Context, req interface{}, route string) ratelimit.Request {
+	r := ratelimit.Request{
+		Rekening: func(param string) string {
+			if strings.Contains(route, "/:"+param) {
+				return requestRekening(req)
+			}
+			return ""
+		},
+	}
+	if pr, ok := peer.FromContext(ctx); ok && pr.Addr != nil {
+		r.IP = pr.Addr.String()
+		if host, _, err := net.SplitHostPort(r.IP); err == nil {
+			r.IP = host
+		}
+	}
+	return r
+}
+
+// requestRekening mengambil rekening target dari pesan request jika handler tidak
+// sempat mengisinya, misalnya karena RPC ditolak sebelum handler berjalan
+func requestRekening(req interface{}) string {
+	switch r := req.(type) {
+	case interface{ GetNoRekening() string }:
+		return r.GetNoRekening()
+	case interface{ GetDariRekening() string }:
+		return r.GetDariRekening()
+	}
+	return ""
+}
+
+func encode(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"context"
+	"golang-echo-postgresql/grpcapi/bankpb"
+	"golang-echo-postgresql/onboarding"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/ratelimit"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/transaction"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// newLimitedClient menjalankan server gRPC di atas store in-memory dengan policy rate
+// limit dan mengembalikan klien yang terhubung lewat loopback
+func newLimitedClient(t *testing.T, policies string) bankpb.BankServiceClient {
+	t.Helper()
+	set, err := ratelimit.ParsePolicies([]byte(policies))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mem := store.NewMemory()
+	s := New(Options{
+		Store:        mem,
+		Transactions: transaction.NewService(mem, policy.New(1_000_000), nil, nil, nil),
+		Onboarding:   onboarding.NewService(mem, nil),
+		Limiter:      &ratelimit.Limiter{Policies: set, Store: ratelimit.NewMemoryStore()},
+	})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve(lis)
+	t.Cleanup(s.GRPC.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return bankpb.NewBankServiceClient(conn)
+}
+
+func TestRateLimitRegisterNasabahByIP(t *testing.T) {
+	client := newLimitedClient(t, `{"policies":[
+		{"id":"daftar_ip","key":"ip","routes":["POST /daftar"],"limit":1,"period":"1h"}
+	]}`)
+
+	var header metadata.MD
+	_, err := client.RegisterNasabah(context.Background(), &bankpb.RegisterNasabahRequest{
+		Nama: "Budi", Nik: "3171011501900001", NoHp: "081234567801", Pin: "123456",
+	}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("first RegisterNasabah: %v", err)
+	}
+	if got := header.Get("ratelimit-remaining"); len(got) != 1 || got[0] != "0" {
+		t.Errorf("ratelimit-remaining = %v, want [0]", got)
+	}
+
+	_, err = client.RegisterNasabah(context.Background(), &bankpb.RegisterNasabahRequest{
+		Nama: "Ani", Nik: "3171015501900002", NoHp: "081234567802", Pin: "123456",
+	}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second RegisterNasabah = %v, want ResourceExhausted", err)
+	}
+	if got := header.Get("retry-after"); len(got) != 1 {
+		t.Errorf("retry-after = %v, want one value", got)
+	}
+}
+
+func TestRateLimitGetSaldoByRekening(t *testing.T) {
+	client := newLimitedClient(t, `{"policies":[
+		{"id":"saldo_rekening","key":"rekening","routes":["GET /saldo/:no_rekening"],"limit":1,"period":"1m"}
+	]}`)
+
+	// Rate limit dihitung sebelum autentikasi, sama dengan API HTTP
+	_, err := client.GetSaldo(context.Background(), &bankpb.GetSaldoRequest{NoRekening: "1000000001"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("first GetSaldo = %v, want Unauthenticated", err)
+	}
+	_, err = client.GetSaldo(context.Background(), &bankpb.GetSaldoRequest{NoRekening: "1000000001"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second GetSaldo = %v, want ResourceExhausted", err)
+	}
+	_, err = client.GetSaldo(context.Background(), &bankpb.GetSaldoRequest{NoRekening: "1000000002"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("GetSaldo on other rekening = %v, want separate bucket", err)
+	}
+}
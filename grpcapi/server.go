@@ -0,0 +1,135 @@
+// Package grpcapi menyediakan API gRPC (bank.v1.BankService) yang berjalan di proses
+// yang sama dengan API HTTP. Setiap RPC memanggil service yang sama dengan handler Echo
+// sehingga validasi, otorisasi dan pemetaan error tetap identik.
+package grpcapi
+
+import (
+	"context"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/db"
+	"golang-echo-postgresql/eod"
+	"golang-echo-postgresql/grpcapi/bankpb"
+	"golang-echo-postgresql/onboarding"
+	"golang-echo-postgresql/ratelimit"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/transaction"
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Options adalah dependensi server gRPC. Recorder, Breaker, EOD dan Limiter boleh nil,
+// misalnya saat memakai store in-memory.
+type Options struct {
+	Store        store.Store
+	Transactions *transaction.Service
+	Onboarding   *onboarding.Service
+	Tokens       *auth.TokenService
+	Recorder     *audit.Recorder
+	Breaker      *db.Breaker
+	EOD          *eod.Service
+	Limiter      *ratelimit.Limiter
+
+	// Timeout adalah deadline bawaan untuk RPC yang dikirim tanpa deadline
+	Timeout time.Duration
+}
+
+// Server membungkus grpc.Server beserta health service dan metrik RPC
+type Server struct {
+	GRPC    *grpc.Server
+	Health  *health.Server
+	Metrics *Metrics
+
+	opts Options
+}
+
+func New(opts Options) *Server {
+	s := &Server{
+		Health:  health.NewServer(),
+		Metrics: NewMetrics(),
+		opts:    opts,
+	}
+	s.GRPC = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryInterceptors()...),
+		grpc.ChainStreamInterceptor(s.logStream),
+	)
+
+	bankpb.RegisterBankServiceServer(s.GRPC, &bankService{
+		store:        opts.Store,
+		transactions: opts.Transactions,
+		onboarding:   opts.Onboarding,
+	})
+	healthpb.RegisterHealthServer(s.GRPC, s.Health)
+	reflection.Register(s.GRPC)
+
+	s.setServing(true)
+	return s
+}
+
+// Serve menerima koneksi sampai Shutdown dipanggil
+func (s *Server) Serve(lis net.Listener) error {
+	log.WithFields(log.Fields{
+		"addr": lis.Addr().String(),
+	}).Info("gRPC server started")
+	return s.GRPC.Serve(lis)
+}
+
+// WatchHealth menyelaraskan status health service dengan circuit breaker database
+// sampai ctx dibatalkan
+func (s *Server) WatchHealth(ctx context.Context, interval time.Duration) {
+	if s.opts.Breaker == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.setServing(s.opts.Breaker.Allow())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain menandai server NOT_SERVING secara permanen supaya load balancer berhenti
+// mengirim RPC baru, tanpa memutus RPC yang sedang berjalan
+func (s *Server) Drain() {
+	s.Health.Shutdown()
+}
+
+// Shutdown menandai server NOT_SERVING lalu menunggu RPC yang sedang berjalan selesai.
+// Jika ctx habis lebih dulu, koneksi yang tersisa diputus paksa.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.Health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.GRPC.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.GRPC.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) setServing(ok bool) {
+	st := healthpb.HealthCheckResponse_SERVING
+	if !ok {
+		st = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.Health.SetServingStatus("", st)
+	s.Health.SetServingStatus(bankpb.BankService_ServiceDesc.ServiceName, st)
+}
@@ -0,0 +1,183 @@
+package grpcapi
+
+import (
+	"context"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/grpcapi/bankpb"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/onboarding"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/transaction"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// bankService mengimplementasikan bankpb.BankServiceServer di atas service yang sama
+// dengan handler HTTP, sehingga semantik kedua API identik
+type bankService struct {
+	bankpb.UnimplementedBankServiceServer
+
+	store        store.Store
+	transactions *transaction.Service
+	onboarding   *onboarding.Service
+}
+
+func (s *bankService) RegisterNasabah(ctx context.Context, req *bankpb.RegisterNasabahRequest) (*bankpb.RegisterNasabahResponse, error) {
+	res, err := s.onboarding.Register(ctx, onboarding.Registration{
+		Nama: req.GetNama(),
+		NIK:  req.GetNik(),
+		NoHP: req.GetNoHp(),
+		PIN:  req.GetPin(),
+	})
+	if err != nil {
+		return nil, onboardingStatus(err)
+	}
+
+	log.WithContext(ctx).WithFields(log.Fields{
+		"NoRekening": res.Nasabah.NoRekening,
+		"status":     res.Nasabah.Status,
+	}).Info("Nasabah registered successfully")
+	setChange(ctx, res.Nasabah.NoRekening, nil, map[string]interface{}{"nama": res.Nasabah.Nama, "status": res.Nasabah.Status})
+
+	return &bankpb.RegisterNasabahResponse{
+		NoRekening: res.Nasabah.NoRekening,
+		Status:     res.Nasabah.Status,
+		Held:       res.Held,
+	}, nil
+}
+
+func (s *bankService) Deposit(ctx context.Context, req *bankpb.DepositRequest) (*bankpb.TransactionResponse, error) {
+	res, err := s.transactions.Deposit(ctx, transaction.Deposit{
+		NoRekening: req.GetNoRekening(),
+		Nominal:    req.GetNominal(),
+		Meta:       meta(ctx, req.GetReferensi(), req.GetKeterangan(), req.GetStepUpPin()),
+	})
+	if err != nil {
+		return nil, transactionStatus(err)
+	}
+	setChange(ctx, res.NoRekening, map[string]interface{}{"saldo": res.SaldoAwal}, map[string]interface{}{"saldo": res.SaldoAkhir})
+	return transactionResponse(res), nil
+}
+
+func (s *bankService) Withdraw(ctx context.Context, req *bankpb.WithdrawRequest) (*bankpb.TransactionResponse, error) {
+	res, err := s.transactions.Withdraw(ctx, transaction.Withdraw{
+		NoRekening: req.GetNoRekening(),
+		Nominal:    req.GetNominal(),
+		Meta:       meta(ctx, req.GetReferensi(), req.GetKeterangan(), req.GetStepUpPin()),
+	})
+	if err != nil {
+		return nil, transactionStatus(err)
+	}
+	if res.Status == transaction.StatusPendingApproval {
+		setChange(ctx, res.NoRekening, nil, map[string]interface{}{"operation_id": res.Operation.ID, "status": res.Operation.Status})
+	} else {
+		setChange(ctx, res.NoRekening, map[string]interface{}{"saldo": res.SaldoAwal}, map[string]interface{}{"saldo": res.SaldoAkhir})
+	}
+	return transactionResponse(res), nil
+}
+
+func (s *bankService) Transfer(ctx context.Context, req *bankpb.TransferRequest) (*bankpb.TransferResponse, error) {
+	res, err := s.transactions.Transfer(ctx, transaction.Transfer{
+		DariRekening: req.GetDariRekening(),
+		KeRekening:   req.GetKeRekening(),
+		Nominal:      req.GetNominal(),
+		Meta:         meta(ctx, req.GetReferensi(), req.GetKeterangan(), req.GetStepUpPin()),
+	})
+	if err != nil {
+		return nil, transactionStatus(err)
+	}
+	if res.Status == transaction.StatusPendingApproval {
+		setChange(ctx, res.NoRekening, nil, map[string]interface{}{"operation_id": res.Operation.ID, "status": res.Operation.Status})
+	} else {
+		setChange(ctx, res.NoRekening, map[string]interface{}{"saldo": res.SaldoAwal},
+			map[string]interface{}{"saldo": res.SaldoAkhir, "ke_rekening": res.KeRekening, "referensi": res.Referensi})
+	}
+	return &bankpb.TransferResponse{
+		Debit:            transactionResponse(&res.Result),
+		KeRekening:       res.KeRekening,
+		KreditTabunganId: int64(res.KreditTabunganID),
+	}, nil
+}
+
+func (s *bankService) GetSaldo(ctx context.Context, req *bankpb.GetSaldoRequest) (*bankpb.GetSaldoResponse, error) {
+	if !auth.CanAccessRekening(principalFrom(ctx), req.GetNoRekening()) {
+		return nil, apiStatus(apierror.New(apierror.Forbidden))
+	}
+
+	var saldo float64
+	err := s.store.Do(ctx, func(uow store.UnitOfWork) error {
+		var err error
+		saldo, err = uow.Accounts().GetSaldo(req.GetNoRekening())
+		return err
+	})
+	if err != nil {
+		return nil, transactionStatus(err)
+	}
+	return &bankpb.GetSaldoResponse{Saldo: saldo}, nil
+}
+
+func (s *bankService) GetRiwayatTransaksi(ctx context.Context, req *bankpb.GetRiwayatTransaksiRequest) (*bankpb.GetRiwayatTransaksiResponse, error) {
+	if !auth.CanAccessRekening(principalFrom(ctx), req.GetNoRekening()) {
+		return nil, apiStatus(apierror.New(apierror.Forbidden))
+	}
+
+	var riwayat []models.Tabungan
+	err := s.store.Do(ctx, func(uow store.UnitOfWork) error {
+		nasabah, err := uow.Customers().GetByNoRekening(req.GetNoRekening())
+		if err != nil {
+			return err
+		}
+		riwayat, err = uow.Transactions().ListByNasabah(nasabah.ID)
+		return err
+	})
+	if err != nil {
+		return nil, transactionStatus(err)
+	}
+
+	resp := &bankpb.GetRiwayatTransaksiResponse{Transaksi: make([]*bankpb.Tabungan, 0, len(riwayat))}
+	for _, t := range riwayat {
+		item := &bankpb.Tabungan{
+			Id:             int64(t.ID),
+			JenisTransaksi: t.JenisTransaksi,
+			Nominal:        t.Nominal,
+			Keterangan:     t.Keterangan,
+			Channel:        t.Channel,
+			Referensi:      t.Referensi,
+			CreatedAt:      timestamppb.New(t.CreatedAt),
+		}
+		if t.RefTabunganID != nil {
+			item.RefTabunganId = int64(*t.RefTabunganID)
+		}
+		resp.Transaksi = append(resp.Transaksi, item)
+	}
+	return resp, nil
+}
+
+// meta membentuk metadata perintah transaksi dari RPC saat ini
+func meta(ctx context.Context, referensi, keterangan, stepUpPIN string) transaction.Meta {
+	return transaction.Meta{
+		Referensi:  referensi,
+		Keterangan: keterangan,
+		Principal:  principalFrom(ctx),
+		StepUpPIN:  stepUpPIN,
+	}
+}
+
+func transactionResponse(res *transaction.Result) *bankpb.TransactionResponse {
+	resp := &bankpb.TransactionResponse{
+		Status:     res.Status,
+		TabunganId: int64(res.TabunganID),
+		NoRekening: res.NoRekening,
+		Nominal:    res.Nominal,
+		SaldoAwal:  res.SaldoAwal,
+		SaldoAkhir: res.SaldoAkhir,
+		Channel:    res.Channel,
+		Referensi:  res.Referensi,
+	}
+	if res.Operation != nil {
+		resp.OperationId = int64(res.Operation.ID)
+	}
+	return resp
+}
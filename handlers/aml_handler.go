@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"golang-echo-postgresql/aml"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+type AMLHandler struct {
+	AML *aml.Service
+}
+
+func NewAMLHandler(service *aml.Service) *AMLHandler {
+	return &AMLHandler{AML: service}
+}
+
+// ListLTKT menampilkan laporan transaksi tunai untuk ?tanggal= (bawaan kemarin)
+func (h *AMLHandler) ListLTKT(c echo.Context) error {
+	tanggal := time.Now().AddDate(0, 0, -1)
+	if v := c.QueryParam("tanggal"); v != "" {
+		t, err := time.ParseInLocation(aml.DateLayout, v, time.Local)
+		if err != nil {
+			return apierror.New(apierror.InvalidParameter, "query.tanggal: must be a date (YYYY-MM-DD)")
+		}
+		tanggal = t
+	}
+
+	reports, err := h.AML.LTKT(tanggal)
+	if err != nil {
+		return amlError(err)
+	}
+	return c.JSON(http.StatusOK, reports)
+}
+
+// ListCases menampilkan kasus AML, difilter ?status= dan ?no_rekening=
+func (h *AMLHandler) ListCases(c echo.Context) error {
+	limit, err := queryLimit(c)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "query.limit: must be between 1 and 1000")
+	}
+
+	cases, err := h.AML.Cases(c.QueryParam("status"), c.QueryParam("no_rekening"), limit)
+	if err != nil {
+		return amlError(err)
+	}
+	return c.JSON(http.StatusOK, cases)
+}
+
+func (h *AMLHandler) GetCase(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	amlCase, err := h.AML.Case(id)
+	if err != nil {
+		return amlError(err)
+	}
+	return c.JSON(http.StatusOK, amlCase)
+}
+
+func (h *AMLHandler) OpenCase(c echo.Context) error {
+	var request models.OpenAMLCaseRequest
+	if err := c.Bind(&request); err != nil || request.NoRekening == "" {
+		return apierror.New(apierror.InvalidPayload)
+	}
+
+	amlCase, err := h.AML.OpenCase(auth.PrincipalFrom(c), request)
+	if err != nil {
+		return amlError(err)
+	}
+
+	audit.SetChange(c, amlCase.NoRekening, nil, map[string]interface{}{"case_id": amlCase.ID, "status": amlCase.Status})
+	return c.JSON(http.StatusOK, amlCase)
+}
+
+func (h *AMLHandler) TransitionCase(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	var request models.AMLCaseTransitionRequest
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	amlCase, err := h.AML.Transition(auth.PrincipalFrom(c), id, request)
+	if err != nil {
+		return amlError(err)
+	}
+
+	audit.SetChange(c, amlCase.NoRekening, nil, map[string]interface{}{"case_id": amlCase.ID, "status": amlCase.Status})
+	return c.JSON(http.StatusOK, amlCase)
+}
+
+// Run menjalankan ulang job AML untuk satu tanggal, misalnya setelah koreksi data
+func (h *AMLHandler) Run(c echo.Context) error {
+	var request models.AMLRunRequest
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	tanggal, err := time.ParseInLocation(aml.DateLayout, request.Tanggal, time.Local)
+	if err != nil || !tanggal.Before(time.Now()) {
+		return apierror.New(apierror.InvalidPayload, "tanggal: must be a past date (YYYY-MM-DD)")
+	}
+
+	run, err := h.AML.RunFor(c.Request().Context(), tanggal)
+	if err != nil {
+		return amlError(err)
+	}
+	return c.JSON(http.StatusOK, run)
+}
+
+func (h *AMLHandler) ListExports(c echo.Context) error {
+	limit, err := queryLimit(c)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "query.limit: must be between 1 and 1000")
+	}
+
+	exports, err := h.AML.Exports(limit)
+	if err != nil {
+		return amlError(err)
+	}
+	return c.JSON(http.StatusOK, exports)
+}
+
+func queryLimit(c echo.Context) (int, error) {
+	v := c.QueryParam("limit")
+	if v == "" {
+		return 100, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit <= 0 || limit > 1000 {
+		return 0, errors.New("invalid limit")
+	}
+	return limit, nil
+}
+
+func amlError(err error) error {
+	switch {
+	case errors.Is(err, aml.ErrCaseNotFound):
+		return apierror.New(apierror.AMLCaseNotFound)
+	case errors.Is(err, aml.ErrRekeningNotFound):
+		return apierror.New(apierror.RekeningNotFound)
+	case errors.Is(err, aml.ErrCaseExists):
+		return apierror.New(apierror.AMLCaseExists)
+	case errors.Is(err, aml.ErrInvalidTransition):
+		return apierror.New(apierror.AMLTransitionNotAllowed)
+	case errors.Is(err, aml.ErrNoteRequired):
+		return apierror.New(apierror.InvalidPayload, "catatan: is required")
+	}
+
+	log.WithFields(log.Fields{
+		"error": err,
+	}).Error("AML operation failed")
+	return apierror.New(apierror.InternalError)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+type AuditHandler struct {
+	Recorder *audit.Recorder
+}
+
+func NewAuditHandler(recorder *audit.Recorder) *AuditHandler {
+	return &AuditHandler{Recorder: recorder}
+}
+
+// SearchEvents mencari log audit berdasarkan ?no_rekening= dan/atau ?actor=<type>:<id>,
+// dengan paginasi ?after_id= dan ?limit=
+func (h *AuditHandler) SearchEvents(c echo.Context) error {
+	filter := models.AuditFilter{
+		NoRekening: c.QueryParam("no_rekening"),
+		Limit:      100,
+	}
+
+	if actor := c.QueryParam("actor"); actor != "" {
+		actorType, actorID, found := strings.Cut(actor, ":")
+		if !found {
+			return apierror.New(apierror.InvalidParameter, "query.actor: must be <type>:<id>")
+		}
+		filter.ActorType, filter.ActorID = actorType, actorID
+	}
+
+	if filter.NoRekening == "" && filter.ActorType == "" {
+		return apierror.New(apierror.InvalidParameter, "query: no_rekening or actor is required")
+	}
+
+	if v := c.QueryParam("after_id"); v != "" {
+		afterID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return apierror.New(apierror.InvalidParameter, "query.after_id: must be an integer")
+		}
+		filter.AfterID = afterID
+	}
+	if v := c.QueryParam("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > 1000 {
+			return apierror.New(apierror.InvalidParameter, "query.limit: must be between 1 and 1000")
+		}
+		filter.Limit = limit
+	}
+
+	events, err := h.Recorder.Search(filter)
+	if err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to search audit events")
+		return apierror.New(apierror.InternalError)
+	}
+
+	return c.JSON(http.StatusOK, events)
+}
+
+func (h *AuditHandler) VerifyChain(c echo.Context) error {
+	report, err := h.Recorder.Verify()
+	if err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to verify audit chain")
+		return apierror.New(apierror.InternalError)
+	}
+
+	if !report.OK() {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"breaks": len(report.Breaks),
+		}).Warn("Audit chain verification found breaks")
+	}
+	return c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+type AuthHandler struct {
+	DB     *sql.DB
+	Store  store.Store
+	Tokens *auth.TokenService
+}
+
+func NewAuthHandler(db *sql.DB, st store.Store, tokens *auth.TokenService) *AuthHandler {
+	return &AuthHandler{DB: db, Store: st, Tokens: tokens}
+}
+
+func (h *AuthHandler) LoginNasabah(c echo.Context) error {
+	var request models.LoginNasabahRequest
+	log.WithContext(c.Request().Context()).Info("Starting LoginNasabah process")
+
+	if err := c.Bind(&request); err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to bind request data")
+		return err
+	}
+
+	var id int
+	var pinHash string
+	err := h.Store.Do(c.Request().Context(), func(uow store.UnitOfWork) (err error) {
+		id, pinHash, err = uow.Customers().GetCredentials(request.NoRekening)
+		return err
+	})
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Database error while loading nasabah credentials")
+		return apierror.New(apierror.InternalError)
+	}
+
+	if !auth.CheckSecret(pinHash, request.PIN) {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"NoRekening": request.NoRekening,
+		}).Warn("Invalid nasabah credentials")
+		return apierror.New(apierror.InvalidCredentials)
+	}
+
+	tokens, err := h.Tokens.IssueTokens(&auth.Principal{Type: auth.SubjectNasabah, ID: id, NoRekening: request.NoRekening})
+	if err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to issue tokens")
+		return apierror.New(apierror.InternalError)
+	}
+
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"NoRekening": request.NoRekening,
+	}).Info("Nasabah logged in successfully")
+
+	return c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) LoginStaff(c echo.Context) error {
+	var request models.LoginStaffRequest
+	log.WithContext(c.Request().Context()).Info("Starting LoginStaff process")
+
+	if err := c.Bind(&request); err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to bind request data")
+		return err
+	}
+
+	staff, err := repositories.GetStaffByUsername(h.DB, request.Username)
+	if err != nil && err != sql.ErrNoRows {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Database error while loading staff credentials")
+		return apierror.New(apierror.InternalError)
+	}
+
+	var passwordHash string
+	if staff != nil && staff.Aktif {
+		passwordHash = staff.PasswordHash
+	}
+	if !auth.CheckSecret(passwordHash, request.Password) {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"username": request.Username,
+		}).Warn("Invalid staff credentials")
+		return apierror.New(apierror.InvalidCredentials)
+	}
+
+	tokens, err := h.Tokens.IssueTokens(&auth.Principal{Type: auth.SubjectStaff, ID: staff.ID, Role: staff.Role})
+	if err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to issue tokens")
+		return apierror.New(apierror.InternalError)
+	}
+
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"username": staff.Username,
+		"role":     staff.Role,
+	}).Info("Staff logged in successfully")
+
+	return c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) RefreshToken(c echo.Context) error {
+	var request models.RefreshTokenRequest
+	log.WithContext(c.Request().Context()).Info("Starting RefreshToken process")
+
+	if err := c.Bind(&request); err != nil || request.RefreshToken == "" {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to bind request data")
+		return apierror.New(apierror.InvalidPayload)
+	}
+
+	tokens, err := h.Tokens.RotateRefreshToken(request.RefreshToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrRefreshTokenReused) {
+			log.WithContext(c.Request().Context()).WithFields(log.Fields{
+				"error": err,
+			}).Warn("Rejected refresh token")
+			return apierror.New(apierror.InvalidRefreshToken)
+		}
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to rotate refresh token")
+		return apierror.New(apierror.InternalError)
+	}
+
+	log.WithContext(c.Request().Context()).Info("Refresh token rotated successfully")
+	return c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var request models.RefreshTokenRequest
+	p := auth.PrincipalFrom(c)
+
+	// Body bersifat opsional; tanpa refresh token hanya access token yang dicabut
+	_ = c.Bind(&request)
+
+	if err := h.Tokens.Revoke(p, request.RefreshToken); err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to revoke tokens")
+		return apierror.New(apierror.InternalError)
+	}
+
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"type": p.Type,
+		"id":   p.ID,
+	}).Info("Logged out successfully")
+
+	return c.JSON(http.StatusOK, utils.Response{Remark: "Logged out"})
+}
+
+func (h *AuthHandler) JWKS(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.Tokens.Keys.JWKS())
+}
+
+func (h *AuthHandler) RotateKeys(c echo.Context) error {
+	if err := h.Tokens.Keys.Rotate(); err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to rotate signing key")
+		return apierror.New(apierror.InternalError)
+	}
+	return c.JSON(http.StatusOK, h.Tokens.Keys.JWKS())
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/onboarding"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/transaction"
+	"golang-echo-postgresql/utils"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/labstack/echo/v4"
+)
+
+// newLoginServer menjalankan pendaftaran dan login nasabah di atas store in-memory yang
+// sama; hanya kunci JWT dan refresh token yang memakai database (sqlmock)
+func newLoginServer(t *testing.T) (*echo.Echo, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+	mock.ExpectQuery("FROM auth_signing_keys").
+		WillReturnRows(sqlmock.NewRows([]string{"kid", "private_key", "created_at", "expires_at"}).
+			AddRow("k1", string(key), time.Now(), time.Now().Add(time.Hour)))
+	keys := auth.NewKeyManager(db, nil, time.Hour, 15*time.Minute)
+	if err := keys.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	mem := store.NewMemory()
+	transactions := transaction.NewService(mem, policy.New(1_000_000), nil, nil, nil)
+	nasabah := NewNasabahHandler(mem, transactions, onboarding.NewService(mem, nil))
+	login := NewAuthHandler(db, mem, auth.NewTokenService(db, keys, "test", 15*time.Minute, time.Hour))
+
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.Handler
+	e.POST("/daftar", nasabah.RegisterNasabah)
+	e.POST("/auth/nasabah/login", login.LoginNasabah)
+	return e, mock
+}
+
+func TestLoginNasabahOnMemoryStore(t *testing.T) {
+	e, mock := newLoginServer(t)
+	budi := register(t, e, "3171011501900001", "081234567801")
+
+	mock.ExpectQuery("INSERT INTO refresh_tokens").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), auth.SubjectNasabah, 1, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var tokens models.TokenResponse
+	if status := do(t, e, http.MethodPost, "/auth/nasabah/login", "", `{"no_rekening":"`+budi+`","pin":"123456"}`, &tokens); status != http.StatusOK {
+		t.Fatalf("login status = %d, want 200", status)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Errorf("tokens = %+v, want access and refresh token", tokens)
+	}
+
+	var res utils.Response
+	for _, body := range []string{
+		`{"no_rekening":"` + budi + `","pin":"654321"}`,
+		`{"no_rekening":"9999999999","pin":"123456"}`,
+	} {
+		if status := do(t, e, http.MethodPost, "/auth/nasabah/login", "", body, &res); status != http.StatusUnauthorized || res.Code != string(apierror.InvalidCredentials) {
+			t.Errorf("login %s = %d %s, want 401 %s", body, status, res.Code, apierror.InvalidCredentials)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
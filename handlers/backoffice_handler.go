@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/approval"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/backoffice"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/reconcile"
+	"golang-echo-postgresql/repositories"
+	"golang-echo-postgresql/transaction"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+type BackofficeHandler struct {
+	DB        *sql.DB
+	Policy    *policy.Policy
+	Approvals *approval.Queue
+}
+
+// ApprovalResponse dikirim dengan HTTP 202 saat operasi masuk antrean maker-checker
+type ApprovalResponse struct {
+	Remark    string                   `json:"remark"`
+	Operation *models.PendingOperation `json:"operation"`
+}
+
+// DecisionResponse adalah response approve dan reject. Result berisi hasil eksekusi
+// operasi yang disetujui.
+type DecisionResponse struct {
+	Operation *models.PendingOperation `json:"operation"`
+	Result    interface{}              `json:"result,omitempty"`
+}
+
+// ResultResponse adalah response aksi back-office yang langsung dieksekusi
+type ResultResponse struct {
+	Result interface{} `json:"result"`
+}
+
+func NewBackofficeHandler(db *sql.DB, pol *policy.Policy, approvals *approval.Queue) *BackofficeHandler {
+	return &BackofficeHandler{DB: db, Policy: pol, Approvals: approvals}
+}
+
+func (h *BackofficeHandler) Adjust(c echo.Context) error {
+	var request models.AdjustmentRequest
+	log.WithContext(c.Request().Context()).Info("Starting Adjust process")
+
+	if err := c.Bind(&request); err != nil || backoffice.ValidateAdjustment(request) != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Invalid adjustment request")
+		return apierror.New(apierror.InvalidPayload)
+	}
+
+	return h.run(c, policy.ActionAdjustment, request.NoRekening, request.Nominal, request.Alasan, request)
+}
+
+func (h *BackofficeHandler) Reverse(c echo.Context) error {
+	var request models.ReversalRequest
+	log.WithContext(c.Request().Context()).Info("Starting Reverse process")
+
+	if err := c.Bind(&request); err != nil || request.TabunganID <= 0 || request.Alasan == "" {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Invalid reversal request")
+		return apierror.New(apierror.InvalidPayload)
+	}
+
+	original, err := repositories.GetTabunganByID(h.DB, request.TabunganID)
+	if err == sql.ErrNoRows {
+		return apierror.New(apierror.TransactionNotFound)
+	}
+	if err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to load transaction")
+		return apierror.New(apierror.InternalError)
+	}
+
+	noRekening, err := repositories.GetNoRekeningByNasabahID(h.DB, original.NasabahID)
+	if err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to load rekening of transaction")
+		return apierror.New(apierror.InternalError)
+	}
+
+	return h.run(c, policy.ActionReversal, noRekening, original.Nominal, request.Alasan, request)
+}
+
+func (h *BackofficeHandler) Freeze(c echo.Context) error {
+	return h.freeze(c, policy.ActionFreeze)
+}
+
+func (h *BackofficeHandler) Unfreeze(c echo.Context) error {
+	return h.freeze(c, policy.ActionUnfreeze)
+}
+
+func (h *BackofficeHandler) freeze(c echo.Context, action policy.Action) error {
+	var request models.FreezeRequest
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"action": action,
+	}).Info("Starting freeze process")
+
+	if err := c.Bind(&request); err != nil || request.NoRekening == "" || request.Alasan == "" {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Invalid freeze request")
+		return apierror.New(apierror.InvalidPayload)
+	}
+
+	return h.run(c, action, request.NoRekening, 0, request.Alasan, request)
+}
+
+func (h *BackofficeHandler) ListApprovals(c echo.Context) error {
+	status := c.QueryParam("status")
+	operations, err := h.Approvals.List(status, 100)
+	if err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to list pending operations")
+		return apierror.New(apierror.InternalError)
+	}
+	return c.JSON(http.StatusOK, operations)
+}
+
+func (h *BackofficeHandler) Approve(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	var request models.DecisionRequest
+	_ = c.Bind(&request)
+
+	op, result, err := h.Approvals.Approve(auth.PrincipalFrom(c), id, request.Catatan)
+	if op != nil {
+		audit.SetChange(c, op.NoRekening, map[string]interface{}{"operation_id": op.ID, "status": models.OperasiPending},
+			map[string]interface{}{"operation_id": op.ID, "status": op.Status, "result": result})
+	}
+	if err != nil {
+		return approvalError(id, err)
+	}
+
+	return c.JSON(http.StatusOK, DecisionResponse{Operation: op, Result: result})
+}
+
+func (h *BackofficeHandler) Reject(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	var request models.DecisionRequest
+	_ = c.Bind(&request)
+
+	op, err := h.Approvals.Reject(auth.PrincipalFrom(c), id, request.Catatan)
+	if err != nil {
+		return approvalError(id, err)
+	}
+
+	audit.SetChange(c, op.NoRekening, map[string]interface{}{"operation_id": op.ID, "status": models.OperasiPending},
+		map[string]interface{}{"operation_id": op.ID, "status": op.Status})
+
+	return c.JSON(http.StatusOK, DecisionResponse{Operation: op})
+}
+
+func (h *BackofficeHandler) CreateStaff(c echo.Context) error {
+	var request models.CreateStaffRequest
+	log.WithContext(c.Request().Context()).Info("Starting CreateStaff process")
+
+	if err := c.Bind(&request); err != nil || request.Username == "" || len(request.Password) < 8 || !auth.ValidRole(request.Role) {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Invalid staff request")
+		return apierror.New(apierror.InvalidPayload)
+	}
+
+	hash, err := auth.HashSecret(request.Password)
+	if err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to hash password")
+		return apierror.New(apierror.InternalError)
+	}
+
+	staff := &models.Staff{Username: request.Username, PasswordHash: hash, Role: request.Role}
+	if err := repositories.CreateStaff(h.DB, staff); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return apierror.New(apierror.Duplicate, "username already used")
+		}
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to create staff")
+		return apierror.New(apierror.InternalError)
+	}
+
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"username": staff.Username,
+		"role":     staff.Role,
+	}).Info("Staff created successfully")
+
+	return c.JSON(http.StatusOK, staff)
+}
+
+// run mengonsultasikan policy lalu menjalankan aksi secara langsung atau
+// memasukkannya ke antrean maker-checker
+func (h *BackofficeHandler) run(c echo.Context, action policy.Action, noRekening string, nominal float64, alasan string, payload interface{}) error {
+	p := auth.PrincipalFrom(c)
+
+	switch h.Policy.Evaluate(p, action, nominal) {
+	case policy.Deny:
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"action": action,
+			"role":   p.Role,
+		}).Warn("Action denied by policy")
+		return apierror.New(apierror.Forbidden)
+
+	case policy.RequireApproval:
+		op, err := h.Approvals.Submit(p, action, noRekening, nominal, alasan, payload)
+		if err != nil {
+			log.WithContext(c.Request().Context()).WithFields(log.Fields{
+				"error":  err,
+				"action": action,
+			}).Error("Failed to submit operation for approval")
+			return apierror.New(apierror.InternalError)
+		}
+		audit.SetChange(c, noRekening, nil, map[string]interface{}{"operation_id": op.ID, "status": op.Status})
+		return c.JSON(http.StatusAccepted, ApprovalResponse{Remark: "Waiting for approval", Operation: op})
+	}
+
+	tx, err := h.DB.Begin()
+	if err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to start transaction")
+		return apierror.New(apierror.InternalError)
+	}
+	defer tx.Rollback()
+
+	before := accountSnapshot(tx, noRekening)
+
+	result, err := h.Approvals.Execute(tx, action, payload)
+	if err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error":  err,
+			"action": action,
+		}).Warn("Back-office action failed")
+		return backofficeError(err)
+	}
+
+	after := accountSnapshot(tx, noRekening)
+
+	if err := tx.Commit(); err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to commit transaction")
+		return apierror.New(apierror.InternalError)
+	}
+
+	audit.SetChange(c, noRekening, before, after)
+
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"action":     action,
+		"NoRekening": noRekening,
+	}).Info("Back-office action executed")
+
+	return c.JSON(http.StatusOK, ResultResponse{Result: result})
+}
+
+// accountSnapshot mengambil saldo dan status rekening untuk nilai sebelum/sesudah di log audit
+func accountSnapshot(executor repositories.Executor, noRekening string) map[string]interface{} {
+	nasabah, err := repositories.GetNasabahByNoRekening(executor, noRekening)
+	if err != nil {
+		return nil
+	}
+	return map[string]interface{}{"saldo": nasabah.Saldo, "status": nasabah.Status}
+}
+
+func approvalError(id int, err error) error {
+	var execErr *approval.ExecutionError
+	switch {
+	case errors.Is(err, approval.ErrNotFound):
+		return apierror.New(apierror.OperationNotFound)
+	case errors.Is(err, approval.ErrNotPending):
+		return apierror.New(apierror.OperationDecided)
+	case errors.Is(err, approval.ErrExpired):
+		return apierror.New(apierror.OperationExpired)
+	case errors.Is(err, approval.ErrSelfApproval):
+		return apierror.New(apierror.SelfApproval)
+	case errors.As(err, &execErr):
+		apiErr := backofficeError(execErr.Err)
+		apiErr.Errors = append(apiErr.Errors, "operation marked as failed")
+		return apiErr
+	}
+
+	log.WithFields(log.Fields{
+		"error":       err,
+		"OperationID": id,
+	}).Error("Failed to decide operation")
+	return apierror.New(apierror.InternalError)
+}
+
+func backofficeError(err error) *apierror.Error {
+	switch {
+	case errors.Is(err, backoffice.ErrInvalidRequest):
+		return apierror.New(apierror.InvalidPayload)
+	case errors.Is(err, backoffice.ErrRekeningNotFound):
+		return apierror.New(apierror.RekeningNotFound)
+	case errors.Is(err, backoffice.ErrTabunganNotFound):
+		return apierror.New(apierror.TransactionNotFound)
+	case errors.Is(err, backoffice.ErrRekeningBeku):
+		return apierror.New(apierror.RekeningFrozen)
+	case errors.Is(err, backoffice.ErrRekeningReview):
+		return apierror.New(apierror.RekeningUnderReview)
+	case errors.Is(err, backoffice.ErrAlreadyReversed):
+		return apierror.New(apierror.AlreadyReversed)
+	case errors.Is(err, backoffice.ErrNotReversible):
+		return apierror.New(apierror.NotReversible)
+	case errors.Is(err, repositories.ErrSaldoTidakCukup):
+		return apierror.New(apierror.InsufficientBalance)
+	case errors.Is(err, transaction.ErrTujuanNotFound):
+		return apierror.New(apierror.DestinationNotFound)
+	case errors.Is(err, transaction.ErrTujuanTidakAktif):
+		return apierror.New(apierror.DestinationInactive)
+	case errors.Is(err, transaction.ErrTujuanDitahan):
+		return apierror.New(apierror.DestinationHeld)
+	case errors.Is(err, reconcile.ErrInvalidCorrection):
+		return apierror.New(apierror.InvalidPayload)
+	case errors.Is(err, reconcile.ErrMismatchNotFound):
+		return apierror.New(apierror.MismatchNotFound)
+	case errors.Is(err, reconcile.ErrMismatchNotOpen):
+		return apierror.New(apierror.MismatchNotOpen)
+	case errors.Is(err, reconcile.ErrMismatchGone):
+		return apierror.New(apierror.BalanceAlreadyMatch)
+	}
+	return apierror.Wrap(apierror.InternalError, err)
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"golang-echo-postgresql/config"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ConfigHandler struct {
+	Config *config.Config
+}
+
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	return &ConfigHandler{Config: cfg}
+}
+
+// Dump menampilkan semua nilai konfigurasi beserta asalnya (default, file, env atau flag).
+// Password dan kunci disamarkan.
+func (h *ConfigHandler) Dump(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.Config.Redacted())
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"golang-echo-postgresql/db"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DBHandler struct {
+	Breaker *db.Breaker
+}
+
+func NewDBHandler(breaker *db.Breaker) *DBHandler {
+	return &DBHandler{Breaker: breaker}
+}
+
+// Stats menampilkan status circuit breaker dan statistik pool koneksi database
+func (h *DBHandler) Stats(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.Breaker.Stats())
+}
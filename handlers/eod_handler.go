@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/eod"
+	"golang-echo-postgresql/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+type EODHandler struct {
+	EOD *eod.Service
+}
+
+// EODStartedResponse dikirim dengan HTTP 202 saat EOD manual mulai berjalan
+type EODStartedResponse struct {
+	Remark  string `json:"remark"`
+	Tanggal string `json:"tanggal"`
+}
+
+func NewEODHandler(service *eod.Service) *EODHandler {
+	return &EODHandler{EOD: service}
+}
+
+// Status menampilkan tanggal bisnis yang terbuka, apakah EOD sedang berjalan dan
+// progres run EOD terakhir
+func (h *EODHandler) Status(c echo.Context) error {
+	status, err := h.EOD.Status()
+	if err != nil {
+		return eodError(err)
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// GetRun menampilkan run EOD untuk :tanggal beserta progres setiap step
+func (h *EODHandler) GetRun(c echo.Context) error {
+	tanggal, err := time.ParseInLocation(eod.DateLayout, c.Param("tanggal"), time.Local)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.tanggal: must be a date (YYYY-MM-DD)")
+	}
+
+	run, err := h.EOD.RunDetail(tanggal)
+	if err != nil {
+		return eodError(err)
+	}
+	return c.JSON(http.StatusOK, run)
+}
+
+// Run memulai atau melanjutkan EOD untuk satu tanggal (bawaan kemarin). EOD berjalan
+// di latar belakang; progresnya dipantau lewat endpoint status.
+func (h *EODHandler) Run(c echo.Context) error {
+	var request models.EODRunRequest
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	tanggal := time.Now().AddDate(0, 0, -1)
+	if request.Tanggal != "" {
+		t, err := time.ParseInLocation(eod.DateLayout, request.Tanggal, time.Local)
+		if err != nil {
+			return apierror.New(apierror.InvalidPayload, "tanggal: must be a date (YYYY-MM-DD)")
+		}
+		tanggal = t
+	}
+
+	if h.EOD.InProgress() {
+		return eodError(eod.ErrAlreadyRunning)
+	}
+	if err := h.EOD.Check(tanggal); err != nil {
+		return eodError(err)
+	}
+
+	triggeredBy := "-"
+	if p := auth.PrincipalFrom(c); p != nil {
+		triggeredBy = p.Type + ":" + strconv.Itoa(p.ID)
+	}
+	go func() {
+		if _, err := h.EOD.Close(context.Background(), tanggal, triggeredBy); err != nil {
+			log.WithContext(c.Request().Context()).WithFields(log.Fields{
+				"error":   err,
+				"tanggal": tanggal.Format(eod.DateLayout),
+			}).Error("Manual EOD failed")
+		}
+	}()
+
+	return c.JSON(http.StatusAccepted, EODStartedResponse{
+		Remark:  "EOD started",
+		Tanggal: tanggal.Format(eod.DateLayout),
+	})
+}
+
+func eodError(err error) error {
+	switch {
+	case errors.Is(err, eod.ErrRunNotFound):
+		return apierror.New(apierror.EODRunNotFound)
+	case errors.Is(err, eod.ErrAlreadyRunning):
+		return apierror.New(apierror.EODAlreadyRunning)
+	case errors.Is(err, eod.ErrAlreadyClosed):
+		return apierror.New(apierror.BusinessDateClosed)
+	case errors.Is(err, eod.ErrOutOfOrder):
+		return apierror.New(apierror.PreviousDateOpen)
+	case errors.Is(err, eod.ErrDateNotOver):
+		return apierror.New(apierror.BusinessDateNotEnded)
+	}
+
+	log.WithFields(log.Fields{
+		"error": err,
+	}).Error("EOD operation failed")
+	return apierror.New(apierror.InternalError)
+}
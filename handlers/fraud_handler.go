@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/fraud"
+	"golang-echo-postgresql/models"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+type FraudHandler struct {
+	Engine *fraud.Engine
+}
+
+func NewFraudHandler(engine *fraud.Engine) *FraudHandler {
+	return &FraudHandler{Engine: engine}
+}
+
+// ListFlagged menampilkan transaksi yang ditandai aturan fraud, difilter ?status=
+// (bawaan open) dan ?no_rekening=, dengan paginasi ?after_id= dan ?limit=
+func (h *FraudHandler) ListFlagged(c echo.Context) error {
+	filter := models.FraudDecisionFilter{
+		ReviewStatus: c.QueryParam("status"),
+		NoRekening:   c.QueryParam("no_rekening"),
+		Limit:        100,
+	}
+	if filter.ReviewStatus == "" {
+		filter.ReviewStatus = models.FraudReviewOpen
+	} else if filter.ReviewStatus == "all" {
+		filter.ReviewStatus = ""
+	}
+
+	if v := c.QueryParam("after_id"); v != "" {
+		afterID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return apierror.New(apierror.InvalidParameter, "query.after_id: must be an integer")
+		}
+		filter.AfterID = afterID
+	}
+	if v := c.QueryParam("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > 1000 {
+			return apierror.New(apierror.InvalidParameter, "query.limit: must be between 1 and 1000")
+		}
+		filter.Limit = limit
+	}
+
+	decisions, err := h.Engine.Flagged(filter)
+	if err != nil {
+		return fraudError(err)
+	}
+	return c.JSON(http.StatusOK, decisions)
+}
+
+func (h *FraudHandler) GetDecision(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	decision, err := h.Engine.Decision(id)
+	if err != nil {
+		return fraudError(err)
+	}
+	return c.JSON(http.StatusOK, decision)
+}
+
+func (h *FraudHandler) Review(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	var request models.FraudReviewRequest
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	decision, err := h.Engine.Review(auth.PrincipalFrom(c), id, request)
+	if err != nil {
+		return fraudError(err)
+	}
+
+	audit.SetChange(c, decision.NoRekening, map[string]interface{}{"review_status": models.FraudReviewOpen}, map[string]interface{}{"review_status": decision.ReviewStatus})
+	return c.JSON(http.StatusOK, decision)
+}
+
+// Rules menampilkan aturan fraud yang sedang berlaku beserta versinya
+func (h *FraudHandler) Rules(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.Engine.Rules())
+}
+
+// fraudRejected membentuk error untuk transaksi yang ditahan pemeriksaan fraud; decision_id
+// di metadata dipakai klien untuk merujuk keputusan tersebut
+func fraudRejected(decision *fraud.Decision) error {
+	code := apierror.StepUpRequired
+	if decision.Blocked() {
+		code = apierror.FraudBlocked
+	}
+	return apierror.New(code).With("decision_id", strconv.FormatInt(decision.ID, 10))
+}
+
+func fraudError(err error) error {
+	switch {
+	case errors.Is(err, fraud.ErrDecisionNotFound):
+		return apierror.New(apierror.FraudDecisionNotFound)
+	case errors.Is(err, fraud.ErrAlreadyReviewed):
+		return apierror.New(apierror.FraudDecisionClosed)
+	case errors.Is(err, fraud.ErrInvalidReview):
+		return apierror.New(apierror.InvalidPayload, "status: must be one of confirmed_fraud, false_positive")
+	}
+
+	log.WithFields(log.Fields{
+		"error": err,
+	}).Error("Fraud review operation failed")
+	return apierror.New(apierror.InternalError)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/eod"
+	"golang-echo-postgresql/gl"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+type GLHandler struct {
+	GL *gl.Service
+}
+
+func NewGLHandler(service *gl.Service) *GLHandler {
+	return &GLHandler{GL: service}
+}
+
+// Mapping menampilkan bagan akun dan pemetaan transaksi yang sedang berlaku
+func (h *GLHandler) Mapping(c echo.Context) error {
+	if !h.GL.Enabled() {
+		return glError(gl.ErrNoMapping)
+	}
+	return c.JSON(http.StatusOK, h.GL.Mapping)
+}
+
+// Vouchers mengekspor voucher jurnal ?tanggal= (bawaan kemarin) sebagai ?format=json atau csv
+func (h *GLHandler) Vouchers(c echo.Context) error {
+	tanggal, err := glTanggal(c)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "query.tanggal: must be a date (YYYY-MM-DD)")
+	}
+	format := c.QueryParam("format")
+	if format == "" {
+		format = gl.FormatJSON
+	}
+	if format != gl.FormatJSON && format != gl.FormatCSV {
+		return apierror.New(apierror.InvalidParameter, "query.format: must be json or csv")
+	}
+
+	vouchers, err := h.GL.Vouchers(tanggal)
+	if err != nil {
+		return glError(err)
+	}
+
+	if format == gl.FormatCSV {
+		c.Response().Header().Set(echo.HeaderContentType, "text/csv")
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="jurnal-`+tanggal.Format(eod.DateLayout)+`.csv"`)
+		c.Response().WriteHeader(http.StatusOK)
+		return gl.WriteCSV(c.Response(), vouchers)
+	}
+	return c.JSON(http.StatusOK, vouchers)
+}
+
+// TrialBalance menampilkan neraca saldo sampai ?tanggal= (bawaan kemarin) beserta
+// pemeriksaan saldo akun tabungan terhadap jumlah saldo rekening
+func (h *GLHandler) TrialBalance(c echo.Context) error {
+	tanggal, err := glTanggal(c)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "query.tanggal: must be a date (YYYY-MM-DD)")
+	}
+
+	tb, err := h.GL.TrialBalance(tanggal)
+	if err != nil {
+		return glError(err)
+	}
+	return c.JSON(http.StatusOK, tb)
+}
+
+func glTanggal(c echo.Context) (time.Time, error) {
+	if v := c.QueryParam("tanggal"); v != "" {
+		return time.ParseInLocation(eod.DateLayout, v, time.Local)
+	}
+	return time.Now().AddDate(0, 0, -1), nil
+}
+
+func glError(err error) error {
+	switch {
+	case errors.Is(err, gl.ErrNoMapping):
+		return apierror.New(apierror.GLMappingUnavailable)
+	case errors.Is(err, gl.ErrNotClosed):
+		return apierror.New(apierror.BusinessDateNotClosed)
+	}
+
+	log.WithFields(log.Fields{
+		"error": err,
+	}).Error("GL operation failed")
+	return apierror.New(apierror.InternalError)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"golang-echo-postgresql/grpcapi"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type GRPCHandler struct {
+	Server *grpcapi.Server
+}
+
+// GRPCStatsResponse adalah response /grpc/stats
+type GRPCStatsResponse struct {
+	Enabled bool                  `json:"enabled"`
+	Methods []grpcapi.MethodStats `json:"methods"`
+}
+
+func NewGRPCHandler(server *grpcapi.Server) *GRPCHandler {
+	return &GRPCHandler{Server: server}
+}
+
+// Stats menampilkan jumlah dan durasi RPC per method dan kode status. Server bernilai
+// nil jika gRPC dinonaktifkan (GRPC_PORT=0).
+func (h *GRPCHandler) Stats(c echo.Context) error {
+	if h.Server == nil {
+		return c.JSON(http.StatusOK, GRPCStatsResponse{Methods: []grpcapi.MethodStats{}})
+	}
+	return c.JSON(http.StatusOK, GRPCStatsResponse{Enabled: true, Methods: h.Server.Metrics.Snapshot()})
+}
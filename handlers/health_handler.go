@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"golang-echo-postgresql/health"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HealthHandler struct {
+	Checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{Checker: checker}
+}
+
+// Live menjawab probe liveness; 200 selama proses masih bisa melayani request, juga saat
+// database tidak tersedia, supaya orchestrator tidak me-restart pod yang hanya menunggu
+// database pulih
+func (h *HealthHandler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.Checker.Live())
+}
+
+// Ready menjawab probe readiness; 503 jika salah satu pemeriksaan gagal atau aplikasi
+// sedang shutdown
+func (h *HealthHandler) Ready(c echo.Context) error {
+	report := h.Checker.Ready()
+	if !report.Ready() {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+	return c.JSON(http.StatusOK, report)
+}
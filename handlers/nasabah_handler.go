@@ -1,10 +1,13 @@
 package handlers
 
 import (
-	"database/sql"
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/audit"
 	"golang-echo-postgresql/models"
-	"golang-echo-postgresql/repositories"
-	"golang-echo-postgresql/utils"
+	"golang-echo-postgresql/onboarding"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/transaction"
 	"net/http"
 	"strings"
 
@@ -12,277 +15,271 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// NasabahHandler melayani pendaftaran dan transaksi nasabah lewat Store, sehingga bisa
+// dijalankan di atas Postgres maupun store in-memory. Pendaftaran didelegasikan ke
+// onboarding.Service; setor, tarik dan transfer ke transaction.Service.
 type NasabahHandler struct {
-	DB *sql.DB
+	Store        store.Store
+	Transactions *transaction.Service
+	Onboarding   *onboarding.Service
 }
 
-func NewNasabahHandler(db *sql.DB) *NasabahHandler {
-	return &NasabahHandler{DB: db}
+// RegisterResponse adalah response pendaftaran nasabah. Status hanya diisi jika
+// rekening ditahan untuk review kepatuhan (HTTP 202).
+type RegisterResponse struct {
+	NoRekening string `json:"no_rekening"`
+	Status     string `json:"status,omitempty"`
+}
+
+// SaldoResponse adalah response tarik dana dan cek saldo
+type SaldoResponse struct {
+	Saldo float64 `json:"saldo"`
+}
+
+func NewNasabahHandler(st store.Store, transactions *transaction.Service, onboard *onboarding.Service) *NasabahHandler {
+	return &NasabahHandler{Store: st, Transactions: transactions, Onboarding: onboard}
+}
+
+// transactionError menerjemahkan error domain transaksi menjadi error katalog
+func transactionError(err error) error {
+	var fraudErr *transaction.FraudError
+	switch {
+	case errors.As(err, &fraudErr):
+		return fraudRejected(fraudErr.Decision)
+	case errors.Is(err, transaction.ErrInvalidAmount):
+		return apierror.New(apierror.InvalidAmount)
+	case errors.Is(err, transaction.ErrInvalidRequest):
+		return apierror.New(apierror.InvalidPayload)
+	case errors.Is(err, transaction.ErrSameRekening):
+		return apierror.New(apierror.SameRekening)
+	case errors.Is(err, transaction.ErrForbidden):
+		return apierror.New(apierror.Forbidden)
+	case errors.Is(err, transaction.ErrRekeningNotLinked):
+		return apierror.New(apierror.RekeningNotLinked)
+	case errors.Is(err, transaction.ErrRekeningNotFound), errors.Is(err, store.ErrNotFound):
+		return apierror.New(apierror.RekeningNotFound)
+	case errors.Is(err, transaction.ErrTujuanNotFound):
+		return apierror.New(apierror.DestinationNotFound)
+	case errors.Is(err, transaction.ErrRekeningBeku):
+		return apierror.New(apierror.RekeningFrozen)
+	case errors.Is(err, transaction.ErrRekeningReview):
+		return apierror.New(apierror.RekeningUnderReview)
+	case errors.Is(err, transaction.ErrTujuanTidakAktif):
+		return apierror.New(apierror.DestinationInactive)
+	case errors.Is(err, transaction.ErrTujuanDitahan):
+		return apierror.New(apierror.DestinationHeld)
+	case errors.Is(err, transaction.ErrSaldoTidakCukup):
+		return apierror.New(apierror.InsufficientBalance)
+	case errors.Is(err, transaction.ErrApprovalUnavailable):
+		return apierror.New(apierror.ApprovalUnavailable)
+	}
+	return apierror.Wrap(apierror.InternalError, err)
 }
 
 func (h *NasabahHandler) RegisterNasabah(c echo.Context) error {
-	var nasabah models.Nasabah
-	log.Info("Starting RegisterNasabah process")
+	var request models.RegisterNasabahRequest
+	log.WithContext(c.Request().Context()).Info("Starting RegisterNasabah process")
 
-	if err := c.Bind(&nasabah); err != nil {
-		log.WithFields(log.Fields{
+	if err := c.Bind(&request); err != nil {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
 			"error": err,
 		}).Error("Failed to bind request data")
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "Invalid request format"})
+		return err
 	}
 
-	log.WithFields(log.Fields{
-		"Nama": nasabah.Nama,
-		"NIK":  nasabah.NIK,
-		"NoHP": nasabah.NoHP,
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"Nama": request.Nama,
+		"NIK":  request.NIK,
+		"NoHP": request.NoHP,
 	}).Info("Validating NIK and No HP")
 
-	if !utils.ValidateNIK(nasabah.NIK) || !utils.ValidateNoHP(nasabah.NoHP) {
-		log.Warn("Invalid NIK or No HP format")
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "Invalid NIK or No HP format"})
-	}
-
-	exists, fields, err := repositories.CheckExistingNasabah(h.DB, nasabah.NIK, nasabah.NoHP)
-	if err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("Database error while checking existing nasabah")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Internal server error"})
-	}
-
-	if exists {
-		log.WithFields(log.Fields{
+	res, err := h.Onboarding.Register(c.Request().Context(), onboarding.Registration{
+		Nama: request.Nama,
+		NIK:  request.NIK,
+		NoHP: request.NoHP,
+		PIN:  request.PIN,
+	})
+	var dupErr *onboarding.DuplicateError
+	switch {
+	case errors.Is(err, onboarding.ErrInvalidIdentity):
+		log.WithContext(c.Request().Context()).Warn("Invalid NIK or No HP format")
+		return apierror.New(apierror.InvalidIdentity)
+	case errors.Is(err, onboarding.ErrInvalidPIN):
+		log.WithContext(c.Request().Context()).Warn("Invalid PIN format")
+		return apierror.New(apierror.InvalidPIN)
+	case errors.As(err, &dupErr):
+		fields := dupErr.Fields
+		if len(fields) == 0 {
+			fields = []string{"NIK or No HP"}
+		}
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
 			"fields": fields,
 		}).Warn("Duplicate nasabah detected")
-		return c.JSON(http.StatusBadRequest, utils.Response{
-			Remark: "Duplicate detected",
-			Errors: []string{strings.Join(fields, " and ") + " already used"},
-		})
-	}
-
-	nasabah.NoRekening = utils.GenerateAccountNumber()
-	log.WithFields(log.Fields{
-		"NoRekening": nasabah.NoRekening,
-	}).Info("Generated account number")
-
-	err = repositories.CreateNasabah(h.DB, &nasabah)
-	if err != nil {
-		log.WithFields(log.Fields{
+		return apierror.New(apierror.Duplicate, strings.Join(fields, " and ")+" already used")
+	case err != nil:
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
 			"error": err,
 		}).Error("Failed to register nasabah")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to register nasabah"})
+		return apierror.New(apierror.InternalError)
 	}
 
-	log.WithFields(log.Fields{
-		"NoRekening": nasabah.NoRekening,
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"NoRekening": res.Nasabah.NoRekening,
+		"status":     res.Nasabah.Status,
 	}).Info("Nasabah registered successfully")
 
-	return c.JSON(http.StatusOK, map[string]string{"no_rekening": nasabah.NoRekening})
+	audit.SetChange(c, res.Nasabah.NoRekening, nil, map[string]interface{}{"nama": res.Nasabah.Nama, "status": res.Nasabah.Status})
+
+	if res.Held {
+		return c.JSON(http.StatusAccepted, RegisterResponse{NoRekening: res.Nasabah.NoRekening, Status: res.Nasabah.Status})
+	}
+	return c.JSON(http.StatusOK, RegisterResponse{NoRekening: res.Nasabah.NoRekening})
 }
 
 func (h *NasabahHandler) TarikDana(c echo.Context) error {
-	var request models.Tabung
-	log.Info("Starting TarikDana process")
+	var request TabungRequest
+	log.WithContext(c.Request().Context()).Info("Starting TarikDana process")
 
 	if err := c.Bind(&request); err != nil {
-		log.WithFields(log.Fields{
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
 			"error": err,
 		}).Error("Failed to bind request data")
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "Invalid request payload"})
-	}
-
-	tx, err := h.DB.Begin()
-	if err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("Failed to start transaction")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to start transaction"})
+		return err
 	}
 
-	nasabah, err := repositories.GetNasabahByNoRekening(tx, request.NoRekening)
+	res, err := h.Transactions.Withdraw(c.Request().Context(), transaction.Withdraw{
+		NoRekening: request.NoRekening,
+		Nominal:    request.Nominal,
+		Meta:       requestMeta(c, request.Referensi, request.Keterangan),
+	})
 	if err != nil {
-		log.WithFields(log.Fields{
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
 			"error":      err,
 			"NoRekening": request.NoRekening,
-		}).Error("No rekening not found")
-		tx.Rollback()
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "No rekening not found"})
+		}).Warn("Withdrawal failed")
+		return transactionError(err)
 	}
 
-	if nasabah.Saldo < request.Nominal {
-		log.WithFields(log.Fields{
-			"Saldo":            nasabah.Saldo,
-			"RequestedNominal": request.Nominal,
-		}).Warn("Insufficient balance")
-		tx.Rollback()
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "Insufficient balance"})
+	if res.Status == transaction.StatusPendingApproval {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"NoRekening":  request.NoRekening,
+			"OperationID": res.Operation.ID,
+		}).Info("Large withdrawal waiting for approval")
+		audit.SetChange(c, request.NoRekening, nil, map[string]interface{}{"operation_id": res.Operation.ID, "status": res.Operation.Status})
+		return c.JSON(http.StatusAccepted, ApprovalResponse{Remark: "Waiting for approval", Operation: res.Operation})
 	}
 
-	nasabah.Saldo -= request.Nominal
-	err = repositories.UpdateSaldo(tx, nasabah.NoRekening, "tarik", request.Nominal)
-	if err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("Failed to update saldo")
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to process transaction"})
-	}
-
-	repositories.InsertTabungan(tx, nasabah.ID, "tarik", request.Nominal)
-
-	if err := tx.Commit(); err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("Failed to commit transaction")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to commit transaction"})
-	}
-
-	log.WithFields(log.Fields{
-		"NoRekening":     nasabah.NoRekening,
-		"RemainingSaldo": nasabah.Saldo,
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"NoRekening":     res.NoRekening,
+		"RemainingSaldo": res.SaldoAkhir,
 	}).Info("Transaction successful")
 
-	return c.JSON(http.StatusOK, map[string]interface{}{"saldo": nasabah.Saldo})
+	audit.SetChange(c, res.NoRekening, map[string]interface{}{"saldo": res.SaldoAwal}, map[string]interface{}{"saldo": res.SaldoAkhir})
+
+	return c.JSON(http.StatusOK, SaldoResponse{Saldo: res.SaldoAkhir})
 }
 
-func (h *NasabahHandler) Nabung(c echo.Context) error {
-	var request models.Tabung
-	log.Info("Starting Nabung process")
+// Transfer memindahkan dana dari rekening principal (atau rekening mana pun untuk petugas)
+// ke rekening lain
+func (h *NasabahHandler) Transfer(c echo.Context) error {
+	var request TransferRequest
+	log.WithContext(c.Request().Context()).Info("Starting Transfer process")
 
 	if err := c.Bind(&request); err != nil {
-		log.WithFields(log.Fields{
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
 			"error": err,
 		}).Error("Failed to bind request data")
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "Invalid request payload"})
+		return err
 	}
 
-	tx, err := h.DB.Begin()
+	res, err := h.Transactions.Transfer(c.Request().Context(), transaction.Transfer{
+		DariRekening: request.DariRekening,
+		KeRekening:   request.KeRekening,
+		Nominal:      request.Nominal,
+		Meta:         requestMeta(c, request.Referensi, request.Keterangan),
+	})
 	if err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("Failed to start transaction")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to start transaction"})
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error":        err,
+			"DariRekening": request.DariRekening,
+			"KeRekening":   request.KeRekening,
+		}).Warn("Transfer failed")
+		return transactionError(err)
 	}
 
-	nasabah, err := repositories.GetNasabahByNoRekening(tx, request.NoRekening)
-	if err != nil {
-		log.WithFields(log.Fields{
-			"error":      err,
-			"NoRekening": request.NoRekening,
-		}).Error("No rekening not found")
-		tx.Rollback()
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "No rekening not found"})
+	if res.Status == transaction.StatusPendingApproval {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"DariRekening": request.DariRekening,
+			"OperationID":  res.Operation.ID,
+		}).Info("Large transfer waiting for approval")
+		audit.SetChange(c, request.DariRekening, nil, map[string]interface{}{"operation_id": res.Operation.ID, "status": res.Operation.Status})
+		return c.JSON(http.StatusAccepted, ApprovalResponse{Remark: "Waiting for approval", Operation: res.Operation})
 	}
 
-	nasabah.Saldo += request.Nominal
-	err = repositories.UpdateSaldo(tx, nasabah.NoRekening, "setor", request.Nominal)
-	if err != nil {
-		log.WithFields(log.Fields{
-			"error":      err,
-			"NoRekening": nasabah.NoRekening,
-		}).Error("Failed to update saldo")
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to process transaction"})
-	}
-
-	repositories.InsertTabungan(tx, nasabah.ID, "setor", request.Nominal)
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"DariRekening": res.NoRekening,
+		"KeRekening":   res.KeRekening,
+		"Referensi":    res.Referensi,
+	}).Info("Transfer successful")
 
-	if err := tx.Commit(); err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("Failed to commit transaction")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to commit transaction"})
-	}
+	audit.SetChange(c, res.NoRekening, map[string]interface{}{"saldo": res.SaldoAwal},
+		map[string]interface{}{"saldo": res.SaldoAkhir, "ke_rekening": res.KeRekening, "referensi": res.Referensi})
 
-	log.WithFields(log.Fields{
-		"NoRekening":   nasabah.NoRekening,
-		"UpdatedSaldo": nasabah.Saldo,
-	}).Info("Deposit successful")
-
-	return c.JSON(http.StatusOK, map[string]interface{}{"saldo": nasabah.Saldo})
+	return c.JSON(http.StatusOK, res)
 }
 
 func (h *NasabahHandler) GetSaldo(c echo.Context) error {
 	noRekening := c.Param("no_rekening")
-	log.WithFields(log.Fields{
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
 		"NoRekening": noRekening,
 	}).Info("Starting GetSaldo process")
 
-	tx, err := h.DB.Begin()
-	if err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("Failed to start transaction")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to start transaction"})
-	}
-
-	saldo, err := repositories.GetSaldo(tx, noRekening)
+	var saldo float64
+	err := h.Store.Do(c.Request().Context(), func(uow store.UnitOfWork) error {
+		var err error
+		saldo, err = uow.Accounts().GetSaldo(noRekening)
+		return err
+	})
 	if err != nil {
-		log.WithFields(log.Fields{
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
 			"error":      err,
 			"NoRekening": noRekening,
-		}).Error("No rekening found")
-		tx.Rollback()
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "No rekening not found"})
-	}
-
-	if err := tx.Commit(); err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("Failed to commit transaction")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to commit transaction"})
+		}).Error("Failed to retrieve saldo")
+		return transactionError(err)
 	}
 
-	log.WithFields(log.Fields{
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
 		"NoRekening": noRekening,
 		"Saldo":      saldo,
 	}).Info("Retrieved saldo successfully")
 
-	return c.JSON(http.StatusOK, map[string]interface{}{"saldo": saldo})
+	return c.JSON(http.StatusOK, SaldoResponse{Saldo: saldo})
 }
 
 func (h *NasabahHandler) GetRiwayatTransaksi(c echo.Context) error {
 	noRekening := c.Param("no_rekening")
-	log.WithFields(log.Fields{
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
 		"NoRekening": noRekening,
 	}).Info("Starting GetRiwayatTransaksi process")
 
-	tx, err := h.DB.Begin()
+	var riwayat []models.Tabungan
+	err := h.Store.Do(c.Request().Context(), func(uow store.UnitOfWork) error {
+		nasabah, err := uow.Customers().GetByNoRekening(noRekening)
+		if err != nil {
+			return err
+		}
+		riwayat, err = uow.Transactions().ListByNasabah(nasabah.ID)
+		return err
+	})
 	if err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("Failed to start transaction")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to start transaction"})
-	}
-
-	nasabah, err := repositories.GetNasabahByNoRekening(tx, noRekening)
-	if err != nil {
-		log.WithFields(log.Fields{
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
 			"error":      err,
 			"NoRekening": noRekening,
-		}).Error("No rekening found")
-		tx.Rollback()
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "No rekening not found"})
-	}
-
-	riwayat, err := repositories.GetRiwayatTransaksi(tx, nasabah.ID)
-	if err != nil {
-		log.WithFields(log.Fields{
-			"error":     err,
-			"NasabahID": nasabah.ID,
 		}).Error("Failed to retrieve transaction history")
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to retrieve transaction history"})
-	}
-
-	if err := tx.Commit(); err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("Failed to commit transaction")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to commit transaction"})
+		return transactionError(err)
 	}
 
-	log.WithFields(log.Fields{
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
 		"NoRekening":   noRekening,
 		"Transactions": len(riwayat),
 	}).Info("Transaction history retrieved successfully")
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/onboarding"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/transaction"
+	"golang-echo-postgresql/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// headerTestRekening menggantikan autentikasi token di test: request dengan header ini
+// dijalankan sebagai nasabah pemilik rekening tersebut
+const headerTestRekening = "X-Test-Rekening"
+
+// newNasabahServer menjalankan handler nasabah di atas store in-memory, dengan route
+// dan envelope error yang sama dengan aplikasi
+func newNasabahServer(t *testing.T) *echo.Echo {
+	t.Helper()
+	mem := store.NewMemory()
+	transactions := transaction.NewService(mem, policy.New(1_000_000), nil, nil, nil)
+	h := NewNasabahHandler(mem, transactions, onboarding.NewService(mem, nil))
+
+	authenticate := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if noRekening := c.Request().Header.Get(headerTestRekening); noRekening != "" {
+				auth.SetPrincipal(c, &auth.Principal{Type: auth.SubjectNasabah, ID: 1, NoRekening: noRekening})
+			}
+			return next(c)
+		}
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.Handler
+	e.POST("/daftar", h.RegisterNasabah)
+	e.POST("/tabung", h.Tabung, authenticate)
+	e.POST("/tarik", h.TarikDana, authenticate)
+	e.POST("/transfer", h.Transfer, authenticate)
+	e.GET("/saldo/:no_rekening", h.GetSaldo, authenticate, auth.RequireRekeningAccess("no_rekening"))
+	return e
+}
+
+func do(t *testing.T, e *echo.Echo, method, path, noRekening, body string, out interface{}) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if noRekening != "" {
+		req.Header.Set(headerTestRekening, noRekening)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: invalid JSON %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func register(t *testing.T, e *echo.Echo, nik, noHP string) string {
+	t.Helper()
+	var res RegisterResponse
+	body := `{"nama":"Budi","nik":"` + nik + `","no_hp":"` + noHP + `","pin":"123456"}`
+	if status := do(t, e, http.MethodPost, "/daftar", "", body, &res); status != http.StatusOK {
+		t.Fatalf("daftar status = %d, want 200", status)
+	}
+	return res.NoRekening
+}
+
+func TestNasabahFlowOnMemoryStore(t *testing.T) {
+	e := newNasabahServer(t)
+	budi := register(t, e, "3171011501900001", "081234567801")
+	ani := register(t, e, "3171015501900002", "081234567802")
+
+	var saldo TabungResponse
+	if status := do(t, e, http.MethodPost, "/tabung", budi, `{"no_rekening":"`+budi+`","nominal":50000}`, &saldo); status != http.StatusOK {
+		t.Fatalf("tabung status = %d, want 200", status)
+	}
+	if saldo.Saldo != 50000 {
+		t.Errorf("saldo setelah tabung = %v, want 50000", saldo.Saldo)
+	}
+
+	var tarik SaldoResponse
+	if status := do(t, e, http.MethodPost, "/tarik", budi, `{"no_rekening":"`+budi+`","nominal":20000}`, &tarik); status != http.StatusOK {
+		t.Fatalf("tarik status = %d, want 200", status)
+	}
+	if tarik.Saldo != 30000 {
+		t.Errorf("saldo setelah tarik = %v, want 30000", tarik.Saldo)
+	}
+
+	var transfer transaction.TransferResult
+	body := `{"dari_rekening":"` + budi + `","ke_rekening":"` + ani + `","nominal":10000}`
+	if status := do(t, e, http.MethodPost, "/transfer", budi, body, &transfer); status != http.StatusOK {
+		t.Fatalf("transfer status = %d, want 200", status)
+	}
+	if transfer.SaldoAkhir != 20000 || !strings.HasPrefix(transfer.Referensi, "TRF") {
+		t.Errorf("transfer = %+v, want saldo 20000 and generated referensi", transfer)
+	}
+
+	var saldoAni SaldoResponse
+	if status := do(t, e, http.MethodGet, "/saldo/"+ani, ani, "", &saldoAni); status != http.StatusOK {
+		t.Fatalf("saldo status = %d, want 200", status)
+	}
+	if saldoAni.Saldo != 10000 {
+		t.Errorf("saldo ani = %v, want 10000", saldoAni.Saldo)
+	}
+}
+
+func TestNasabahErrorsOnMemoryStore(t *testing.T) {
+	e := newNasabahServer(t)
+	budi := register(t, e, "3171011501900001", "081234567801")
+	ani := register(t, e, "3171015501900002", "081234567802")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		noRekening string
+		body       string
+		status     int
+		code       apierror.Code
+	}{
+		{"duplicate nik", http.MethodPost, "/daftar", "", `{"nama":"Budi","nik":"3171011501900001","no_hp":"081234567809","pin":"123456"}`, http.StatusBadRequest, apierror.Duplicate},
+		{"invalid pin", http.MethodPost, "/daftar", "", `{"nama":"Cici","nik":"3171016001900003","no_hp":"081234567803","pin":"12"}`, http.StatusBadRequest, apierror.InvalidPIN},
+		{"deposit to other rekening", http.MethodPost, "/tabung", budi, `{"no_rekening":"` + ani + `","nominal":1000}`, http.StatusForbidden, apierror.Forbidden},
+		{"deposit without principal", http.MethodPost, "/tabung", "", `{"no_rekening":"` + budi + `","nominal":1000}`, http.StatusForbidden, apierror.Forbidden},
+		{"zero nominal", http.MethodPost, "/tabung", budi, `{"no_rekening":"` + budi + `","nominal":0}`, http.StatusBadRequest, apierror.InvalidAmount},
+		{"insufficient balance", http.MethodPost, "/tarik", budi, `{"no_rekening":"` + budi + `","nominal":1000}`, http.StatusBadRequest, apierror.InsufficientBalance},
+		{"withdraw from other rekening", http.MethodPost, "/tarik", ani, `{"no_rekening":"` + budi + `","nominal":1000}`, http.StatusForbidden, apierror.Forbidden},
+		{"transfer to self", http.MethodPost, "/transfer", budi, `{"dari_rekening":"` + budi + `","ke_rekening":"` + budi + `","nominal":1000}`, http.StatusBadRequest, apierror.SameRekening},
+		{"transfer to unknown", http.MethodPost, "/transfer", budi, `{"dari_rekening":"` + budi + `","ke_rekening":"0000000000","nominal":1000}`, http.StatusNotFound, apierror.DestinationNotFound},
+		{"large withdrawal without approvals", http.MethodPost, "/tarik", budi, `{"no_rekening":"` + budi + `","nominal":2000000}`, http.StatusServiceUnavailable, apierror.ApprovalUnavailable},
+		{"saldo of other rekening", http.MethodGet, "/saldo/" + ani, budi, "", http.StatusForbidden, apierror.Forbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res utils.Response
+			status := do(t, e, tt.method, tt.path, tt.noRekening, tt.body, &res)
+			if status != tt.status || res.Code != string(tt.code) {
+				t.Errorf("got %d %s, want %d %s", status, res.Code, tt.status, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/partner"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var endpointPattern = regexp.MustCompile(`^(GET|POST) /\S*$`)
+
+type PartnerHandler struct {
+	Partners *partner.Service
+}
+
+// CreatePartnerResponse berisi partner baru dan kredensialnya; secret hanya
+// ditampilkan sekali
+type CreatePartnerResponse struct {
+	Partner    *models.Partner                   `json:"partner"`
+	Credential *models.PartnerCredentialResponse `json:"credential"`
+}
+
+func NewPartnerHandler(partners *partner.Service) *PartnerHandler {
+	return &PartnerHandler{Partners: partners}
+}
+
+func (h *PartnerHandler) CreatePartner(c echo.Context) error {
+	var request models.CreatePartnerRequest
+	log.WithContext(c.Request().Context()).Info("Starting CreatePartner process")
+
+	if err := c.Bind(&request); err != nil || request.Nama == "" || len(request.AllowedEndpoints) == 0 {
+		log.WithContext(c.Request().Context()).WithFields(log.Fields{
+			"error": err,
+		}).Error("Invalid partner request")
+		return apierror.New(apierror.InvalidPayload)
+	}
+
+	var invalid []string
+	for _, endpoint := range request.AllowedEndpoints {
+		if !endpointPattern.MatchString(endpoint) {
+			invalid = append(invalid, endpoint)
+		}
+	}
+	if len(invalid) > 0 {
+		return apierror.New(apierror.InvalidPayload, "allowed_endpoints: "+strings.Join(invalid, ", ")+" must look like \"POST /tabung\"")
+	}
+
+	p, cred, err := h.Partners.Create(request)
+	if err != nil {
+		return partnerError(err)
+	}
+
+	return c.JSON(http.StatusOK, CreatePartnerResponse{Partner: p, Credential: cred})
+}
+
+func (h *PartnerHandler) RotateSecret(c echo.Context) error {
+	clientID := c.Param("client_id")
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"ClientID": clientID,
+	}).Info("Starting RotateSecret process")
+
+	cred, err := h.Partners.RotateSecret(clientID)
+	if err != nil {
+		return partnerError(err)
+	}
+
+	return c.JSON(http.StatusOK, cred)
+}
+
+// ListRekening menampilkan rekening yang terhubung dengan partner
+func (h *PartnerHandler) ListRekening(c echo.Context) error {
+	clientID := c.Param("client_id")
+
+	rekening, err := h.Partners.Rekening(clientID)
+	if err != nil {
+		return partnerError(err)
+	}
+	return c.JSON(http.StatusOK, models.PartnerRekeningResponse{ClientID: clientID, Rekening: rekening})
+}
+
+// LinkRekening menghubungkan rekening dengan partner
+func (h *PartnerHandler) LinkRekening(c echo.Context) error {
+	var request models.PartnerRekeningRequest
+	clientID := c.Param("client_id")
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"ClientID": clientID,
+	}).Info("Starting LinkRekening process")
+
+	if err := c.Bind(&request); err != nil || request.NoRekening == "" {
+		return apierror.New(apierror.InvalidPayload)
+	}
+	if err := h.Partners.LinkRekening(clientID, request.NoRekening); err != nil {
+		return partnerError(err)
+	}
+	return h.ListRekening(c)
+}
+
+// UnlinkRekening memutus hubungan rekening dengan partner
+func (h *PartnerHandler) UnlinkRekening(c echo.Context) error {
+	clientID := c.Param("client_id")
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"ClientID":   clientID,
+		"NoRekening": c.Param("no_rekening"),
+	}).Info("Starting UnlinkRekening process")
+
+	if err := h.Partners.UnlinkRekening(clientID, c.Param("no_rekening")); err != nil {
+		return partnerError(err)
+	}
+	return h.ListRekening(c)
+}
+
+func partnerError(err error) error {
+	switch {
+	case errors.Is(err, partner.ErrPartnerNotFound):
+		return apierror.New(apierror.PartnerNotFound)
+	case errors.Is(err, partner.ErrRekeningNotFound), errors.Is(err, partner.ErrNotLinked):
+		return apierror.New(apierror.RekeningNotFound)
+	case errors.Is(err, partner.ErrNotConfigured):
+		return apierror.New(apierror.PartnerSigningUnavailable)
+	}
+
+	log.WithFields(log.Fields{
+		"error": err,
+	}).Error("Partner operation failed")
+	return apierror.New(apierror.InternalError)
+}
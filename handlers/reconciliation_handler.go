@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/approval"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/reconcile"
+	"golang-echo-postgresql/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+type ReconciliationHandler struct {
+	Reconciler *reconcile.Service
+	Approvals  *approval.Queue
+}
+
+func NewReconciliationHandler(service *reconcile.Service, approvals *approval.Queue) *ReconciliationHandler {
+	return &ReconciliationHandler{Reconciler: service, Approvals: approvals}
+}
+
+// ListRuns menampilkan run rekonsiliasi terbaru
+func (h *ReconciliationHandler) ListRuns(c echo.Context) error {
+	limit, err := queryLimit(c)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "query.limit: must be between 1 and 1000")
+	}
+
+	runs, err := h.Reconciler.Runs(limit)
+	if err != nil {
+		return reconciliationError(err)
+	}
+	return c.JSON(http.StatusOK, runs)
+}
+
+// Run memulai rekonsiliasi di latar belakang; hasilnya dipantau lewat daftar run
+func (h *ReconciliationHandler) Run(c echo.Context) error {
+	triggeredBy := "-"
+	if p := auth.PrincipalFrom(c); p != nil {
+		triggeredBy = p.Type + ":" + strconv.Itoa(p.ID)
+	}
+	go func() {
+		if _, err := h.Reconciler.RunOnce(context.Background(), triggeredBy); err != nil && !errors.Is(err, reconcile.ErrAlreadyRunning) {
+			log.WithContext(c.Request().Context()).WithFields(log.Fields{
+				"error": err,
+			}).Error("Manual reconciliation failed")
+		}
+	}()
+
+	return c.JSON(http.StatusAccepted, utils.Response{Remark: "Reconciliation started"})
+}
+
+// ListMismatches menampilkan selisih rekonsiliasi, difilter ?status=
+func (h *ReconciliationHandler) ListMismatches(c echo.Context) error {
+	limit, err := queryLimit(c)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "query.limit: must be between 1 and 1000")
+	}
+
+	mismatches, err := h.Reconciler.Mismatches(c.QueryParam("status"), limit)
+	if err != nil {
+		return reconciliationError(err)
+	}
+	return c.JSON(http.StatusOK, mismatches)
+}
+
+func (h *ReconciliationHandler) GetMismatch(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	m, err := h.Reconciler.Mismatch(id)
+	if err != nil {
+		return reconciliationError(err)
+	}
+	return c.JSON(http.StatusOK, m)
+}
+
+// SubmitCorrection mengajukan koreksi selisih ke antrean maker-checker
+func (h *ReconciliationHandler) SubmitCorrection(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	var request models.CorrectionRequest
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	request.SelisihID = id
+
+	op, err := h.Reconciler.SubmitCorrection(auth.PrincipalFrom(c), h.Approvals, request)
+	if err != nil {
+		return reconciliationError(err)
+	}
+
+	audit.SetChange(c, op.NoRekening, nil, map[string]interface{}{"selisih_id": id, "operation_id": op.ID, "status": op.Status})
+	return c.JSON(http.StatusAccepted, ApprovalResponse{Remark: "Waiting for approval", Operation: op})
+}
+
+func reconciliationError(err error) error {
+	switch {
+	case errors.Is(err, reconcile.ErrMismatchNotFound):
+		return apierror.New(apierror.MismatchNotFound)
+	case errors.Is(err, reconcile.ErrMismatchNotOpen):
+		return apierror.New(apierror.MismatchNotOpen)
+	case errors.Is(err, reconcile.ErrInvalidCorrection):
+		return apierror.New(apierror.InvalidPayload, "mode: must be one of saldo, riwayat", "alasan: is required")
+	}
+
+	log.WithFields(log.Fields{
+		"error": err,
+	}).Error("Reconciliation operation failed")
+	return apierror.New(apierror.InternalError)
+}
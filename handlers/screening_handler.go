@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/screening"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxWatchlistSize membatasi ukuran file watchlist yang diunggah
+const maxWatchlistSize = 32 << 20
+
+type ScreeningHandler struct {
+	Service *screening.Service
+}
+
+func NewScreeningHandler(service *screening.Service) *ScreeningHandler {
+	return &ScreeningHandler{Service: service}
+}
+
+// ListHits menampilkan hit screening, difilter ?status= (bawaan pending, "all" untuk semua)
+func (h *ScreeningHandler) ListHits(c echo.Context) error {
+	status := c.QueryParam("status")
+	if status == "" {
+		status = models.HitPending
+	} else if status == "all" {
+		status = ""
+	}
+	limit, err := queryLimit(c)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "query.limit: must be between 1 and 1000")
+	}
+
+	hits, err := h.Service.Hits(status, limit)
+	if err != nil {
+		return screeningError(err)
+	}
+	return c.JSON(http.StatusOK, hits)
+}
+
+// Clear menandai hit sebagai bukan orang yang sama
+func (h *ScreeningHandler) Clear(c echo.Context) error {
+	return h.decide(c, h.Service.Clear)
+}
+
+// Confirm menandai hit sebagai kecocokan benar dan membekukan rekening
+func (h *ScreeningHandler) Confirm(c echo.Context) error {
+	return h.decide(c, h.Service.Confirm)
+}
+
+func (h *ScreeningHandler) decide(c echo.Context, fn func(*auth.Principal, int, string) (*models.ScreeningHit, error)) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	var request models.ScreeningDecisionRequest
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	hit, err := fn(auth.PrincipalFrom(c), id, request.Catatan)
+	if err != nil {
+		return screeningError(err)
+	}
+
+	audit.SetChange(c, hit.NoRekening, map[string]interface{}{"hit_status": models.HitPending}, map[string]interface{}{"hit_status": hit.Status})
+	return c.JSON(http.StatusOK, hit)
+}
+
+// ListWatchlists menampilkan watchlist beserta riwayat versinya
+func (h *ScreeningHandler) ListWatchlists(c echo.Context) error {
+	watchlists, err := h.Service.Watchlists()
+	if err != nil {
+		return screeningError(err)
+	}
+	return c.JSON(http.StatusOK, watchlists)
+}
+
+// Import mengunggah versi baru watchlist :kode dari body request mentah,
+// dengan format ?format=csv (bawaan) atau ?format=xml
+func (h *ScreeningHandler) Import(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = screening.FormatCSV
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWatchlistSize+1))
+	if err != nil {
+		return apierror.New(apierror.InvalidWatchlist, "file: cannot be read")
+	}
+	if len(data) > maxWatchlistSize {
+		return apierror.New(apierror.PayloadTooLarge, "file: watchlist file is too large")
+	}
+
+	version, err := h.Service.Import(auth.PrincipalFrom(c), c.Param("kode"), format, data)
+	if err != nil {
+		return screeningError(err)
+	}
+	return c.JSON(http.StatusCreated, version)
+}
+
+func screeningError(err error) error {
+	switch {
+	case errors.Is(err, screening.ErrHitNotFound):
+		return apierror.New(apierror.HitNotFound)
+	case errors.Is(err, screening.ErrHitNotPending):
+		return apierror.New(apierror.HitAlreadyReviewed)
+	case errors.Is(err, screening.ErrNoteRequired):
+		return apierror.New(apierror.InvalidPayload, "catatan: is required")
+	case errors.Is(err, screening.ErrInvalidFormat):
+		return apierror.New(apierror.InvalidWatchlist, "format: must be csv or xml")
+	case errors.Is(err, screening.ErrInvalidKode):
+		return apierror.New(apierror.InvalidWatchlist, "kode: is invalid")
+	case errors.Is(err, screening.ErrEmptyWatchlist):
+		return apierror.New(apierror.InvalidWatchlist, "file: has no entries")
+	case errors.Is(err, screening.ErrInvalidFile):
+		return apierror.New(apierror.InvalidWatchlist, err.Error())
+	}
+	log.WithFields(log.Fields{
+		"error": err,
+	}).Error("Screening request failed")
+	return apierror.New(apierror.InternalError)
+}
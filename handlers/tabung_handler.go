@@ -1,19 +1,31 @@
 package handlers
 
 import (
-	"database/sql"
-	"golang-echo-postgresql/repositories"
-	"golang-echo-postgresql/utils"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/fraud"
+	"golang-echo-postgresql/transaction"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 )
 
-// TabungRequest adalah struktur request untuk menabung
+// TabungRequest adalah struktur request untuk menabung atau menarik saldo
 type TabungRequest struct {
-	NoRekening string  `json:"no_rekening"`
-	Nominal    float64 `json:"nominal"`
+	NoRekening string  `json:"no_rekening" openapi:"required"`
+	Nominal    float64 `json:"nominal" openapi:"required,min=0,exclusive"`
+	Referensi  string  `json:"referensi,omitempty" openapi:"maxLength=64"`
+	Keterangan string  `json:"keterangan,omitempty"`
+}
+
+// TransferRequest adalah struktur request untuk transfer antarrekening
+type TransferRequest struct {
+	DariRekening string  `json:"dari_rekening" openapi:"required"`
+	KeRekening   string  `json:"ke_rekening" openapi:"required"`
+	Nominal      float64 `json:"nominal" openapi:"required,min=0,exclusive"`
+	Referensi    string  `json:"referensi,omitempty" openapi:"maxLength=64"`
+	Keterangan   string  `json:"keterangan,omitempty"`
 }
 
 // TabungResponse adalah struktur response setelah menabung
@@ -22,14 +34,24 @@ type TabungResponse struct {
 	Saldo  float64 `json:"saldo"`
 }
 
-func Tabung(c echo.Context) error {
+// requestMeta membentuk metadata perintah transaksi dari request HTTP
+func requestMeta(c echo.Context, referensi, keterangan string) transaction.Meta {
+	return transaction.Meta{
+		Referensi:  referensi,
+		Keterangan: keterangan,
+		Principal:  auth.PrincipalFrom(c),
+		StepUpPIN:  c.Request().Header.Get(fraud.HeaderStepUpPIN),
+	}
+}
+
+func (h *NasabahHandler) Tabung(c echo.Context) error {
 	var req TabungRequest
 	if err := c.Bind(&req); err != nil {
 		logrus.WithFields(logrus.Fields{
 			"handler": "Tabung",
 			"error":   err.Error(),
 		}).Warn("Failed to bind request body")
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "Invalid payload"})
+		return err
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -38,118 +60,32 @@ func Tabung(c echo.Context) error {
 		"Nominal":    req.Nominal,
 	}).Info("Received tabung request")
 
-	// Ambil koneksi database dari context Echo
-	db, ok := c.Get("db").(*sql.DB)
-	if !ok || db == nil {
-		logrus.WithFields(logrus.Fields{
-			"handler": "Tabung",
-		}).Error("Database connection is missing in context")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Internal server error"})
-	}
-
-	// Mulai transaksi
-	tx, err := db.Begin()
-	if err != nil {
-		logrus.WithFields(logrus.Fields{
-			"handler": "Tabung",
-			"error":   err.Error(),
-		}).Error("Failed to start transaction")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Internal server error"})
-	}
-	defer tx.Rollback() // Jika terjadi error, rollback transaksi
-
-	logrus.WithFields(logrus.Fields{
-		"handler":    "Tabung",
-		"NoRekening": req.NoRekening,
-	}).Info("Transaction started")
-
-	// Cek apakah no_rekening valid
-	nasabah, err := repositories.GetNasabahByNoRekening(tx, req.NoRekening)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			logrus.WithFields(logrus.Fields{
-				"handler":    "Tabung",
-				"NoRekening": req.NoRekening,
-			}).Warn("No rekening not found")
-			return c.JSON(http.StatusNotFound, utils.Response{Remark: "No rekening not found"})
-		}
-		logrus.WithFields(logrus.Fields{
-			"handler":    "Tabung",
-			"NoRekening": req.NoRekening,
-			"error":      err.Error(),
-		}).Error("Error retrieving nasabah")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "An error occurred on the server"})
-	}
-
-	logrus.WithFields(logrus.Fields{
-		"handler":      "Tabung",
-		"NoRekening":   req.NoRekening,
-		"CurrentSaldo": nasabah.Saldo,
-	}).Info("Nasabah found")
-
-	// Cek apakah nominal valid (> 0)
-	if req.Nominal <= 0 {
-		logrus.WithFields(logrus.Fields{
-			"handler":    "Tabung",
-			"NoRekening": req.NoRekening,
-			"Nominal":    req.Nominal,
-		}).Warn("Invalid deposit amount")
-		return c.JSON(http.StatusBadRequest, utils.Response{Remark: "Deposit amount must be greater than zero"})
-	}
-
-	// Update saldo nasabah dalam transaksi
-	nasabah.Saldo += req.Nominal
-	err = repositories.UpdateSaldo(tx, nasabah.NoRekening, "setor", nasabah.Saldo)
-	if err != nil {
-		logrus.WithFields(logrus.Fields{
-			"handler":    "Tabung",
-			"NoRekening": req.NoRekening,
-			"error":      err.Error(),
-		}).Error("Failed to topup balance")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to top up balance"})
-	}
-
-	logrus.WithFields(logrus.Fields{
-		"handler":    "Tabung",
-		"NoRekening": req.NoRekening,
-		"NewSaldo":   nasabah.Saldo,
-	}).Info("Saldo updated successfully")
-
-	// Catat transaksi tabungan dalam transaksi
-	err = repositories.InsertTabungan(tx, nasabah.ID, "setor", req.Nominal)
+	res, err := h.Transactions.Deposit(c.Request().Context(), transaction.Deposit{
+		NoRekening: req.NoRekening,
+		Nominal:    req.Nominal,
+		Meta:       requestMeta(c, req.Referensi, req.Keterangan),
+	})
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"handler":    "Tabung",
 			"NoRekening": req.NoRekening,
 			"error":      err.Error(),
-		}).Error("Failed to insert tabungan record")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Failed to record deposit"})
-	}
-
-	logrus.WithFields(logrus.Fields{
-		"handler":    "Tabung",
-		"NoRekening": req.NoRekening,
-	}).Info("Tabungan record inserted successfully")
-
-	// Commit transaksi hanya sekali, di sini
-	if err := tx.Commit(); err != nil {
-		logrus.WithFields(logrus.Fields{
-			"handler": "Tabung",
-			"error":   err.Error(),
-		}).Error("Failed to commit transaction")
-		return c.JSON(http.StatusInternalServerError, utils.Response{Remark: "Internal server error"})
+		}).Warn("Topup balance failed")
+		return transactionError(err)
 	}
 
 	// Log sukses
 	logrus.WithFields(logrus.Fields{
 		"handler":    "Tabung",
 		"NoRekening": req.NoRekening,
-		"NewSaldo":   nasabah.Saldo,
+		"NewSaldo":   res.SaldoAkhir,
 	}).Info("Topup balance success")
 
+	audit.SetChange(c, res.NoRekening, map[string]interface{}{"saldo": res.SaldoAwal}, map[string]interface{}{"saldo": res.SaldoAkhir})
+
 	// Return saldo nasabah yang terbaru
 	return c.JSON(http.StatusOK, TabungResponse{
 		Remark: "Topup successful",
-		Saldo:  nasabah.Saldo,
+		Saldo:  res.SaldoAkhir,
 	})
 }
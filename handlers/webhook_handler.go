@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/utils"
+	"golang-echo-postgresql/webhook"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+type WebhookHandler struct {
+	Webhooks *webhook.Service
+}
+
+func NewWebhookHandler(webhooks *webhook.Service) *WebhookHandler {
+	return &WebhookHandler{Webhooks: webhooks}
+}
+
+func (h *WebhookHandler) Subscribe(c echo.Context) error {
+	var request models.WebhookSubscriptionRequest
+	p := auth.PrincipalFrom(c)
+	log.WithContext(c.Request().Context()).WithFields(log.Fields{
+		"ClientID": p.ClientID,
+	}).Info("Starting webhook Subscribe process")
+
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	resp, err := h.Webhooks.Subscribe(p.ID, request)
+	if err != nil {
+		return webhookError(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *WebhookHandler) ListSubscriptions(c echo.Context) error {
+	subs, err := h.Webhooks.List(auth.PrincipalFrom(c).ID)
+	if err != nil {
+		return webhookError(err)
+	}
+	return c.JSON(http.StatusOK, subs)
+}
+
+func (h *WebhookHandler) Deactivate(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	if err := h.Webhooks.Deactivate(auth.PrincipalFrom(c).ID, id); err != nil {
+		return webhookError(err)
+	}
+	return c.JSON(http.StatusOK, utils.Response{Remark: "Webhook subscription deactivated"})
+}
+
+func (h *WebhookHandler) RotateSecret(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	resp, err := h.Webhooks.RotateSecret(auth.PrincipalFrom(c).ID, id)
+	if err != nil {
+		return webhookError(err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListDeliveries menampilkan log pengiriman milik partner, opsional difilter ?status=
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	return h.listDeliveries(c, auth.PrincipalFrom(c).ID, c.QueryParam("status"))
+}
+
+func (h *WebhookHandler) ListAttempts(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	attempts, err := h.Webhooks.Attempts(auth.PrincipalFrom(c).ID, id)
+	if err != nil {
+		return webhookError(err)
+	}
+	return c.JSON(http.StatusOK, attempts)
+}
+
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	return h.redeliver(c, auth.PrincipalFrom(c).ID)
+}
+
+// ListDeadLetters menampilkan pengiriman yang gagal permanen dari semua partner
+func (h *WebhookHandler) ListDeadLetters(c echo.Context) error {
+	return h.listDeliveries(c, 0, models.WebhookDead)
+}
+
+// RedeliverAny dipakai petugas untuk mengirim ulang pengiriman milik partner mana pun
+func (h *WebhookHandler) RedeliverAny(c echo.Context) error {
+	return h.redeliver(c, 0)
+}
+
+func (h *WebhookHandler) listDeliveries(c echo.Context, partnerID int, status string) error {
+	limit := 100
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			return apierror.New(apierror.InvalidParameter, "query.limit: must be between 1 and 1000")
+		}
+		limit = n
+	}
+
+	deliveries, err := h.Webhooks.Deliveries(partnerID, status, limit)
+	if err != nil {
+		return webhookError(err)
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) redeliver(c echo.Context, partnerID int) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apierror.New(apierror.InvalidParameter, "path.id: must be an integer")
+	}
+
+	if err := h.Webhooks.Redeliver(partnerID, id); err != nil {
+		return webhookError(err)
+	}
+	return c.JSON(http.StatusAccepted, utils.Response{Remark: "Webhook delivery scheduled"})
+}
+
+func webhookError(err error) error {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidSubscription):
+		return apierror.New(apierror.InvalidPayload, err.Error())
+	case errors.Is(err, webhook.ErrRekeningNotLinked):
+		return apierror.New(apierror.RekeningNotLinked, err.Error())
+	case errors.Is(err, webhook.ErrSubscriptionNotFound):
+		return apierror.New(apierror.SubscriptionNotFound)
+	case errors.Is(err, webhook.ErrDeliveryNotFound):
+		return apierror.New(apierror.DeliveryNotFound)
+	case errors.Is(err, webhook.ErrNotConfigured):
+		return apierror.New(apierror.WebhookUnavailable)
+	}
+
+	log.WithFields(log.Fields{
+		"error": err,
+	}).Error("Webhook operation failed")
+	return apierror.New(apierror.InternalError)
+}
@@ -0,0 +1,163 @@
+// Package health menyediakan liveness dan readiness untuk orchestrator. Liveness hanya
+// menyatakan proses masih melayani request; readiness memeriksa database, versi skema,
+// worker latar belakang dan status shutdown.
+package health
+
+import (
+	"context"
+	"fmt"
+	"golang-echo-postgresql/db"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Status laporan health
+const (
+	StatusOK       = "ok"
+	StatusNotReady = "not_ready"
+)
+
+// Check adalah hasil satu pemeriksaan readiness
+type Check struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Report adalah respons /healthz dan /readyz
+type Report struct {
+	Status string  `json:"status"`
+	Uptime string  `json:"uptime,omitempty"`
+	Checks []Check `json:"checks,omitempty"`
+}
+
+// Checker menyusun laporan health. Versi skema diperiksa berkala oleh Run supaya probe
+// readiness tidak menambah query ke database di setiap panggilan; status database
+// diambil dari circuit breaker.
+type Checker struct {
+	Breaker  *db.Breaker
+	Migrator *db.Migrator
+	Workers  *Workers
+	Interval time.Duration
+	Timeout  time.Duration
+
+	started  time.Time
+	draining atomic.Bool
+
+	mu     sync.RWMutex
+	schema Check
+}
+
+func NewChecker(breaker *db.Breaker, migrator *db.Migrator, workers *Workers, interval, timeout time.Duration) *Checker {
+	return &Checker{
+		Breaker:  breaker,
+		Migrator: migrator,
+		Workers:  workers,
+		Interval: interval,
+		Timeout:  timeout,
+		started:  time.Now(),
+		schema:   Check{Name: "schema", Detail: "not checked yet"},
+	}
+}
+
+// Run memeriksa versi skema setiap Interval sampai ctx dibatalkan
+func (h *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.CheckSchema(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckSchema membandingkan migrasi yang sudah diterapkan dengan migrasi di binary.
+// Binary yang lebih baru dari skema database tidak ready sampai migrasi dijalankan.
+func (h *Checker) CheckSchema(ctx context.Context) {
+	queryCtx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	check := Check{Name: "schema", OK: true}
+	pending, err := h.Migrator.Pending(queryCtx)
+	switch {
+	case ctx.Err() != nil:
+		// Dibatalkan saat shutdown; pertahankan hasil terakhir
+		return
+	case err != nil:
+		check.OK = false
+		check.Detail = "cannot read schema version"
+		log.WithError(err).Warn("Readiness schema check failed")
+	case len(pending) > 0:
+		check.OK = false
+		check.Detail = fmt.Sprintf("pending migrations %v, latest %d", pending, h.Migrator.Latest())
+	default:
+		check.Detail = fmt.Sprintf("version %d", h.Migrator.Latest())
+	}
+
+	h.mu.Lock()
+	h.schema = check
+	h.mu.Unlock()
+}
+
+// Drain menandai aplikasi sedang shutdown; readiness langsung gagal supaya load
+// balancer berhenti mengirim request baru sebelum listener ditutup
+func (h *Checker) Drain() {
+	h.draining.Store(true)
+}
+
+// Live mengembalikan laporan liveness; selalu ok selama proses bisa menjawab
+func (h *Checker) Live() Report {
+	return Report{Status: StatusOK, Uptime: time.Since(h.started).Round(time.Second).String()}
+}
+
+// Ready mengembalikan laporan readiness beserta hasil setiap pemeriksaan
+func (h *Checker) Ready() Report {
+	shutdown := Check{Name: "shutdown", OK: !h.draining.Load()}
+	if !shutdown.OK {
+		shutdown.Detail = "draining"
+	}
+
+	database := Check{Name: "database", OK: h.Breaker.Allow()}
+	if !database.OK {
+		database.Detail = "circuit breaker open"
+	}
+
+	h.mu.RLock()
+	schema := h.schema
+	h.mu.RUnlock()
+
+	var stopped []string
+	for _, w := range h.Workers.Status() {
+		if !w.Running {
+			stopped = append(stopped, w.Name)
+		}
+	}
+	background := Check{Name: "workers", OK: len(stopped) == 0}
+	if !background.OK {
+		background.Detail = "stopped: " + strings.Join(stopped, ", ")
+	}
+
+	report := Report{
+		Status: StatusOK,
+		Checks: []Check{shutdown, database, schema, background},
+	}
+	for _, check := range report.Checks {
+		if !check.OK {
+			report.Status = StatusNotReady
+		}
+	}
+	return report
+}
+
+// Ready melaporkan apakah semua pemeriksaan readiness lolos
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Workers menjalankan pekerjaan latar belakang (probe database, relay outbox, webhook,
+// EOD, ...) dengan satu context bersama dan mencatat statusnya untuk readiness. Saat
+// shutdown semua worker dihentikan bersamaan lalu ditunggu sampai selesai.
+type Workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	workers []*WorkerStatus
+}
+
+// WorkerStatus adalah status satu worker
+type WorkerStatus struct {
+	Name      string     `json:"name"`
+	Running   bool       `json:"running"`
+	StartedAt time.Time  `json:"started_at"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+}
+
+func NewWorkers() *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{ctx: ctx, cancel: cancel}
+}
+
+// Go menjalankan run di goroutine sendiri sampai Stop dipanggil. Worker yang berhenti
+// sebelum Stop membuat aplikasi tidak ready.
+func (w *Workers) Go(name string, run func(ctx context.Context)) {
+	st := &WorkerStatus{Name: name, Running: true, StartedAt: time.Now()}
+	w.mu.Lock()
+	w.workers = append(w.workers, st)
+	w.mu.Unlock()
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		run(w.ctx)
+
+		now := time.Now()
+		w.mu.Lock()
+		st.Running = false
+		st.StoppedAt = &now
+		w.mu.Unlock()
+		if w.ctx.Err() == nil {
+			log.WithFields(log.Fields{
+				"worker": name,
+			}).Error("Background worker stopped unexpectedly")
+		}
+	}()
+}
+
+// Status mengembalikan salinan status semua worker sesuai urutan start
+func (w *Workers) Status() []WorkerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out := make([]WorkerStatus, len(w.workers))
+	for i, st := range w.workers {
+		out[i] = *st
+	}
+	return out
+}
+
+// Stop membatalkan context semua worker lalu menunggu sampai semuanya selesai. Jika ctx
+// habis lebih dulu, error berisi nama worker yang masih berjalan.
+func (w *Workers) Stop(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		var running []string
+		for _, st := range w.Status() {
+			if st.Running {
+				running = append(running, st.Name)
+			}
+		}
+		return fmt.Errorf("worker belum berhenti: %s", strings.Join(running, ", "))
+	}
+}
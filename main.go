@@ -2,18 +2,47 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"golang-echo-postgresql/aml"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/approval"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/backoffice"
+	"golang-echo-postgresql/config"
 	"golang-echo-postgresql/db"
+	"golang-echo-postgresql/eod"
+	"golang-echo-postgresql/fraud"
+	"golang-echo-postgresql/gl"
+	"golang-echo-postgresql/grpcapi"
 	"golang-echo-postgresql/handlers"
+	"golang-echo-postgresql/health"
+	"golang-echo-postgresql/metrics"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/onboarding"
+	"golang-echo-postgresql/openapi"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/partner"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/ratelimit"
+	"golang-echo-postgresql/reconcile"
 	"golang-echo-postgresql/routes"
-	"log"
+	"golang-echo-postgresql/screening"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/tracing"
+	"golang-echo-postgresql/transaction"
+	"golang-echo-postgresql/webhook"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
-	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/sirupsen/logrus"
 )
 
@@ -23,56 +52,337 @@ func MethodNotAllowedHandler(next echo.HandlerFunc) echo.HandlerFunc {
 		if c.Request().Method != http.MethodPost && c.Request().Method != http.MethodGet {
 			// Log event Method Not Allowed dengan logrus
 			logrus.Warnf("Method Not Allowed: %s %s", c.Request().Method, c.Request().URL.Path)
-			// Kirim response kembali
-			return c.JSON(http.StatusMethodNotAllowed, map[string]string{
-				"message": "Method Not Allowed",
-			})
+			return apierror.New(apierror.MethodNotAllowed)
 		}
 		return next(c)
 	}
 }
 
 func main() {
+	// Konfigurasi berlapis: nilai bawaan, file .env (atau -config), environment, lalu -set
+	src := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	cfg, err := config.Load(*src)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
 	// Konfigurasi logrus
-	logrus.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-	})
-	logrus.SetLevel(logrus.DebugLevel) // Memastikan semua log ditampilkan
+	if cfg.LogFormat == "json" {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logrus.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp: true,
+		})
+	}
+	logrus.SetLevel(cfg.LogLevel)
+	logrus.AddHook(tracing.LogHook{})
 
-	// Memuat variabel lingkungan
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+	// Tracing OpenTelemetry; tanpa endpoint OTLP span tidak diekspor, tetapi trace
+	// context dari header masuk tetap diteruskan
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Endpoint:    cfg.TracingEndpoint,
+		ServiceName: cfg.TracingServiceName,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		logrus.Fatalf("Failed to initialize tracing: %v", err)
 	}
 
 	// Inisialisasi koneksi database
-	dbConn := db.InitDB()
+	dbConn := db.InitDB(cfg)
 	defer dbConn.Close()
 
-	// Inisialisasi Echo router
-	e := echo.New()
+	// Migrasi skema yang di-embed; replika lain menunggu advisory lock selama migrasi berjalan
+	migrator, err := db.NewMigrator(dbConn)
+	if err != nil {
+		logrus.Fatalf("Failed to load migrations: %v", err)
+	}
+	if cfg.DBMigrateOnStart {
+		if _, err := migrator.Up(context.Background()); err != nil {
+			logrus.Fatalf("Failed to migrate database: %v", err)
+		}
+	}
+
+	// Pekerjaan latar belakang, dihentikan bersamaan saat shutdown setelah server berhenti
+	// menerima request
+	workers := health.NewWorkers()
+
+	// Probe kesehatan database; selama tidak terjangkau request langsung ditolak dengan 503
+	breaker := db.NewBreaker(dbConn, cfg.DBHealthInterval, cfg.DBHealthTimeout, cfg.DBBreakerFailures)
+	workers.Go("db-breaker", breaker.Run)
+	metrics.RegisterDB(dbConn, func() bool { return !breaker.Allow() })
+
+	// Readiness memakai status breaker, versi skema dan worker latar belakang
+	checker := health.NewChecker(breaker, migrator, workers, cfg.DBHealthInterval, cfg.DBHealthTimeout)
+	workers.Go("readiness", checker.Run)
 
-	// Middleware untuk menyimpan koneksi database ke dalam context Echo
-	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			c.Set("db", dbConn)
-			return next(c)
+	// Kunci enkripsi untuk secret partner dan kunci privat JWT
+	var secretBox *partner.SecretBox
+	var keyBox auth.Sealer
+	if cfg.PartnerSecretKey != "" {
+		box, err := partner.NewSecretBox(cfg.PartnerSecretKey)
+		if err != nil {
+			logrus.Fatalf("Invalid PARTNER_SECRET_KEY: %v", err)
 		}
+		secretBox, keyBox = box, box
+	} else {
+		logrus.Warn("PARTNER_SECRET_KEY is not set, partner signed requests are disabled and JWT signing keys are stored unencrypted")
+	}
+
+	// Inisialisasi kunci JWT dan layanan token
+	keys := auth.NewKeyManager(dbConn, keyBox, cfg.KeyRotationInterval, cfg.AccessTokenTTL)
+	if err := keys.EnsureActive(); err != nil {
+		logrus.Fatalf("Failed to initialize signing keys: %v", err)
+	}
+	workers.Go("jwt-keys", keys.Run)
+	tokens := auth.NewTokenService(dbConn, keys, cfg.AuthIssuer, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+
+	if err := auth.BootstrapAdmin(dbConn, cfg.BootstrapAdminUser, cfg.BootstrapAdminPassword); err != nil {
+		logrus.Fatalf("Failed to bootstrap admin account: %v", err)
+	}
+
+	// Policy akses dan antrean maker-checker untuk operasi back-office
+	pol := policy.New(cfg.LargeWithdrawalThreshold)
+	approvals := approval.NewQueue(dbConn, cfg.ApprovalTTL)
+	backoffice.Register(approvals)
+	reconcile.Register(approvals)
+
+	// Kredensial partner untuk request bertanda tangan HMAC
+	partners := partner.NewService(dbConn, secretBox, cfg.PartnerClockSkew, cfg.PartnerSecretOverlap)
+	workers.Go("partner-nonces", partners.Run)
+
+	// Relay outbox untuk event domain (AccountOpened, FundsDeposited, FundsWithdrawn, ...)
+	events := outbox.NewInProcessPublisher()
+	events.Subscribe("*", func(ctx context.Context, event models.OutboxEvent) error {
+		logrus.WithFields(logrus.Fields{
+			"EventID":     event.ID,
+			"type":        event.Type,
+			"AggregateID": event.AggregateID,
+			"sequence":    event.Sequence,
+		}).Debug("Domain event published")
+		return nil
 	})
+	if cfg.OutboxPublisher == "filestream" {
+		// Stream lokal sebagai pengganti NATS/Redis Streams saat pengembangan
+		stream, err := outbox.NewFileStreamPublisher(cfg.OutboxStreamDir, "bank.events")
+		if err != nil {
+			logrus.Fatalf("Failed to initialize outbox stream: %v", err)
+		}
+		events.Subscribe("*", stream.Publish)
+	}
+
+	// Webhook partner: event dijadwalkan lewat outbox lalu dikirim oleh worker terpisah
+	webhooks := webhook.NewService(dbConn, secretBox, cfg.WebhookSecretOverlap, cfg.WebhookAllowInsecure)
+	events.Subscribe("*", webhooks.Dispatch)
+	deliverer := webhook.NewDeliverer(dbConn, secretBox, cfg.WebhookTimeout, cfg.WebhookMaxAttempts, cfg.OutboxBatchSize, cfg.WebhookPollInterval, cfg.WebhookAllowInsecure)
+	workers.Go("webhook-deliverer", deliverer.Run)
+
+	relay := outbox.NewRelay(dbConn, events, cfg.OutboxBatchSize, cfg.OutboxPollInterval)
+	workers.Go("outbox-relay", relay.Run)
+
+	// Aturan fraud dari file JSON, dimuat ulang otomatis jika file berubah
+	fraudEngine, err := fraud.NewEngine(dbConn, cfg.FraudRulesFile, cfg.FraudRulesReloadInterval)
+	if err != nil {
+		logrus.Fatalf("Failed to load fraud rules: %v", err)
+	}
+	workers.Go("fraud", fraudEngine.Run)
+
+	// Job AML harian: agregasi tunai, laporan LTKT, kasus mencurigakan dan ekspor PPATK
+	amlService := aml.NewService(dbConn, aml.Thresholds{
+		LTKT:             cfg.AMLLTKTThreshold,
+		StructuringFloor: cfg.AMLStructuringFloor,
+		PassThroughMin:   cfg.AMLPassThroughMin,
+	}, cfg.AMLExportDir, cfg.AMLReportingEntityID, cfg.AMLRunAt)
+	workers.Go("aml", amlService.Run)
+
+	// Screening watchlist saat registrasi dan untuk lawan transaksi transfer
+	screener, err := screening.NewService(dbConn, cfg.ScreeningThreshold, cfg.ScreeningRefreshInterval)
+	if err != nil {
+		logrus.Fatalf("Failed to load watchlists: %v", err)
+	}
+	workers.Go("screening", screener.Run)
+
+	// Setor, tarik dan transfer untuk semua channel; penarikan dan transfer besar yang
+	// disetujui checker dieksekusi lewat service yang sama. Pendaftaran nasabah beserta
+	// screening watchlist dipakai HTTP dan gRPC. Store in-memory hanya untuk pengembangan
+	// lokal: antrean persetujuan, aturan fraud, screening dan hubungan partner-rekening
+	// membutuhkan Postgres.
+	var st store.Store = store.NewPostgres(dbConn)
+	transactions := transaction.NewService(st, pol, approvals, fraudEngine, screener)
+	transactions.PartnerLinks = transaction.NewPartnerLinks(dbConn)
+	onboard := onboarding.NewService(st, screener)
+	if cfg.StoreBackend == store.BackendMemory {
+		logrus.Warn("STORE_BACKEND=memory: nasabah and transactions are lost on restart, approvals, fraud rules and screening are skipped, partner deposits are refused; Postgres is still used for tokens, back-office and audit")
+		st = store.NewMemory()
+		transactions = transaction.NewService(st, pol, nil, nil, nil)
+		onboard = onboarding.NewService(st, nil)
+		tokens.NoRekening = store.NoRekeningLookup(st)
+	}
+	transactions.Register(approvals)
+	workers.Go("approvals", approvals.Run)
+
+	// Proses tutup hari: snapshot saldo, bunga, biaya dan dormansi
+	eodService := eod.NewService(dbConn, cfg.EODBatchSize, cfg.EODRunAt, cfg.EODTransactionMode, cfg.EODQueueTimeout)
+	eodService.Register(eod.DefaultSteps(eod.Settings{
+		SukuBunga:      cfg.EODInterestRate,
+		BiayaAdmin:     cfg.EODMonthlyFee,
+		DormansiPeriod: cfg.EODDormancyDays,
+	})...)
+
+	// Jurnal GL harian dibentuk sebagai step EOD sesuai file pemetaan akun
+	ledger, err := gl.NewService(dbConn, cfg.GLMappingFile)
+	if err != nil {
+		logrus.Fatalf("Failed to load GL mapping: %v", err)
+	}
+	if ledger.Enabled() {
+		eodService.Register(ledger.Step())
+	}
+	workers.Go("eod", eodService.Run)
+
+	// Rekonsiliasi saldo terhadap riwayat transaksi secara berkala
+	reconciler := reconcile.NewService(dbConn, cfg.ReconBatchSize, cfg.ReconInterval)
+	workers.Go("reconciliation", reconciler.Run)
+
+	// Rate limit per IP, principal, partner dan rekening; bucket di memori atau di Postgres
+	// supaya dibagi semua replika
+	var limitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimitBackend == ratelimit.BackendPostgres {
+		limitStore = ratelimit.NewPostgresStore(dbConn)
+	}
+	limiter, err := ratelimit.New(limitStore, cfg.RateLimitFile)
+	if err != nil {
+		logrus.Fatalf("Failed to load rate limit policies: %v", err)
+	}
+	workers.Go("rate-limit-cleanup", limitStore.Run)
+
+	// Log audit berantai hash untuk setiap request yang mengubah state
+	recorder := audit.NewRecorder(dbConn)
+
+	// Server gRPC di port terpisah memakai service, audit, breaker, rate limit dan guard EOD yang sama
+	var grpcServer *grpcapi.Server
+	if cfg.GRPCPort != 0 {
+		grpcServer = grpcapi.New(grpcapi.Options{
+			Store:        st,
+			Transactions: transactions,
+			Onboarding:   onboard,
+			Tokens:       tokens,
+			Recorder:     recorder,
+			Breaker:      breaker,
+			EOD:          eodService,
+			Limiter:      limiter,
+			Timeout:      cfg.ServerWriteTimeout,
+		})
+		lis, err := net.Listen("tcp", cfg.GRPCAddr())
+		if err != nil {
+			logrus.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+		workers.Go("grpc-health", func(ctx context.Context) {
+			grpcServer.WatchHealth(ctx, cfg.DBHealthInterval)
+		})
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				logrus.Fatalf("gRPC server stopped: %v", err)
+			}
+		}()
+	}
+
+	// Dokumen OpenAPI dipakai untuk validasi request sekaligus disajikan di /openapi.json
+	spec, err := routes.Spec()
+	if err != nil {
+		logrus.Fatalf("Failed to build OpenAPI document: %v", err)
+	}
+
+	// Inisialisasi Echo router. Semua error (handler, Bind, router, panic) dibalas dengan
+	// envelope katalog apierror.
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.Handler
+
+	// IP klien untuk rate limit: X-Forwarded-For hanya dipercaya dari proxy di TRUSTED_PROXIES
+	e.IPExtractor = echo.ExtractIPDirect()
+	if proxies := cfg.TrustedProxyRanges(); len(proxies) > 0 {
+		trust := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+		for _, proxy := range proxies {
+			trust = append(trust, echo.TrustIPRange(proxy))
+		}
+		e.IPExtractor = echo.ExtractIPFromXFFHeader(trust...)
+	}
+
+	// Metrik HTTP dipasang paling luar agar penolakan middleware lain ikut tercatat, lalu
+	// span request kecuali probe health. Request ID dipakai untuk menautkan log aplikasi
+	// dengan log audit. Probe health tetap dijawab walaupun breaker database terbuka dan
+	// tidak dibatasi rate limit; request yang ditolak rate limit tidak ditulis ke log audit.
+	e.Use(metrics.Middleware())
+	e.Use(tracing.Middleware(routes.LivePath, routes.ReadyPath))
+	e.Use(audit.RequestID())
+	e.Use(breaker.Guard(routes.LivePath, routes.ReadyPath))
+	e.Use(limiter.Middleware(routes.LivePath, routes.ReadyPath))
+	e.Use(recorder.Middleware())
+	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			logrus.WithFields(logrus.Fields{
+				"path":  c.Path(),
+				"stack": string(stack),
+			}).Errorf("Recovered from panic: %v", err)
+			return err
+		},
+	}))
+	e.Use(openapi.Validator(spec))
 
 	// Daftarkan route handler untuk Nasabah
-	nasabahHandler := handlers.NewNasabahHandler(dbConn)
-	routes.RegisterRoutes(e, nasabahHandler)
+	routes.RegisterRoutes(e, routes.Dependencies{
+		Nasabah:       handlers.NewNasabahHandler(st, transactions, onboard),
+		Auth:          handlers.NewAuthHandler(dbConn, st, tokens),
+		Backoffice:    handlers.NewBackofficeHandler(dbConn, pol, approvals),
+		Partner:       handlers.NewPartnerHandler(partners),
+		Audit:         handlers.NewAuditHandler(recorder),
+		Webhook:       handlers.NewWebhookHandler(webhooks),
+		Fraud:         handlers.NewFraudHandler(fraudEngine),
+		AML:           handlers.NewAMLHandler(amlService),
+		Screening:     handlers.NewScreeningHandler(screener),
+		EOD:           handlers.NewEODHandler(eodService),
+		Reconcile:     handlers.NewReconciliationHandler(reconciler, approvals),
+		GL:            handlers.NewGLHandler(ledger),
+		Config:        handlers.NewConfigHandler(cfg),
+		DB:            handlers.NewDBHandler(breaker),
+		GRPC:          handlers.NewGRPCHandler(grpcServer),
+		Health:        handlers.NewHealthHandler(checker),
+		Authenticate:  auth.Authenticate(tokens),
+		VerifyPartner: partners.Verify(),
+		EODGuard:      eodService.Guard(),
+		RateLimit:     limiter.Identified(),
+		Spec:          spec,
+	})
 
 	// Menambahkan handler untuk method not allowed
 	e.Use(MethodNotAllowedHandler)
 
 	// Mulai server di goroutine terpisah
+	e.Server.ReadTimeout = cfg.ServerReadTimeout
+	e.Server.WriteTimeout = cfg.ServerWriteTimeout
 	go func() {
-		if err := e.Start(":8080"); err != nil {
+		if err := e.Start(cfg.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logrus.Fatalf("Shutting down the server: %v", err)
 		}
 	}()
 
+	// Endpoint /metrics Prometheus di listener terpisah, di luar breaker, auth dan audit
+	var metricsServer *http.Server
+	if cfg.MetricsPort != 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{
+			Addr:        cfg.MetricsAddr(),
+			Handler:     mux,
+			ReadTimeout: cfg.ServerReadTimeout,
+		}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logrus.Fatalf("Metrics server stopped: %v", err)
+			}
+		}()
+	}
+
 	// Membuat channel untuk mendengarkan sinyal penghentian
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -80,17 +390,65 @@ func main() {
 	// Menunggu sinyal penghentian
 	<-stop
 
-	// Inisiasi graceful shutdown
-	logrus.Info("Received shutdown signal. Shutting down gracefully...")
+	// Tahap 1: readiness gagal dan gRPC NOT_SERVING, server tetap melayani request selama
+	// SHUTDOWN_DRAIN_DELAY supaya load balancer sempat mengeluarkan instance ini. Sinyal
+	// kedua melewati jeda ini.
+	logrus.WithField("drain_delay", cfg.ShutdownDrainDelay).Info("Received shutdown signal. Draining...")
+	checker.Drain()
+	if grpcServer != nil {
+		grpcServer.Drain()
+	}
+	select {
+	case <-time.After(cfg.ShutdownDrainDelay):
+	case <-stop:
+		logrus.Warn("Received second shutdown signal, skipping drain delay")
+	}
 
-	// Membuat context dengan timeout untuk graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// Tahap 2: hentikan server HTTP dan gRPC bersamaan; request yang sedang berjalan diberi
+	// waktu SERVER_SHUTDOWN_TIMEOUT
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
-	// Coba untuk menghentikan server Echo secara graceful
+	var wg sync.WaitGroup
+	if grpcServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := grpcServer.Shutdown(ctx); err != nil {
+				logrus.Errorf("Error during gRPC graceful shutdown: %v", err)
+			} else {
+				logrus.Info("gRPC server shut down gracefully")
+			}
+		}()
+	}
+
 	if err := e.Shutdown(ctx); err != nil {
 		logrus.Errorf("Error during graceful shutdown: %v", err)
 	} else {
 		logrus.Info("Server shut down gracefully")
 	}
+	wg.Wait()
+
+	// Tahap 3: hentikan worker latar belakang setelah tidak ada request yang bisa
+	// menambah pekerjaan baru (outbox, webhook, ...)
+	workerCtx, cancelWorkers := context.WithTimeout(context.Background(), cfg.WorkerShutdownTimeout)
+	defer cancelWorkers()
+	if err := workers.Stop(workerCtx); err != nil {
+		logrus.Errorf("Error during background worker shutdown: %v", err)
+	} else {
+		logrus.Info("Background workers stopped")
+	}
+
+	// Tahap 4: metrics terakhir tetap bisa di-scrape sampai di sini, lalu kirim span yang
+	// tersisa sebelum koneksi database ditutup
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelFlush()
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(flushCtx); err != nil {
+			logrus.Errorf("Error during metrics server shutdown: %v", err)
+		}
+	}
+	if err := shutdownTracing(flushCtx); err != nil {
+		logrus.Errorf("Error during tracing shutdown: %v", err)
+	}
 }
@@ -0,0 +1,61 @@
+package metrics
+
+import "golang-echo-postgresql/models"
+
+// Jenis transaksi untuk label type
+const (
+	TransactionDeposit  = "setor"
+	TransactionWithdraw = "tarik"
+	TransactionTransfer = "transfer"
+)
+
+// Langkah funnel pendaftaran nasabah, berurutan
+const (
+	RegistrationStarted   = "started"   // request pendaftaran diterima
+	RegistrationValidated = "validated" // NIK, No HP dan PIN lolos validasi format
+	RegistrationCreated   = "created"   // rekening dibuat (NIK dan No HP belum dipakai)
+	RegistrationActive    = "active"    // lolos screening, rekening langsung aktif
+	RegistrationHeld      = "held"      // cocok dengan watchlist, rekening ditahan pending_review
+)
+
+// channel membatasi label channel ke nilai yang dikenal; channel dari request yang tidak
+// valid dicatat sebagai unknown
+func channel(ch string) string {
+	switch ch {
+	case models.ChannelApp, models.ChannelTeller, models.ChannelPartner, models.ChannelSistem:
+		return ch
+	}
+	return "unknown"
+}
+
+// TransactionCompleted mencatat transaksi yang selesai dibukukan beserta nominalnya
+func TransactionCompleted(kind, ch string, nominal float64) {
+	ch = channel(ch)
+	transactions.WithLabelValues(kind, ch, "completed").Inc()
+	transactionAmount.WithLabelValues(kind, ch).Add(nominal)
+}
+
+// TransactionPending mencatat transaksi yang masuk antrean persetujuan. Nominalnya baru
+// dihitung saat dieksekusi setelah disetujui.
+func TransactionPending(kind, ch string) {
+	transactions.WithLabelValues(kind, channel(ch), "pending_approval").Inc()
+}
+
+// TransactionRejected mencatat transaksi yang ditolak. reason harus berasal dari
+// himpunan tetap, lihat transaction.Reason.
+func TransactionRejected(kind, ch, reason string) {
+	ch = channel(ch)
+	transactions.WithLabelValues(kind, ch, "rejected").Inc()
+	transactionsRejected.WithLabelValues(kind, ch, reason).Inc()
+}
+
+// RegistrationStep mencatat pendaftaran yang mencapai satu langkah funnel
+func RegistrationStep(step string) {
+	registrationSteps.WithLabelValues(step).Inc()
+}
+
+// RegistrationRejected mencatat pendaftaran yang ditolak. reason harus berasal dari
+// himpunan tetap, lihat onboarding.Reason.
+func RegistrationRejected(reason string) {
+	registrationsRejected.WithLabelValues(reason).Inc()
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// RegisterDB menambahkan statistik pool koneksi (go_sql_*) dan status circuit breaker
+// database. Statistik pool memakai label db_name="bank"; open melaporkan apakah breaker
+// sedang terbuka.
+func RegisterDB(db *sql.DB, open func() bool) {
+	Registry.MustRegister(
+		collectors.NewDBStatsCollector(db, namespace),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_breaker_open",
+			Help:      "1 jika circuit breaker database terbuka dan request ditolak 503.",
+		}, func() float64 {
+			if open() {
+				return 1
+			}
+			return 0
+		}),
+	)
+}
+
+// ObserveQuery mencatat durasi satu query database. statement adalah kata kunci pertama
+// SQL (select, insert, ...), tidak pernah teks query atau argumennya.
+func ObserveQuery(operation, statement string, failed bool, d time.Duration) {
+	outcome := "ok"
+	if failed {
+		outcome = "error"
+	}
+	dbQueryDuration.WithLabelValues(operation, statement, outcome).Observe(d.Seconds())
+}
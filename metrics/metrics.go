@@ -0,0 +1,119 @@
+// Package metrics berisi semua metrik Prometheus aplikasi: request HTTP dan gRPC, query
+// dan pool database, serta counter bisnis (transaksi dan pendaftaran nasabah).
+//
+// Semua metrik didefinisikan di package ini supaya label mudah diaudit. Label hanya boleh
+// berisi nilai dari himpunan kecil yang tetap (route template, kode status, channel,
+// alasan penolakan). Nomor rekening, NIK, No HP dan nilai lain dari request tidak pernah
+// dipakai sebagai label.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bank"
+
+// Registry menampung semua metrik aplikasi beserta metrik runtime Go dan proses
+var Registry = prometheus.NewRegistry()
+
+// Bucket durasi dalam detik, dari 5ms sampai 10s
+var durationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Jumlah request HTTP per method, route template dan status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Durasi request HTTP per method, route template dan status.",
+		Buckets:   durationBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Jumlah request HTTP yang sedang diproses.",
+	})
+
+	grpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "Jumlah RPC per method dan kode status gRPC.",
+	}, []string{"method", "code"})
+
+	grpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Durasi RPC per method dan kode status gRPC.",
+		Buckets:   durationBuckets,
+	}, []string{"method", "code"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Durasi query database per operasi (query atau exec), jenis statement dan hasil.",
+		Buckets:   durationBuckets,
+	}, []string{"operation", "statement", "outcome"})
+
+	transactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_total",
+		Help:      "Jumlah transaksi per jenis (setor, tarik, transfer), channel dan status (completed, pending_approval, rejected).",
+	}, []string{"type", "channel", "status"})
+
+	transactionAmount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transaction_amount_rupiah_total",
+		Help:      "Jumlah nominal transaksi yang selesai dibukukan, dalam rupiah.",
+	}, []string{"type", "channel"})
+
+	transactionsRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_rejected_total",
+		Help:      "Jumlah transaksi yang ditolak per jenis, channel dan alasan.",
+	}, []string{"type", "channel", "reason"})
+
+	registrationSteps = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registration_steps_total",
+		Help:      "Jumlah pendaftaran nasabah yang mencapai setiap langkah funnel.",
+	}, []string{"step"})
+
+	registrationsRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_rejected_total",
+		Help:      "Jumlah pendaftaran nasabah yang ditolak per alasan.",
+	}, []string{"reason"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Jumlah request yang ditolak 429 per policy rate limit dan jenis kunci.",
+	}, []string{"policy", "key"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, httpInFlight,
+		grpcRequests, grpcDuration,
+		dbQueryDuration,
+		transactions, transactionAmount, transactionsRejected,
+		registrationSteps, registrationsRejected,
+		rateLimited,
+	)
+}
+
+// Handler menyajikan Registry dalam format teks Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
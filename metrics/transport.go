@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// routeUnmatched adalah label route untuk request yang tidak cocok dengan route mana pun,
+// supaya path acak dari pemindai tidak menambah seri baru
+const routeUnmatched = "unmatched"
+
+// Middleware mencatat jumlah dan durasi request HTTP per route template (misalnya
+// /saldo/:no_rekening, bukan nomor rekeningnya). Pasang paling luar agar penolakan dari
+// middleware lain, seperti circuit breaker, ikut tercatat dengan status akhirnya.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			httpInFlight.Inc()
+			defer httpInFlight.Dec()
+
+			start := time.Now()
+			if err := next(c); err != nil {
+				// Tulis response sekarang agar status yang dicatat sama dengan yang diterima klien
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" || route == "/*" {
+				route = routeUnmatched
+			}
+			method := methodLabel(c.Request().Method)
+			status := strconv.Itoa(c.Response().Status)
+			httpRequests.WithLabelValues(method, route, status).Inc()
+			httpDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
+
+// methodLabel membatasi label method ke method HTTP standar
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace:
+		return method
+	}
+	return "other"
+}
+
+// ObserveRPC mencatat satu RPC gRPC; method adalah nama lengkap seperti
+// /bank.v1.BankService/Deposit
+func ObserveRPC(method, code string, d time.Duration) {
+	grpcRequests.WithLabelValues(method, code).Inc()
+	grpcDuration.WithLabelValues(method, code).Observe(d.Seconds())
+}
+
+// RateLimited mencatat request yang ditolak rate limit. policy adalah id policy dari file
+// rate limit, bukan nilai kuncinya (IP, principal atau nomor rekening).
+func RateLimited(policy, key string) {
+	rateLimited.WithLabelValues(policy, key).Inc()
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status kasus AML
+const (
+	AMLCaseOpen          = "open"
+	AMLCaseInvestigating = "investigating"
+	AMLCaseFiled         = "filed"
+	AMLCaseClosed        = "closed"
+)
+
+// Jenis laporan PPATK
+const (
+	LaporanLTKT = "ltkt" // Laporan Transaksi Keuangan Tunai
+	LaporanLTKM = "ltkm" // Laporan Transaksi Keuangan Mencurigakan
+)
+
+// AMLDailyAggregate adalah ringkasan transaksi tunai satu nasabah pada satu hari
+type AMLDailyAggregate struct {
+	NasabahID   int       `json:"nasabah_id"`
+	Tanggal     time.Time `json:"tanggal"`
+	NoRekening  string    `json:"no_rekening"`
+	TotalSetor  float64   `json:"total_setor"`
+	TotalTarik  float64   `json:"total_tarik"`
+	JumlahSetor int       `json:"jumlah_setor"`
+	JumlahTarik int       `json:"jumlah_tarik"`
+}
+
+// LTKTReport adalah satu baris laporan transaksi tunai di atas ambang batas
+type LTKTReport struct {
+	ID              int       `json:"id"`
+	NasabahID       int       `json:"nasabah_id"`
+	Tanggal         time.Time `json:"tanggal"`
+	Arah            string    `json:"arah"`
+	NoRekening      string    `json:"no_rekening"`
+	Nama            string    `json:"nama"`
+	NIK             string    `json:"nik"`
+	Total           float64   `json:"total"`
+	JumlahTransaksi int       `json:"jumlah_transaksi"`
+	ExportID        *int      `json:"export_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AMLCase adalah kasus aktivitas mencurigakan
+type AMLCase struct {
+	ID             int             `json:"id"`
+	NasabahID      int             `json:"nasabah_id"`
+	NoRekening     string          `json:"no_rekening"`
+	Tanggal        time.Time       `json:"tanggal"`
+	Tipologi       string          `json:"tipologi"`
+	Detail         json.RawMessage `json:"detail"`
+	Status         string          `json:"status"`
+	AssignedTo     *int            `json:"assigned_to,omitempty"`
+	ReferensiPPATK string          `json:"referensi_ppatk,omitempty"`
+	FiledAt        *time.Time      `json:"filed_at,omitempty"`
+	ExportID       *int            `json:"export_id,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	Notes          []AMLCaseNote   `json:"notes,omitempty"`
+}
+
+// AMLCaseNote adalah catatan perpindahan status kasus
+type AMLCaseNote struct {
+	ID         int       `json:"id"`
+	CaseID     int       `json:"case_id"`
+	StaffID    *int      `json:"staff_id,omitempty"`
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	Catatan    string    `json:"catatan,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AMLExport adalah satu file laporan yang dihasilkan job ekspor
+type AMLExport struct {
+	ID           int       `json:"id"`
+	JenisLaporan string    `json:"jenis_laporan"`
+	Tanggal      time.Time `json:"tanggal"`
+	Format       string    `json:"format"`
+	FilePath     string    `json:"file_path"`
+	JumlahRecord int       `json:"jumlah_record"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AMLRun adalah ringkasan job AML untuk satu tanggal
+type AMLRun struct {
+	Tanggal    time.Time  `json:"tanggal"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	LTKTCount  int        `json:"ltkt_count"`
+	CaseCount  int        `json:"case_count"`
+}
+
+// OpenAMLCaseRequest adalah model untuk request pembukaan kasus manual
+type OpenAMLCaseRequest struct {
+	NoRekening string `json:"no_rekening" openapi:"required"`
+	Tipologi   string `json:"tipologi"`
+	Catatan    string `json:"catatan" openapi:"required"`
+}
+
+// AMLCaseTransitionRequest adalah model untuk request perpindahan status kasus
+type AMLCaseTransitionRequest struct {
+	Status         string `json:"status" openapi:"required,enum=investigating|filed|closed"`
+	Catatan        string `json:"catatan" openapi:"required"`
+	ReferensiPPATK string `json:"referensi_ppatk"`
+}
+
+// AMLRunRequest adalah model untuk request menjalankan ulang job AML
+type AMLRunRequest struct {
+	Tanggal string `json:"tanggal" openapi:"required,format=date"` // format 2006-01-02
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// AuditEvent adalah satu baris log audit. Setiap baris menyimpan hash baris sebelumnya
+// sehingga perubahan atau penghapusan baris bisa dideteksi.
+type AuditEvent struct {
+	ID         int64     `json:"id"`
+	RequestID  string    `json:"request_id,omitempty"`
+	ActorType  string    `json:"actor_type"`
+	ActorID    string    `json:"actor_id"`
+	Action     string    `json:"action"`
+	NoRekening string    `json:"no_rekening,omitempty"`
+	Before     string    `json:"before,omitempty"`
+	After      string    `json:"after,omitempty"`
+	StatusCode int       `json:"status_code"`
+	CreatedAt  time.Time `json:"created_at"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// AuditFilter adalah filter untuk pencarian log audit
+type AuditFilter struct {
+	NoRekening string
+	ActorType  string
+	ActorID    string
+	AfterID    int64
+	Limit      int
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Staff adalah model untuk petugas bank (teller, supervisor, auditor, admin)
+type Staff struct {
+	ID           int       `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	Aktif        bool      `json:"aktif"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// LoginNasabahRequest adalah model untuk request login nasabah
+type LoginNasabahRequest struct {
+	NoRekening string `json:"no_rekening" openapi:"required"`
+	PIN        string `json:"pin" openapi:"required"`
+}
+
+// LoginStaffRequest adalah model untuk request login petugas
+type LoginStaffRequest struct {
+	Username string `json:"username" openapi:"required"`
+	Password string `json:"password" openapi:"required"`
+}
+
+// RefreshTokenRequest adalah model untuk request rotasi refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenResponse adalah pasangan access token dan refresh token yang dikembalikan ke klien
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// RefreshToken adalah model untuk baris pada tabel refresh_tokens
+type RefreshToken struct {
+	ID          int
+	TokenHash   string
+	FamilyID    string
+	SubjectType string
+	SubjectID   int
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+	RevokedAt   *time.Time
+}
+
+// SigningKey adalah model untuk kunci penandatangan JWT yang tersimpan di database
+type SigningKey struct {
+	KID        string
+	PrivateKey string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status operasi yang menunggu persetujuan (maker-checker)
+const (
+	OperasiPending  = "pending"
+	OperasiApproved = "approved"
+	OperasiRejected = "rejected"
+	OperasiExpired  = "expired"
+	OperasiFailed   = "failed"
+)
+
+// AdjustmentRequest adalah model untuk request penyesuaian saldo oleh petugas
+type AdjustmentRequest struct {
+	NoRekening string  `json:"no_rekening" openapi:"required"`
+	Arah       string  `json:"arah" openapi:"required,enum=kredit|debit"`
+	Nominal    float64 `json:"nominal" openapi:"required,min=0,exclusive"`
+	Alasan     string  `json:"alasan" openapi:"required"`
+}
+
+// ReversalRequest adalah model untuk request pembatalan (reversal) transaksi
+type ReversalRequest struct {
+	TabunganID int    `json:"tabungan_id" openapi:"required,min=1"`
+	Alasan     string `json:"alasan" openapi:"required"`
+}
+
+// FreezeRequest adalah model untuk request pembekuan atau pembukaan blokir rekening
+type FreezeRequest struct {
+	NoRekening string `json:"no_rekening" openapi:"required"`
+	Alasan     string `json:"alasan" openapi:"required"`
+}
+
+// DecisionRequest adalah model untuk keputusan checker atas operasi yang menunggu persetujuan
+type DecisionRequest struct {
+	Catatan string `json:"catatan"`
+}
+
+// CreateStaffRequest adalah model untuk request pembuatan akun petugas
+type CreateStaffRequest struct {
+	Username string `json:"username" openapi:"required"`
+	Password string `json:"password" openapi:"required,minLength=8"`
+	Role     string `json:"role" openapi:"required,enum=teller|supervisor|auditor|compliance|admin"`
+}
+
+// PendingOperation adalah operasi berisiko tinggi yang menunggu persetujuan checker
+type PendingOperation struct {
+	ID             int             `json:"id"`
+	Action         string          `json:"action"`
+	NoRekening     string          `json:"no_rekening,omitempty"`
+	Nominal        float64         `json:"nominal,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	MakerType      string          `json:"maker_type"`
+	MakerID        int             `json:"maker_id"`
+	Alasan         string          `json:"alasan,omitempty"`
+	CheckerID      *int            `json:"checker_id,omitempty"`
+	CatatanChecker string          `json:"catatan_checker,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ExpiresAt      time.Time       `json:"expires_at"`
+	DecidedAt      *time.Time      `json:"decided_at,omitempty"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Status run dan step EOD
+const (
+	EODRunning   = "running"
+	EODFailed    = "failed"
+	EODCompleted = "completed"
+	EODPending   = "pending"
+	EODSkipped   = "skipped"
+)
+
+// EODRun adalah proses tutup hari untuk satu tanggal bisnis
+type EODRun struct {
+	ID          int        `json:"id"`
+	Tanggal     time.Time  `json:"tanggal"`
+	Status      string     `json:"status"`
+	TriggeredBy string     `json:"triggered_by"`
+	Attempts    int        `json:"attempts"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	Steps       []EODStep  `json:"steps,omitempty"`
+}
+
+// EODStep adalah progres satu step EOD. Checkpoint adalah id nasabah terakhir yang
+// sudah diproses sehingga step yang gagal bisa dilanjutkan dari titik itu.
+type EODStep struct {
+	Step       string     `json:"step"`
+	Urutan     int        `json:"urutan"`
+	Status     string     `json:"status"`
+	Checkpoint int        `json:"checkpoint"`
+	Diproses   int        `json:"diproses"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// EODStatus adalah ringkasan tanggal bisnis dan run EOD terakhir
+type EODStatus struct {
+	TanggalBisnis   string  `json:"tanggal_bisnis"`
+	TanggalTerakhir string  `json:"tanggal_tutup_terakhir,omitempty"`
+	InProgress      bool    `json:"in_progress"`
+	LastRun         *EODRun `json:"last_run,omitempty"`
+}
+
+// EODRunRequest adalah model untuk request menjalankan EOD secara manual
+type EODRunRequest struct {
+	Tanggal string `json:"tanggal" openapi:"format=date"`
+}
+
+// SaldoHarian adalah saldo penutupan satu rekening pada satu tanggal bisnis
+type SaldoHarian struct {
+	Tanggal   time.Time `json:"tanggal"`
+	NasabahID int       `json:"nasabah_id"`
+	Saldo     float64   `json:"saldo"`
+}
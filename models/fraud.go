@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Hasil penilaian fraud, dari yang paling ringan
+const (
+	FraudAllow     = "allow"
+	FraudChallenge = "challenge"
+	FraudBlock     = "block"
+)
+
+// Status review analis untuk transaksi yang ditandai
+const (
+	FraudReviewOpen           = "open"
+	FraudReviewConfirmedFraud = "confirmed_fraud"
+	FraudReviewFalsePositive  = "false_positive"
+)
+
+// FraudRuleResult adalah hasil satu aturan pada satu penilaian
+type FraudRuleResult struct {
+	RuleID  string `json:"rule_id"`
+	Type    string `json:"type"`
+	Matched bool   `json:"matched"`
+	Outcome string `json:"outcome,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// FraudDecision adalah satu baris log keputusan mesin aturan fraud
+type FraudDecision struct {
+	ID             int64           `json:"id"`
+	NoRekening     string          `json:"no_rekening"`
+	JenisTransaksi string          `json:"jenis_transaksi"`
+	Nominal        float64         `json:"nominal"`
+	Channel        string          `json:"channel"`
+	ActorType      string          `json:"actor_type"`
+	ActorID        string          `json:"actor_id"`
+	Outcome        string          `json:"outcome"`
+	Rules          json.RawMessage `json:"rules"`
+	RulesVersion   string          `json:"rules_version"`
+	StepUpPassed   bool            `json:"step_up_passed"`
+	ReviewStatus   string          `json:"review_status,omitempty"`
+	ReviewedBy     *int            `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time      `json:"reviewed_at,omitempty"`
+	ReviewNote     string          `json:"review_note,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// FraudDecisionFilter adalah filter untuk daftar keputusan fraud
+type FraudDecisionFilter struct {
+	ReviewStatus string
+	NoRekening   string
+	AfterID      int64
+	Limit        int
+}
+
+// FraudReviewRequest adalah model untuk request review analis
+type FraudReviewRequest struct {
+	Status  string `json:"status" openapi:"required,enum=confirmed_fraud|false_positive"`
+	Catatan string `json:"catatan"`
+}
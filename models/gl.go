@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Jenis akun buku besar
+const (
+	AkunAset       = "aset"
+	AkunLiabilitas = "liabilitas"
+	AkunEkuitas    = "ekuitas"
+	AkunPendapatan = "pendapatan"
+	AkunBeban      = "beban"
+)
+
+// GLAccount adalah satu akun pada bagan akun (chart of accounts)
+type GLAccount struct {
+	Kode  string `json:"kode"`
+	Nama  string `json:"nama"`
+	Jenis string `json:"jenis"`
+}
+
+// NormalDebit mengembalikan true untuk akun bersaldo normal debit (aset dan beban)
+func (a GLAccount) NormalDebit() bool {
+	return a.Jenis == AkunAset || a.Jenis == AkunBeban
+}
+
+// GLVoucher adalah voucher jurnal harian untuk satu jenis transaksi dan channel
+type GLVoucher struct {
+	ID              int             `json:"id"`
+	Tanggal         time.Time       `json:"tanggal"`
+	Nomor           string          `json:"nomor"`
+	JenisTransaksi  string          `json:"jenis_transaksi"`
+	Channel         string          `json:"channel,omitempty"`
+	AkunDebit       string          `json:"akun_debit"`
+	AkunKredit      string          `json:"akun_kredit"`
+	JumlahTransaksi int             `json:"jumlah_transaksi"`
+	Total           float64         `json:"total"`
+	MappingVersion  string          `json:"mapping_version"`
+	Lines           []GLVoucherLine `json:"lines"`
+}
+
+// GLVoucherLine adalah satu baris debit atau kredit pada voucher jurnal
+type GLVoucherLine struct {
+	Akun     string  `json:"akun"`
+	NamaAkun string  `json:"nama_akun"`
+	Debit    float64 `json:"debit"`
+	Kredit   float64 `json:"kredit"`
+}
+
+// TrialBalanceLine adalah mutasi kumulatif satu akun sampai tanggal neraca saldo
+type TrialBalanceLine struct {
+	Kode   string  `json:"kode"`
+	Nama   string  `json:"nama"`
+	Jenis  string  `json:"jenis"`
+	Debit  float64 `json:"debit"`
+	Kredit float64 `json:"kredit"`
+	Saldo  float64 `json:"saldo"` // saldo pada sisi normal akun
+}
+
+// LiabilityCheck membandingkan saldo akun tabungan nasabah di buku besar dengan jumlah saldo rekening
+type LiabilityCheck struct {
+	Akun         string  `json:"akun"`
+	SaldoGL      float64 `json:"saldo_gl"`
+	SaldoNasabah float64 `json:"saldo_nasabah"`
+	Selisih      float64 `json:"selisih"`
+	Cocok        bool    `json:"cocok"`
+}
+
+// TrialBalance adalah neraca saldo buku besar per tanggal bisnis
+type TrialBalance struct {
+	Tanggal     string             `json:"tanggal"`
+	Akun        []TrialBalanceLine `json:"akun"`
+	TotalDebit  float64            `json:"total_debit"`
+	TotalKredit float64            `json:"total_kredit"`
+	Seimbang    bool               `json:"seimbang"`
+	Tabungan    LiabilityCheck     `json:"tabungan"`
+}
@@ -2,6 +2,42 @@ package models
 
 import "time"
 
+// Status rekening nasabah
+const (
+	StatusAktif         = "aktif"
+	StatusBeku          = "beku"
+	StatusPendingReview = "pending_review" // menunggu review compliance atas hit watchlist
+)
+
+// Jenis transaksi pada tabel tabungan
+const (
+	JenisSetor          = "setor"
+	JenisTarik          = "tarik"
+	JenisKoreksiKredit  = "koreksi_kredit"
+	JenisKoreksiDebit   = "koreksi_debit"
+	JenisBunga          = "bunga"           // kapitalisasi bunga bulanan dari EOD
+	JenisBiayaAdmin     = "biaya_admin"     // biaya administrasi bulanan dari EOD
+	JenisTransferKeluar = "transfer_keluar" // sisi debit transfer antarrekening
+	JenisTransferMasuk  = "transfer_masuk"  // sisi kredit transfer antarrekening
+)
+
+// Channel asal transaksi
+const (
+	ChannelApp     = "app"
+	ChannelTeller  = "teller"
+	ChannelPartner = "partner"
+	ChannelSistem  = "sistem" // dibukukan oleh proses sistem seperti EOD dan rekonsiliasi
+)
+
+// IsKredit mengembalikan true jika jenis transaksi menambah saldo
+func IsKredit(jenisTransaksi string) bool {
+	switch jenisTransaksi {
+	case JenisSetor, JenisKoreksiKredit, JenisBunga, JenisTransferMasuk:
+		return true
+	}
+	return false
+}
+
 // Nasabah adalah model untuk data nasabah
 type Nasabah struct {
 	ID         int     `json:"id"`
@@ -10,6 +46,15 @@ type Nasabah struct {
 	NoHP       string  `json:"no_hp"`
 	NoRekening string  `json:"no_rekening"`
 	Saldo      float64 `json:"saldo"`
+	Status     string  `json:"status,omitempty"`
+}
+
+// RegisterNasabahRequest adalah model untuk request pendaftaran nasabah; PIN tidak pernah disimpan mentah
+type RegisterNasabahRequest struct {
+	Nama string `json:"nama"`
+	NIK  string `json:"nik" openapi:"required,pattern=^[0-9]{16}$"`
+	NoHP string `json:"no_hp" openapi:"required,pattern=^[0-9]{10,15}$"`
+	PIN  string `json:"pin" openapi:"required,pattern=^[0-9]{6}$"`
 }
 
 // Tabungan adalah model untuk riwayat transaksi nasabah
@@ -18,12 +63,9 @@ type Tabungan struct {
 	NasabahID      int       `json:"nasabah_id"`
 	JenisTransaksi string    `json:"jenis_transaksi"`
 	Nominal        float64   `json:"nominal"`
+	Keterangan     string    `json:"keterangan,omitempty"`
+	RefTabunganID  *int      `json:"ref_tabungan_id,omitempty"`
+	Channel        string    `json:"channel,omitempty"`
+	Referensi      string    `json:"referensi,omitempty"` // referensi pemanggil; kedua sisi transfer memakai referensi yang sama
 	CreatedAt      time.Time `json:"created_at"`
 }
-
-// Tabung adalah model untuk request menabung atau menarik saldo
-type Tabung struct {
-	NoRekening     string  `json:"no_rekening"`
-	JenisTransaksi string  `json:"jenis_transaksi"`
-	Nominal        float64 `json:"nominal"`
-}
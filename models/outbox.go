@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent adalah event domain yang ditulis dalam transaksi yang sama dengan perubahan
+// saldo, lalu dikirim oleh relay ke publisher. Sequence bertambah per rekening sehingga
+// konsumen bisa menjaga urutan event per rekening.
+type OutboxEvent struct {
+	ID          int64           `json:"id"`
+	AggregateID string          `json:"aggregate_id"`
+	Sequence    int64           `json:"sequence"`
+	Type        string          `json:"type"`
+	Version     int             `json:"version"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Attempts    int             `json:"-"`
+	TraceParent string          `json:"-"` // header W3C traceparent request asal, kosong jika tidak ada
+}
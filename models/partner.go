@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Partner adalah aplikasi mitra yang memanggil API dengan request bertanda tangan HMAC
+type Partner struct {
+	ID               int       `json:"id"`
+	ClientID         string    `json:"client_id"`
+	Nama             string    `json:"nama"`
+	AllowedEndpoints []string  `json:"allowed_endpoints"`
+	Aktif            bool      `json:"aktif"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// PartnerSecret adalah satu versi secret milik partner; beberapa versi bisa berlaku bersamaan saat rotasi
+type PartnerSecret struct {
+	ID         int
+	PartnerID  int
+	SecretEnc  string
+	ValidFrom  time.Time
+	ValidUntil *time.Time
+}
+
+// CreatePartnerRequest adalah model untuk request pendaftaran partner
+type CreatePartnerRequest struct {
+	Nama             string   `json:"nama" openapi:"required"`
+	AllowedEndpoints []string `json:"allowed_endpoints" openapi:"required,minItems=1"`
+}
+
+// PartnerCredentialResponse berisi kredensial partner; secret hanya ditampilkan sekali
+type PartnerCredentialResponse struct {
+	ClientID        string     `json:"client_id"`
+	Secret          string     `json:"secret"`
+	ValidFrom       time.Time  `json:"valid_from"`
+	PreviousExpires *time.Time `json:"previous_secret_valid_until,omitempty"`
+}
+
+// PartnerRekeningRequest adalah model untuk request menghubungkan rekening dengan partner
+type PartnerRekeningRequest struct {
+	NoRekening string `json:"no_rekening" openapi:"required"`
+}
+
+// PartnerRekeningResponse berisi rekening yang terhubung dengan partner
+type PartnerRekeningResponse struct {
+	ClientID string   `json:"client_id"`
+	Rekening []string `json:"rekening"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Status run rekonsiliasi
+const (
+	ReconciliationRunning   = "running"
+	ReconciliationFailed    = "failed"
+	ReconciliationCompleted = "completed"
+)
+
+// Status selisih rekonsiliasi
+const (
+	SelisihOpen            = "open"
+	SelisihKoreksiDiajukan = "koreksi_diajukan"
+	SelisihDikoreksi       = "dikoreksi"
+	SelisihSelesai         = "selesai" // saldo kembali cocok tanpa koreksi dari rekonsiliasi
+)
+
+// Mode koreksi selisih rekonsiliasi
+const (
+	KoreksiSaldo   = "saldo"   // saldo disamakan dengan hasil hitung ulang riwayat
+	KoreksiRiwayat = "riwayat" // transaksi koreksi dicatat tanpa mengubah saldo agar riwayat sama dengan saldo
+)
+
+// ReconciliationRun adalah satu kali proses rekonsiliasi saldo
+type ReconciliationRun struct {
+	ID          int        `json:"id"`
+	Status      string     `json:"status"`
+	TriggeredBy string     `json:"triggered_by"`
+	Diperiksa   int        `json:"diperiksa"`
+	Selisih     int        `json:"selisih"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// ReconciliationMismatch adalah rekening yang saldonya berbeda dengan hasil hitung ulang
+// riwayat transaksinya. TabunganID adalah transaksi pertama yang saldo_setelah-nya
+// berbeda dengan saldo hasil hitung ulang, jika bisa ditemukan.
+type ReconciliationMismatch struct {
+	ID                   int        `json:"id"`
+	RunID                int        `json:"run_id"`
+	NasabahID            int        `json:"nasabah_id"`
+	NoRekening           string     `json:"no_rekening"`
+	SaldoTercatat        float64    `json:"saldo_tercatat"`
+	SaldoHitung          float64    `json:"saldo_hitung"`
+	Selisih              float64    `json:"selisih"`
+	TabunganID           *int       `json:"tabungan_id,omitempty"`
+	SaldoSetelahTercatat *float64   `json:"saldo_setelah_tercatat,omitempty"`
+	SaldoSetelahHitung   *float64   `json:"saldo_setelah_hitung,omitempty"`
+	Status               string     `json:"status"`
+	OperationID          *int       `json:"operation_id,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+	ResolvedAt           *time.Time `json:"resolved_at,omitempty"`
+}
+
+// CorrectionRequest adalah model untuk request koreksi selisih rekonsiliasi
+type CorrectionRequest struct {
+	SelisihID int    `json:"selisih_id"` // diisi dari path
+	Mode      string `json:"mode" openapi:"required,enum=saldo|riwayat"`
+	Alasan    string `json:"alasan" openapi:"required"`
+}
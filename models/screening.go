@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Konteks screening
+const (
+	ScreeningOnboarding = "onboarding"
+	ScreeningTransfer   = "transfer"
+)
+
+// Status hit screening
+const (
+	HitPending   = "pending"
+	HitCleared   = "cleared"
+	HitConfirmed = "confirmed"
+)
+
+// Watchlist adalah daftar pantauan, misalnya DTTOT atau daftar hitam internal
+type Watchlist struct {
+	ID        int                `json:"id"`
+	Kode      string             `json:"kode"`
+	CreatedAt time.Time          `json:"created_at"`
+	Versions  []WatchlistVersion `json:"versions,omitempty"`
+}
+
+// WatchlistVersion adalah satu kali impor watchlist
+type WatchlistVersion struct {
+	ID          int       `json:"id"`
+	WatchlistID int       `json:"watchlist_id"`
+	Versi       int       `json:"versi"`
+	Format      string    `json:"format"`
+	Checksum    string    `json:"checksum"`
+	JumlahEntri int       `json:"jumlah_entri"`
+	Aktif       bool      `json:"aktif"`
+	ImportedBy  *int      `json:"imported_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WatchlistEntry adalah satu orang atau entitas di watchlist
+type WatchlistEntry struct {
+	ID           int      `json:"id"`
+	VersionID    int      `json:"version_id"`
+	Watchlist    string   `json:"watchlist"`
+	Versi        int      `json:"versi"`
+	Nama         string   `json:"nama"`
+	Alias        []string `json:"alias"`
+	NIK          string   `json:"nik,omitempty"`
+	TanggalLahir string   `json:"tanggal_lahir,omitempty"`
+	Keterangan   string   `json:"keterangan,omitempty"`
+}
+
+// ScreeningHit adalah kecocokan nama nasabah atau lawan transaksi dengan entri watchlist
+type ScreeningHit struct {
+	ID         int        `json:"id"`
+	NasabahID  int        `json:"nasabah_id"`
+	NoRekening string     `json:"no_rekening,omitempty"`
+	Konteks    string     `json:"konteks"`
+	NamaSubjek string     `json:"nama_subjek"`
+	EntryID    int        `json:"entry_id"`
+	Watchlist  string     `json:"watchlist"`
+	Versi      int        `json:"versi"`
+	NamaEntri  string     `json:"nama_entri"`
+	Skor       float64    `json:"skor"`
+	Status     string     `json:"status"`
+	ReviewedBy *int       `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	Catatan    string     `json:"catatan,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ScreeningDecisionRequest adalah model untuk request clear atau confirm hit
+type ScreeningDecisionRequest struct {
+	Catatan string `json:"catatan"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status pengiriman webhook
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookDead      = "dead"
+)
+
+// WebhookSubscription adalah pendaftaran URL partner untuk menerima event
+type WebhookSubscription struct {
+	ID                  int        `json:"id"`
+	PartnerID           int        `json:"partner_id"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"event_types"`
+	Rekening            []string   `json:"rekening"` // Kosong berarti semua rekening yang terhubung dengan partner
+	SecretEnc           string     `json:"-"`
+	PreviousSecretEnc   string     `json:"-"`
+	PreviousSecretUntil *time.Time `json:"previous_secret_until,omitempty"`
+	Aktif               bool       `json:"aktif"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// WebhookSubscriptionRequest adalah model untuk request pendaftaran webhook
+type WebhookSubscriptionRequest struct {
+	URL        string   `json:"url" openapi:"required,format=uri"`
+	EventTypes []string `json:"event_types" openapi:"required,minItems=1"`
+	Rekening   []string `json:"rekening"` // Harus terhubung dengan partner
+}
+
+// WebhookSecretResponse berisi secret penandatangan webhook; hanya ditampilkan sekali
+type WebhookSecretResponse struct {
+	Subscription        *WebhookSubscription `json:"subscription"`
+	Secret              string               `json:"secret"`
+	PreviousSecretUntil *time.Time           `json:"previous_secret_until,omitempty"`
+}
+
+// WebhookDelivery adalah satu event yang harus dikirim ke satu subscription
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int             `json:"subscription_id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	TraceParent    string          `json:"-"` // trace context event asal, diteruskan ke partner
+}
+
+// WebhookDeliveryAttempt adalah satu baris log percobaan pengiriman
+type WebhookDeliveryAttempt struct {
+	ID          int64     `json:"id"`
+	DeliveryID  int64     `json:"delivery_id"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	StatusCode  *int      `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int       `json:"duration_ms"`
+}
@@ -0,0 +1,165 @@
+// Package onboarding mendaftarkan nasabah baru: validasi identitas, pembuatan rekening
+// dan screening watchlist. Handler HTTP dan server gRPC memakai service yang sama.
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/metrics"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/screening"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/tracing"
+	"golang-echo-postgresql/utils"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidIdentity = errors.New("format NIK atau No HP tidak valid")
+	ErrInvalidPIN      = errors.New("PIN harus 6 digit")
+)
+
+// DuplicateError dikembalikan jika NIK atau No HP sudah dipakai nasabah lain. Fields
+// kosong berarti pendaftaran lain dengan data yang sama lebih dulu di-commit.
+type DuplicateError struct {
+	Fields []string
+}
+
+func (e *DuplicateError) Error() string {
+	if len(e.Fields) == 0 {
+		return "NIK atau No HP sudah dipakai"
+	}
+	return strings.Join(e.Fields, " dan ") + " sudah dipakai"
+}
+
+func (e *DuplicateError) Is(target error) bool { return target == store.ErrDuplicate }
+
+// Registration adalah data pendaftaran nasabah baru
+type Registration struct {
+	Nama string
+	NIK  string
+	NoHP string
+	PIN  string
+}
+
+// Result adalah hasil pendaftaran. Held bernilai true jika nasabah cocok dengan watchlist
+// dan rekeningnya ditahan di status pending_review.
+type Result struct {
+	Nasabah *models.Nasabah
+	Held    bool
+}
+
+// Service mendaftarkan nasabah lewat Store. Screening membutuhkan Postgres; biarkan nil
+// saat memakai store in-memory.
+type Service struct {
+	Store     store.Store
+	Screening *screening.Service
+}
+
+func NewService(st store.Store, screener *screening.Service) *Service {
+	return &Service{Store: st, Screening: screener}
+}
+
+// Register memvalidasi data, membuat rekening dan men-screening nasabah dalam satu unit of work
+func (s *Service) Register(ctx context.Context, req Registration) (*Result, error) {
+	ctx, span := tracing.Child(ctx, "onboarding.Register")
+	metrics.RegistrationStep(metrics.RegistrationStarted)
+	res, err := s.register(ctx, req)
+	tracing.End(span, err)
+	if err != nil {
+		metrics.RegistrationRejected(Reason(err))
+		return nil, err
+	}
+
+	// Langkah created baru dicatat setelah unit of work commit
+	metrics.RegistrationStep(metrics.RegistrationCreated)
+	if res.Held {
+		metrics.RegistrationStep(metrics.RegistrationHeld)
+	} else {
+		metrics.RegistrationStep(metrics.RegistrationActive)
+	}
+	return res, nil
+}
+
+// Reason mengembalikan alasan penolakan pendaftaran yang stabil untuk label metrik
+func Reason(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidIdentity):
+		return "invalid_identity"
+	case errors.Is(err, ErrInvalidPIN):
+		return "invalid_pin"
+	case errors.Is(err, store.ErrDuplicate):
+		return "duplicate"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	}
+	return "error"
+}
+
+func (s *Service) register(ctx context.Context, req Registration) (*Result, error) {
+	if !utils.ValidateNIK(req.NIK) || !utils.ValidateNoHP(req.NoHP) {
+		return nil, ErrInvalidIdentity
+	}
+	if !utils.ValidatePIN(req.PIN) {
+		return nil, ErrInvalidPIN
+	}
+	metrics.RegistrationStep(metrics.RegistrationValidated)
+
+	pinHash, err := auth.HashSecret(req.PIN)
+	if err != nil {
+		return nil, err
+	}
+
+	nasabah := &models.Nasabah{Nama: req.Nama, NIK: req.NIK, NoHP: req.NoHP}
+	var fields []string
+	var hits []models.ScreeningHit
+	err = s.Store.Do(ctx, func(uow store.UnitOfWork) error {
+		var err error
+		fields, err = uow.Customers().CheckExisting(nasabah.NIK, nasabah.NoHP)
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			return store.ErrDuplicate
+		}
+
+		nasabah.NoRekening = utils.GenerateAccountNumber()
+		log.WithContext(ctx).WithFields(log.Fields{
+			"NoRekening": nasabah.NoRekening,
+		}).Info("Generated account number")
+
+		if err := uow.Customers().Create(nasabah, pinHash); err != nil {
+			return err
+		}
+
+		// Screening watchlist; nasabah yang cocok tetap terdaftar tetapi ditahan di
+		// status pending_review sampai compliance me-review hit-nya
+		nasabah.Status = models.StatusAktif
+		if s.Screening != nil {
+			_, span := tracing.Child(uow.Context(), "screening.ScreenNasabah")
+			hits, err = s.Screening.ScreenNasabah(uow.SQL(), nasabah, models.ScreeningOnboarding)
+			tracing.End(span, err)
+			if err != nil {
+				return err
+			}
+		}
+
+		// AccountOpened baru dikirim setelah hit di-clear jika nasabah tertahan
+		if len(hits) == 0 {
+			return uow.Enqueue(outbox.AccountOpenedV1{NoRekening: nasabah.NoRekening, Nama: nasabah.Nama, OpenedAt: time.Now()})
+		}
+		return nil
+	})
+	if errors.Is(err, store.ErrDuplicate) {
+		return nil, &DuplicateError{Fields: fields}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Nasabah: nasabah, Held: len(hits) > 0}, nil
+}
@@ -0,0 +1,238 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema adalah subset JSON Schema yang dipakai OpenAPI 3.0
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+func String() *Schema  { return &Schema{Type: "string"} }
+func Integer() *Schema { return &Schema{Type: "integer"} }
+func Number() *Schema  { return &Schema{Type: "number"} }
+func Boolean() *Schema { return &Schema{Type: "boolean"} }
+
+// Date adalah tanggal bisnis berformat YYYY-MM-DD
+func Date() *Schema { return &Schema{Type: "string", Format: "date"} }
+
+func Enum(values ...string) *Schema { return &Schema{Type: "string", Enum: values} }
+
+// Object membentuk schema objek dengan properti yang diberikan; semua properti wajib
+func Object(props map[string]*Schema) *Schema {
+	s := &Schema{Type: "object", Properties: props}
+	for name := range props {
+		s.Required = append(s.Required, name)
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	rawMessageType  = reflect.TypeOf(json.RawMessage{})
+	marshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// generator membentuk schema dari tipe Go lewat tag json. Struct bernama disimpan di
+// components dan dirujuk dengan $ref, sehingga setiap model hanya ditulis sekali.
+//
+// Batasan validasi ditulis di tag openapi, dipisah koma:
+//
+//	Nominal float64 `json:"nominal" openapi:"required,min=0,exclusive"`
+//	PIN     string  `json:"pin" openapi:"required,pattern=^[0-9]{6}$"`
+//	Arah    string  `json:"arah" openapi:"required,enum=kredit|debit"`
+//
+// pattern selalu ditulis terakhir karena nilainya boleh mengandung koma.
+type generator struct {
+	schemas map[string]*Schema
+	err     error // tag openapi pertama yang tidak valid
+}
+
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		s := g.schemaOf(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		// Bentuk JSON ditentukan MarshalJSON, tidak bisa diturunkan dari field-nya
+		return &Schema{}
+	case t.Implements(textMarshalType):
+		return String()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return String()
+	case reflect.Bool:
+		return Boolean()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return Integer()
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return Number()
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := schemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			// Placeholder dulu agar tipe yang saling merujuk tidak berulang tanpa akhir
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	// interface{} dan tipe lain: nilai JSON apa pun
+	return &Schema{}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(s, t)
+	sort.Strings(s.Required)
+	return s
+}
+
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		// Field embedded tanpa nama JSON diratakan seperti encoding/json
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := g.schemaOf(f.Type)
+		required, err := applyTag(prop, f.Tag.Get("openapi"))
+		if err != nil && g.err == nil {
+			g.err = fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+		s.Properties[name] = prop
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// applyTag menerapkan tag openapi ke schema dan melaporkan apakah field wajib diisi
+func applyTag(s *Schema, tag string) (bool, error) {
+	required := false
+	for tag != "" {
+		var item string
+		if strings.HasPrefix(tag, "pattern=") {
+			item, tag = tag, ""
+		} else {
+			item, tag, _ = strings.Cut(tag, ",")
+		}
+		key, value, _ := strings.Cut(item, "=")
+		switch key {
+		case "required":
+			required = true
+		case "exclusive":
+			s.ExclusiveMinimum = true
+		case "min", "max":
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return false, fmt.Errorf("nilai %s tidak valid: %q", key, value)
+			}
+			if key == "min" {
+				s.Minimum = &f
+			} else {
+				s.Maximum = &f
+			}
+		case "minLength", "maxLength", "minItems":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return false, fmt.Errorf("nilai %s tidak valid: %q", key, value)
+			}
+			switch key {
+			case "minLength":
+				s.MinLength = &n
+			case "maxLength":
+				s.MaxLength = &n
+			default:
+				s.MinItems = &n
+			}
+		case "enum":
+			s.Enum = strings.Split(value, "|")
+		case "format":
+			s.Format = value
+		case "pattern":
+			if _, err := regexp.Compile(value); err != nil {
+				return false, fmt.Errorf("pattern tidak valid: %v", err)
+			}
+			s.Pattern = value
+		default:
+			return false, fmt.Errorf("kunci tag tidak dikenal: %q", key)
+		}
+	}
+	return required, nil
+}
+
+func schemaName(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
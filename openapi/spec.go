@@ -0,0 +1,292 @@
+// Package openapi membentuk dokumen OpenAPI 3 dari tabel route dan tipe request/response
+// Go, memvalidasi request masuk terhadap dokumen tersebut, dan menyajikan Swagger UI.
+// Schema diturunkan dari tag json sehingga dokumen selalu mengikuti model yang dipakai handler.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const Version = "3.0.3"
+
+// Document adalah dokumen OpenAPI 3.0
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Tags       []Tag                            `json:"tags,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Route mendeskripsikan satu route Echo untuk dokumen OpenAPI
+type Route struct {
+	Method  string
+	Path    string // path Echo, parameter ditulis ":nama"
+	Tag     string
+	Summary string
+
+	// Security berisi nama security scheme yang diterima; kosong berarti publik
+	Security []string
+
+	// Params berisi parameter query dan path. Parameter path yang tidak disebut
+	// dianggap string.
+	Params []*Parameter
+
+	// Body adalah nilai tipe request JSON (misalnya models.FreezeRequest{}), nil jika
+	// route tidak menerima body. RawBody dipakai untuk body selain JSON.
+	Body         interface{}
+	BodyOptional bool
+	RawBody      []string
+
+	Replies []Reply
+}
+
+// Reply adalah satu kemungkinan response route. Body nil berarti response berupa
+// utils.Response (remark dan errors); Body bertipe *Schema dipakai apa adanya, misalnya
+// untuk response selain JSON.
+type Reply struct {
+	Status      int
+	Description string
+	Body        interface{}
+	ContentType string // bawaan application/json
+}
+
+// Query membentuk parameter query opsional
+func Query(name string, schema *Schema, description string) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// PathParam membentuk parameter path dengan schema selain string
+func PathParam(name string, schema *Schema) *Parameter {
+	return &Parameter{Name: name, In: "path", Required: true, Schema: schema}
+}
+
+// Build menyusun dokumen dari tabel route. errorBody adalah tipe response error
+// bawaan (utils.Response), dirujuk oleh setiap Reply tanpa Body dan response default.
+func Build(info Info, tags []Tag, schemes map[string]*SecurityScheme, errorBody interface{}, routes []Route) (*Document, error) {
+	g := &generator{schemas: map[string]*Schema{}}
+	errSchema := g.schemaOf(reflect.TypeOf(errorBody))
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Tags:    tags,
+		Paths:   map[string]map[string]*Operation{},
+		Components: Components{
+			Schemas:         g.schemas,
+			SecuritySchemes: schemes,
+		},
+	}
+
+	for _, r := range routes {
+		p := oasPath(r.Path)
+		method := strings.ToLower(r.Method)
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = map[string]*Operation{}
+		}
+		if _, dup := doc.Paths[p][method]; dup {
+			return nil, fmt.Errorf("route %s %s didefinisikan lebih dari sekali", r.Method, r.Path)
+		}
+
+		op := &Operation{
+			Summary:     r.Summary,
+			OperationID: operationID(r.Method, r.Path),
+			Responses:   map[string]*Response{},
+		}
+		if r.Tag != "" {
+			op.Tags = []string{r.Tag}
+		}
+		for _, name := range r.Security {
+			if _, ok := schemes[name]; !ok {
+				return nil, fmt.Errorf("route %s %s: security scheme %q tidak dikenal", r.Method, r.Path, name)
+			}
+			op.Security = append(op.Security, map[string][]string{name: {}})
+		}
+
+		params, err := parameters(r)
+		if err != nil {
+			return nil, err
+		}
+		op.Parameters = params
+
+		switch {
+		case r.Body != nil:
+			op.RequestBody = &RequestBody{
+				Required: !r.BodyOptional,
+				Content:  map[string]*MediaType{"application/json": {Schema: g.schemaOf(reflect.TypeOf(r.Body))}},
+			}
+		case len(r.RawBody) > 0:
+			op.RequestBody = &RequestBody{Required: !r.BodyOptional, Content: map[string]*MediaType{}}
+			for _, ct := range r.RawBody {
+				op.RequestBody.Content[ct] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+			}
+		}
+
+		for _, reply := range r.Replies {
+			schema := errSchema
+			switch body := reply.Body.(type) {
+			case nil:
+			case *Schema:
+				schema = body
+			default:
+				schema = g.schemaOf(reflect.TypeOf(body))
+			}
+			ct := reply.ContentType
+			if ct == "" {
+				ct = "application/json"
+			}
+			code := strconv.Itoa(reply.Status)
+			if res, ok := op.Responses[code]; ok {
+				// Status yang sama dengan content type lain, misalnya JSON dan CSV
+				res.Content[ct] = &MediaType{Schema: schema}
+				continue
+			}
+			desc := reply.Description
+			if desc == "" {
+				desc = http.StatusText(reply.Status)
+			}
+			op.Responses[code] = &Response{
+				Description: desc,
+				Content:     map[string]*MediaType{ct: {Schema: schema}},
+			}
+		}
+		if len(r.Security) > 0 {
+			if _, ok := op.Responses["401"]; !ok {
+				op.Responses["401"] = errorResponse("Token atau tanda tangan tidak valid", errSchema)
+			}
+		}
+		op.Responses["default"] = errorResponse("Error dengan kode katalog (lihat GET /errors), remark dan daftar error per field", errSchema)
+
+		doc.Paths[p][method] = op
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+	return doc, nil
+}
+
+func errorResponse(desc string, schema *Schema) *Response {
+	return &Response{Description: desc, Content: map[string]*MediaType{"application/json": {Schema: schema}}}
+}
+
+// parameters melengkapi parameter route dengan parameter path yang tidak disebut
+func parameters(r Route) ([]*Parameter, error) {
+	declared := map[string]bool{}
+	for _, p := range r.Params {
+		if p.In == "path" {
+			declared[p.Name] = true
+		}
+	}
+
+	var params []*Parameter
+	for _, seg := range strings.Split(r.Path, "/") {
+		name, ok := strings.CutPrefix(seg, ":")
+		if !ok || declared[name] {
+			continue
+		}
+		params = append(params, PathParam(name, String()))
+	}
+	for _, p := range r.Params {
+		if p.In == "path" && !strings.Contains(r.Path, ":"+p.Name) {
+			return nil, fmt.Errorf("route %s %s: parameter path %q tidak ada di path", r.Method, r.Path, p.Name)
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// oasPath mengubah path Echo (/saldo/:no_rekening) menjadi path OpenAPI (/saldo/{no_rekening})
+func oasPath(p string) string {
+	segs := strings.Split(p, "/")
+	for i, seg := range segs {
+		if name, ok := strings.CutPrefix(seg, ":"); ok {
+			segs[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// EchoPath adalah kebalikan oasPath
+func EchoPath(p string) string {
+	segs := strings.Split(p, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			segs[i] = ":" + seg[1:len(seg)-1]
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// operationID membentuk id unik yang stabil dari method dan path, misalnya
+// POST /backoffice/approvals/:id/approve menjadi postBackofficeApprovalsIdApprove
+func operationID(method, p string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, word := range strings.FieldsFunc(p, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
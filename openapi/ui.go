@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// swaggerUIVersion adalah versi swagger-ui-dist yang dimuat dari CDN
+const swaggerUIVersion = "5.17.14"
+
+// SpecHandler menyajikan dokumen sebagai JSON
+func SpecHandler(doc *Document) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, doc)
+	}
+}
+
+// UIHandler menyajikan Swagger UI untuk dokumen di specURL
+func UIHandler(title, specURL string) echo.HandlerFunc {
+	url, _ := json.Marshal(specURL)
+	page := `<!DOCTYPE html>
+<html lang="id">
+<head>
+  <meta charset="utf-8">
+  <title>` + html.EscapeString(title) + `</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({ url: ` + string(url) + `, dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+	return func(c echo.Context) error {
+		return c.HTML(http.StatusOK, page)
+	}
+}
@@ -0,0 +1,352 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"golang-echo-postgresql/apierror"
+	"io"
+	"mime"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxBodyBytes membatasi body yang dibaca validator; body lebih besar ditolak
+const maxBodyBytes = 1 << 20
+
+// Validator memeriksa parameter path, query dan body JSON setiap request terhadap
+// dokumen sebelum handler dijalankan. Request yang tidak sesuai ditolak dengan 400,
+// remark "Invalid request payload" dan satu pesan per field di errors. Route yang
+// tidak ada di dokumen diteruskan apa adanya.
+func Validator(doc *Document) echo.MiddlewareFunc {
+	v := &validator{
+		schemas:    doc.Components.Schemas,
+		operations: map[string]*Operation{},
+		patterns:   map[string]*regexp.Regexp{},
+	}
+	for p, item := range doc.Paths {
+		for method, op := range item {
+			v.operations[strings.ToUpper(method)+" "+EchoPath(p)] = op
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			op, ok := v.operations[c.Request().Method+" "+c.Path()]
+			if !ok {
+				return next(c)
+			}
+
+			errs, err := v.request(c, op)
+			if err != nil {
+				return err
+			}
+			if len(errs) > 0 {
+				log.WithFields(log.Fields{
+					"path":   c.Path(),
+					"errors": errs,
+				}).Warn("Request rejected by schema validation")
+				return apierror.New(errorCode(errs), errs...)
+			}
+			return next(c)
+		}
+	}
+}
+
+type validator struct {
+	schemas    map[string]*Schema
+	operations map[string]*Operation
+
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+// errorCode memilih INVALID_PARAMETER jika semua pelanggaran ada di path atau query
+func errorCode(errs []string) apierror.Code {
+	for _, e := range errs {
+		if !strings.HasPrefix(e, "path.") && !strings.HasPrefix(e, "query.") {
+			return apierror.InvalidPayload
+		}
+	}
+	return apierror.InvalidParameter
+}
+
+// request memvalidasi satu request. Error non-nil (*apierror.Error) berarti body tidak
+// bisa dibaca sebagai JSON; errs berisi pelanggaran schema per field.
+func (v *validator) request(c echo.Context, op *Operation) ([]string, error) {
+	var errs []string
+	for _, p := range op.Parameters {
+		var raw string
+		var present bool
+		switch p.In {
+		case "path":
+			raw = c.Param(p.Name)
+			present = raw != ""
+		case "query":
+			values := c.QueryParams()
+			_, present = values[p.Name]
+			raw = values.Get(p.Name)
+		default:
+			continue
+		}
+		name := p.In + "." + p.Name
+		if !present {
+			if p.Required {
+				errs = append(errs, name+": is required")
+			}
+			continue
+		}
+		errs = append(errs, v.param(p.Schema, raw, name)...)
+	}
+
+	if op.RequestBody == nil {
+		return errs, nil
+	}
+	media, ok := op.RequestBody.Content["application/json"]
+	if !ok {
+		return errs, nil
+	}
+
+	req := c.Request()
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBodyBytes+1))
+	if err != nil {
+		return nil, apierror.New(apierror.InvalidPayload, fmt.Sprintf("body: %v", err))
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) > maxBodyBytes {
+		return nil, apierror.New(apierror.PayloadTooLarge, "body: exceeds 1 MiB")
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			errs = append(errs, "body: is required")
+		}
+		return errs, nil
+	}
+	if ct := req.Header.Get(echo.HeaderContentType); ct != "" {
+		if mt, _, _ := mime.ParseMediaType(ct); mt != echo.MIMEApplicationJSON {
+			return nil, apierror.New(apierror.UnsupportedMediaType, "body: content type must be "+echo.MIMEApplicationJSON)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, apierror.New(apierror.InvalidPayload, fmt.Sprintf("body: invalid JSON: %v", err))
+	}
+	return append(errs, v.value(media.Schema, value, "")...), nil
+}
+
+// param memvalidasi parameter path atau query yang selalu berupa string
+func (v *validator) param(s *Schema, raw, name string) []string {
+	s = v.resolve(s)
+	switch s.Type {
+	case "integer":
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return []string{name + ": must be an integer"}
+		}
+		return v.value(s, json.Number(raw), name)
+	case "number":
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return []string{name + ": must be a number"}
+		}
+		return v.value(s, json.Number(raw), name)
+	case "boolean":
+		if _, err := strconv.ParseBool(raw); err != nil {
+			return []string{name + ": must be a boolean"}
+		}
+		return nil
+	}
+	return v.value(s, raw, name)
+}
+
+// value memvalidasi nilai JSON hasil decode terhadap schema
+func (v *validator) value(s *Schema, value interface{}, name string) []string {
+	s = v.resolve(s)
+	label := name
+	if label == "" {
+		label = "body"
+	}
+	if value == nil {
+		// null diperlakukan seperti field yang tidak dikirim
+		return nil
+	}
+
+	switch s.Type {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return []string{label + ": must be a string"}
+		}
+		return v.stringValue(s, str, label)
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			return []string{label + ": must be a number"}
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return []string{label + ": must be a number"}
+		}
+		if s.Type == "integer" {
+			if _, err := n.Int64(); err != nil {
+				return []string{label + ": must be an integer"}
+			}
+		}
+		return numberValue(s, f, label)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{label + ": must be a boolean"}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{label + ": must be an array"}
+		}
+		var errs []string
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			errs = append(errs, fmt.Sprintf("%s: must contain at least %d item(s)", label, *s.MinItems))
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				errs = append(errs, v.value(s.Items, item, fmt.Sprintf("%s[%d]", name, i))...)
+			}
+		}
+		return errs
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{label + ": must be an object"}
+		}
+		return v.object(s, obj, name)
+	}
+	return nil
+}
+
+func (v *validator) object(s *Schema, obj map[string]interface{}, name string) []string {
+	var errs []string
+	for _, req := range s.Required {
+		if val, ok := obj[req]; !ok || val == nil || val == "" {
+			errs = append(errs, join(name, req)+": is required")
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		prop, ok := s.Properties[k]
+		if !ok {
+			if s.AdditionalProperties != nil {
+				errs = append(errs, v.value(s.AdditionalProperties, obj[k], join(name, k))...)
+			}
+			continue
+		}
+		if obj[k] == "" && contains(s.Required, k) {
+			// Sudah dilaporkan sebagai field wajib
+			continue
+		}
+		errs = append(errs, v.value(prop, obj[k], join(name, k))...)
+	}
+	return errs
+}
+
+func (v *validator) stringValue(s *Schema, str, label string) []string {
+	var errs []string
+	n := utf8.RuneCountInString(str)
+	if s.MinLength != nil && n < *s.MinLength {
+		errs = append(errs, fmt.Sprintf("%s: must be at least %d characters", label, *s.MinLength))
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		errs = append(errs, fmt.Sprintf("%s: must be at most %d characters", label, *s.MaxLength))
+	}
+	if len(s.Enum) > 0 && !contains(s.Enum, str) {
+		errs = append(errs, fmt.Sprintf("%s: must be one of %s", label, strings.Join(s.Enum, ", ")))
+	}
+	if s.Pattern != "" && !v.pattern(s.Pattern).MatchString(str) {
+		errs = append(errs, fmt.Sprintf("%s: must match %s", label, s.Pattern))
+	}
+	switch s.Format {
+	case "date":
+		if _, err := time.Parse("2006-01-02", str); err != nil {
+			errs = append(errs, label+": must be a date (YYYY-MM-DD)")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			errs = append(errs, label+": must be an RFC 3339 timestamp")
+		}
+	case "uri":
+		if !strings.HasPrefix(str, "http://") && !strings.HasPrefix(str, "https://") {
+			errs = append(errs, label+": must be an http(s) URL")
+		}
+	}
+	return errs
+}
+
+func numberValue(s *Schema, f float64, label string) []string {
+	var errs []string
+	if s.Minimum != nil {
+		switch {
+		case s.ExclusiveMinimum && f <= *s.Minimum:
+			errs = append(errs, fmt.Sprintf("%s: must be greater than %s", label, formatNumber(*s.Minimum)))
+		case !s.ExclusiveMinimum && f < *s.Minimum:
+			errs = append(errs, fmt.Sprintf("%s: must be at least %s", label, formatNumber(*s.Minimum)))
+		}
+	}
+	if s.Maximum != nil && f > *s.Maximum {
+		errs = append(errs, fmt.Sprintf("%s: must be at most %s", label, formatNumber(*s.Maximum)))
+	}
+	return errs
+}
+
+func (v *validator) resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = v.schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	if s == nil {
+		return &Schema{}
+	}
+	return s
+}
+
+func (v *validator) pattern(p string) *regexp.Regexp {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	re, ok := v.patterns[p]
+	if !ok {
+		re = regexp.MustCompile(p)
+		v.patterns[p] = re
+	}
+	return re
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,117 @@
+package outbox
+
+import "time"
+
+// Tipe event domain. Versi payload dinaikkan jika ada perubahan yang tidak kompatibel.
+const (
+	TypeAccountOpened        = "AccountOpened"
+	TypeFundsDeposited       = "FundsDeposited"
+	TypeFundsWithdrawn       = "FundsWithdrawn"
+	TypeBalanceAdjusted      = "BalanceAdjusted"
+	TypeTransactionReversed  = "TransactionReversed"
+	TypeAccountStatusChanged = "AccountStatusChanged"
+	TypeFundsTransferred     = "FundsTransferred"
+)
+
+// Message adalah payload event yang bisa ditulis ke outbox
+type Message interface {
+	AggregateID() string
+	EventType() string
+	EventVersion() int
+}
+
+// AccountOpenedV1 dikirim saat nasabah baru terdaftar
+type AccountOpenedV1 struct {
+	NoRekening string    `json:"no_rekening"`
+	Nama       string    `json:"nama"`
+	OpenedAt   time.Time `json:"opened_at"`
+}
+
+func (e AccountOpenedV1) AggregateID() string { return e.NoRekening }
+func (e AccountOpenedV1) EventType() string   { return TypeAccountOpened }
+func (e AccountOpenedV1) EventVersion() int   { return 1 }
+
+// FundsDepositedV1 dikirim saat setoran berhasil di-commit
+type FundsDepositedV1 struct {
+	NoRekening string    `json:"no_rekening"`
+	Nominal    float64   `json:"nominal"`
+	SaldoAkhir float64   `json:"saldo_akhir"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (e FundsDepositedV1) AggregateID() string { return e.NoRekening }
+func (e FundsDepositedV1) EventType() string   { return TypeFundsDeposited }
+func (e FundsDepositedV1) EventVersion() int   { return 1 }
+
+// FundsWithdrawnV1 dikirim saat penarikan berhasil di-commit
+type FundsWithdrawnV1 struct {
+	NoRekening string    `json:"no_rekening"`
+	Nominal    float64   `json:"nominal"`
+	SaldoAkhir float64   `json:"saldo_akhir"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (e FundsWithdrawnV1) AggregateID() string { return e.NoRekening }
+func (e FundsWithdrawnV1) EventType() string   { return TypeFundsWithdrawn }
+func (e FundsWithdrawnV1) EventVersion() int   { return 1 }
+
+// BalanceAdjustedV1 dikirim saat petugas melakukan penyesuaian saldo
+type BalanceAdjustedV1 struct {
+	NoRekening string    `json:"no_rekening"`
+	Arah       string    `json:"arah"`
+	Nominal    float64   `json:"nominal"`
+	SaldoAkhir float64   `json:"saldo_akhir"`
+	Alasan     string    `json:"alasan"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (e BalanceAdjustedV1) AggregateID() string { return e.NoRekening }
+func (e BalanceAdjustedV1) EventType() string   { return TypeBalanceAdjusted }
+func (e BalanceAdjustedV1) EventVersion() int   { return 1 }
+
+// TransactionReversedV1 dikirim saat transaksi dibatalkan
+type TransactionReversedV1 struct {
+	NoRekening         string    `json:"no_rekening"`
+	TabunganID         int       `json:"tabungan_id"`
+	ReversalTabunganID int       `json:"reversal_tabungan_id"`
+	Nominal            float64   `json:"nominal"`
+	OccurredAt         time.Time `json:"occurred_at"`
+}
+
+func (e TransactionReversedV1) AggregateID() string { return e.NoRekening }
+func (e TransactionReversedV1) EventType() string   { return TypeTransactionReversed }
+func (e TransactionReversedV1) EventVersion() int   { return 1 }
+
+// AccountStatusChangedV1 dikirim saat rekening dibekukan atau dibuka kembali
+type AccountStatusChangedV1 struct {
+	NoRekening string    `json:"no_rekening"`
+	Status     string    `json:"status"`
+	Alasan     string    `json:"alasan"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (e AccountStatusChangedV1) AggregateID() string { return e.NoRekening }
+func (e AccountStatusChangedV1) EventType() string   { return TypeAccountStatusChanged }
+func (e AccountStatusChangedV1) EventVersion() int   { return 1 }
+
+// Arah transfer pada FundsTransferredV1
+const (
+	ArahKeluar = "keluar"
+	ArahMasuk  = "masuk"
+)
+
+// FundsTransferredV1 dikirim untuk setiap sisi transfer antarrekening yang berhasil di-commit,
+// sehingga masing-masing rekening menerima event pada urutan event-nya sendiri
+type FundsTransferredV1 struct {
+	NoRekening    string    `json:"no_rekening"`
+	Arah          string    `json:"arah"`
+	LawanRekening string    `json:"lawan_rekening"`
+	Nominal       float64   `json:"nominal"`
+	SaldoAkhir    float64   `json:"saldo_akhir"`
+	Referensi     string    `json:"referensi"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+func (e FundsTransferredV1) AggregateID() string { return e.NoRekening }
+func (e FundsTransferredV1) EventType() string   { return TypeFundsTransferred }
+func (e FundsTransferredV1) EventVersion() int   { return 1 }
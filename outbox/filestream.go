@@ -0,0 +1,75 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StreamEntry adalah satu entri di stream lokal, meniru entri Redis Streams/NATS JetStream
+type StreamEntry struct {
+	StreamID string             `json:"stream_id"` // "<unix-ms>-<seq>", seperti ID Redis Streams
+	Subject  string             `json:"subject"`
+	Event    models.OutboxEvent `json:"event"`
+}
+
+// FileStreamPublisher adalah pengganti lokal untuk NATS JetStream atau Redis Streams.
+// Setiap event ditambahkan sebagai satu baris JSON ke <dir>/<subject>.log dan di-fsync
+// sebelum Publish kembali, sehingga bisa dipakai untuk pengembangan tanpa broker.
+type FileStreamPublisher struct {
+	dir    string
+	prefix string
+
+	mu     sync.Mutex
+	lastMs int64
+	seq    int64
+}
+
+// NewFileStreamPublisher membuat publisher yang menulis ke direktori dir dengan
+// subject "<prefix>.<EventType>"
+func NewFileStreamPublisher(dir, prefix string) (*FileStreamPublisher, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStreamPublisher{dir: dir, prefix: prefix}, nil
+}
+
+func (p *FileStreamPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	subject := p.prefix + "." + event.Type
+	entry := StreamEntry{StreamID: p.nextID(), Subject: subject, Event: event}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(p.dir, subject+".log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func (p *FileStreamPublisher) nextID() string {
+	ms := time.Now().UnixMilli()
+	if ms <= p.lastMs {
+		ms = p.lastMs
+		p.seq++
+	} else {
+		p.lastMs = ms
+		p.seq = 0
+	}
+	return fmt.Sprintf("%d-%d", ms, p.seq)
+}
@@ -0,0 +1,31 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"golang-echo-postgresql/tracing"
+)
+
+// Enqueue menulis event ke tabel outbox. Panggil dengan tx yang sama dengan
+// UpdateSaldo/InsertTabunganDetail supaya event hanya ada jika perubahan saldo ter-commit.
+func Enqueue(executor repositories.Executor, msg Message) error {
+	return EnqueueContext(context.Background(), executor, msg)
+}
+
+// EnqueueContext sama dengan Enqueue dan menyimpan trace context dari ctx bersama
+// event, sehingga webhook yang dikirim untuk event ini masuk ke trace request asal
+func EnqueueContext(ctx context.Context, executor repositories.Executor, msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return repositories.InsertOutboxEvent(executor, &models.OutboxEvent{
+		AggregateID: msg.AggregateID(),
+		Type:        msg.EventType(),
+		Version:     msg.EventVersion(),
+		Payload:     payload,
+		TraceParent: tracing.TraceParent(ctx),
+	})
+}
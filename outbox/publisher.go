@@ -0,0 +1,49 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"sync"
+)
+
+// Publisher mengirim event ke sistem lain. Relay menjamin pengiriman minimal sekali
+// (at least once), jadi implementasi dan konsumen harus idempoten terhadap event.ID.
+type Publisher interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// HandlerFunc memproses satu event di dalam proses yang sama
+type HandlerFunc func(ctx context.Context, event models.OutboxEvent) error
+
+// InProcessPublisher meneruskan event ke handler yang terdaftar di proses yang sama.
+// Jika salah satu handler gagal, Publish mengembalikan error dan event dikirim ulang.
+type InProcessPublisher struct {
+	mu       sync.RWMutex
+	handlers map[string][]HandlerFunc
+}
+
+// NewInProcessPublisher membuat InProcessPublisher kosong
+func NewInProcessPublisher() *InProcessPublisher {
+	return &InProcessPublisher{handlers: map[string][]HandlerFunc{}}
+}
+
+// Subscribe mendaftarkan handler untuk tipe event tertentu, atau "*" untuk semua tipe
+func (p *InProcessPublisher) Subscribe(eventType string, handler HandlerFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[eventType] = append(p.handlers[eventType], handler)
+}
+
+func (p *InProcessPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	p.mu.RLock()
+	handlers := append(append([]HandlerFunc{}, p.handlers[event.Type]...), p.handlers["*"]...)
+	p.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return fmt.Errorf("handler %s gagal: %w", event.Type, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"golang-echo-postgresql/tracing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const maxBackoff = 5 * time.Minute
+
+// Relay membaca event dari tabel outbox dan mengirimkannya ke Publisher. Event hanya
+// ditandai terkirim setelah Publish berhasil, jadi pengiriman bersifat at least once.
+// Per rekening hanya event terdepan yang diambil, sehingga urutan per rekening terjaga.
+type Relay struct {
+	DB           *sql.DB
+	Publisher    Publisher
+	BatchSize    int
+	PollInterval time.Duration
+}
+
+// NewRelay membuat Relay baru
+func NewRelay(db *sql.DB, publisher Publisher, batchSize int, pollInterval time.Duration) *Relay {
+	return &Relay{DB: db, Publisher: publisher, BatchSize: batchSize, PollInterval: pollInterval}
+}
+
+// Run menjalankan relay sampai ctx dibatalkan
+func (r *Relay) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		published, err := r.RelayOnce(ctx)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err,
+			}).Error("Outbox relay failed")
+		}
+
+		// Jika masih ada event, langsung ambil batch berikutnya
+		if published > 0 {
+			timer.Reset(0)
+		} else {
+			timer.Reset(r.PollInterval)
+		}
+	}
+}
+
+// RelayOnce mengirim satu batch event dan mengembalikan jumlah event yang terkirim
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	events, err := repositories.ClaimOutboxEvents(tx, now, r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, event := range events {
+		if err := r.publish(ctx, event); err != nil {
+			next := now.Add(backoff(event.Attempts))
+			log.WithFields(log.Fields{
+				"error":       err,
+				"EventID":     event.ID,
+				"type":        event.Type,
+				"AggregateID": event.AggregateID,
+				"attempts":    event.Attempts + 1,
+			}).Warn("Failed to publish outbox event")
+			if err := repositories.MarkOutboxEventFailed(tx, event.ID, next, err.Error()); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if err := repositories.MarkOutboxEventPublished(tx, event.ID, time.Now()); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, tx.Commit()
+}
+
+// publish mengirim event dengan span di bawah trace request yang membuat event
+func (r *Relay) publish(ctx context.Context, event models.OutboxEvent) (err error) {
+	ctx, span := tracing.Child(tracing.WithTraceParent(ctx, event.TraceParent), "outbox.Publish "+event.Type)
+	defer func() { tracing.End(span, err) }()
+	return r.Publisher.Publish(ctx, event)
+}
+
+func backoff(attempts int) time.Duration {
+	d := time.Second << uint(min(attempts, 16))
+	if d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
@@ -0,0 +1,128 @@
+package partner
+
+import (
+	"bytes"
+	"database/sql"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/partnersign"
+	"golang-echo-postgresql/repositories"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// Verify memverifikasi request bertanda tangan HMAC dari partner. Request tanpa
+// header X-Client-Id diteruskan apa adanya supaya autentikasi lain (JWT) bisa
+// menanganinya; request dengan X-Client-Id wajib lolos verifikasi.
+func (s *Service) Verify() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			clientID := req.Header.Get(partnersign.HeaderClientID)
+			if clientID == "" {
+				return next(c)
+			}
+
+			fields := log.Fields{
+				"ClientID": clientID,
+				"path":     c.Path(),
+			}
+
+			if s.Box == nil {
+				log.WithContext(c.Request().Context()).WithFields(fields).Error("Partner request received but partner signing is not configured")
+				return apierror.New(apierror.Unauthorized)
+			}
+
+			timestamp := req.Header.Get(partnersign.HeaderTimestamp)
+			nonce := req.Header.Get(partnersign.HeaderNonce)
+			signature := req.Header.Get(partnersign.HeaderSignature)
+			if timestamp == "" || nonce == "" || signature == "" || len(nonce) > 64 {
+				log.WithContext(c.Request().Context()).WithFields(fields).Warn("Missing partner signature headers")
+				return apierror.New(apierror.Unauthorized)
+			}
+
+			unix, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				log.WithContext(c.Request().Context()).WithFields(fields).Warn("Invalid partner timestamp")
+				return apierror.New(apierror.Unauthorized)
+			}
+			signedAt := time.Unix(unix, 0)
+			now := time.Now()
+			if signedAt.Before(now.Add(-s.ClockSkew)) || signedAt.After(now.Add(s.ClockSkew)) {
+				log.WithContext(c.Request().Context()).WithFields(fields).Warn("Partner timestamp outside allowed clock skew")
+				return apierror.New(apierror.SignatureExpired)
+			}
+
+			p, err := repositories.GetPartnerByClientID(s.DB, clientID)
+			if err != nil && err != sql.ErrNoRows {
+				log.WithContext(c.Request().Context()).WithFields(fields).WithField("error", err).Error("Failed to load partner")
+				return apierror.New(apierror.InternalError)
+			}
+			if p == nil || !p.Aktif {
+				log.WithContext(c.Request().Context()).WithFields(fields).Warn("Unknown or inactive partner")
+				return apierror.New(apierror.Unauthorized)
+			}
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return apierror.New(apierror.InvalidPayload)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			secrets, err := repositories.GetValidPartnerSecrets(s.DB, p.ID, now)
+			if err != nil {
+				log.WithContext(c.Request().Context()).WithFields(fields).WithField("error", err).Error("Failed to load partner secrets")
+				return apierror.New(apierror.InternalError)
+			}
+
+			valid := false
+			for _, stored := range secrets {
+				secret, err := s.Box.Open(stored.SecretEnc)
+				if err != nil {
+					log.WithContext(c.Request().Context()).WithFields(fields).WithField("error", err).Error("Failed to decrypt partner secret")
+					continue
+				}
+				if partnersign.Verify(secret, req.Method, req.URL.Path, req.URL.RawQuery, timestamp, nonce, body, signature) {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				log.WithContext(c.Request().Context()).WithFields(fields).Warn("Invalid partner signature")
+				return apierror.New(apierror.Unauthorized)
+			}
+
+			if !allowed(p.AllowedEndpoints, req.Method+" "+c.Path()) {
+				log.WithContext(c.Request().Context()).WithFields(fields).Warn("Partner is not allowed to call endpoint")
+				return apierror.New(apierror.Forbidden)
+			}
+
+			// Nonce hanya perlu diingat selama timestamp-nya masih di dalam jendela skew
+			fresh, err := repositories.InsertPartnerNonce(s.DB, p.ID, nonce, signedAt.Add(s.ClockSkew))
+			if err != nil {
+				log.WithContext(c.Request().Context()).WithFields(fields).WithField("error", err).Error("Failed to record partner nonce")
+				return apierror.New(apierror.InternalError)
+			}
+			if !fresh {
+				log.WithContext(c.Request().Context()).WithFields(fields).Warn("Replayed partner request")
+				return apierror.New(apierror.ReplayedRequest)
+			}
+
+			auth.SetPrincipal(c, &auth.Principal{Type: auth.SubjectPartner, ID: p.ID, ClientID: p.ClientID})
+			return next(c)
+		}
+	}
+}
+
+func allowed(endpoints []string, endpoint string) bool {
+	for _, e := range endpoints {
+		if e == endpoint {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package partner
+
+import (
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/partnersign"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	testClientID = "pt_test"
+	testSecret   = "rahasia-partner"
+	testBody     = `{"no_rekening":"1000000001","nominal":5000}`
+)
+
+func newVerifier(t *testing.T) (*Service, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	box, err := NewSecretBox(strings.Repeat("cd", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(db, box, 5*time.Minute, time.Hour), mock
+}
+
+// signedRequest membuat request POST /tabung bertanda tangan dengan timestamp signedAt
+func signedRequest(secret string, signedAt time.Time, nonce string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/tabung", strings.NewReader(testBody))
+	ts := strconv.FormatInt(signedAt.Unix(), 10)
+	req.Header.Set(partnersign.HeaderClientID, testClientID)
+	req.Header.Set(partnersign.HeaderTimestamp, ts)
+	req.Header.Set(partnersign.HeaderNonce, nonce)
+	req.Header.Set(partnersign.HeaderSignature, partnersign.Signature(secret, http.MethodPost, "/tabung", "", ts, nonce, []byte(testBody)))
+	return req
+}
+
+func expectPartner(mock sqlmock.Sqlmock, endpoints string) {
+	mock.ExpectQuery("FROM partners WHERE client_id").
+		WithArgs(testClientID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "client_id", "nama", "allowed_endpoints", "aktif", "created_at"}).
+			AddRow(7, testClientID, "Partner", endpoints, true, time.Now()))
+}
+
+func expectSecrets(t *testing.T, s *Service, mock sqlmock.Sqlmock, secrets ...string) {
+	t.Helper()
+	rows := sqlmock.NewRows([]string{"id", "partner_id", "secret_enc", "valid_from", "valid_until"})
+	for i, secret := range secrets {
+		sealed, err := s.Box.Seal(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.AddRow(i+1, 7, sealed, time.Now().Add(-time.Hour), nil)
+	}
+	mock.ExpectQuery("FROM partner_secrets").WithArgs(7, sqlmock.AnyArg()).WillReturnRows(rows)
+}
+
+// serve menjalankan Verify dan mengembalikan kode apierror, atau "" jika request diteruskan
+func serve(t *testing.T, s *Service, req *http.Request) (apierror.Code, *auth.Principal) {
+	t.Helper()
+	e := echo.New()
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetPath("/tabung")
+
+	var principal *auth.Principal
+	err := s.Verify()(func(c echo.Context) error {
+		principal = auth.PrincipalFrom(c)
+		return nil
+	})(c)
+
+	var apiErr *apierror.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code, nil
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return "", principal
+}
+
+func TestVerifyAcceptsValidSignature(t *testing.T) {
+	s, mock := newVerifier(t)
+	expectPartner(mock, "{POST /tabung}")
+	expectSecrets(t, s, mock, testSecret)
+	mock.ExpectExec("INSERT INTO partner_nonces").WithArgs(7, "nonce-1", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	code, principal := serve(t, s, signedRequest(testSecret, time.Now(), "nonce-1"))
+	if code != "" {
+		t.Fatalf("code = %s, want request forwarded", code)
+	}
+	if principal == nil || principal.Type != auth.SubjectPartner || principal.ClientID != testClientID {
+		t.Errorf("principal = %+v, want partner %s", principal, testClientID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyAcceptsPreviousSecretDuringOverlap(t *testing.T) {
+	s, mock := newVerifier(t)
+	expectPartner(mock, "{POST /tabung}")
+	expectSecrets(t, s, mock, "secret-baru", testSecret)
+	mock.ExpectExec("INSERT INTO partner_nonces").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if code, _ := serve(t, s, signedRequest(testSecret, time.Now(), "nonce-1")); code != "" {
+		t.Fatalf("code = %s, want request forwarded", code)
+	}
+}
+
+func TestVerifyRejectsReplayedNonce(t *testing.T) {
+	s, mock := newVerifier(t)
+	expectPartner(mock, "{POST /tabung}")
+	expectSecrets(t, s, mock, testSecret)
+	mock.ExpectExec("INSERT INTO partner_nonces").WithArgs(7, "nonce-1", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if code, _ := serve(t, s, signedRequest(testSecret, time.Now(), "nonce-1")); code != apierror.ReplayedRequest {
+		t.Fatalf("code = %s, want %s", code, apierror.ReplayedRequest)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    func() *http.Request
+		expect func(t *testing.T, s *Service, mock sqlmock.Sqlmock)
+		want   apierror.Code
+	}{
+		{
+			name: "timestamp too old",
+			req:  func() *http.Request { return signedRequest(testSecret, time.Now().Add(-10*time.Minute), "n") },
+			want: apierror.SignatureExpired,
+		},
+		{
+			name: "timestamp in the future",
+			req:  func() *http.Request { return signedRequest(testSecret, time.Now().Add(10*time.Minute), "n") },
+			want: apierror.SignatureExpired,
+		},
+		{
+			name: "missing nonce",
+			req: func() *http.Request {
+				req := signedRequest(testSecret, time.Now(), "n")
+				req.Header.Del(partnersign.HeaderNonce)
+				return req
+			},
+			want: apierror.Unauthorized,
+		},
+		{
+			name: "wrong secret",
+			req:  func() *http.Request { return signedRequest("bukan-rahasia", time.Now(), "n") },
+			expect: func(t *testing.T, s *Service, mock sqlmock.Sqlmock) {
+				expectPartner(mock, "{POST /tabung}")
+				expectSecrets(t, s, mock, testSecret)
+			},
+			want: apierror.Unauthorized,
+		},
+		{
+			name: "tampered body",
+			req: func() *http.Request {
+				req := signedRequest(testSecret, time.Now(), "n")
+				req.Body = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Replace(testBody, "5000", "9000", 1))).Body
+				return req
+			},
+			expect: func(t *testing.T, s *Service, mock sqlmock.Sqlmock) {
+				expectPartner(mock, "{POST /tabung}")
+				expectSecrets(t, s, mock, testSecret)
+			},
+			want: apierror.Unauthorized,
+		},
+		{
+			name: "query added after signing",
+			req: func() *http.Request {
+				req := signedRequest(testSecret, time.Now(), "n")
+				req.URL.RawQuery = "no_rekening=1000000002"
+				return req
+			},
+			expect: func(t *testing.T, s *Service, mock sqlmock.Sqlmock) {
+				expectPartner(mock, "{POST /tabung}")
+				expectSecrets(t, s, mock, testSecret)
+			},
+			want: apierror.Unauthorized,
+		},
+		{
+			name: "endpoint not allowed",
+			req:  func() *http.Request { return signedRequest(testSecret, time.Now(), "n") },
+			expect: func(t *testing.T, s *Service, mock sqlmock.Sqlmock) {
+				expectPartner(mock, "{POST /tarik}")
+				expectSecrets(t, s, mock, testSecret)
+			},
+			want: apierror.Forbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newVerifier(t)
+			if tt.expect != nil {
+				tt.expect(t, s, mock)
+			}
+			if code, _ := serve(t, s, tt.req()); code != tt.want {
+				t.Fatalf("code = %s, want %s", code, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
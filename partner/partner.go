@@ -0,0 +1,237 @@
+package partner
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrPartnerNotFound  = errors.New("partner tidak ditemukan")
+	ErrNotConfigured    = errors.New("penandatanganan partner belum dikonfigurasi")
+	ErrRekeningNotFound = errors.New("rekening tidak ditemukan")
+	ErrNotLinked        = errors.New("rekening tidak terhubung dengan partner")
+)
+
+// Service mengelola kredensial partner dan memverifikasi request bertanda tangan
+type Service struct {
+	DB            *sql.DB
+	Box           *SecretBox // nil jika PARTNER_SECRET_KEY belum diatur
+	ClockSkew     time.Duration
+	SecretOverlap time.Duration
+}
+
+// NewService membuat Service partner
+func NewService(db *sql.DB, box *SecretBox, clockSkew, secretOverlap time.Duration) *Service {
+	return &Service{DB: db, Box: box, ClockSkew: clockSkew, SecretOverlap: secretOverlap}
+}
+
+// Create mendaftarkan partner baru beserta secret pertamanya
+func (s *Service) Create(req models.CreatePartnerRequest) (*models.Partner, *models.PartnerCredentialResponse, error) {
+	if s.Box == nil {
+		return nil, nil, ErrNotConfigured
+	}
+
+	clientID, err := randomHex(12)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	partner := &models.Partner{ClientID: "pt_" + clientID, Nama: req.Nama, AllowedEndpoints: req.AllowedEndpoints}
+	if err := repositories.CreatePartner(tx, partner); err != nil {
+		return nil, nil, err
+	}
+
+	cred, err := s.issueSecret(tx, partner, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"ClientID": partner.ClientID,
+		"nama":     partner.Nama,
+	}).Info("Partner created")
+
+	return partner, cred, nil
+}
+
+// RotateSecret menerbitkan secret baru. Secret lama tetap berlaku selama SecretOverlap
+// agar partner sempat mengganti konfigurasinya tanpa gangguan.
+func (s *Service) RotateSecret(clientID string) (*models.PartnerCredentialResponse, error) {
+	if s.Box == nil {
+		return nil, ErrNotConfigured
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	partner, err := repositories.GetPartnerByClientID(tx, clientID)
+	if err == sql.ErrNoRows {
+		return nil, ErrPartnerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	previousUntil := now.Add(s.SecretOverlap)
+	if err := repositories.ExpirePartnerSecrets(tx, partner.ID, previousUntil); err != nil {
+		return nil, err
+	}
+
+	cred, err := s.issueSecret(tx, partner, now)
+	if err != nil {
+		return nil, err
+	}
+	cred.PreviousExpires = &previousUntil
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"ClientID":      clientID,
+		"PreviousUntil": previousUntil,
+	}).Info("Partner secret rotated")
+
+	return cred, nil
+}
+
+// LinkRekening menghubungkan rekening dengan partner sehingga partner boleh
+// berlangganan event webhook dari rekening tersebut
+func (s *Service) LinkRekening(clientID, noRekening string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	partner, err := s.partner(tx, clientID)
+	if err != nil {
+		return err
+	}
+	_, err = repositories.GetNasabahByNoRekening(tx, noRekening)
+	if err == sql.ErrNoRows {
+		return ErrRekeningNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := repositories.LinkPartnerRekening(tx, partner.ID, noRekening); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"ClientID":   clientID,
+		"NoRekening": noRekening,
+	}).Info("Rekening linked to partner")
+	return nil
+}
+
+// UnlinkRekening memutus hubungan rekening dengan partner. Subscription webhook yang
+// menyebut rekening ini berhenti menerima event darinya.
+func (s *Service) UnlinkRekening(clientID, noRekening string) error {
+	partner, err := s.partner(s.DB, clientID)
+	if err != nil {
+		return err
+	}
+	ok, err := repositories.UnlinkPartnerRekening(s.DB, partner.ID, noRekening)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotLinked
+	}
+
+	log.WithFields(log.Fields{
+		"ClientID":   clientID,
+		"NoRekening": noRekening,
+	}).Info("Rekening unlinked from partner")
+	return nil
+}
+
+// Rekening mengambil nomor rekening yang terhubung dengan partner
+func (s *Service) Rekening(clientID string) ([]string, error) {
+	partner, err := s.partner(s.DB, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return repositories.ListPartnerRekening(s.DB, partner.ID)
+}
+
+// Run membersihkan nonce yang sudah di luar jendela replay secara berkala
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := repositories.DeleteExpiredPartnerNonces(s.DB, time.Now()); err != nil {
+				log.WithFields(log.Fields{
+					"error": err,
+				}).Warn("Failed to delete expired partner nonces")
+			}
+		}
+	}
+}
+
+func (s *Service) partner(executor repositories.Executor, clientID string) (*models.Partner, error) {
+	partner, err := repositories.GetPartnerByClientID(executor, clientID)
+	if err == sql.ErrNoRows {
+		return nil, ErrPartnerNotFound
+	}
+	return partner, err
+}
+
+func (s *Service) issueSecret(executor repositories.Executor, partner *models.Partner, validFrom time.Time) (*models.PartnerCredentialResponse, error) {
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.Box.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	err = repositories.InsertPartnerSecret(executor, &models.PartnerSecret{
+		PartnerID: partner.ID,
+		SecretEnc: sealed,
+		ValidFrom: validFrom,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.PartnerCredentialResponse{ClientID: partner.ClientID, Secret: secret, ValidFrom: validFrom}, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
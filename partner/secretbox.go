@@ -0,0 +1,64 @@
+package partner
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// SecretBox mengenkripsi secret partner sebelum disimpan di database. Server butuh
+// secret asli untuk menghitung HMAC, jadi secret tidak bisa di-hash satu arah.
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox membuat SecretBox dari kunci AES-256 dalam bentuk hex (64 karakter)
+func NewSecretBox(hexKey string) (*SecretBox, error) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("kunci enkripsi partner bukan hex: %v", err)
+	}
+	if len(key) != 32 {
+		return nil, errors.New("kunci enkripsi partner harus 32 byte")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+// Seal mengenkripsi secret dan mengembalikan nonce+ciphertext dalam base64
+func (b *SecretBox) Seal(plain string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open mendekripsi hasil Seal
+func (b *SecretBox) Open(encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	size := b.aead.NonceSize()
+	if len(sealed) < size {
+		return "", errors.New("secret terenkripsi terlalu pendek")
+	}
+	plain, err := b.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,105 @@
+// Package partnersign adalah helper kecil untuk aplikasi partner yang menandatangani
+// request ke API. Package ini tidak bergantung pada package lain di repository ini
+// sehingga bisa disalin atau di-import langsung oleh partner.
+//
+// Tanda tangan adalah HMAC-SHA256 (hex) atas string kanonik:
+//
+//	METHOD \n PATH \n QUERY \n TIMESTAMP \n NONCE \n hex(SHA256(body))
+//
+// QUERY adalah query string mentah (tanpa "?") dengan pasangan key=value diurutkan
+// berdasarkan key; urutan nilai untuk key yang sama dipertahankan. Query kosong ditulis
+// sebagai baris kosong.
+//
+// dan dikirim bersama header X-Client-Id, X-Timestamp (detik Unix), X-Nonce dan X-Signature.
+package partnersign
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Nama header yang dipakai dalam request bertanda tangan
+const (
+	HeaderClientID  = "X-Client-Id"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// BodyHash mengembalikan hex SHA-256 dari body request
+func BodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// CanonicalQuery mengurutkan pasangan query string mentah berdasarkan key tanpa
+// men-decode atau meng-encode ulang, sehingga yang ditandatangani persis byte yang dikirim
+func CanonicalQuery(rawQuery string) string {
+	var pairs []string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+	key := func(pair string) string {
+		k, _, _ := strings.Cut(pair, "=")
+		return k
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return key(pairs[i]) < key(pairs[j]) })
+	return strings.Join(pairs, "&")
+}
+
+// Canonical menyusun string yang ditandatangani
+func Canonical(method, path, rawQuery, timestamp, nonce string, body []byte) string {
+	return strings.Join([]string{strings.ToUpper(method), path, CanonicalQuery(rawQuery), timestamp, nonce, BodyHash(body)}, "\n")
+}
+
+// Signature menghitung tanda tangan HMAC-SHA256 dalam bentuk hex
+func Signature(secret, method, path, rawQuery, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(Canonical(method, path, rawQuery, timestamp, nonce, body)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify membandingkan tanda tangan dalam waktu konstan
+func Verify(secret, method, path, rawQuery, timestamp, nonce string, body []byte, signature string) bool {
+	expected := Signature(secret, method, path, rawQuery, timestamp, nonce, body)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
+
+// Sign menandatangani http.Request dengan kredensial partner. Body request dibaca
+// lalu dipasang kembali sehingga request tetap bisa dikirim.
+func Sign(req *http.Request, clientID, secret string) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return err
+	}
+	nonce := hex.EncodeToString(nonceBytes)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set(HeaderClientID, clientID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, Signature(secret, req.Method, req.URL.Path, req.URL.RawQuery, timestamp, nonce, body))
+	return nil
+}
@@ -0,0 +1,84 @@
+package partnersign
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := `{"no_rekening":"1000000001","nominal":5000}`
+	req, err := http.NewRequest(http.MethodPost, "https://bank.example/tabung?ref=a1&channel=partner", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Sign(req, "pt_test", "rahasia"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Body tetap bisa dikirim setelah ditandatangani
+	sent, err := io.ReadAll(req.Body)
+	if err != nil || string(sent) != body {
+		t.Fatalf("body after Sign = %q, %v", sent, err)
+	}
+
+	ts, nonce, sig := req.Header.Get(HeaderTimestamp), req.Header.Get(HeaderNonce), req.Header.Get(HeaderSignature)
+	if req.Header.Get(HeaderClientID) != "pt_test" || ts == "" || nonce == "" {
+		t.Fatalf("missing signature headers: %v", req.Header)
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		method string
+		path   string
+		query  string
+		body   string
+		sig    string
+		want   bool
+	}{
+		{"valid", "rahasia", http.MethodPost, "/tabung", "ref=a1&channel=partner", body, sig, true},
+		{"reordered query", "rahasia", http.MethodPost, "/tabung", "channel=partner&ref=a1", body, sig, true},
+		{"uppercase hex", "rahasia", http.MethodPost, "/tabung", "ref=a1&channel=partner", body, strings.ToUpper(sig), true},
+		{"wrong secret", "lain", http.MethodPost, "/tabung", "ref=a1&channel=partner", body, sig, false},
+		{"other method", "rahasia", http.MethodGet, "/tabung", "ref=a1&channel=partner", body, sig, false},
+		{"other path", "rahasia", http.MethodPost, "/tarik", "ref=a1&channel=partner", body, sig, false},
+		{"tampered query", "rahasia", http.MethodPost, "/tabung", "ref=a2&channel=partner", body, sig, false},
+		{"dropped query", "rahasia", http.MethodPost, "/tabung", "", body, sig, false},
+		{"tampered body", "rahasia", http.MethodPost, "/tabung", "ref=a1&channel=partner", strings.Replace(body, "5000", "9000", 1), sig, false},
+		{"empty signature", "rahasia", http.MethodPost, "/tabung", "ref=a1&channel=partner", body, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.method, tt.path, tt.query, ts, nonce, []byte(tt.body), tt.sig); got != tt.want {
+				t.Errorf("Verify = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	got := Canonical("post", "/tabung", "", "1700000000", "abc", nil)
+	want := "POST\n/tabung\n\n1700000000\nabc\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if got != want {
+		t.Errorf("Canonical = %q, want %q", got, want)
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"", ""},
+		{"b=2&a=1", "a=1&b=2"},
+		{"tag=z&a=1&tag=y", "a=1&tag=z&tag=y"},
+		{"q=a%20b&&flag", "flag&q=a%20b"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalQuery(tt.raw); got != tt.want {
+			t.Errorf("CanonicalQuery(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
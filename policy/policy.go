@@ -0,0 +1,182 @@
+package policy
+
+import (
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// Permission adalah hak akses atas satu jenis operasi back-office
+type Permission string
+
+const (
+	PermAdjust           Permission = "saldo.adjust"
+	PermReverse          Permission = "tabungan.reverse"
+	PermFreeze           Permission = "rekening.freeze"
+	PermUnfreeze         Permission = "rekening.unfreeze"
+	PermAssistedWithdraw Permission = "tarik.assisted"
+	PermDeposit          Permission = "tabung.setor"
+	PermApprove          Permission = "approval.decide"
+	PermApprovalRead     Permission = "approval.read"
+	PermAuditRead        Permission = "audit.read"
+	PermManageStaff      Permission = "staff.manage"
+	PermManagePartners   Permission = "partner.manage"
+	PermFraudRead        Permission = "fraud.read"
+	PermFraudReview      Permission = "fraud.review"
+	PermAMLRead          Permission = "aml.read"
+	PermAMLManage        Permission = "aml.manage"
+	PermScreeningRead    Permission = "screening.read"
+	PermScreeningReview  Permission = "screening.review"
+	PermWatchlistImport  Permission = "watchlist.import"
+	PermEODRead          Permission = "eod.read"
+	PermEODRun           Permission = "eod.run"
+	PermReconcileRead    Permission = "reconciliation.read"
+	PermReconcileRun     Permission = "reconciliation.run"
+	PermReconcileCorrect Permission = "reconciliation.correct"
+	PermGLRead           Permission = "gl.read"
+)
+
+var rolePermissions = map[string][]Permission{
+	auth.RoleTeller: {
+		PermAdjust, PermReverse, PermFreeze, PermAssistedWithdraw, PermDeposit, PermApprovalRead,
+	},
+	auth.RoleSupervisor: {
+		PermAdjust, PermReverse, PermFreeze, PermUnfreeze, PermAssistedWithdraw, PermDeposit, PermApprove, PermApprovalRead,
+		PermFraudRead, PermFraudReview, PermEODRead, PermEODRun,
+		PermReconcileRead, PermReconcileRun, PermReconcileCorrect, PermGLRead,
+	},
+	auth.RoleAuditor: {
+		PermApprovalRead, PermAuditRead, PermFraudRead, PermAMLRead, PermScreeningRead, PermEODRead,
+		PermReconcileRead, PermReconcileRun, PermGLRead,
+	},
+	auth.RoleCompliance: {
+		PermAuditRead, PermFraudRead, PermFraudReview, PermAMLRead, PermAMLManage,
+		PermScreeningRead, PermScreeningReview, PermWatchlistImport,
+	},
+	auth.RoleAdmin: {
+		PermAdjust, PermReverse, PermFreeze, PermUnfreeze, PermAssistedWithdraw, PermDeposit, PermApprove, PermApprovalRead,
+		PermAuditRead, PermManageStaff, PermManagePartners, PermFraudRead, PermFraudReview,
+		PermAMLRead, PermAMLManage, PermScreeningRead, PermScreeningReview, PermWatchlistImport,
+		PermEODRead, PermEODRun, PermReconcileRead, PermReconcileRun, PermReconcileCorrect, PermGLRead,
+	},
+}
+
+// Action adalah operasi yang dinilai oleh policy
+type Action string
+
+const (
+	ActionAdjustment Action = "adjustment"
+	ActionReversal   Action = "reversal"
+	ActionFreeze     Action = "freeze"
+	ActionUnfreeze   Action = "unfreeze"
+	ActionDeposit    Action = "deposit"
+	ActionWithdrawal Action = "withdrawal"
+	ActionTransfer   Action = "transfer"
+	ActionReconcile  Action = "reconciliation_correction"
+)
+
+// ApprovalMode menentukan kapan sebuah aksi harus melalui maker-checker
+type ApprovalMode int
+
+const (
+	ApprovalNever ApprovalMode = iota
+	ApprovalAlways
+	ApprovalAboveThreshold
+)
+
+// Rule mengatur hak akses dan kebutuhan persetujuan untuk satu aksi
+type Rule struct {
+	Permission Permission
+	Approval   ApprovalMode
+	Threshold  float64
+}
+
+// Outcome adalah hasil evaluasi policy
+type Outcome int
+
+const (
+	Deny Outcome = iota
+	Allow
+	RequireApproval
+)
+
+// Policy adalah lapisan otorisasi yang dikonsultasikan handler sebelum menjalankan operasi
+type Policy struct {
+	Rules map[Action]Rule
+}
+
+// New membuat Policy dengan aturan bawaan. Penarikan dan transfer di atas largeWithdrawal,
+// penyesuaian saldo, reversal, pembukaan blokir dan koreksi rekonsiliasi selalu melalui maker-checker;
+// pembekuan langsung berlaku karena mengurangi risiko.
+func New(largeWithdrawal float64) *Policy {
+	return &Policy{Rules: map[Action]Rule{
+		ActionAdjustment: {Permission: PermAdjust, Approval: ApprovalAlways},
+		ActionReversal:   {Permission: PermReverse, Approval: ApprovalAlways},
+		ActionFreeze:     {Permission: PermFreeze, Approval: ApprovalNever},
+		ActionUnfreeze:   {Permission: PermUnfreeze, Approval: ApprovalAlways},
+		ActionDeposit:    {Permission: PermDeposit, Approval: ApprovalNever},
+		ActionWithdrawal: {Permission: PermAssistedWithdraw, Approval: ApprovalAboveThreshold, Threshold: largeWithdrawal},
+		ActionTransfer:   {Permission: PermAssistedWithdraw, Approval: ApprovalAboveThreshold, Threshold: largeWithdrawal},
+		ActionReconcile:  {Permission: PermReconcileCorrect, Approval: ApprovalAlways},
+	}}
+}
+
+// Can memeriksa apakah principal memiliki permission tertentu
+func Can(p *auth.Principal, perm Permission) bool {
+	if !p.IsStaff() {
+		return false
+	}
+	for _, granted := range rolePermissions[p.Role] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate menilai apakah principal boleh menjalankan aksi dengan nominal tertentu,
+// dan apakah aksi tersebut harus menunggu persetujuan checker
+func (pol *Policy) Evaluate(p *auth.Principal, action Action, nominal float64) Outcome {
+	rule, ok := pol.Rules[action]
+	if !ok || p == nil {
+		return Deny
+	}
+
+	// Nasabah hanya boleh menyetor, menarik atau mentransfer dana pada rekeningnya sendiri;
+	// kepemilikan diperiksa pemanggil. Partner hanya boleh menyetor, dan endpoint-nya
+	// sudah dibatasi allowed_endpoints.
+	isOwn := p.Type == auth.SubjectNasabah && (action == ActionDeposit || action == ActionWithdrawal || action == ActionTransfer)
+	isPartnerDeposit := p.Type == auth.SubjectPartner && action == ActionDeposit
+	if !isOwn && !isPartnerDeposit && !Can(p, rule.Permission) {
+		return Deny
+	}
+
+	switch rule.Approval {
+	case ApprovalAlways:
+		return RequireApproval
+	case ApprovalAboveThreshold:
+		if nominal >= rule.Threshold {
+			return RequireApproval
+		}
+	}
+	return Allow
+}
+
+// Require adalah middleware Echo yang hanya meneruskan petugas dengan permission tertentu
+func Require(perm Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			p := auth.PrincipalFrom(c)
+			if !Can(p, perm) {
+				log.WithFields(log.Fields{
+					"permission": perm,
+					"path":       c.Request().URL.Path,
+				}).Warn("Permission denied")
+				return apierror.New(apierror.Forbidden)
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Package ratelimit membatasi jumlah request dengan token bucket per policy. Setiap policy
+// menghitung per satu jenis kunci: alamat IP, principal yang terautentikasi, partner atau
+// nomor rekening pada path, dan bisa dibatasi ke route tertentu. Bucket disimpan di memori
+// atau di Postgres supaya semua replika berbagi batas yang sama.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/metrics"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// Header rate limit mengikuti draft IETF RateLimit header fields
+const (
+	HeaderLimit     = "RateLimit-Limit"
+	HeaderRemaining = "RateLimit-Remaining"
+	HeaderReset     = "RateLimit-Reset"
+	HeaderPolicy    = "RateLimit-Policy"
+
+	HeaderRetryAfter = "Retry-After"
+)
+
+// counterKey menyimpan Counter request ini di context Echo; Middleware dan Identified
+// menghitung ke Counter yang sama supaya header respons memakai policy paling ketat
+const counterKey = "ratelimit.counter"
+
+// identifiedKey menandai policy principal dan partner sudah dihitung untuk request ini
+const identifiedKey = "ratelimit.identified"
+
+// Limiter menerapkan policy dari file policy ke request HTTP dan RPC gRPC
+type Limiter struct {
+	Policies *PolicySet
+	Store    Store
+}
+
+// Request berisi nilai kunci satu request. Rekening mengembalikan nilai path parameter
+// untuk policy berkunci rekening, kosong jika route tidak memilikinya.
+type Request struct {
+	IP        string
+	Principal *auth.Principal
+	Rekening  func(param string) string
+}
+
+// value mengembalikan nilai kunci policy untuk request ini, kosong jika tidak ada
+func (r Request) value(policy Policy) string {
+	switch policy.Key {
+	case KeyIP:
+		return r.IP
+	case KeyRekening:
+		if r.Rekening == nil {
+			return ""
+		}
+		return r.Rekening(policy.Param)
+	}
+
+	p := r.Principal
+	if p == nil {
+		return ""
+	}
+	switch {
+	case policy.Key == KeyPartner && p.Type == auth.SubjectPartner:
+		return p.ClientID
+	case policy.Key == KeyPrincipal && p.Type != auth.SubjectPartner:
+		return fmt.Sprintf("%s:%d", p.Type, p.ID)
+	}
+	return ""
+}
+
+// Counter menghitung satu request pada route berbentuk "METHOD /route/template", bisa
+// dalam beberapa tahap (sebelum dan sesudah autentikasi). Header respons mengikuti
+// policy paling ketat dari semua tahap, atau policy yang menolak.
+type Counter struct {
+	limiter  *Limiter
+	route    string
+	tightest *result
+	rejected *result
+}
+
+// result adalah isi bucket satu policy setelah request dihitung
+type result struct {
+	policy    Policy
+	remaining int
+	reset     time.Duration // sampai bucket penuh kembali
+	retry     time.Duration // sampai satu token tersedia, hanya jika ditolak
+}
+
+// New membaca file policy di path. Jika file tidak ada, rate limit dinonaktifkan.
+func New(store Store, path string) (*Limiter, error) {
+	l := &Limiter{Store: store}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.WithFields(log.Fields{
+			"path": path,
+		}).Warn("Rate limit policy file not found, rate limiting is disabled")
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	l.Policies, err = ParsePolicies(data)
+	if err != nil {
+		return nil, err
+	}
+	log.WithFields(log.Fields{
+		"path":     path,
+		"version":  l.Policies.Version,
+		"policies": len(l.Policies.Policies),
+	}).Info("Rate limit policies loaded")
+	return l, nil
+}
+
+// Enabled mengembalikan true jika file policy tersedia
+func (l *Limiter) Enabled() bool {
+	return l.Policies != nil
+}
+
+// Counter membuat Counter untuk satu request pada route
+func (l *Limiter) Counter(route string) *Counter {
+	return &Counter{limiter: l, route: route}
+}
+
+// Middleware menerapkan policy berkunci IP dan rekening. Pasang dengan e.Use supaya
+// request yang gagal autentikasi ikut terhitung; route di skip, seperti probe health,
+// tidak dibatasi.
+func (l *Limiter) Middleware(skip ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !l.Enabled() || slices.Contains(skip, c.Path()) {
+				return next(c)
+			}
+			if err := l.limit(c, KeyIP, KeyRekening); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// Identified menerapkan policy berkunci principal dan partner. Pasang tepat setelah
+// middleware autentikasi; request yang belum terautentikasi diteruskan tanpa dihitung,
+// dan setiap request hanya dihitung sekali walaupun melewati dua autentikasi.
+func (l *Limiter) Identified() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !l.Enabled() || auth.PrincipalFrom(c) == nil || c.Get(identifiedKey) != nil {
+				return next(c)
+			}
+			c.Set(identifiedKey, true)
+			if err := l.limit(c, KeyPrincipal, KeyPartner); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// limit menghitung request Echo ke Counter request ini lalu memasang header respons
+func (l *Limiter) limit(c echo.Context, keys ...string) error {
+	counter, _ := c.Get(counterKey).(*Counter)
+	if counter == nil {
+		counter = l.Counter(c.Request().Method + " " + c.Path())
+		c.Set(counterKey, counter)
+	}
+
+	err := counter.Count(c.Request().Context(), Request{
+		IP:        c.RealIP(),
+		Principal: auth.PrincipalFrom(c),
+		Rekening:  c.Param,
+	}, keys...)
+
+	h := c.Response().Header()
+	for name, value := range counter.Headers() {
+		h.Set(name, value)
+	}
+	return err
+}
+
+// Count menghitung request pada setiap policy dengan jenis kunci keys yang berlaku untuk
+// route, berhenti pada policy pertama yang menolak dan mengembalikan apierror
+// RateLimited. Jika store gagal, request diteruskan: rate limit tidak boleh membuat API
+// tidak tersedia.
+func (c *Counter) Count(ctx context.Context, req Request, keys ...string) error {
+	if !c.limiter.Enabled() {
+		return nil
+	}
+	for _, policy := range c.limiter.Policies.Policies {
+		if !slices.Contains(keys, policy.Key) || !policy.appliesTo(c.route) {
+			continue
+		}
+		value := req.value(policy)
+		if value == "" {
+			continue
+		}
+
+		allowed, tokens, err := c.limiter.Store.Take(policy.ID+":"+value, policy.capacity(), policy.rate())
+		if err != nil {
+			log.WithContext(ctx).WithFields(log.Fields{
+				"error":  err,
+				"policy": policy.ID,
+			}).Warn("Rate limit store failed, request allowed")
+			continue
+		}
+
+		res := newResult(policy, allowed, tokens)
+		if c.tightest == nil || res.tighterThan(c.tightest) {
+			c.tightest = res
+		}
+		if !allowed {
+			c.rejected = res
+			metrics.RateLimited(policy.ID, policy.Key)
+			log.WithContext(ctx).WithFields(log.Fields{
+				"policy": policy.ID,
+				"key":    policy.Key,
+				"route":  c.route,
+			}).Warn("Request rejected by rate limit")
+			return apierror.New(apierror.RateLimited).With("policy", policy.ID)
+		}
+	}
+	return nil
+}
+
+// Headers mengembalikan header RateLimit-* untuk policy paling ketat, atau untuk policy
+// yang menolak beserta Retry-After. Kosong jika belum ada policy yang berlaku.
+func (c *Counter) Headers() map[string]string {
+	r := c.tightest
+	if c.rejected != nil {
+		r = c.rejected
+	}
+	if r == nil {
+		return nil
+	}
+
+	policy := fmt.Sprintf("%d;w=%d", r.policy.Limit, seconds(time.Duration(r.policy.Period)))
+	if r.policy.Burst > 0 {
+		policy += ";burst=" + strconv.Itoa(r.policy.Burst)
+	}
+	headers := map[string]string{
+		HeaderLimit:     strconv.Itoa(int(r.policy.capacity())),
+		HeaderRemaining: strconv.Itoa(r.remaining),
+		HeaderReset:     strconv.Itoa(seconds(r.reset)),
+		HeaderPolicy:    policy,
+	}
+	if c.rejected != nil {
+		headers[HeaderRetryAfter] = strconv.Itoa(seconds(r.retry))
+	}
+	return headers
+}
+
+func newResult(policy Policy, allowed bool, tokens float64) *result {
+	rate := policy.rate()
+	res := &result{
+		policy:    policy,
+		remaining: int(math.Max(0, math.Floor(tokens))),
+		reset:     time.Duration((policy.capacity() - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		res.retry = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return res
+}
+
+// tighterThan membandingkan dua hasil; yang sisa tokennya lebih sedikit dilaporkan di header
+func (r *result) tighterThan(other *result) bool {
+	if r.remaining != other.remaining {
+		return r.remaining < other.remaining
+	}
+	return r.reset > other.reset
+}
+
+// seconds membulatkan ke atas ke detik penuh, minimal 1 untuk durasi positif
+func seconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,169 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// clock adalah waktu tiruan untuk MemoryStore
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newClockStore() (*MemoryStore, *clock) {
+	c := &clock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = c.now
+	return s, c
+}
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	s, clock := newClockStore()
+	const capacity, rate = 3, 1.0 // 3 token, diisi ulang 1 per detik
+
+	for i := 0; i < capacity; i++ {
+		if allowed, _, _ := s.Take("k", capacity, rate); !allowed {
+			t.Fatalf("take %d rejected, want burst of %d allowed", i+1, capacity)
+		}
+	}
+	if allowed, tokens, _ := s.Take("k", capacity, rate); allowed || tokens != 0 {
+		t.Fatalf("take after burst = %v (tokens %v), want rejected", allowed, tokens)
+	}
+	if allowed, _, _ := s.Take("other", capacity, rate); !allowed {
+		t.Error("separate key shares bucket")
+	}
+
+	clock.advance(500 * time.Millisecond)
+	if allowed, _, _ := s.Take("k", capacity, rate); allowed {
+		t.Error("take after half a token refilled was allowed")
+	}
+	clock.advance(500 * time.Millisecond)
+	if allowed, _, _ := s.Take("k", capacity, rate); !allowed {
+		t.Error("take after one token refilled was rejected")
+	}
+
+	clock.advance(time.Hour)
+	if _, tokens, _ := s.Take("k", capacity, rate); tokens != capacity-1 {
+		t.Errorf("tokens after long idle = %v, want refill capped at capacity", tokens)
+	}
+}
+
+func newTestLimiter(t *testing.T, policies string) (*Limiter, *clock) {
+	t.Helper()
+	set, err := ParsePolicies([]byte(policies))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, clock := newClockStore()
+	return &Limiter{Policies: set, Store: store}, clock
+}
+
+func call(e *echo.Echo, target, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.RemoteAddr = ip + ":1234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddlewareRejectsWithHeaders(t *testing.T) {
+	l, clock := newTestLimiter(t, `{"policies":[
+		{"id":"ip","key":"ip","limit":2,"period":"1m"},
+		{"id":"saldo","key":"rekening","routes":["GET /saldo/:no_rekening"],"limit":60,"period":"1m"}
+	]}`)
+
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.Handler
+	e.Use(l.Middleware("/health"))
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	e.GET("/saldo/:no_rekening", ok)
+	e.GET("/health", ok)
+
+	first := call(e, "/saldo/1000000001", "10.0.0.1")
+	if first.Code != http.StatusNoContent {
+		t.Fatalf("first status = %d", first.Code)
+	}
+	if got := first.Header().Get(HeaderRemaining); got != "1" {
+		t.Errorf("%s = %q, want tightest policy remaining 1", HeaderRemaining, got)
+	}
+	if got := first.Header().Get(HeaderPolicy); got != "2;w=60" {
+		t.Errorf("%s = %q, want 2;w=60", HeaderPolicy, got)
+	}
+
+	call(e, "/saldo/1000000001", "10.0.0.1")
+	rejected := call(e, "/saldo/1000000002", "10.0.0.1")
+	if rejected.Code != http.StatusTooManyRequests {
+		t.Fatalf("third status = %d, want 429", rejected.Code)
+	}
+	if got := rejected.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+
+	if rec := call(e, "/saldo/1000000001", "10.0.0.2"); rec.Code != http.StatusNoContent {
+		t.Errorf("other ip status = %d, want separate bucket", rec.Code)
+	}
+	if rec := call(e, "/health", "10.0.0.1"); rec.Code != http.StatusNoContent {
+		t.Errorf("skipped route status = %d, want not limited", rec.Code)
+	}
+
+	clock.advance(30 * time.Second)
+	if rec := call(e, "/saldo/1000000001", "10.0.0.1"); rec.Code != http.StatusNoContent {
+		t.Errorf("status after refill = %d, want 204", rec.Code)
+	}
+}
+
+func TestIdentifiedCountsPrincipalOnce(t *testing.T) {
+	l, _ := newTestLimiter(t, `{"policies":[{"id":"user","key":"principal","limit":1,"period":"1h"}]}`)
+	setPrincipal := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			auth.SetPrincipal(c, &auth.Principal{Type: auth.SubjectNasabah, ID: 1, NoRekening: "1000000001"})
+			return next(c)
+		}
+	}
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/saldo", nil), httptest.NewRecorder())
+	// Dua middleware autentikasi berurutan tetap dihitung satu kali
+	h := setPrincipal(l.Identified()(setPrincipal(l.Identified()(func(c echo.Context) error { return nil }))))
+	if err := h(c); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/saldo", nil), httptest.NewRecorder())
+	var apiErr *apierror.Error
+	if err := h(c); !errors.As(err, &apiErr) || apiErr.Code != apierror.RateLimited {
+		t.Fatalf("second request err = %v, want %s", err, apierror.RateLimited)
+	}
+}
+
+// failingStore selalu gagal, seperti Postgres yang tidak terjangkau
+type failingStore struct{}
+
+func (failingStore) Take(string, float64, float64) (bool, float64, error) {
+	return false, 0, errors.New("connection refused")
+}
+
+func (failingStore) Run(context.Context) {}
+
+func TestStoreFailureAllowsRequest(t *testing.T) {
+	l, _ := newTestLimiter(t, `{"policies":[{"id":"ip","key":"ip","limit":1,"period":"1m"}]}`)
+	l.Store = failingStore{}
+
+	e := echo.New()
+	e.Use(l.Middleware())
+	e.GET("/", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+	for i := 0; i < 3; i++ {
+		if rec := call(e, "/", "10.0.0.1"); rec.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want request allowed when store fails", rec.Code)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Jenis kunci bucket. Setiap policy menghitung request per nilai kunci, misalnya per
+// alamat IP atau per nomor rekening tujuan.
+const (
+	KeyIP        = "ip"        // alamat IP klien (lihat TRUSTED_PROXIES)
+	KeyPrincipal = "principal" // nasabah atau petugas yang terautentikasi
+	KeyPartner   = "partner"   // partner dari request bertanda tangan HMAC
+	KeyRekening  = "rekening"  // nomor rekening pada path parameter
+)
+
+// defaultParam adalah path parameter untuk kunci rekening jika policy tidak menyebutkannya
+const defaultParam = "no_rekening"
+
+// Duration adalah time.Duration yang ditulis sebagai string ("1m", "1h") di file policy
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Policy adalah satu token bucket per nilai kunci: Limit request per Period, dengan
+// lonjakan sampai Burst request sekaligus. Routes berisi "METHOD /route/template" seperti
+// pada allowed_endpoints partner; kosong berarti semua route.
+type Policy struct {
+	ID       string   `json:"id"`
+	Key      string   `json:"key"`
+	Routes   []string `json:"routes,omitempty"`
+	Param    string   `json:"param,omitempty"`
+	Limit    int      `json:"limit"`
+	Period   Duration `json:"period"`
+	Burst    int      `json:"burst,omitempty"`
+	Disabled bool     `json:"disabled,omitempty"`
+}
+
+// PolicySet adalah isi file policy rate limit. Version dihitung dari isi file.
+type PolicySet struct {
+	Version  string   `json:"version"`
+	Policies []Policy `json:"policies"`
+}
+
+// ParsePolicies membaca dan memvalidasi file policy rate limit
+func ParsePolicies(data []byte) (*PolicySet, error) {
+	var set PolicySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("file policy rate limit tidak valid: %v", err)
+	}
+
+	var errs []error
+	seen := map[string]bool{}
+	for i := range set.Policies {
+		policy := &set.Policies[i]
+		if policy.ID == "" {
+			errs = append(errs, fmt.Errorf("policy #%d: id wajib diisi", i+1))
+			continue
+		}
+		if seen[policy.ID] {
+			errs = append(errs, fmt.Errorf("policy %s: id duplikat", policy.ID))
+		}
+		seen[policy.ID] = true
+		if err := policy.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("policy %s: %v", policy.ID, err))
+		}
+		if policy.Key == KeyRekening && policy.Param == "" {
+			policy.Param = defaultParam
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sum := sha256.Sum256(data)
+	set.Version = hex.EncodeToString(sum[:6])
+	return &set, nil
+}
+
+func (p Policy) validate() error {
+	switch p.Key {
+	case KeyIP, KeyPrincipal, KeyPartner, KeyRekening:
+	default:
+		return fmt.Errorf("key %q tidak dikenal", p.Key)
+	}
+	if p.Limit <= 0 || p.Period <= 0 {
+		return errors.New("limit dan period wajib diisi")
+	}
+	if p.Burst < 0 {
+		return errors.New("burst tidak boleh negatif")
+	}
+	if p.Param != "" && p.Key != KeyRekening {
+		return errors.New("param hanya berlaku untuk key rekening")
+	}
+	for _, route := range p.Routes {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || method == "" || method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("route %q harus berbentuk \"METHOD /path\"", route)
+		}
+	}
+	return nil
+}
+
+// capacity adalah jumlah token maksimum di bucket
+func (p Policy) capacity() float64 {
+	if p.Burst > 0 {
+		return float64(p.Burst)
+	}
+	return float64(p.Limit)
+}
+
+// rate adalah jumlah token yang diisi ulang per detik
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / time.Duration(p.Period).Seconds()
+}
+
+func (p Policy) appliesTo(route string) bool {
+	if p.Disabled {
+		return false
+	}
+	if len(p.Routes) == 0 {
+		return true
+	}
+	for _, r := range p.Routes {
+		if r == route {
+			return true
+		}
+	}
+	return false
+}
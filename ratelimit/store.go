@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"golang-echo-postgresql/repositories"
+	"math"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Backend penyimpanan bucket
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+)
+
+// cleanupInterval adalah jeda antar penghapusan bucket yang sudah penuh kembali
+const cleanupInterval = time.Minute
+
+// Store menyimpan token bucket. Take mengambil satu token dari bucket key yang diisi
+// ulang rate token per detik sampai capacity, lalu mengembalikan apakah token tersedia
+// dan isi bucket setelahnya.
+type Store interface {
+	Take(key string, capacity, rate float64) (allowed bool, tokens float64, err error)
+	Run(ctx context.Context)
+}
+
+// MemoryStore menyimpan bucket di memori proses. Setiap replika menghitung sendiri,
+// jadi batas efektif dikali jumlah replika.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+func (s *MemoryStore) Take(key string, capacity, rate float64) (bool, float64, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updatedAt: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updatedAt).Seconds()*rate)
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	b.fullAt = now.Add(time.Duration((capacity - b.tokens) / rate * float64(time.Second)))
+	return allowed, b.tokens, nil
+}
+
+// Run menghapus bucket yang sudah penuh kembali secara berkala sampai ctx dibatalkan
+func (s *MemoryStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := s.now()
+		s.mu.Lock()
+		for key, b := range s.buckets {
+			if !b.fullAt.After(now) {
+				delete(s.buckets, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// PostgresStore menyimpan bucket di tabel rate_limit_buckets sehingga semua replika
+// berbagi batas yang sama. Setiap request yang terkena policy menambah satu query.
+type PostgresStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+func (s *PostgresStore) Take(key string, capacity, rate float64) (bool, float64, error) {
+	return repositories.TakeRateLimitToken(s.DB, key, capacity, rate)
+}
+
+// Run menghapus bucket yang sudah penuh kembali secara berkala sampai ctx dibatalkan
+func (s *PostgresStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := repositories.DeleteExpiredRateLimitBuckets(s.DB); err != nil {
+			log.WithFields(log.Fields{
+				"error": err,
+			}).Warn("Failed to delete expired rate limit buckets")
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package reconcile
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/approval"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/backoffice"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/repositories"
+	"math"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidCorrection = errors.New("request koreksi tidak valid")
+	ErrMismatchNotOpen   = errors.New("selisih tidak dalam status open")
+	ErrMismatchGone      = errors.New("saldo rekening sudah cocok dengan riwayat")
+)
+
+// Register mendaftarkan eksekusi koreksi rekonsiliasi ke antrean persetujuan
+func Register(q *approval.Queue) {
+	q.Register(policy.ActionReconcile, func(tx *sql.Tx, payload json.RawMessage) (interface{}, error) {
+		var req models.CorrectionRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCorrection, err)
+		}
+		return Correct(tx, req)
+	})
+}
+
+// SubmitCorrection mengajukan koreksi selisih ke antrean maker-checker. Koreksi tidak
+// pernah dijalankan langsung; checker menyetujuinya lewat endpoint approvals.
+func (s *Service) SubmitCorrection(maker *auth.Principal, approvals *approval.Queue, req models.CorrectionRequest) (*models.PendingOperation, error) {
+	if err := validateCorrection(req); err != nil {
+		return nil, err
+	}
+
+	m, err := s.Mismatch(req.SelisihID)
+	if err != nil {
+		return nil, err
+	}
+	if m.Status != models.SelisihOpen {
+		return nil, ErrMismatchNotOpen
+	}
+
+	op, err := approvals.Submit(maker, policy.ActionReconcile, m.NoRekening, math.Abs(m.Selisih), req.Alasan, req)
+	if err != nil {
+		return nil, err
+	}
+
+	submitted, err := repositories.MarkMismatchCorrectionSubmitted(s.DB, m.ID, op.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !submitted {
+		// Selisih berubah status sejak dibaca; operasi tetap di antrean dan akan gagal
+		// saat disetujui karena Correct memeriksa ulang statusnya
+		log.WithFields(log.Fields{
+			"SelisihID":   m.ID,
+			"OperationID": op.ID,
+		}).Warn("Reconciliation mismatch changed while submitting correction")
+	}
+	return op, nil
+}
+
+// Correct menjalankan koreksi selisih yang sudah disetujui. Saldo dan riwayat dihitung
+// ulang di dalam tx dengan rekening terkunci, sehingga koreksi memakai angka terkini,
+// bukan angka saat selisih ditemukan.
+//
+// Mode saldo menyamakan saldo dengan hasil hitung ulang riwayat tanpa menambah riwayat,
+// karena riwayat (dan jurnal GL yang dibentuk darinya) sudah benar; checkpoint langsung
+// dimajukan ke transaksi terakhir dengan saldo tersebut. Mode riwayat mencatat transaksi koreksi sebesar
+// selisih tanpa mengubah saldo, untuk kasus saldo benar tetapi ada transaksi yang tidak
+// tercatat.
+func Correct(tx *sql.Tx, req models.CorrectionRequest) (*models.ReconciliationMismatch, error) {
+	if err := validateCorrection(req); err != nil {
+		return nil, err
+	}
+
+	m, err := repositories.GetReconciliationMismatchForUpdate(tx, req.SelisihID)
+	if err == sql.ErrNoRows {
+		return nil, ErrMismatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m.Status != models.SelisihOpen && m.Status != models.SelisihKoreksiDiajukan {
+		return nil, ErrMismatchNotOpen
+	}
+
+	if err := repositories.LockNasabah(tx, m.NasabahID); err != nil {
+		return nil, err
+	}
+	states, err := repositories.GetReconciliationStates(tx, []int{m.NasabahID})
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, backoffice.ErrRekeningNotFound
+	}
+	state := states[0]
+
+	diff := state.Saldo - state.Expected()
+	if math.Abs(diff) < tolerance {
+		return nil, ErrMismatchGone
+	}
+	nominal := math.Round(math.Abs(diff)*100) / 100
+
+	switch req.Mode {
+	case models.KoreksiSaldo:
+		// Riwayat benar sehingga tidak ada transaksi yang dicatat; GL dibentuk dari
+		// riwayat, jadi jurnalnya sudah sama dengan saldo hasil koreksi
+		arah := backoffice.ArahKredit
+		if diff > 0 {
+			arah = backoffice.ArahDebit
+		}
+		if err := repositories.SetSaldo(tx, state.NasabahID, state.Expected()); err != nil {
+			return nil, err
+		}
+		if err := repositories.SaveReconciliationCheckpoint(tx, state.NasabahID, state.LastTabungan, state.Expected()); err != nil {
+			return nil, err
+		}
+		err = outbox.Enqueue(tx, outbox.BalanceAdjustedV1{
+			NoRekening: state.NoRekening,
+			Arah:       arah,
+			Nominal:    nominal,
+			SaldoAkhir: state.Expected(),
+			Alasan:     req.Alasan,
+			OccurredAt: time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+	case models.KoreksiRiwayat:
+		// Saldo lebih besar dari riwayat berarti ada kredit yang tidak tercatat
+		jenis := models.JenisKoreksiKredit
+		if diff < 0 {
+			jenis = models.JenisKoreksiDebit
+		}
+		err = repositories.InsertTabunganDetail(tx, &models.Tabungan{
+			NasabahID:      state.NasabahID,
+			JenisTransaksi: jenis,
+			Nominal:        nominal,
+			Keterangan:     req.Alasan,
+			Channel:        models.ChannelSistem,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := repositories.UpdateMismatchStatus(tx, m.ID, models.SelisihDikoreksi); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"SelisihID":  m.ID,
+		"NoRekening": state.NoRekening,
+		"mode":       req.Mode,
+		"nominal":    nominal,
+	}).Info("Reconciliation mismatch corrected")
+
+	return repositories.GetReconciliationMismatch(tx, m.ID)
+}
+
+func validateCorrection(req models.CorrectionRequest) error {
+	if req.SelisihID <= 0 || req.Alasan == "" {
+		return ErrInvalidCorrection
+	}
+	if req.Mode != models.KoreksiSaldo && req.Mode != models.KoreksiRiwayat {
+		return ErrInvalidCorrection
+	}
+	return nil
+}
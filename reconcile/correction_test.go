@@ -0,0 +1,80 @@
+package reconcile
+
+import (
+	"golang-echo-postgresql/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var mismatchRow = []string{"id", "run_id", "nasabah_id", "no_rekening", "saldo_tercatat", "saldo_hitung", "tabungan_id",
+	"saldo_setelah_tercatat", "saldo_setelah_hitung", "status", "operation_id", "created_at", "updated_at", "resolved_at"}
+
+func mismatch(status string) *sqlmock.Rows {
+	return sqlmock.NewRows(mismatchRow).
+		AddRow(7, 1, 3, "1000000001", 150000, 100000, nil, nil, nil, status, 9, time.Now(), time.Now(), nil)
+}
+
+// correct menjalankan Correct di dalam transaksi sqlmock dengan saldo tercatat saldo dan
+// riwayat yang menghasilkan 100000
+func correct(t *testing.T, mode string, saldo float64, expect func(mock sqlmock.Sqlmock)) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM rekonsiliasi_selisih s .* FOR UPDATE OF s").WithArgs(7).WillReturnRows(mismatch(models.SelisihKoreksiDiajukan))
+	mock.ExpectExec("SELECT 1 FROM nasabah WHERE id = \\$1 FOR UPDATE").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("FROM nasabah n").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "no_rekening", "saldo", "checkpoint", "saldo_hitung", "mutasi", "last_tabungan"}).
+			AddRow(3, "1000000001", saldo, 40, 80000, 20000, 42))
+	expect(mock)
+	mock.ExpectExec("UPDATE rekonsiliasi_selisih SET status").WithArgs(models.SelisihDikoreksi, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("FROM rekonsiliasi_selisih s").WithArgs(7).WillReturnRows(mismatch(models.SelisihDikoreksi))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	m, err := Correct(tx, models.CorrectionRequest{SelisihID: 7, Mode: mode, Alasan: "Selisih saldo migrasi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Status != models.SelisihDikoreksi {
+		t.Errorf("status = %s, want %s", m.Status, models.SelisihDikoreksi)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func expectBalanceAdjusted(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("INSERT INTO outbox_events").
+		WithArgs("1000000001", "BalanceAdjusted", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "aggregate_seq"}).AddRow(1, 1))
+}
+
+func TestCorrectSaldoAlignsToHistory(t *testing.T) {
+	for _, saldo := range []float64{150000, 60000} {
+		correct(t, models.KoreksiSaldo, saldo, func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec("UPDATE nasabah SET saldo").WithArgs(100000.0, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+			// Riwayat tidak berubah, jadi checkpoint sampai transaksi terakhir memang berjumlah 100000
+			mock.ExpectExec("INSERT INTO rekonsiliasi_checkpoint").WithArgs(3, 42, 100000.0).WillReturnResult(sqlmock.NewResult(0, 1))
+			expectBalanceAdjusted(mock)
+		})
+	}
+}
+
+func TestCorrectRiwayatKeepsSaldo(t *testing.T) {
+	correct(t, models.KoreksiRiwayat, 150000, func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("INSERT INTO tabungan").
+			WithArgs(3, models.JenisKoreksiKredit, 50000.0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), models.ChannelSistem, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(43))
+	})
+}
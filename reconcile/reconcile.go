@@ -0,0 +1,261 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"math"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// tolerance adalah selisih terkecil yang dianggap nyata; di bawahnya hanya pembulatan
+const tolerance = 0.005
+
+var (
+	ErrAlreadyRunning   = errors.New("rekonsiliasi sedang berjalan")
+	ErrMismatchNotFound = errors.New("selisih rekonsiliasi tidak ditemukan")
+)
+
+// Service membandingkan nasabah.saldo dengan hasil hitung ulang riwayat tabungan
+// setiap rekening. Pemeriksaan bersifat inkremental: setiap rekening menyimpan
+// checkpoint transaksi terakhir yang sudah terbukti cocok, sehingga run berikutnya
+// hanya menjumlahkan transaksi baru. Hanya satu rekonsiliasi berjalan di semua instance.
+type Service struct {
+	DB        *sql.DB
+	BatchSize int
+	Interval  time.Duration
+}
+
+// NewService membuat Service rekonsiliasi
+func NewService(db *sql.DB, batchSize int, interval time.Duration) *Service {
+	return &Service{DB: db, BatchSize: batchSize, Interval: interval}
+}
+
+// Run menjalankan rekonsiliasi setiap Interval sampai ctx dibatalkan
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RunOnce(ctx, "scheduler"); err != nil && !errors.Is(err, ErrAlreadyRunning) && ctx.Err() == nil {
+				log.WithFields(log.Fields{
+					"error": err,
+				}).Error("Scheduled reconciliation failed")
+			}
+		}
+	}
+}
+
+// RunOnce memeriksa semua rekening sekali dan mencatat hasilnya sebagai satu run
+func (s *Service) RunOnce(ctx context.Context, triggeredBy string) (*models.ReconciliationRun, error) {
+	conn, err := s.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	locked, err := repositories.TryLockReconciliation(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrAlreadyRunning
+	}
+	defer repositories.UnlockReconciliation(context.Background(), conn)
+
+	run := &models.ReconciliationRun{TriggeredBy: triggeredBy}
+	if err := repositories.CreateReconciliationRun(s.DB, run); err != nil {
+		return nil, err
+	}
+
+	run.Status = models.ReconciliationCompleted
+	if err := s.check(ctx, run); err != nil {
+		run.Status = models.ReconciliationFailed
+		run.Error = err.Error()
+	}
+	if ferr := repositories.FinishReconciliationRun(s.DB, run); ferr != nil {
+		return nil, ferr
+	}
+
+	fields := log.Fields{
+		"RunID":     run.ID,
+		"trigger":   triggeredBy,
+		"diperiksa": run.Diperiksa,
+		"selisih":   run.Selisih,
+	}
+	if run.Error != "" {
+		fields["error"] = run.Error
+		log.WithFields(fields).Error("Reconciliation failed")
+		return run, errors.New(run.Error)
+	}
+	if run.Selisih > 0 {
+		log.WithFields(fields).Warn("Reconciliation found balance mismatches")
+	} else {
+		log.WithFields(fields).Info("Reconciliation completed")
+	}
+	return run, nil
+}
+
+// Preview memeriksa semua rekening tanpa mencatat run, selisih maupun checkpoint
+func (s *Service) Preview(ctx context.Context) ([]models.ReconciliationMismatch, int, error) {
+	mismatches := []models.ReconciliationMismatch{}
+	diperiksa := 0
+	err := s.eachBatch(ctx, func(ids []int) error {
+		tx, err := s.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		states, err := repositories.GetReconciliationStates(tx, ids)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			diperiksa++
+			if math.Abs(state.Saldo-state.Expected()) < tolerance {
+				continue
+			}
+			m, err := newMismatch(tx, 0, state)
+			if err != nil {
+				return err
+			}
+			mismatches = append(mismatches, *m)
+		}
+		return nil
+	})
+	return mismatches, diperiksa, err
+}
+
+func (s *Service) check(ctx context.Context, run *models.ReconciliationRun) error {
+	return s.eachBatch(ctx, func(ids []int) error {
+		return s.checkBatch(ctx, run, ids)
+	})
+}
+
+func (s *Service) eachBatch(ctx context.Context, fn func(ids []int) error) error {
+	afterID := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ids, err := repositories.ListNasabahIDsAfter(s.DB, afterID, s.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := fn(ids); err != nil {
+			return err
+		}
+		afterID = ids[len(ids)-1]
+
+		if len(ids) < s.BatchSize {
+			return nil
+		}
+	}
+}
+
+// checkBatch memeriksa satu batch rekening di dalam satu transaksi REPEATABLE READ agar
+// saldo dan riwayat dibaca dari snapshot yang sama walaupun transaksi nasabah terus berjalan
+func (s *Service) checkBatch(ctx context.Context, run *models.ReconciliationRun, ids []int) error {
+	tx, err := s.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	states, err := repositories.GetReconciliationStates(tx, ids)
+	if err != nil {
+		return err
+	}
+	for _, state := range states {
+		run.Diperiksa++
+		if math.Abs(state.Saldo-state.Expected()) < tolerance {
+			if err := repositories.SaveReconciliationCheckpoint(tx, state.NasabahID, state.LastTabungan, state.Expected()); err != nil {
+				return err
+			}
+			if err := repositories.ResolveReconciliationMismatches(tx, state.NasabahID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		run.Selisih++
+		if err := recordMismatch(tx, run.ID, state); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// newMismatch menyusun selisih satu rekening beserta transaksi pertama yang berbeda
+func newMismatch(tx *sql.Tx, runID int, state repositories.ReconciliationState) (*models.ReconciliationMismatch, error) {
+	m := &models.ReconciliationMismatch{
+		RunID:         runID,
+		NasabahID:     state.NasabahID,
+		NoRekening:    state.NoRekening,
+		SaldoTercatat: state.Saldo,
+		SaldoHitung:   state.Expected(),
+		Selisih:       state.Saldo - state.Expected(),
+		Status:        models.SelisihOpen,
+	}
+
+	divergence, err := repositories.FindFirstDivergence(tx, state)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if divergence != nil {
+		m.TabunganID = &divergence.TabunganID
+		m.SaldoSetelahTercatat = &divergence.SaldoSetelahTercatat
+		m.SaldoSetelahHitung = &divergence.SaldoSetelahHitung
+	}
+	return m, nil
+}
+
+func recordMismatch(tx *sql.Tx, runID int, state repositories.ReconciliationState) error {
+	m, err := newMismatch(tx, runID, state)
+	if err != nil {
+		return err
+	}
+	if err := repositories.UpsertReconciliationMismatch(tx, m); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"NoRekening":    state.NoRekening,
+		"SaldoTercatat": state.Saldo,
+		"SaldoHitung":   state.Expected(),
+		"TabunganID":    m.TabunganID,
+	}).Warn("Balance mismatch detected")
+	return nil
+}
+
+// Runs mengambil run rekonsiliasi terbaru
+func (s *Service) Runs(limit int) ([]models.ReconciliationRun, error) {
+	return repositories.ListReconciliationRuns(s.DB, limit)
+}
+
+// Mismatches mengambil selisih rekonsiliasi, opsional difilter status
+func (s *Service) Mismatches(status string, limit int) ([]models.ReconciliationMismatch, error) {
+	return repositories.ListReconciliationMismatches(s.DB, status, limit)
+}
+
+// Mismatch mengambil satu selisih rekonsiliasi
+func (s *Service) Mismatch(id int) (*models.ReconciliationMismatch, error) {
+	m, err := repositories.GetReconciliationMismatch(s.DB, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrMismatchNotFound
+	}
+	return m, err
+}
@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// amlLockKey adalah kunci advisory lock agar job AML tidak berjalan bersamaan di beberapa instance
+const amlLockKey = 7302
+
+const amlCaseColumns = "id, nasabah_id, no_rekening, tanggal, tipologi, detail, status, assigned_to, COALESCE(referensi_ppatk, ''), filed_at, export_id, created_at, updated_at"
+
+const ltktReportColumns = "id, nasabah_id, tanggal, arah, no_rekening, nama, nik, total, jumlah_transaksi, export_id, created_at"
+
+// LockAML mengunci job AML sampai transaksi selesai
+func LockAML(tx *sql.Tx) error {
+	_, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", amlLockKey)
+	return err
+}
+
+// ComputeAMLDailyAggregates menghitung ulang total setor dan tarik tunai per nasabah untuk satu tanggal.
+// Koreksi petugas tidak dihitung karena bukan transaksi tunai.
+func ComputeAMLDailyAggregates(tx *sql.Tx, tanggal time.Time) error {
+	if _, err := tx.Exec("DELETE FROM aml_daily_aggregates WHERE tanggal = $1", dateParam(tanggal)); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`INSERT INTO aml_daily_aggregates (nasabah_id, tanggal, no_rekening, total_setor, total_tarik, jumlah_setor, jumlah_tarik)
+		SELECT t.nasabah_id, $1::date, n.no_rekening,
+			COALESCE(SUM(t.nominal) FILTER (WHERE t.jenis_transaksi = 'setor'), 0),
+			COALESCE(SUM(t.nominal) FILTER (WHERE t.jenis_transaksi = 'tarik'), 0),
+			COUNT(*) FILTER (WHERE t.jenis_transaksi = 'setor'),
+			COUNT(*) FILTER (WHERE t.jenis_transaksi = 'tarik')
+		FROM tabungan t
+		JOIN nasabah n ON n.id = t.nasabah_id
+		WHERE t.created_at >= $1::date AND t.created_at < $1::date + 1
+		  AND t.jenis_transaksi IN ('setor', 'tarik')
+		GROUP BY t.nasabah_id, n.no_rekening`, dateParam(tanggal))
+	return err
+}
+
+// ListAMLDailyAggregates mengambil ringkasan harian untuk satu tanggal
+func ListAMLDailyAggregates(executor Executor, tanggal time.Time) ([]models.AMLDailyAggregate, error) {
+	rows, err := executor.Query(`SELECT nasabah_id, tanggal, no_rekening, total_setor, total_tarik, jumlah_setor, jumlah_tarik
+		FROM aml_daily_aggregates WHERE tanggal = $1 ORDER BY nasabah_id`, dateParam(tanggal))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aggregates := []models.AMLDailyAggregate{}
+	for rows.Next() {
+		var a models.AMLDailyAggregate
+		if err := rows.Scan(&a.NasabahID, &a.Tanggal, &a.NoRekening, &a.TotalSetor, &a.TotalTarik, &a.JumlahSetor, &a.JumlahTarik); err != nil {
+			return nil, err
+		}
+		aggregates = append(aggregates, a)
+	}
+	return aggregates, rows.Err()
+}
+
+// UpsertLTKTReports membuat baris LTKT untuk total tunai harian per arah yang mencapai ambang batas
+func UpsertLTKTReports(tx *sql.Tx, tanggal time.Time, threshold float64) error {
+	_, err := tx.Exec(`INSERT INTO aml_ltkt_reports (nasabah_id, tanggal, arah, no_rekening, nama, nik, total, jumlah_transaksi)
+		SELECT a.nasabah_id, a.tanggal, x.arah, a.no_rekening, n.nama, n.nik, x.total, x.jumlah
+		FROM aml_daily_aggregates a
+		JOIN nasabah n ON n.id = a.nasabah_id
+		CROSS JOIN LATERAL (VALUES ('setor', a.total_setor, a.jumlah_setor), ('tarik', a.total_tarik, a.jumlah_tarik)) AS x(arah, total, jumlah)
+		WHERE a.tanggal = $1 AND x.total >= $2
+		ON CONFLICT (nasabah_id, tanggal, arah) DO UPDATE
+			SET total = EXCLUDED.total, jumlah_transaksi = EXCLUDED.jumlah_transaksi`, dateParam(tanggal), threshold)
+	return err
+}
+
+// ListLTKTReports mengambil laporan LTKT untuk satu tanggal
+func ListLTKTReports(executor Executor, tanggal time.Time) ([]models.LTKTReport, error) {
+	rows, err := executor.Query("SELECT "+ltktReportColumns+" FROM aml_ltkt_reports WHERE tanggal = $1 ORDER BY id", dateParam(tanggal))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []models.LTKTReport{}
+	for rows.Next() {
+		var r models.LTKTReport
+		var exportID sql.NullInt64
+		if err := rows.Scan(&r.ID, &r.NasabahID, &r.Tanggal, &r.Arah, &r.NoRekening, &r.Nama, &r.NIK, &r.Total,
+			&r.JumlahTransaksi, &exportID, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		r.ExportID = nullIntPtr(exportID)
+		reports = append(reports, r)
+	}
+	return reports, rows.Err()
+}
+
+// MarkLTKTReportsExported menautkan laporan LTKT satu tanggal ke file ekspor
+func MarkLTKTReportsExported(executor Executor, tanggal time.Time, exportID int) error {
+	_, err := executor.Exec("UPDATE aml_ltkt_reports SET export_id = $1 WHERE tanggal = $2", exportID, dateParam(tanggal))
+	return err
+}
+
+// InsertAMLCase membuka kasus baru. Kasus dengan nasabah, tanggal dan tipologi yang sama
+// tidak dibuat dua kali; created bernilai false jika kasus sudah ada.
+func InsertAMLCase(executor Executor, c *models.AMLCase) (bool, error) {
+	err := executor.QueryRow(`INSERT INTO aml_cases (nasabah_id, no_rekening, tanggal, tipologi, detail)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (nasabah_id, tanggal, tipologi) DO NOTHING
+		RETURNING id, status, created_at, updated_at`,
+		c.NasabahID, c.NoRekening, dateParam(c.Tanggal), c.Tipologi, []byte(c.Detail)).Scan(&c.ID, &c.Status, &c.CreatedAt, &c.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// GetAMLCase mengambil kasus berdasarkan id
+func GetAMLCase(executor Executor, id int) (*models.AMLCase, error) {
+	return scanAMLCase(executor.QueryRow("SELECT "+amlCaseColumns+" FROM aml_cases WHERE id = $1", id))
+}
+
+// GetAMLCaseForUpdate mengambil dan mengunci kasus sampai transaksi selesai
+func GetAMLCaseForUpdate(tx *sql.Tx, id int) (*models.AMLCase, error) {
+	return scanAMLCase(tx.QueryRow("SELECT "+amlCaseColumns+" FROM aml_cases WHERE id = $1 FOR UPDATE", id))
+}
+
+// ListAMLCases mengambil kasus, opsional difilter status dan rekening
+func ListAMLCases(executor Executor, status, noRekening string, limit int) ([]models.AMLCase, error) {
+	rows, err := executor.Query(`SELECT `+amlCaseColumns+` FROM aml_cases
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR no_rekening = $2)
+		ORDER BY id DESC LIMIT $3`, status, noRekening, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectAMLCases(rows)
+}
+
+// ListUnexportedFiledAMLCases mengambil kasus yang sudah dilaporkan tetapi belum masuk file LTKM
+func ListUnexportedFiledAMLCases(executor Executor) ([]models.AMLCase, error) {
+	rows, err := executor.Query(`SELECT ` + amlCaseColumns + ` FROM aml_cases
+		WHERE filed_at IS NOT NULL AND export_id IS NULL ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	return collectAMLCases(rows)
+}
+
+// UpdateAMLCaseStatus menyimpan status baru sebuah kasus
+func UpdateAMLCaseStatus(executor Executor, c *models.AMLCase) error {
+	_, err := executor.Exec(`UPDATE aml_cases
+		SET status = $1, assigned_to = $2, referensi_ppatk = $3, filed_at = $4, updated_at = $5
+		WHERE id = $6`, c.Status, c.AssignedTo, nullString(c.ReferensiPPATK), c.FiledAt, c.UpdatedAt, c.ID)
+	return err
+}
+
+// MarkAMLCasesExported menautkan kasus ke file ekspor LTKM
+func MarkAMLCasesExported(executor Executor, ids []int, exportID int) error {
+	_, err := executor.Exec("UPDATE aml_cases SET export_id = $1 WHERE id = ANY($2)", exportID, pq.Array(ids))
+	return err
+}
+
+// InsertAMLCaseNote menambahkan catatan ke riwayat kasus
+func InsertAMLCaseNote(executor Executor, n *models.AMLCaseNote) error {
+	return executor.QueryRow(`INSERT INTO aml_case_notes (case_id, staff_id, from_status, to_status, catatan)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		n.CaseID, n.StaffID, nullString(n.FromStatus), n.ToStatus, nullString(n.Catatan)).Scan(&n.ID, &n.CreatedAt)
+}
+
+// ListAMLCaseNotes mengambil riwayat catatan sebuah kasus
+func ListAMLCaseNotes(executor Executor, caseID int) ([]models.AMLCaseNote, error) {
+	rows, err := executor.Query(`SELECT id, case_id, staff_id, COALESCE(from_status, ''), to_status, COALESCE(catatan, ''), created_at
+		FROM aml_case_notes WHERE case_id = $1 ORDER BY id`, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []models.AMLCaseNote{}
+	for rows.Next() {
+		var n models.AMLCaseNote
+		var staffID sql.NullInt64
+		if err := rows.Scan(&n.ID, &n.CaseID, &staffID, &n.FromStatus, &n.ToStatus, &n.Catatan, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		n.StaffID = nullIntPtr(staffID)
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
+
+// InsertAMLExport mencatat file laporan yang dihasilkan
+func InsertAMLExport(executor Executor, e *models.AMLExport) error {
+	return executor.QueryRow(`INSERT INTO aml_exports (jenis_laporan, tanggal, format, file_path, jumlah_record)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		e.JenisLaporan, dateParam(e.Tanggal), e.Format, e.FilePath, e.JumlahRecord).Scan(&e.ID, &e.CreatedAt)
+}
+
+// ListAMLExports mengambil file laporan terbaru
+func ListAMLExports(executor Executor, limit int) ([]models.AMLExport, error) {
+	rows, err := executor.Query(`SELECT id, jenis_laporan, tanggal, format, file_path, jumlah_record, created_at
+		FROM aml_exports ORDER BY id DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exports := []models.AMLExport{}
+	for rows.Next() {
+		var e models.AMLExport
+		if err := rows.Scan(&e.ID, &e.JenisLaporan, &e.Tanggal, &e.Format, &e.FilePath, &e.JumlahRecord, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		exports = append(exports, e)
+	}
+	return exports, rows.Err()
+}
+
+// GetAMLRun mengambil status job AML untuk satu tanggal
+func GetAMLRun(executor Executor, tanggal time.Time) (*models.AMLRun, error) {
+	var run models.AMLRun
+	var finishedAt sql.NullTime
+	err := executor.QueryRow("SELECT tanggal, started_at, finished_at, ltkt_count, case_count FROM aml_runs WHERE tanggal = $1", dateParam(tanggal)).
+		Scan(&run.Tanggal, &run.StartedAt, &finishedAt, &run.LTKTCount, &run.CaseCount)
+	if err != nil {
+		return nil, err
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	return &run, nil
+}
+
+// SaveAMLRun menyimpan hasil job AML untuk satu tanggal
+func SaveAMLRun(executor Executor, run *models.AMLRun) error {
+	_, err := executor.Exec(`INSERT INTO aml_runs (tanggal, started_at, finished_at, ltkt_count, case_count)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tanggal) DO UPDATE
+			SET started_at = EXCLUDED.started_at, finished_at = EXCLUDED.finished_at,
+			    ltkt_count = EXCLUDED.ltkt_count, case_count = EXCLUDED.case_count`,
+		dateParam(run.Tanggal), run.StartedAt, run.FinishedAt, run.LTKTCount, run.CaseCount)
+	return err
+}
+
+func scanAMLCase(row rowScanner) (*models.AMLCase, error) {
+	var c models.AMLCase
+	var detail []byte
+	var assignedTo, exportID sql.NullInt64
+	var filedAt sql.NullTime
+	err := row.Scan(&c.ID, &c.NasabahID, &c.NoRekening, &c.Tanggal, &c.Tipologi, &detail, &c.Status, &assignedTo,
+		&c.ReferensiPPATK, &filedAt, &exportID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.Detail = detail
+	c.AssignedTo = nullIntPtr(assignedTo)
+	c.ExportID = nullIntPtr(exportID)
+	if filedAt.Valid {
+		c.FiledAt = &filedAt.Time
+	}
+	return &c, nil
+}
+
+func collectAMLCases(rows *sql.Rows) ([]models.AMLCase, error) {
+	defer rows.Close()
+
+	cases := []models.AMLCase{}
+	for rows.Next() {
+		c, err := scanAMLCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, *c)
+	}
+	return cases, rows.Err()
+}
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
+
+// dateParam mengirim tanggal sebagai teks agar kolom DATE tidak bergeser karena zona waktu sesi
+func dateParam(t time.Time) string {
+	return t.Format("2006-01-02")
+}
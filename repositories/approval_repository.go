@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"time"
+)
+
+const pendingOperationColumns = "id, action, COALESCE(no_rekening, ''), COALESCE(nominal, 0), payload, status, maker_type, maker_id, COALESCE(alasan, ''), checker_id, COALESCE(catatan_checker, ''), COALESCE(error, ''), created_at, expires_at, decided_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// InsertPendingOperation menyimpan operasi baru yang menunggu persetujuan
+func InsertPendingOperation(executor Executor, op *models.PendingOperation) error {
+	query := `INSERT INTO pending_operations (action, no_rekening, nominal, payload, maker_type, maker_id, alasan, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, status, created_at`
+	return executor.QueryRow(query, op.Action, sql.NullString{String: op.NoRekening, Valid: op.NoRekening != ""},
+		op.Nominal, []byte(op.Payload), op.MakerType, op.MakerID, op.Alasan, op.ExpiresAt).
+		Scan(&op.ID, &op.Status, &op.CreatedAt)
+}
+
+// GetPendingOperationForUpdate mengambil operasi dan menguncinya sampai transaksi selesai
+func GetPendingOperationForUpdate(tx *sql.Tx, id int) (*models.PendingOperation, error) {
+	return scanPendingOperation(tx.QueryRow("SELECT "+pendingOperationColumns+" FROM pending_operations WHERE id = $1 FOR UPDATE", id))
+}
+
+// GetPendingOperation mengambil operasi berdasarkan id
+func GetPendingOperation(executor Executor, id int) (*models.PendingOperation, error) {
+	return scanPendingOperation(executor.QueryRow("SELECT "+pendingOperationColumns+" FROM pending_operations WHERE id = $1", id))
+}
+
+// ListPendingOperations mengambil operasi berdasarkan status, terbaru lebih dulu
+func ListPendingOperations(executor Executor, status string, limit int) ([]models.PendingOperation, error) {
+	rows, err := executor.Query("SELECT "+pendingOperationColumns+" FROM pending_operations WHERE ($1 = '' OR status = $1) ORDER BY id DESC LIMIT $2", status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	operations := []models.PendingOperation{}
+	for rows.Next() {
+		op, err := scanPendingOperation(rows)
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, *op)
+	}
+	return operations, rows.Err()
+}
+
+// UpdatePendingOperationDecision menyimpan hasil keputusan checker atau kegagalan eksekusi.
+// Hanya operasi yang masih pending yang diubah agar keputusan tidak saling menimpa.
+func UpdatePendingOperationDecision(executor Executor, op *models.PendingOperation) error {
+	_, err := executor.Exec(`UPDATE pending_operations
+		SET status = $1, checker_id = $2, catatan_checker = $3, error = $4, decided_at = $5
+		WHERE id = $6 AND status = 'pending'`,
+		op.Status, op.CheckerID, sql.NullString{String: op.CatatanChecker, Valid: op.CatatanChecker != ""},
+		sql.NullString{String: op.Error, Valid: op.Error != ""}, op.DecidedAt, op.ID)
+	return err
+}
+
+// ExpirePendingOperations menandai operasi yang melewati batas waktu sebagai kedaluwarsa
+func ExpirePendingOperations(executor Executor, now time.Time) (int64, error) {
+	result, err := executor.Exec("UPDATE pending_operations SET status = 'expired', decided_at = $1 WHERE status = 'pending' AND expires_at <= $1", now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanPendingOperation(row rowScanner) (*models.PendingOperation, error) {
+	var op models.PendingOperation
+	var payload []byte
+	var checkerID sql.NullInt64
+	var decidedAt sql.NullTime
+	err := row.Scan(&op.ID, &op.Action, &op.NoRekening, &op.Nominal, &payload, &op.Status, &op.MakerType, &op.MakerID,
+		&op.Alasan, &checkerID, &op.CatatanChecker, &op.Error, &op.CreatedAt, &op.ExpiresAt, &decidedAt)
+	if err != nil {
+		return nil, err
+	}
+	op.Payload = payload
+	if checkerID.Valid {
+		id := int(checkerID.Int64)
+		op.CheckerID = &id
+	}
+	if decidedAt.Valid {
+		op.DecidedAt = &decidedAt.Time
+	}
+	return &op, nil
+}
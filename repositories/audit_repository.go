@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+)
+
+// auditChainLockKey adalah kunci advisory lock untuk menserialkan penambahan rantai audit
+const auditChainLockKey = 7301
+
+const auditEventColumns = "id, COALESCE(request_id, ''), actor_type, actor_id, action, COALESCE(no_rekening, ''), COALESCE(before_value, ''), COALESCE(after_value, ''), status_code, created_at, prev_hash, hash"
+
+// LockAuditChain mengunci rantai audit sampai transaksi selesai
+func LockAuditChain(tx *sql.Tx) error {
+	_, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", auditChainLockKey)
+	return err
+}
+
+// GetLastAuditHash mengambil hash baris audit terakhir, string kosong jika tabel masih kosong
+func GetLastAuditHash(executor Executor) (string, error) {
+	var hash string
+	err := executor.QueryRow("SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1").Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return hash, err
+}
+
+// InsertAuditEvent menambahkan baris audit baru
+func InsertAuditEvent(executor Executor, e *models.AuditEvent) error {
+	query := `INSERT INTO audit_events (request_id, actor_type, actor_id, action, no_rekening, before_value, after_value, status_code, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	return executor.QueryRow(query, nullString(e.RequestID), e.ActorType, e.ActorID, e.Action, nullString(e.NoRekening),
+		nullString(e.Before), nullString(e.After), e.StatusCode, e.CreatedAt, e.PrevHash, e.Hash).Scan(&e.ID)
+}
+
+// ListAuditEvents mencari log audit berdasarkan rekening dan/atau aktor, urut dari id terkecil
+func ListAuditEvents(executor Executor, f models.AuditFilter) ([]models.AuditEvent, error) {
+	rows, err := executor.Query(`SELECT `+auditEventColumns+` FROM audit_events
+		WHERE ($1 = '' OR no_rekening = $1)
+		  AND ($2 = '' OR actor_type = $2)
+		  AND ($3 = '' OR actor_id = $3)
+		  AND id > $4
+		ORDER BY id LIMIT $5`, f.NoRekening, f.ActorType, f.ActorID, f.AfterID, f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+	for rows.Next() {
+		var e models.AuditEvent
+		if err := rows.Scan(&e.ID, &e.RequestID, &e.ActorType, &e.ActorID, &e.Action, &e.NoRekening, &e.Before, &e.After,
+			&e.StatusCode, &e.CreatedAt, &e.PrevHash, &e.Hash); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
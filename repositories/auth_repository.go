@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"time"
+)
+
+// signingKeyLockKey adalah kunci advisory lock agar hanya satu replika yang merotasi kunci JWT
+const signingKeyLockKey = 7306
+
+// GetNasabahPINHash mengambil id nasabah dan hash PIN berdasarkan nomor rekening
+func GetNasabahPINHash(executor Executor, noRekening string) (int, string, error) {
+	var id int
+	var pinHash sql.NullString
+	err := executor.QueryRow("SELECT id, pin_hash FROM nasabah WHERE no_rekening = $1", noRekening).Scan(&id, &pinHash)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, pinHash.String, nil
+}
+
+// GetNoRekeningByNasabahID mengambil nomor rekening milik nasabah
+func GetNoRekeningByNasabahID(executor Executor, nasabahID int) (string, error) {
+	var noRekening string
+	err := executor.QueryRow("SELECT no_rekening FROM nasabah WHERE id = $1", nasabahID).Scan(&noRekening)
+	return noRekening, err
+}
+
+// CountStaff menghitung jumlah petugas yang terdaftar
+func CountStaff(executor Executor) (int, error) {
+	var total int
+	err := executor.QueryRow("SELECT COUNT(*) FROM staff").Scan(&total)
+	return total, err
+}
+
+// CreateStaff menyimpan petugas baru
+func CreateStaff(executor Executor, staff *models.Staff) error {
+	query := "INSERT INTO staff (username, password_hash, role) VALUES ($1, $2, $3) RETURNING id, aktif, created_at"
+	return executor.QueryRow(query, staff.Username, staff.PasswordHash, staff.Role).Scan(&staff.ID, &staff.Aktif, &staff.CreatedAt)
+}
+
+// GetStaffByUsername mengambil data petugas berdasarkan username
+func GetStaffByUsername(executor Executor, username string) (*models.Staff, error) {
+	return scanStaff(executor.QueryRow("SELECT id, username, password_hash, role, aktif, created_at FROM staff WHERE username = $1", username))
+}
+
+// GetStaffByID mengambil data petugas berdasarkan id
+func GetStaffByID(executor Executor, id int) (*models.Staff, error) {
+	return scanStaff(executor.QueryRow("SELECT id, username, password_hash, role, aktif, created_at FROM staff WHERE id = $1", id))
+}
+
+func scanStaff(row *sql.Row) (*models.Staff, error) {
+	var staff models.Staff
+	err := row.Scan(&staff.ID, &staff.Username, &staff.PasswordHash, &staff.Role, &staff.Aktif, &staff.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &staff, nil
+}
+
+// LockSigningKeys mengunci rotasi kunci penandatangan sampai transaksi selesai
+func LockSigningKeys(tx *sql.Tx) error {
+	_, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", signingKeyLockKey)
+	return err
+}
+
+// InsertSigningKey menyimpan kunci penandatangan JWT baru
+func InsertSigningKey(executor Executor, key *models.SigningKey) error {
+	_, err := executor.Exec("INSERT INTO auth_signing_keys (kid, private_key, created_at, expires_at) VALUES ($1, $2, $3, $4)",
+		key.KID, key.PrivateKey, key.CreatedAt, key.ExpiresAt)
+	return err
+}
+
+// GetSigningKeys mengambil semua kunci yang belum kedaluwarsa, terbaru lebih dulu
+func GetSigningKeys(executor Executor, now time.Time) ([]models.SigningKey, error) {
+	rows, err := executor.Query("SELECT kid, private_key, created_at, expires_at FROM auth_signing_keys WHERE expires_at > $1 ORDER BY created_at DESC", now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []models.SigningKey
+	for rows.Next() {
+		var k models.SigningKey
+		if err := rows.Scan(&k.KID, &k.PrivateKey, &k.CreatedAt, &k.ExpiresAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// DeleteExpiredSigningKeys menghapus kunci yang sudah tidak dipakai untuk verifikasi
+func DeleteExpiredSigningKeys(executor Executor, now time.Time) error {
+	_, err := executor.Exec("DELETE FROM auth_signing_keys WHERE expires_at <= $1", now)
+	return err
+}
+
+// InsertRefreshToken menyimpan hash refresh token baru
+func InsertRefreshToken(executor Executor, token *models.RefreshToken) error {
+	query := "INSERT INTO refresh_tokens (token_hash, family_id, subject_type, subject_id, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	return executor.QueryRow(query, token.TokenHash, token.FamilyID, token.SubjectType, token.SubjectID, token.ExpiresAt).Scan(&token.ID)
+}
+
+// GetRefreshTokenForUpdate mengambil refresh token dan menguncinya sampai transaksi selesai
+func GetRefreshTokenForUpdate(tx *sql.Tx, tokenHash string) (*models.RefreshToken, error) {
+	var t models.RefreshToken
+	var usedAt, revokedAt sql.NullTime
+	err := tx.QueryRow("SELECT id, token_hash, family_id, subject_type, subject_id, expires_at, used_at, revoked_at FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE", tokenHash).
+		Scan(&t.ID, &t.TokenHash, &t.FamilyID, &t.SubjectType, &t.SubjectID, &t.ExpiresAt, &usedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		t.UsedAt = &usedAt.Time
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+	return &t, nil
+}
+
+// MarkRefreshTokenUsed menandai refresh token sudah dirotasi
+func MarkRefreshTokenUsed(executor Executor, id int) error {
+	_, err := executor.Exec("UPDATE refresh_tokens SET used_at = $1 WHERE id = $2", time.Now(), id)
+	return err
+}
+
+// RevokeRefreshTokenFamily mencabut seluruh refresh token dalam satu keluarga rotasi
+func RevokeRefreshTokenFamily(executor Executor, familyID string) error {
+	_, err := executor.Exec("UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL", time.Now(), familyID)
+	return err
+}
+
+// RevokeToken memasukkan jti access token ke daftar pencabutan
+func RevokeToken(executor Executor, jti string, expiresAt time.Time) error {
+	_, err := executor.Exec("INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING", jti, expiresAt)
+	return err
+}
+
+// IsTokenRevoked memeriksa apakah jti access token sudah dicabut
+func IsTokenRevoked(executor Executor, jti string) (bool, error) {
+	var exists bool
+	err := executor.QueryRow("SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)", jti).Scan(&exists)
+	return exists, err
+}
+
+// DeleteExpiredAuthRecords membersihkan daftar pencabutan dan refresh token yang sudah kedaluwarsa
+func DeleteExpiredAuthRecords(executor Executor, now time.Time) error {
+	if _, err := executor.Exec("DELETE FROM revoked_tokens WHERE expires_at <= $1", now); err != nil {
+		return err
+	}
+	_, err := executor.Exec("DELETE FROM refresh_tokens WHERE expires_at <= $1", now)
+	return err
+}
@@ -0,0 +1,314 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// eodLockKey adalah kunci advisory lock sesi yang dipegang selama EOD berjalan.
+// Selama kunci dipegang, transaksi nasabah ditahan atau ditolak.
+const eodLockKey = 7303
+
+// signedNominal menghasilkan nominal bertanda dari baris tabungan t: positif untuk
+// transaksi yang menambah saldo, negatif untuk yang mengurangi (lihat models.IsKredit)
+const signedNominal = "CASE WHEN t.jenis_transaksi IN ('setor', 'koreksi_kredit', 'bunga', 'transfer_masuk') THEN t.nominal ELSE -t.nominal END"
+
+const eodRunColumns = "id, tanggal, status, triggered_by, attempts, COALESCE(error, ''), started_at, finished_at"
+
+// TryLockEOD mencoba mengambil kunci EOD pada koneksi khusus. Kunci dilepas dengan
+// UnlockEOD atau otomatis saat koneksi tertutup, sehingga proses yang mati tidak
+// meninggalkan kunci menggantung.
+func TryLockEOD(ctx context.Context, conn *sql.Conn) (bool, error) {
+	return tryAdvisoryLock(ctx, conn, eodLockKey)
+}
+
+// UnlockEOD melepas kunci EOD yang diambil TryLockEOD
+func UnlockEOD(ctx context.Context, conn *sql.Conn) error {
+	return advisoryUnlock(ctx, conn, eodLockKey)
+}
+
+// IsEODLocked memeriksa apakah ada proses (di instance mana pun) yang sedang memegang kunci EOD
+func IsEODLocked(executor Executor) (bool, error) {
+	var locked bool
+	err := executor.QueryRow(`SELECT EXISTS (SELECT 1 FROM pg_locks
+		WHERE locktype = 'advisory' AND classid = 0 AND objid = $1 AND objsubid = 1 AND granted)`, eodLockKey).Scan(&locked)
+	return locked, err
+}
+
+// GetEODRun mengambil run EOD untuk satu tanggal bisnis
+func GetEODRun(executor Executor, tanggal time.Time) (*models.EODRun, error) {
+	return scanEODRun(executor.QueryRow("SELECT "+eodRunColumns+" FROM eod_runs WHERE tanggal = $1", dateParam(tanggal)))
+}
+
+// GetLastEODRun mengambil run EOD dengan tanggal bisnis terbaru
+func GetLastEODRun(executor Executor) (*models.EODRun, error) {
+	return scanEODRun(executor.QueryRow("SELECT " + eodRunColumns + " FROM eod_runs ORDER BY tanggal DESC LIMIT 1"))
+}
+
+// GetLastClosedDate mengambil tanggal bisnis terakhir yang EOD-nya selesai
+func GetLastClosedDate(executor Executor) (*time.Time, error) {
+	var tanggal sql.NullTime
+	err := executor.QueryRow("SELECT MAX(tanggal) FROM eod_runs WHERE status = 'completed'").Scan(&tanggal)
+	if err != nil || !tanggal.Valid {
+		return nil, err
+	}
+	return &tanggal.Time, nil
+}
+
+// CreateEODRun membuat run EOD baru dengan status running
+func CreateEODRun(executor Executor, run *models.EODRun) error {
+	return executor.QueryRow(`INSERT INTO eod_runs (tanggal, triggered_by) VALUES ($1, $2)
+		RETURNING id, status, attempts, started_at`, dateParam(run.Tanggal), run.TriggeredBy).
+		Scan(&run.ID, &run.Status, &run.Attempts, &run.StartedAt)
+}
+
+// ResumeEODRun menandai run yang gagal atau terputus sebagai berjalan lagi
+func ResumeEODRun(executor Executor, run *models.EODRun) error {
+	return executor.QueryRow(`UPDATE eod_runs SET status = 'running', triggered_by = $1, attempts = attempts + 1,
+			error = NULL, finished_at = NULL
+		WHERE id = $2 RETURNING status, attempts`, run.TriggeredBy, run.ID).Scan(&run.Status, &run.Attempts)
+}
+
+// FinishEODRun menyimpan hasil akhir run EOD
+func FinishEODRun(executor Executor, id int, status, errMsg string) error {
+	_, err := executor.Exec("UPDATE eod_runs SET status = $1, error = $2, finished_at = NOW() WHERE id = $3",
+		status, nullString(errMsg), id)
+	return err
+}
+
+// EnsureEODSteps mendaftarkan step untuk run EOD sesuai urutan eksekusinya. Step yang
+// sudah terdaftar (run yang dilanjutkan) tidak diubah sehingga checkpoint-nya tetap.
+func EnsureEODSteps(executor Executor, runID int, steps []string) error {
+	_, err := executor.Exec(`INSERT INTO eod_steps (run_id, step, urutan)
+		SELECT $1, s.step, s.urutan FROM unnest($2::text[]) WITH ORDINALITY AS s(step, urutan)
+		ON CONFLICT (run_id, step) DO NOTHING`, runID, pq.Array(steps))
+	return err
+}
+
+// ListEODSteps mengambil progres semua step satu run EOD
+func ListEODSteps(executor Executor, runID int) ([]models.EODStep, error) {
+	rows, err := executor.Query(`SELECT step, urutan, status, checkpoint, diproses, COALESCE(error, ''), started_at, finished_at
+		FROM eod_steps WHERE run_id = $1 ORDER BY urutan`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	steps := []models.EODStep{}
+	for rows.Next() {
+		var s models.EODStep
+		var startedAt, finishedAt sql.NullTime
+		if err := rows.Scan(&s.Step, &s.Urutan, &s.Status, &s.Checkpoint, &s.Diproses, &s.Error, &startedAt, &finishedAt); err != nil {
+			return nil, err
+		}
+		if startedAt.Valid {
+			s.StartedAt = &startedAt.Time
+		}
+		if finishedAt.Valid {
+			s.FinishedAt = &finishedAt.Time
+		}
+		steps = append(steps, s)
+	}
+	return steps, rows.Err()
+}
+
+// StartEODStep menandai step sedang berjalan; waktu mulai pertama dipertahankan saat dilanjutkan
+func StartEODStep(executor Executor, runID int, step string) error {
+	_, err := executor.Exec(`UPDATE eod_steps SET status = 'running', error = NULL, started_at = COALESCE(started_at, NOW())
+		WHERE run_id = $1 AND step = $2`, runID, step)
+	return err
+}
+
+// SaveEODCheckpoint menyimpan id nasabah terakhir yang diproses step. Dipanggil di
+// transaksi yang sama dengan perubahan batch sehingga batch tidak pernah diproses dua kali.
+func SaveEODCheckpoint(tx *sql.Tx, runID int, step string, checkpoint, processed int) error {
+	_, err := tx.Exec("UPDATE eod_steps SET checkpoint = $1, diproses = diproses + $2 WHERE run_id = $3 AND step = $4",
+		checkpoint, processed, runID, step)
+	return err
+}
+
+// FinishEODStep menyimpan status akhir step
+func FinishEODStep(executor Executor, runID int, step, status, errMsg string) error {
+	_, err := executor.Exec("UPDATE eod_steps SET status = $1, error = $2, finished_at = NOW() WHERE run_id = $3 AND step = $4",
+		status, nullString(errMsg), runID, step)
+	return err
+}
+
+// ListNasabahIDsAfter mengambil satu batch id nasabah setelah afterID secara berurutan
+func ListNasabahIDsAfter(executor Executor, afterID, limit int) ([]int, error) {
+	var ids []int64
+	err := executor.QueryRow(`SELECT COALESCE(array_agg(id ORDER BY id), '{}')
+		FROM (SELECT id FROM nasabah WHERE id > $1 ORDER BY id LIMIT $2) batch`, afterID, limit).Scan(pq.Array(&ids))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int, len(ids))
+	for i, id := range ids {
+		result[i] = int(id)
+	}
+	return result, nil
+}
+
+// SnapshotSaldoHarian menyimpan saldo penutupan tanggal bisnis untuk satu batch nasabah.
+// Saldo dihitung dari saldo saat ini dikurangi mutasi setelah tanggal tersebut, sehingga
+// transaksi yang masuk setelah tengah malam tidak ikut terhitung.
+func SnapshotSaldoHarian(tx *sql.Tx, tanggal time.Time, ids []int) (int, error) {
+	result, err := tx.Exec(`INSERT INTO saldo_harian (tanggal, nasabah_id, saldo)
+		SELECT $1::date, n.id, n.saldo - COALESCE((
+			SELECT SUM(`+signedNominal+`) FROM tabungan t
+			WHERE t.nasabah_id = n.id AND t.created_at >= $1::date + 1), 0)
+		FROM nasabah n WHERE n.id = ANY($2)
+		ON CONFLICT (tanggal, nasabah_id) DO UPDATE SET saldo = EXCLUDED.saldo`, dateParam(tanggal), pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// GetSaldoHarian mengambil riwayat saldo penutupan satu nasabah dalam rentang tanggal
+func GetSaldoHarian(executor Executor, nasabahID int, dari, sampai time.Time) ([]models.SaldoHarian, error) {
+	rows, err := executor.Query(`SELECT tanggal, nasabah_id, saldo FROM saldo_harian
+		WHERE nasabah_id = $1 AND tanggal BETWEEN $2 AND $3 ORDER BY tanggal`, nasabahID, dateParam(dari), dateParam(sampai))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.SaldoHarian{}
+	for rows.Next() {
+		var s models.SaldoHarian
+		if err := rows.Scan(&s.Tanggal, &s.NasabahID, &s.Saldo); err != nil {
+			return nil, err
+		}
+		history = append(history, s)
+	}
+	return history, rows.Err()
+}
+
+// AccrueBungaHarian mencatat bunga harian (saldo penutupan x suku bunga tahunan / 365)
+// untuk satu batch nasabah bersaldo positif
+func AccrueBungaHarian(tx *sql.Tx, tanggal time.Time, ids []int, sukuBunga float64) (int, error) {
+	result, err := tx.Exec(`INSERT INTO bunga_harian (tanggal, nasabah_id, saldo, suku_bunga, bunga)
+		SELECT s.tanggal, s.nasabah_id, s.saldo, $3, s.saldo * $3 / 365
+		FROM saldo_harian s
+		WHERE s.tanggal = $1 AND s.nasabah_id = ANY($2) AND s.saldo > 0
+		ON CONFLICT (tanggal, nasabah_id) DO NOTHING`, dateParam(tanggal), pq.Array(ids), sukuBunga)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// BungaTerutang adalah akumulasi bunga harian yang belum dikapitalisasi
+type BungaTerutang struct {
+	NasabahID  int
+	NoRekening string
+	Total      float64
+}
+
+// ListUnpostedBunga mengambil total bunga harian yang belum dikapitalisasi sampai tanggal
+// tertentu, dibulatkan ke sen, untuk satu batch nasabah
+func ListUnpostedBunga(tx *sql.Tx, tanggal time.Time, ids []int) ([]BungaTerutang, error) {
+	rows, err := tx.Query(`SELECT b.nasabah_id, n.no_rekening, ROUND(SUM(b.bunga), 2)
+		FROM bunga_harian b JOIN nasabah n ON n.id = b.nasabah_id
+		WHERE b.nasabah_id = ANY($1) AND b.tanggal <= $2 AND b.tabungan_id IS NULL
+		GROUP BY b.nasabah_id, n.no_rekening ORDER BY b.nasabah_id`, pq.Array(ids), dateParam(tanggal))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []BungaTerutang
+	for rows.Next() {
+		var b BungaTerutang
+		if err := rows.Scan(&b.NasabahID, &b.NoRekening, &b.Total); err != nil {
+			return nil, err
+		}
+		result = append(result, b)
+	}
+	return result, rows.Err()
+}
+
+// MarkBungaPosted menautkan bunga harian yang sudah dikapitalisasi ke transaksi bunganya
+func MarkBungaPosted(tx *sql.Tx, nasabahID int, tanggal time.Time, tabunganID int) error {
+	_, err := tx.Exec("UPDATE bunga_harian SET tabungan_id = $1 WHERE nasabah_id = $2 AND tanggal <= $3 AND tabungan_id IS NULL",
+		tabunganID, nasabahID, dateParam(tanggal))
+	return err
+}
+
+// ListNasabahByIDs mengambil data rekening untuk satu batch nasabah
+func ListNasabahByIDs(tx *sql.Tx, ids []int) ([]models.Nasabah, error) {
+	rows, err := tx.Query("SELECT id, nik, nama, no_hp, no_rekening, saldo, status FROM nasabah WHERE id = ANY($1) ORDER BY id", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.Nasabah
+	for rows.Next() {
+		var n models.Nasabah
+		if err := rows.Scan(&n.ID, &n.NIK, &n.Nama, &n.NoHP, &n.NoRekening, &n.Saldo, &n.Status); err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+	return result, rows.Err()
+}
+
+// UpdateDormansi menandai rekening tanpa setor, tarik atau transfer sejak batas sebagai dorman mulai
+// tanggal bisnis, dan menghapus tanda dorman dari rekening yang kembali aktif.
+// Bunga, biaya dan koreksi petugas tidak dihitung sebagai aktivitas nasabah.
+func UpdateDormansi(tx *sql.Tx, tanggal, batas time.Time, ids []int) (int, error) {
+	marked, err := tx.Exec(`UPDATE nasabah n SET dorman_sejak = $1
+		WHERE n.id = ANY($2) AND n.dorman_sejak IS NULL AND n.created_at < $3::date
+		  AND NOT EXISTS (SELECT 1 FROM tabungan t WHERE t.nasabah_id = n.id
+			AND t.jenis_transaksi IN ('setor', 'tarik', 'transfer_keluar', 'transfer_masuk') AND t.created_at >= $3::date)`,
+		dateParam(tanggal), pq.Array(ids), dateParam(batas))
+	if err != nil {
+		return 0, err
+	}
+	cleared, err := tx.Exec(`UPDATE nasabah n SET dorman_sejak = NULL
+		WHERE n.id = ANY($1) AND n.dorman_sejak IS NOT NULL
+		  AND EXISTS (SELECT 1 FROM tabungan t WHERE t.nasabah_id = n.id
+			AND t.jenis_transaksi IN ('setor', 'tarik', 'transfer_keluar', 'transfer_masuk') AND t.created_at >= n.dorman_sejak)`, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+
+	m, err := marked.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	c, err := cleared.RowsAffected()
+	return int(m + c), err
+}
+
+func tryAdvisoryLock(ctx context.Context, conn *sql.Conn, key int64) (bool, error) {
+	var locked bool
+	err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked)
+	return locked, err
+}
+
+func advisoryUnlock(ctx context.Context, conn *sql.Conn, key int64) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key)
+	return err
+}
+
+func scanEODRun(row rowScanner) (*models.EODRun, error) {
+	var run models.EODRun
+	var finishedAt sql.NullTime
+	err := row.Scan(&run.ID, &run.Tanggal, &run.Status, &run.TriggeredBy, &run.Attempts, &run.Error, &run.StartedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	return &run, nil
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const fraudDecisionColumns = "id, no_rekening, jenis_transaksi, nominal, channel, actor_type, actor_id, outcome, rules, rules_version, step_up_passed, COALESCE(review_status, ''), reviewed_by, reviewed_at, COALESCE(review_note, ''), created_at"
+
+// GetNasabahCreatedAt mengambil waktu pembukaan rekening
+func GetNasabahCreatedAt(executor Executor, nasabahID int) (time.Time, error) {
+	var createdAt time.Time
+	err := executor.QueryRow("SELECT created_at FROM nasabah WHERE id = $1", nasabahID).Scan(&createdAt)
+	return createdAt, err
+}
+
+// CountTabunganSince menghitung transaksi dengan jenis tertentu sejak waktu tertentu
+func CountTabunganSince(executor Executor, nasabahID int, jenis []string, since time.Time) (int, error) {
+	var count int
+	err := executor.QueryRow(`SELECT COUNT(*) FROM tabungan
+		WHERE nasabah_id = $1 AND jenis_transaksi = ANY($2) AND created_at >= $3`,
+		nasabahID, pq.Array(jenis), since).Scan(&count)
+	return count, err
+}
+
+// CountRoundTabunganSince menghitung transaksi bernominal bulat (kelipatan roundTo) sejak waktu tertentu
+func CountRoundTabunganSince(executor Executor, nasabahID int, jenis []string, since time.Time, roundTo, minNominal float64) (int, error) {
+	var count int
+	err := executor.QueryRow(`SELECT COUNT(*) FROM tabungan
+		WHERE nasabah_id = $1 AND jenis_transaksi = ANY($2) AND created_at >= $3
+		  AND nominal >= $5 AND MOD(nominal, $4) = 0`,
+		nasabahID, pq.Array(jenis), since, roundTo, minNominal).Scan(&count)
+	return count, err
+}
+
+// GetTabunganNominalStats mengambil jumlah dan rata-rata nominal transaksi sejak waktu tertentu
+func GetTabunganNominalStats(executor Executor, nasabahID int, jenis []string, since time.Time) (int, float64, error) {
+	var count int
+	var avg float64
+	err := executor.QueryRow(`SELECT COUNT(*), COALESCE(AVG(nominal), 0) FROM tabungan
+		WHERE nasabah_id = $1 AND jenis_transaksi = ANY($2) AND created_at >= $3`,
+		nasabahID, pq.Array(jenis), since).Scan(&count, &avg)
+	return count, avg, err
+}
+
+// InsertFraudDecision menyimpan satu keputusan mesin aturan fraud
+func InsertFraudDecision(executor Executor, d *models.FraudDecision) error {
+	query := `INSERT INTO fraud_decisions (no_rekening, jenis_transaksi, nominal, channel, actor_type, actor_id, outcome, rules, rules_version, step_up_passed, review_status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at`
+	return executor.QueryRow(query, d.NoRekening, d.JenisTransaksi, d.Nominal, d.Channel, d.ActorType, d.ActorID, d.Outcome,
+		[]byte(d.Rules), d.RulesVersion, d.StepUpPassed, nullString(d.ReviewStatus)).Scan(&d.ID, &d.CreatedAt)
+}
+
+// GetFraudDecision mengambil keputusan fraud berdasarkan id
+func GetFraudDecision(executor Executor, id int64) (*models.FraudDecision, error) {
+	return scanFraudDecision(executor.QueryRow("SELECT "+fraudDecisionColumns+" FROM fraud_decisions WHERE id = $1", id))
+}
+
+// ListFraudDecisions mengambil keputusan fraud yang ditandai, urut dari id terkecil
+func ListFraudDecisions(executor Executor, f models.FraudDecisionFilter) ([]models.FraudDecision, error) {
+	rows, err := executor.Query(`SELECT `+fraudDecisionColumns+` FROM fraud_decisions
+		WHERE review_status IS NOT NULL
+		  AND ($1 = '' OR review_status = $1)
+		  AND ($2 = '' OR no_rekening = $2)
+		  AND id > $3
+		ORDER BY id LIMIT $4`, f.ReviewStatus, f.NoRekening, f.AfterID, f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	decisions := []models.FraudDecision{}
+	for rows.Next() {
+		d, err := scanFraudDecision(rows)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, *d)
+	}
+	return decisions, rows.Err()
+}
+
+// UpdateFraudReview menyimpan hasil review analis; hanya keputusan yang masih open yang bisa direview
+func UpdateFraudReview(executor Executor, id int64, status string, reviewerID int, note string, reviewedAt time.Time) (bool, error) {
+	result, err := executor.Exec(`UPDATE fraud_decisions
+		SET review_status = $1, reviewed_by = $2, review_note = $3, reviewed_at = $4
+		WHERE id = $5 AND review_status = 'open'`, status, reviewerID, nullString(note), reviewedAt, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func scanFraudDecision(row rowScanner) (*models.FraudDecision, error) {
+	var d models.FraudDecision
+	var rules []byte
+	var reviewedBy sql.NullInt64
+	var reviewedAt sql.NullTime
+	err := row.Scan(&d.ID, &d.NoRekening, &d.JenisTransaksi, &d.Nominal, &d.Channel, &d.ActorType, &d.ActorID, &d.Outcome,
+		&rules, &d.RulesVersion, &d.StepUpPassed, &d.ReviewStatus, &reviewedBy, &reviewedAt, &d.ReviewNote, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	d.Rules = rules
+	if reviewedBy.Valid {
+		id := int(reviewedBy.Int64)
+		d.ReviewedBy = &id
+	}
+	if reviewedAt.Valid {
+		d.ReviewedAt = &reviewedAt.Time
+	}
+	return &d, nil
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const glVoucherColumns = "id, tanggal, nomor, jenis_transaksi, channel, akun_debit, akun_kredit, jumlah_transaksi, total, mapping_version"
+
+// GLMovement adalah total transaksi satu jenis dan channel dalam satu hari
+type GLMovement struct {
+	Jenis   string
+	Channel string
+	Jumlah  int
+	Total   float64
+}
+
+// AggregateGLMovements menjumlahkan transaksi nasabah dalam ids per jenis dan channel
+// untuk satu tanggal. Transaksi tanpa channel dikelompokkan dengan channel kosong.
+func AggregateGLMovements(tx *sql.Tx, tanggal time.Time, ids []int) ([]GLMovement, error) {
+	rows, err := tx.Query(`SELECT t.jenis_transaksi, COALESCE(t.channel, ''), COUNT(*), SUM(t.nominal)
+		FROM tabungan t
+		WHERE t.nasabah_id = ANY($2) AND t.created_at >= $1::date AND t.created_at < $1::date + 1
+		GROUP BY 1, 2 ORDER BY 1, 2`, dateParam(tanggal), pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []GLMovement
+	for rows.Next() {
+		var m GLMovement
+		if err := rows.Scan(&m.Jenis, &m.Channel, &m.Jumlah, &m.Total); err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+// SumSaldoAwal menghitung saldo awal hari (saldo penutupan dikurangi mutasi hari itu)
+// untuk nasabah dalam ids. Dipakai untuk voucher saldo awal pada hari pertama jurnal GL.
+func SumSaldoAwal(tx *sql.Tx, tanggal time.Time, ids []int) (int, float64, error) {
+	var jumlah int
+	var total float64
+	err := tx.QueryRow(`SELECT COUNT(*), COALESCE(SUM(s.saldo - COALESCE((
+			SELECT SUM(`+signedNominal+`) FROM tabungan t
+			WHERE t.nasabah_id = s.nasabah_id AND t.created_at >= $1::date AND t.created_at < $1::date + 1), 0)), 0)
+		FROM saldo_harian s WHERE s.tanggal = $1 AND s.nasabah_id = ANY($2)`, dateParam(tanggal), pq.Array(ids)).Scan(&jumlah, &total)
+	return jumlah, total, err
+}
+
+// HasGLVouchersBefore mengembalikan true jika sudah ada voucher sebelum tanggal
+func HasGLVouchersBefore(executor Executor, tanggal time.Time) (bool, error) {
+	var exists bool
+	err := executor.QueryRow("SELECT EXISTS (SELECT 1 FROM gl_vouchers WHERE tanggal < $1)", dateParam(tanggal)).Scan(&exists)
+	return exists, err
+}
+
+// AddGLVoucher menambahkan total batch ke voucher harian jenis dan channel yang sama,
+// atau membuat voucher baru jika belum ada
+func AddGLVoucher(tx *sql.Tx, v *models.GLVoucher) error {
+	_, err := tx.Exec(`INSERT INTO gl_vouchers
+			(tanggal, nomor, jenis_transaksi, channel, akun_debit, akun_kredit, jumlah_transaksi, total, mapping_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (tanggal, jenis_transaksi, channel) DO UPDATE
+			SET jumlah_transaksi = gl_vouchers.jumlah_transaksi + EXCLUDED.jumlah_transaksi,
+			    total = gl_vouchers.total + EXCLUDED.total,
+			    akun_debit = EXCLUDED.akun_debit, akun_kredit = EXCLUDED.akun_kredit,
+			    mapping_version = EXCLUDED.mapping_version, updated_at = NOW()`,
+		dateParam(v.Tanggal), v.Nomor, v.JenisTransaksi, v.Channel, v.AkunDebit, v.AkunKredit, v.JumlahTransaksi, v.Total, v.MappingVersion)
+	return err
+}
+
+// ListGLVouchers mengambil voucher jurnal satu tanggal
+func ListGLVouchers(executor Executor, tanggal time.Time) ([]models.GLVoucher, error) {
+	rows, err := executor.Query("SELECT "+glVoucherColumns+" FROM gl_vouchers WHERE tanggal = $1 ORDER BY nomor", dateParam(tanggal))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vouchers := []models.GLVoucher{}
+	for rows.Next() {
+		var v models.GLVoucher
+		if err := rows.Scan(&v.ID, &v.Tanggal, &v.Nomor, &v.JenisTransaksi, &v.Channel, &v.AkunDebit, &v.AkunKredit,
+			&v.JumlahTransaksi, &v.Total, &v.MappingVersion); err != nil {
+			return nil, err
+		}
+		vouchers = append(vouchers, v)
+	}
+	return vouchers, rows.Err()
+}
+
+// GLAccountTotal adalah jumlah debit dan kredit kumulatif satu akun
+type GLAccountTotal struct {
+	Akun   string
+	Debit  float64
+	Kredit float64
+}
+
+// SumGLByAccount menjumlahkan debit dan kredit setiap akun dari semua voucher sampai tanggal
+func SumGLByAccount(executor Executor, sampai time.Time) ([]GLAccountTotal, error) {
+	rows, err := executor.Query(`SELECT akun, SUM(debit), SUM(kredit) FROM (
+			SELECT akun_debit AS akun, total AS debit, 0 AS kredit FROM gl_vouchers WHERE tanggal <= $1
+			UNION ALL
+			SELECT akun_kredit, 0, total FROM gl_vouchers WHERE tanggal <= $1
+		) x GROUP BY akun ORDER BY akun`, dateParam(sampai))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []GLAccountTotal
+	for rows.Next() {
+		var t GLAccountTotal
+		if err := rows.Scan(&t.Akun, &t.Debit, &t.Kredit); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+// SumSaldoNasabahAsOf menghitung jumlah nasabah.saldo pada akhir tanggal, yaitu saldo
+// saat ini dikurangi semua mutasi setelah tanggal tersebut
+func SumSaldoNasabahAsOf(executor Executor, tanggal time.Time) (float64, error) {
+	var total float64
+	err := executor.QueryRow(`SELECT
+			COALESCE((SELECT SUM(saldo) FROM nasabah), 0) -
+			COALESCE((SELECT SUM(`+signedNominal+`) FROM tabungan t WHERE t.created_at >= $1::date + 1), 0)`,
+		dateParam(tanggal)).Scan(&total)
+	return total, err
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"golang-echo-postgresql/models"
 	"time"
@@ -9,6 +10,9 @@ import (
 	_ "github.com/lib/pq" // Import driver PostgreSQL
 )
 
+// ErrSaldoTidakCukup dikembalikan UpdateSaldo jika saldo tidak cukup untuk transaksi debit
+var ErrSaldoTidakCukup = errors.New("saldo tidak mencukupi")
+
 type Executor interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 	Query(query string, args ...interface{}) (*sql.Rows, error)
@@ -16,7 +20,7 @@ type Executor interface {
 }
 
 // Fungsi untuk memeriksa apakah NIK atau No HP sudah ada di database
-func CheckExistingNasabah(db *sql.DB, nik, noHP string) (bool, []string, error) {
+func CheckExistingNasabah(executor Executor, nik, noHP string) (bool, []string, error) {
 	var existingFields []string
 
 	query := `
@@ -26,7 +30,7 @@ func CheckExistingNasabah(db *sql.DB, nik, noHP string) (bool, []string, error)
 	`
 
 	var nikExists, noHPExists sql.NullString
-	err := db.QueryRow(query, nik, noHP).Scan(&nikExists, &noHPExists)
+	err := executor.QueryRow(query, nik, noHP).Scan(&nikExists, &noHPExists)
 	if err != nil {
 		return false, nil, err
 	}
@@ -42,46 +46,148 @@ func CheckExistingNasabah(db *sql.DB, nik, noHP string) (bool, []string, error)
 }
 
 // Fungsi untuk membuat data nasabah baru
-func CreateNasabah(db *sql.DB, nasabah *models.Nasabah) error {
-	query := "INSERT INTO nasabah (nik,nama, no_hp, no_rekening) VALUES ($1, $2, $3, $4) RETURNING id"
-	err := db.QueryRow(query, nasabah.NIK, nasabah.Nama, nasabah.NoHP, nasabah.NoRekening).Scan(&nasabah.ID)
+func CreateNasabah(executor Executor, nasabah *models.Nasabah, pinHash string) error {
+	query := "INSERT INTO nasabah (nik,nama, no_hp, no_rekening, pin_hash) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	err := executor.QueryRow(query, nasabah.NIK, nasabah.Nama, nasabah.NoHP, nasabah.NoRekening, sql.NullString{String: pinHash, Valid: pinHash != ""}).Scan(&nasabah.ID)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-// InsertTabungan inserts a new transaction record in the tabungan table
-func InsertTabungan(executor Executor, nasabahID int, jenisTransaksi string, nominal float64) error {
-	_, err := executor.Exec("INSERT INTO tabungan (nasabah_id, jenis_transaksi, nominal, created_at) VALUES ($1, $2, $3, $4)", nasabahID, jenisTransaksi, nominal, time.Now())
-	return err
+// InsertTabunganDetail mencatat transaksi beserta keterangan, referensi transaksi asal,
+// referensi channel dan saldo setelah transaksi
+func InsertTabunganDetail(executor Executor, t *models.Tabungan) error {
+	t.CreatedAt = time.Now()
+	query := `INSERT INTO tabungan (nasabah_id, jenis_transaksi, nominal, keterangan, ref_tabungan_id, created_at, saldo_setelah, channel, referensi)
+		VALUES ($1, $2, $3, $4, $5, $6, (SELECT saldo FROM nasabah WHERE id = $1), $7, $8) RETURNING id`
+	return executor.QueryRow(query, t.NasabahID, t.JenisTransaksi, t.Nominal, sql.NullString{String: t.Keterangan, Valid: t.Keterangan != ""}, t.RefTabunganID, t.CreatedAt, nullString(t.Channel), nullString(t.Referensi)).Scan(&t.ID)
+}
+
+// GetTabunganByID mengambil satu transaksi berdasarkan id
+func GetTabunganByID(executor Executor, id int) (*models.Tabungan, error) {
+	var t models.Tabungan
+	var keterangan sql.NullString
+	var ref sql.NullInt64
+	err := executor.QueryRow("SELECT id, nasabah_id, jenis_transaksi, nominal, keterangan, ref_tabungan_id, created_at FROM tabungan WHERE id = $1", id).
+		Scan(&t.ID, &t.NasabahID, &t.JenisTransaksi, &t.Nominal, &keterangan, &ref, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	t.Keterangan = keterangan.String
+	if ref.Valid {
+		refID := int(ref.Int64)
+		t.RefTabunganID = &refID
+	}
+	return &t, nil
+}
+
+// IsTabunganReversed memeriksa apakah transaksi sudah pernah dibatalkan
+func IsTabunganReversed(executor Executor, id int) (bool, error) {
+	var exists bool
+	err := executor.QueryRow("SELECT EXISTS (SELECT 1 FROM tabungan WHERE ref_tabungan_id = $1)", id).Scan(&exists)
+	return exists, err
+}
+
+// UpdateStatusRekening mengubah status rekening (aktif atau beku)
+func UpdateStatusRekening(executor Executor, noRekening, status string) error {
+	result, err := executor.Exec("UPDATE nasabah SET status = $1 WHERE no_rekening = $2", status, noRekening)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
 func GetNasabahByNoRekening(executor Executor, noRekening string) (*models.Nasabah, error) {
+	return getNasabahBy(executor, "no_rekening", noRekening)
+}
+
+// GetNasabahByNIK mengambil nasabah berdasarkan NIK
+func GetNasabahByNIK(executor Executor, nik string) (*models.Nasabah, error) {
+	return getNasabahBy(executor, "nik", nik)
+}
+
+// GetNasabahByNoHP mengambil nasabah berdasarkan nomor HP
+func GetNasabahByNoHP(executor Executor, noHP string) (*models.Nasabah, error) {
+	return getNasabahBy(executor, "no_hp", noHP)
+}
+
+// GetNasabahByNoRekeningForUpdate mengambil nasabah dan mengunci barisnya sampai transaksi selesai
+func GetNasabahByNoRekeningForUpdate(tx Executor, noRekening string) (*models.Nasabah, error) {
+	return scanNasabah(tx.QueryRow(selectNasabah+" WHERE no_rekening = $1 FOR UPDATE", noRekening))
+}
+
+const selectNasabah = "SELECT id, nik, nama, no_hp, no_rekening, saldo, status FROM nasabah"
+
+// getNasabahBy mengambil nasabah berdasarkan kolom unik; column selalu konstanta dari pemanggil
+func getNasabahBy(executor Executor, column, value string) (*models.Nasabah, error) {
+	return scanNasabah(executor.QueryRow(selectNasabah+" WHERE "+column+" = $1", value))
+}
+
+func scanNasabah(row *sql.Row) (*models.Nasabah, error) {
 	var nasabah models.Nasabah
-	err := executor.QueryRow("SELECT id, nik, no_hp, no_rekening, saldo FROM nasabah WHERE no_rekening = $1", noRekening).
-		Scan(&nasabah.ID, &nasabah.NIK, &nasabah.NoHP, &nasabah.NoRekening, &nasabah.Saldo)
+	err := row.Scan(&nasabah.ID, &nasabah.NIK, &nasabah.Nama, &nasabah.NoHP, &nasabah.NoRekening, &nasabah.Saldo, &nasabah.Status)
 	if err != nil {
 		return nil, err
 	}
 	return &nasabah, nil
 }
-func UpdateSaldo(tx *sql.Tx, noRekening string, jenisTransaksi string, nominal float64) error {
+
+// GetSaldoAsOf menghitung saldo rekening pada waktu t, yaitu saldo saat ini dikurangi
+// semua mutasi sejak t
+func GetSaldoAsOf(executor Executor, nasabahID int, t time.Time) (float64, error) {
+	var saldo float64
+	err := executor.QueryRow(`SELECT n.saldo - COALESCE((
+			SELECT SUM(`+signedNominal+`) FROM tabungan t WHERE t.nasabah_id = n.id AND t.created_at >= $2), 0)
+		FROM nasabah n WHERE n.id = $1`, nasabahID, t).Scan(&saldo)
+	return saldo, err
+}
+
+// ListTabunganBetween mengambil transaksi rekening dalam rentang [dari, sampai) berurutan
+func ListTabunganBetween(executor Executor, nasabahID int, dari, sampai time.Time) ([]models.Tabungan, error) {
+	rows, err := executor.Query(`SELECT id, nasabah_id, jenis_transaksi, nominal, COALESCE(keterangan, ''), ref_tabungan_id,
+			COALESCE(channel, ''), created_at
+		FROM tabungan WHERE nasabah_id = $1 AND created_at >= $2 AND created_at < $3 ORDER BY id`, nasabahID, dari, sampai)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	riwayat := []models.Tabungan{}
+	for rows.Next() {
+		var t models.Tabungan
+		var ref sql.NullInt64
+		if err := rows.Scan(&t.ID, &t.NasabahID, &t.JenisTransaksi, &t.Nominal, &t.Keterangan, &ref, &t.Channel, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		t.RefTabunganID = nullIntPtr(ref)
+		riwayat = append(riwayat, t)
+	}
+	return riwayat, rows.Err()
+}
+func UpdateSaldo(tx Executor, noRekening string, jenisTransaksi string, nominal float64) error {
 	var saldoSaatIni float64
 	// Mengunci saldo untuk menghindari race condition
 	err := tx.QueryRow("SELECT saldo FROM nasabah WHERE no_rekening = $1 FOR UPDATE", noRekening).Scan(&saldoSaatIni)
 	if err != nil {
-		return fmt.Errorf("gagal mendapatkan saldo: %v", err)
+		return fmt.Errorf("gagal mendapatkan saldo: %w", err)
 	}
 
-	// Validasi jika transaksi adalah penarikan
-	if jenisTransaksi == "tarik" && saldoSaatIni < nominal {
-		return fmt.Errorf("saldo tidak mencukupi")
+	// Validasi jika transaksi mengurangi saldo
+	if !models.IsKredit(jenisTransaksi) && saldoSaatIni < nominal {
+		return ErrSaldoTidakCukup
 	}
 
 	// Hitung saldo baru
 	var saldoBaru float64
-	if jenisTransaksi == "setor" {
+	if models.IsKredit(jenisTransaksi) {
 		saldoBaru = saldoSaatIni + nominal
 	} else {
 		saldoBaru = saldoSaatIni - nominal
@@ -107,7 +213,9 @@ func GetSaldo(executor Executor, noRekening string) (float64, error) {
 
 func GetRiwayatTransaksi(executor Executor, nasabahID int) ([]models.Tabungan, error) {
 	var riwayat []models.Tabungan
-	rows, err := executor.Query("SELECT id, nasabah_id, jenis_transaksi, nominal, created_at FROM tabungan WHERE nasabah_id = $1", nasabahID)
+	rows, err := executor.Query(`SELECT id, nasabah_id, jenis_transaksi, nominal, COALESCE(keterangan, ''), ref_tabungan_id,
+			COALESCE(channel, ''), COALESCE(referensi, ''), created_at
+		FROM tabungan WHERE nasabah_id = $1`, nasabahID)
 	if err != nil {
 		return nil, err
 	}
@@ -115,11 +223,23 @@ func GetRiwayatTransaksi(executor Executor, nasabahID int) ([]models.Tabungan, e
 
 	for rows.Next() {
 		var t models.Tabungan
-		if err := rows.Scan(&t.ID, &t.NasabahID, &t.JenisTransaksi, &t.Nominal, &t.CreatedAt); err != nil {
+		var ref sql.NullInt64
+		if err := rows.Scan(&t.ID, &t.NasabahID, &t.JenisTransaksi, &t.Nominal, &t.Keterangan, &ref, &t.Channel, &t.Referensi, &t.CreatedAt); err != nil {
 			return nil, err
 		}
+		if ref.Valid {
+			refID := int(ref.Int64)
+			t.RefTabunganID = &refID
+		}
 		riwayat = append(riwayat, t)
 	}
 
 	return riwayat, nil
 }
+
+// LockNasabah mengunci baris nasabah sampai transaksi selesai, sehingga pemeriksaan
+// berbasis riwayat (misalnya aturan fraud) tidak balapan dengan transaksi lain
+func LockNasabah(tx *sql.Tx, nasabahID int) error {
+	_, err := tx.Exec("SELECT 1 FROM nasabah WHERE id = $1 FOR UPDATE", nasabahID)
+	return err
+}
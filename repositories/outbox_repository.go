@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"time"
+)
+
+// InsertOutboxEvent menulis event ke outbox dengan nomor urut berikutnya untuk rekening
+// yang sama. Harus dipanggil di dalam transaksi yang sudah mengunci baris rekening
+// (misalnya lewat UpdateSaldo) agar nomor urut tidak bentrok.
+func InsertOutboxEvent(executor Executor, e *models.OutboxEvent) error {
+	query := `INSERT INTO outbox_events (aggregate_id, aggregate_seq, event_type, event_version, payload, created_at, trace_parent)
+		VALUES ($1, (SELECT COALESCE(MAX(aggregate_seq), 0) + 1 FROM outbox_events WHERE aggregate_id = $1), $2, $3, $4, $5, $6)
+		RETURNING id, aggregate_seq`
+	e.CreatedAt = time.Now()
+	return executor.QueryRow(query, e.AggregateID, e.Type, e.Version, []byte(e.Payload), e.CreatedAt, nullString(e.TraceParent)).Scan(&e.ID, &e.Sequence)
+}
+
+// ClaimOutboxEvents mengambil event terdepan yang belum terkirim untuk setiap rekening
+// dan menguncinya. Event yang masih punya pendahulu belum terkirim tidak diambil
+// sehingga urutan per rekening tetap terjaga walaupun ada beberapa relay.
+func ClaimOutboxEvents(tx *sql.Tx, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	rows, err := tx.Query(`SELECT o.id, o.aggregate_id, o.aggregate_seq, o.event_type, o.event_version, o.payload, o.created_at, o.attempts, COALESCE(o.trace_parent, '')
+		FROM outbox_events o
+		WHERE o.published_at IS NULL AND o.next_attempt_at <= $1
+		  AND NOT EXISTS (
+		      SELECT 1 FROM outbox_events p
+		      WHERE p.aggregate_id = o.aggregate_id AND p.published_at IS NULL AND p.aggregate_seq < o.aggregate_seq
+		  )
+		ORDER BY o.id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var e models.OutboxEvent
+		var payload []byte
+		if err := rows.Scan(&e.ID, &e.AggregateID, &e.Sequence, &e.Type, &e.Version, &payload, &e.CreatedAt, &e.Attempts, &e.TraceParent); err != nil {
+			return nil, err
+		}
+		e.Payload = payload
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// MarkOutboxEventPublished menandai event sudah terkirim
+func MarkOutboxEventPublished(executor Executor, id int64, now time.Time) error {
+	_, err := executor.Exec("UPDATE outbox_events SET published_at = $1, attempts = attempts + 1, last_error = NULL WHERE id = $2", now, id)
+	return err
+}
+
+// MarkOutboxEventFailed mencatat kegagalan pengiriman dan menjadwalkan percobaan berikutnya
+func MarkOutboxEventFailed(executor Executor, id int64, nextAttempt time.Time, lastError string) error {
+	_, err := executor.Exec("UPDATE outbox_events SET attempts = attempts + 1, next_attempt_at = $1, last_error = $2 WHERE id = $3", nextAttempt, lastError, id)
+	return err
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CreatePartner menyimpan partner baru
+func CreatePartner(executor Executor, partner *models.Partner) error {
+	query := "INSERT INTO partners (client_id, nama, allowed_endpoints) VALUES ($1, $2, $3) RETURNING id, aktif, created_at"
+	return executor.QueryRow(query, partner.ClientID, partner.Nama, pq.Array(partner.AllowedEndpoints)).
+		Scan(&partner.ID, &partner.Aktif, &partner.CreatedAt)
+}
+
+// GetPartnerByClientID mengambil partner berdasarkan client id
+func GetPartnerByClientID(executor Executor, clientID string) (*models.Partner, error) {
+	var partner models.Partner
+	err := executor.QueryRow("SELECT id, client_id, nama, allowed_endpoints, aktif, created_at FROM partners WHERE client_id = $1", clientID).
+		Scan(&partner.ID, &partner.ClientID, &partner.Nama, pq.Array(&partner.AllowedEndpoints), &partner.Aktif, &partner.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &partner, nil
+}
+
+// InsertPartnerSecret menyimpan versi secret baru milik partner
+func InsertPartnerSecret(executor Executor, secret *models.PartnerSecret) error {
+	query := "INSERT INTO partner_secrets (partner_id, secret_enc, valid_from, valid_until) VALUES ($1, $2, $3, $4) RETURNING id"
+	return executor.QueryRow(query, secret.PartnerID, secret.SecretEnc, secret.ValidFrom, secret.ValidUntil).Scan(&secret.ID)
+}
+
+// ExpirePartnerSecrets membatasi masa berlaku semua secret partner yang masih aktif sampai validUntil
+func ExpirePartnerSecrets(executor Executor, partnerID int, validUntil time.Time) error {
+	_, err := executor.Exec("UPDATE partner_secrets SET valid_until = $1 WHERE partner_id = $2 AND (valid_until IS NULL OR valid_until > $1)",
+		validUntil, partnerID)
+	return err
+}
+
+// GetValidPartnerSecrets mengambil semua secret partner yang berlaku pada waktu now
+func GetValidPartnerSecrets(executor Executor, partnerID int, now time.Time) ([]models.PartnerSecret, error) {
+	rows, err := executor.Query(`SELECT id, partner_id, secret_enc, valid_from, valid_until FROM partner_secrets
+		WHERE partner_id = $1 AND valid_from <= $2 AND (valid_until IS NULL OR valid_until > $2)
+		ORDER BY valid_from DESC`, partnerID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var secrets []models.PartnerSecret
+	for rows.Next() {
+		var s models.PartnerSecret
+		var validUntil sql.NullTime
+		if err := rows.Scan(&s.ID, &s.PartnerID, &s.SecretEnc, &s.ValidFrom, &validUntil); err != nil {
+			return nil, err
+		}
+		if validUntil.Valid {
+			s.ValidUntil = &validUntil.Time
+		}
+		secrets = append(secrets, s)
+	}
+	return secrets, rows.Err()
+}
+
+// InsertPartnerNonce mencatat nonce yang sudah dipakai. Mengembalikan false jika
+// nonce sudah pernah dipakai partner yang sama (replay).
+func InsertPartnerNonce(executor Executor, partnerID int, nonce string, expiresAt time.Time) (bool, error) {
+	result, err := executor.Exec("INSERT INTO partner_nonces (partner_id, nonce, expires_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING",
+		partnerID, nonce, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// DeleteExpiredPartnerNonces menghapus nonce yang sudah di luar jendela replay
+func DeleteExpiredPartnerNonces(executor Executor, now time.Time) error {
+	_, err := executor.Exec("DELETE FROM partner_nonces WHERE expires_at <= $1", now)
+	return err
+}
+
+// LinkPartnerRekening menghubungkan rekening dengan partner. Mengembalikan false jika
+// sudah terhubung sebelumnya.
+func LinkPartnerRekening(executor Executor, partnerID int, noRekening string) (bool, error) {
+	result, err := executor.Exec("INSERT INTO partner_rekening (partner_id, no_rekening) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+		partnerID, noRekening)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// UnlinkPartnerRekening memutus hubungan rekening dengan partner
+func UnlinkPartnerRekening(executor Executor, partnerID int, noRekening string) (bool, error) {
+	result, err := executor.Exec("DELETE FROM partner_rekening WHERE partner_id = $1 AND no_rekening = $2", partnerID, noRekening)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// ListPartnerRekening mengambil nomor rekening yang terhubung dengan partner
+func ListPartnerRekening(executor Executor, partnerID int) ([]string, error) {
+	rows, err := executor.Query("SELECT no_rekening FROM partner_rekening WHERE partner_id = $1 ORDER BY no_rekening", partnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rekening := []string{}
+	for rows.Next() {
+		var noRekening string
+		if err := rows.Scan(&noRekening); err != nil {
+			return nil, err
+		}
+		rekening = append(rekening, noRekening)
+	}
+	return rekening, rows.Err()
+}
+
+// FindUnlinkedPartnerRekening mengembalikan nomor rekening yang tidak terhubung dengan partner
+func FindUnlinkedPartnerRekening(executor Executor, partnerID int, rekening []string) ([]string, error) {
+	rows, err := executor.Query(`SELECT r FROM unnest($2::varchar[]) AS r
+		WHERE NOT EXISTS (SELECT 1 FROM partner_rekening pr WHERE pr.partner_id = $1 AND pr.no_rekening = r)
+		ORDER BY r`, partnerID, pq.Array(rekening))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	unlinked := []string{}
+	for rows.Next() {
+		var noRekening string
+		if err := rows.Scan(&noRekening); err != nil {
+			return nil, err
+		}
+		unlinked = append(unlinked, noRekening)
+	}
+	return unlinked, rows.Err()
+}
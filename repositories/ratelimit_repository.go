@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// TakeRateLimitToken mengambil satu token dari bucket rate limit. Bucket diisi ulang
+// rate token per detik sampai capacity berdasarkan jam database, sehingga semua replika
+// memakai waktu yang sama. Mengembalikan false jika token tidak cukup; tokens adalah
+// isi bucket setelah pengambilan, atau isi saat ini jika ditolak.
+func TakeRateLimitToken(executor Executor, key string, capacity, rate float64) (bool, float64, error) {
+	var tokens float64
+	err := executor.QueryRow(`INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, updated_at, expires_at)
+		VALUES ($1, $2::float8 - 1, now(), now() + make_interval(secs => $2::float8 / $3::float8))
+		ON CONFLICT (bucket_key) DO UPDATE SET
+			tokens = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $3::float8) - 1,
+			updated_at = now(),
+			expires_at = EXCLUDED.expires_at
+		WHERE LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $3::float8) >= 1
+		RETURNING tokens`, key, capacity, rate).Scan(&tokens)
+	if err == nil {
+		return true, tokens, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, 0, err
+	}
+
+	// Baris tidak diubah karena token tidak cukup; baca isi bucket untuk Retry-After
+	err = executor.QueryRow(`SELECT LEAST($2::float8, tokens + EXTRACT(EPOCH FROM now() - updated_at)::float8 * $3::float8)
+		FROM rate_limit_buckets WHERE bucket_key = $1`, key, capacity, rate).Scan(&tokens)
+	if err != nil {
+		return false, 0, err
+	}
+	return false, tokens, nil
+}
+
+// DeleteExpiredRateLimitBuckets menghapus bucket yang sudah penuh kembali
+func DeleteExpiredRateLimitBuckets(executor Executor) (int64, error) {
+	result, err := executor.Exec("DELETE FROM rate_limit_buckets WHERE expires_at <= now()")
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
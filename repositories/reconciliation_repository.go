@@ -0,0 +1,255 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-echo-postgresql/models"
+
+	"github.com/lib/pq"
+)
+
+// reconciliationLockKey adalah kunci advisory lock sesi selama rekonsiliasi berjalan
+const reconciliationLockKey = 7304
+
+const reconciliationRunColumns = "id, status, triggered_by, diperiksa, selisih, COALESCE(error, ''), started_at, finished_at"
+
+const mismatchColumns = `s.id, s.run_id, s.nasabah_id, n.no_rekening, s.saldo_tercatat, s.saldo_hitung, s.tabungan_id,
+	s.saldo_setelah_tercatat, s.saldo_setelah_hitung, s.status, s.operation_id, s.created_at, s.updated_at, s.resolved_at`
+
+// TryLockReconciliation mencoba mengambil kunci rekonsiliasi pada koneksi khusus
+func TryLockReconciliation(ctx context.Context, conn *sql.Conn) (bool, error) {
+	return tryAdvisoryLock(ctx, conn, reconciliationLockKey)
+}
+
+// UnlockReconciliation melepas kunci yang diambil TryLockReconciliation
+func UnlockReconciliation(ctx context.Context, conn *sql.Conn) error {
+	return advisoryUnlock(ctx, conn, reconciliationLockKey)
+}
+
+// CreateReconciliationRun mencatat awal proses rekonsiliasi
+func CreateReconciliationRun(executor Executor, run *models.ReconciliationRun) error {
+	return executor.QueryRow("INSERT INTO rekonsiliasi_runs (triggered_by) VALUES ($1) RETURNING id, status, started_at",
+		run.TriggeredBy).Scan(&run.ID, &run.Status, &run.StartedAt)
+}
+
+// FinishReconciliationRun menyimpan hasil akhir proses rekonsiliasi
+func FinishReconciliationRun(executor Executor, run *models.ReconciliationRun) error {
+	return executor.QueryRow(`UPDATE rekonsiliasi_runs SET status = $1, diperiksa = $2, selisih = $3, error = $4, finished_at = NOW()
+		WHERE id = $5 RETURNING finished_at`, run.Status, run.Diperiksa, run.Selisih, nullString(run.Error), run.ID).Scan(&run.FinishedAt)
+}
+
+// ListReconciliationRuns mengambil proses rekonsiliasi terbaru
+func ListReconciliationRuns(executor Executor, limit int) ([]models.ReconciliationRun, error) {
+	rows, err := executor.Query("SELECT "+reconciliationRunColumns+" FROM rekonsiliasi_runs ORDER BY id DESC LIMIT $1", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []models.ReconciliationRun{}
+	for rows.Next() {
+		var run models.ReconciliationRun
+		var finishedAt sql.NullTime
+		if err := rows.Scan(&run.ID, &run.Status, &run.TriggeredBy, &run.Diperiksa, &run.Selisih, &run.Error, &run.StartedAt, &finishedAt); err != nil {
+			return nil, err
+		}
+		if finishedAt.Valid {
+			run.FinishedAt = &finishedAt.Time
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// ReconciliationState adalah posisi satu rekening saat rekonsiliasi: saldo tercatat,
+// saldo hitung sampai checkpoint terakhir, dan mutasi setelah checkpoint
+type ReconciliationState struct {
+	NasabahID    int
+	NoRekening   string
+	Saldo        float64
+	Checkpoint   int     // id tabungan terakhir yang sudah terbukti cocok
+	SaldoHitung  float64 // saldo hasil hitung ulang sampai Checkpoint
+	Mutasi       float64 // jumlah nominal bertanda setelah Checkpoint
+	LastTabungan int     // id tabungan terbaru, sama dengan Checkpoint jika tidak ada mutasi baru
+}
+
+// Expected mengembalikan saldo hasil hitung ulang seluruh riwayat
+func (s ReconciliationState) Expected() float64 {
+	return s.SaldoHitung + s.Mutasi
+}
+
+// GetReconciliationStates membaca saldo dan mutasi sejak checkpoint untuk satu batch
+// nasabah dalam satu query, sehingga saldo dan riwayat dibaca dari snapshot yang sama.
+// Hanya transaksi setelah checkpoint setiap rekening yang dijumlahkan; ini aman karena
+// setiap transaksi mengunci baris nasabah sebelum mencatat tabungan, sehingga id tabungan
+// satu rekening selalu bertambah sesuai urutan commit.
+func GetReconciliationStates(executor Executor, ids []int) ([]ReconciliationState, error) {
+	rows, err := executor.Query(`SELECT n.id, n.no_rekening, n.saldo,
+			COALESCE(c.last_tabungan_id, 0), COALESCE(c.saldo_hitung, 0),
+			COALESCE(SUM(`+signedNominal+`), 0), COALESCE(MAX(t.id), COALESCE(c.last_tabungan_id, 0))
+		FROM nasabah n
+		LEFT JOIN rekonsiliasi_checkpoint c ON c.nasabah_id = n.id
+		LEFT JOIN tabungan t ON t.nasabah_id = n.id AND t.id > COALESCE(c.last_tabungan_id, 0)
+		WHERE n.id = ANY($1)
+		GROUP BY n.id, n.no_rekening, n.saldo, c.last_tabungan_id, c.saldo_hitung
+		ORDER BY n.id`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var states []ReconciliationState
+	for rows.Next() {
+		var s ReconciliationState
+		if err := rows.Scan(&s.NasabahID, &s.NoRekening, &s.Saldo, &s.Checkpoint, &s.SaldoHitung, &s.Mutasi, &s.LastTabungan); err != nil {
+			return nil, err
+		}
+		states = append(states, s)
+	}
+	return states, rows.Err()
+}
+
+// Divergence adalah transaksi pertama yang saldo_setelah-nya berbeda dengan saldo hasil hitung ulang
+type Divergence struct {
+	TabunganID           int
+	SaldoSetelahTercatat float64
+	SaldoSetelahHitung   float64
+}
+
+// FindFirstDivergence menelusuri transaksi setelah checkpoint secara berurutan dan
+// mengembalikan transaksi pertama yang saldo_setelah-nya tidak sama dengan saldo
+// berjalan hasil hitung ulang. Mengembalikan sql.ErrNoRows jika tidak ada.
+func FindFirstDivergence(executor Executor, state ReconciliationState) (*Divergence, error) {
+	var d Divergence
+	err := executor.QueryRow(`SELECT x.id, x.saldo_setelah, x.berjalan FROM (
+			SELECT t.id, t.saldo_setelah, $3 + SUM(`+signedNominal+`) OVER (ORDER BY t.id) AS berjalan
+			FROM tabungan t WHERE t.nasabah_id = $1 AND t.id > $2
+		) x
+		WHERE x.saldo_setelah IS NOT NULL AND ABS(x.saldo_setelah - x.berjalan) >= 0.005
+		ORDER BY x.id LIMIT 1`, state.NasabahID, state.Checkpoint, state.SaldoHitung).
+		Scan(&d.TabunganID, &d.SaldoSetelahTercatat, &d.SaldoSetelahHitung)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// SaveReconciliationCheckpoint memajukan checkpoint rekening yang saldonya cocok
+func SaveReconciliationCheckpoint(executor Executor, nasabahID, lastTabunganID int, saldoHitung float64) error {
+	_, err := executor.Exec(`INSERT INTO rekonsiliasi_checkpoint (nasabah_id, last_tabungan_id, saldo_hitung)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (nasabah_id) DO UPDATE
+			SET last_tabungan_id = EXCLUDED.last_tabungan_id, saldo_hitung = EXCLUDED.saldo_hitung, updated_at = NOW()`,
+		nasabahID, lastTabunganID, saldoHitung)
+	return err
+}
+
+// UpsertReconciliationMismatch mencatat selisih rekening. Jika rekening sudah punya
+// selisih aktif, angkanya diperbarui sehingga satu rekening hanya punya satu selisih aktif.
+// Selisih yang koreksinya ditolak, kedaluwarsa atau gagal dibuka kembali.
+func UpsertReconciliationMismatch(executor Executor, m *models.ReconciliationMismatch) error {
+	return executor.QueryRow(`INSERT INTO rekonsiliasi_selisih
+			(run_id, nasabah_id, saldo_tercatat, saldo_hitung, tabungan_id, saldo_setelah_tercatat, saldo_setelah_hitung)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (nasabah_id) WHERE status IN ('open', 'koreksi_diajukan') DO UPDATE
+			SET run_id = EXCLUDED.run_id, saldo_tercatat = EXCLUDED.saldo_tercatat, saldo_hitung = EXCLUDED.saldo_hitung,
+			    tabungan_id = EXCLUDED.tabungan_id, saldo_setelah_tercatat = EXCLUDED.saldo_setelah_tercatat,
+			    saldo_setelah_hitung = EXCLUDED.saldo_setelah_hitung, updated_at = NOW(),
+			    status = CASE WHEN EXISTS (
+			        SELECT 1 FROM pending_operations o WHERE o.id = rekonsiliasi_selisih.operation_id AND o.status = 'pending'
+			    ) THEN rekonsiliasi_selisih.status ELSE 'open' END
+		RETURNING id, status, created_at, updated_at`,
+		m.RunID, m.NasabahID, m.SaldoTercatat, m.SaldoHitung, m.TabunganID, m.SaldoSetelahTercatat, m.SaldoSetelahHitung).
+		Scan(&m.ID, &m.Status, &m.CreatedAt, &m.UpdatedAt)
+}
+
+// ResolveReconciliationMismatches menutup selisih aktif rekening yang saldonya sudah cocok kembali
+func ResolveReconciliationMismatches(executor Executor, nasabahID int) error {
+	_, err := executor.Exec(`UPDATE rekonsiliasi_selisih SET status = 'selesai', updated_at = NOW(), resolved_at = NOW()
+		WHERE nasabah_id = $1 AND status IN ('open', 'koreksi_diajukan')`, nasabahID)
+	return err
+}
+
+// ListReconciliationMismatches mengambil selisih rekonsiliasi, opsional difilter status
+func ListReconciliationMismatches(executor Executor, status string, limit int) ([]models.ReconciliationMismatch, error) {
+	rows, err := executor.Query(`SELECT `+mismatchColumns+` FROM rekonsiliasi_selisih s
+		JOIN nasabah n ON n.id = s.nasabah_id
+		WHERE ($1 = '' OR s.status = $1)
+		ORDER BY s.id DESC LIMIT $2`, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mismatches := []models.ReconciliationMismatch{}
+	for rows.Next() {
+		m, err := scanMismatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		mismatches = append(mismatches, *m)
+	}
+	return mismatches, rows.Err()
+}
+
+// GetReconciliationMismatch mengambil satu selisih rekonsiliasi
+func GetReconciliationMismatch(executor Executor, id int) (*models.ReconciliationMismatch, error) {
+	return scanMismatch(executor.QueryRow(`SELECT `+mismatchColumns+` FROM rekonsiliasi_selisih s
+		JOIN nasabah n ON n.id = s.nasabah_id WHERE s.id = $1`, id))
+}
+
+// GetReconciliationMismatchForUpdate mengambil dan mengunci selisih sampai transaksi selesai
+func GetReconciliationMismatchForUpdate(tx *sql.Tx, id int) (*models.ReconciliationMismatch, error) {
+	return scanMismatch(tx.QueryRow(`SELECT `+mismatchColumns+` FROM rekonsiliasi_selisih s
+		JOIN nasabah n ON n.id = s.nasabah_id WHERE s.id = $1 FOR UPDATE OF s`, id))
+}
+
+// MarkMismatchCorrectionSubmitted menautkan selisih yang masih open ke operasi koreksi di antrean persetujuan
+func MarkMismatchCorrectionSubmitted(executor Executor, id, operationID int) (bool, error) {
+	result, err := executor.Exec(`UPDATE rekonsiliasi_selisih SET status = 'koreksi_diajukan', operation_id = $1, updated_at = NOW()
+		WHERE id = $2 AND status = 'open'`, operationID, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// UpdateMismatchStatus mengubah status selisih rekonsiliasi
+func UpdateMismatchStatus(executor Executor, id int, status string) error {
+	_, err := executor.Exec(`UPDATE rekonsiliasi_selisih SET status = $1, updated_at = NOW(),
+			resolved_at = CASE WHEN $1 IN ('dikoreksi', 'selesai') THEN NOW() ELSE resolved_at END
+		WHERE id = $2`, status, id)
+	return err
+}
+
+// SetSaldo menimpa saldo rekening. Hanya dipakai koreksi rekonsiliasi yang sudah disetujui.
+func SetSaldo(tx *sql.Tx, nasabahID int, saldo float64) error {
+	_, err := tx.Exec("UPDATE nasabah SET saldo = $1 WHERE id = $2", saldo, nasabahID)
+	return err
+}
+
+func scanMismatch(row rowScanner) (*models.ReconciliationMismatch, error) {
+	var m models.ReconciliationMismatch
+	var tabunganID, operationID sql.NullInt64
+	var setelahTercatat, setelahHitung sql.NullFloat64
+	var resolvedAt sql.NullTime
+	err := row.Scan(&m.ID, &m.RunID, &m.NasabahID, &m.NoRekening, &m.SaldoTercatat, &m.SaldoHitung, &tabunganID,
+		&setelahTercatat, &setelahHitung, &m.Status, &operationID, &m.CreatedAt, &m.UpdatedAt, &resolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	m.Selisih = m.SaldoTercatat - m.SaldoHitung
+	m.TabunganID = nullIntPtr(tabunganID)
+	m.OperationID = nullIntPtr(operationID)
+	if setelahTercatat.Valid {
+		m.SaldoSetelahTercatat = &setelahTercatat.Float64
+	}
+	if setelahHitung.Valid {
+		m.SaldoSetelahHitung = &setelahHitung.Float64
+	}
+	if resolvedAt.Valid {
+		m.ResolvedAt = &resolvedAt.Time
+	}
+	return &m, nil
+}
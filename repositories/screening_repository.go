@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+
+	"github.com/lib/pq"
+)
+
+const screeningHitColumns = "h.id, h.nasabah_id, n.no_rekening, h.konteks, h.nama_subjek, h.entry_id, h.watchlist, h.versi, h.nama_entri, h.skor, h.status, h.reviewed_by, h.reviewed_at, COALESCE(h.catatan, ''), h.created_at"
+
+// LockWatchlist membuat watchlist jika belum ada lalu menguncinya sampai transaksi selesai,
+// sehingga dua impor untuk watchlist yang sama tidak menghasilkan nomor versi yang sama
+func LockWatchlist(tx *sql.Tx, kode string) (*models.Watchlist, error) {
+	if _, err := tx.Exec("INSERT INTO watchlists (kode) VALUES ($1) ON CONFLICT (kode) DO NOTHING", kode); err != nil {
+		return nil, err
+	}
+	var w models.Watchlist
+	err := tx.QueryRow("SELECT id, kode, created_at FROM watchlists WHERE kode = $1 FOR UPDATE", kode).Scan(&w.ID, &w.Kode, &w.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// InsertWatchlistVersion menyimpan versi baru dengan nomor versi berikutnya. Versi baru
+// belum aktif sampai ActivateWatchlistVersion dipanggil.
+func InsertWatchlistVersion(tx *sql.Tx, v *models.WatchlistVersion) error {
+	return tx.QueryRow(`INSERT INTO watchlist_versions (watchlist_id, versi, format, checksum, jumlah_entri, imported_by)
+		VALUES ($1, (SELECT COALESCE(MAX(versi), 0) + 1 FROM watchlist_versions WHERE watchlist_id = $1), $2, $3, $4, $5)
+		RETURNING id, versi, created_at`,
+		v.WatchlistID, v.Format, v.Checksum, v.JumlahEntri, v.ImportedBy).Scan(&v.ID, &v.Versi, &v.CreatedAt)
+}
+
+// InsertWatchlistEntries menyimpan entri untuk satu versi watchlist
+func InsertWatchlistEntries(tx *sql.Tx, versionID int, entries []models.WatchlistEntry) error {
+	stmt, err := tx.Prepare(`INSERT INTO watchlist_entries (version_id, nama, alias, nik, tanggal_lahir, keterangan)
+		VALUES ($1, $2, $3, $4, $5, $6)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range entries {
+		alias := e.Alias
+		if alias == nil {
+			alias = []string{}
+		}
+		if _, err := stmt.Exec(versionID, e.Nama, pq.Array(alias), nullString(e.NIK), nullString(e.TanggalLahir), nullString(e.Keterangan)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ActivateWatchlistVersion menjadikan satu versi sebagai versi aktif watchlist-nya
+func ActivateWatchlistVersion(tx *sql.Tx, watchlistID, versionID int) error {
+	if _, err := tx.Exec("UPDATE watchlist_versions SET aktif = FALSE WHERE watchlist_id = $1 AND aktif", watchlistID); err != nil {
+		return err
+	}
+	_, err := tx.Exec("UPDATE watchlist_versions SET aktif = TRUE WHERE id = $1", versionID)
+	return err
+}
+
+// ListActiveWatchlistEntries mengambil semua entri dari versi aktif setiap watchlist
+func ListActiveWatchlistEntries(executor Executor) ([]models.WatchlistEntry, error) {
+	rows, err := executor.Query(`SELECT e.id, e.version_id, w.kode, v.versi, e.nama, e.alias, COALESCE(e.nik, ''), COALESCE(e.tanggal_lahir, ''), COALESCE(e.keterangan, '')
+		FROM watchlist_entries e
+		JOIN watchlist_versions v ON v.id = e.version_id AND v.aktif
+		JOIN watchlists w ON w.id = v.watchlist_id
+		ORDER BY e.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.WatchlistEntry{}
+	for rows.Next() {
+		var e models.WatchlistEntry
+		if err := rows.Scan(&e.ID, &e.VersionID, &e.Watchlist, &e.Versi, &e.Nama, pq.Array(&e.Alias), &e.NIK, &e.TanggalLahir, &e.Keterangan); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetActiveWatchlistVersionIDs mengambil id versi yang sedang aktif, dipakai untuk
+// mendeteksi impor dari instance lain
+func GetActiveWatchlistVersionIDs(executor Executor) ([]int64, error) {
+	var ids []int64
+	err := executor.QueryRow("SELECT COALESCE(array_agg(id ORDER BY id), '{}') FROM watchlist_versions WHERE aktif").Scan(pq.Array(&ids))
+	return ids, err
+}
+
+// ListWatchlists mengambil semua watchlist beserta riwayat versinya
+func ListWatchlists(executor Executor) ([]models.Watchlist, error) {
+	rows, err := executor.Query(`SELECT w.id, w.kode, w.created_at, v.id, v.versi, v.format, v.checksum, v.jumlah_entri, v.aktif, v.imported_by, v.created_at
+		FROM watchlists w
+		JOIN watchlist_versions v ON v.watchlist_id = w.id
+		ORDER BY w.kode, v.versi DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	watchlists := []models.Watchlist{}
+	for rows.Next() {
+		var w models.Watchlist
+		var v models.WatchlistVersion
+		var importedBy sql.NullInt64
+		if err := rows.Scan(&w.ID, &w.Kode, &w.CreatedAt, &v.ID, &v.Versi, &v.Format, &v.Checksum, &v.JumlahEntri, &v.Aktif, &importedBy, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		v.WatchlistID = w.ID
+		v.ImportedBy = nullIntPtr(importedBy)
+
+		if n := len(watchlists); n > 0 && watchlists[n-1].ID == w.ID {
+			watchlists[n-1].Versions = append(watchlists[n-1].Versions, v)
+			continue
+		}
+		w.Versions = []models.WatchlistVersion{v}
+		watchlists = append(watchlists, w)
+	}
+	return watchlists, rows.Err()
+}
+
+// InsertScreeningHit menyimpan kecocokan screening yang menunggu review
+func InsertScreeningHit(executor Executor, h *models.ScreeningHit) error {
+	return executor.QueryRow(`INSERT INTO screening_hits (nasabah_id, konteks, nama_subjek, entry_id, watchlist, versi, nama_entri, skor)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, status, created_at`,
+		h.NasabahID, h.Konteks, h.NamaSubjek, h.EntryID, h.Watchlist, h.Versi, h.NamaEntri, h.Skor).Scan(&h.ID, &h.Status, &h.CreatedAt)
+}
+
+// ListScreeningHits mengambil hit screening, opsional difilter status
+func ListScreeningHits(executor Executor, status string, limit int) ([]models.ScreeningHit, error) {
+	rows, err := executor.Query(`SELECT `+screeningHitColumns+` FROM screening_hits h
+		JOIN nasabah n ON n.id = h.nasabah_id
+		WHERE ($1 = '' OR h.status = $1)
+		ORDER BY h.id DESC LIMIT $2`, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := []models.ScreeningHit{}
+	for rows.Next() {
+		h, err := scanScreeningHit(rows)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, *h)
+	}
+	return hits, rows.Err()
+}
+
+// GetScreeningHitForUpdate mengambil dan mengunci hit screening sampai transaksi selesai
+func GetScreeningHitForUpdate(tx *sql.Tx, id int) (*models.ScreeningHit, error) {
+	return scanScreeningHit(tx.QueryRow(`SELECT `+screeningHitColumns+` FROM screening_hits h
+		JOIN nasabah n ON n.id = h.nasabah_id
+		WHERE h.id = $1 FOR UPDATE OF h`, id))
+}
+
+// UpdateScreeningHitDecision menyimpan keputusan compliance atas hit screening
+func UpdateScreeningHitDecision(executor Executor, h *models.ScreeningHit) error {
+	_, err := executor.Exec(`UPDATE screening_hits SET status = $1, reviewed_by = $2, reviewed_at = $3, catatan = $4 WHERE id = $5`,
+		h.Status, h.ReviewedBy, h.ReviewedAt, nullString(h.Catatan), h.ID)
+	return err
+}
+
+// CountPendingScreeningHits menghitung hit yang belum direview untuk satu nasabah
+func CountPendingScreeningHits(executor Executor, nasabahID int) (int, error) {
+	var count int
+	err := executor.QueryRow("SELECT COUNT(*) FROM screening_hits WHERE nasabah_id = $1 AND status = 'pending'", nasabahID).Scan(&count)
+	return count, err
+}
+
+// ActivatePendingNasabah mengaktifkan nasabah yang sedang menunggu review.
+// Nasabah dengan status lain (misalnya dibekukan petugas) tidak diubah.
+func ActivatePendingNasabah(executor Executor, nasabahID int) (bool, error) {
+	result, err := executor.Exec("UPDATE nasabah SET status = 'aktif' WHERE id = $1 AND status = 'pending_review'", nasabahID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func scanScreeningHit(row rowScanner) (*models.ScreeningHit, error) {
+	var h models.ScreeningHit
+	var reviewedBy sql.NullInt64
+	var reviewedAt sql.NullTime
+	err := row.Scan(&h.ID, &h.NasabahID, &h.NoRekening, &h.Konteks, &h.NamaSubjek, &h.EntryID, &h.Watchlist, &h.Versi,
+		&h.NamaEntri, &h.Skor, &h.Status, &reviewedBy, &reviewedAt, &h.Catatan, &h.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	h.ReviewedBy = nullIntPtr(reviewedBy)
+	if reviewedAt.Valid {
+		h.ReviewedAt = &reviewedAt.Time
+	}
+	return &h, nil
+}
@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const webhookSubscriptionColumns = "id, partner_id, url, event_types, rekening, secret_enc, COALESCE(previous_secret_enc, ''), previous_secret_until, aktif, created_at"
+
+const webhookDeliveryColumns = "d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.last_status_code, COALESCE(d.last_error, ''), d.created_at, d.delivered_at, COALESCE(d.trace_parent, '')"
+
+// CreateWebhookSubscription menyimpan subscription webhook baru
+func CreateWebhookSubscription(executor Executor, sub *models.WebhookSubscription) error {
+	query := `INSERT INTO webhook_subscriptions (partner_id, url, event_types, rekening, secret_enc)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, aktif, created_at`
+	return executor.QueryRow(query, sub.PartnerID, sub.URL, pq.Array(sub.EventTypes), pq.Array(sub.Rekening), sub.SecretEnc).
+		Scan(&sub.ID, &sub.Aktif, &sub.CreatedAt)
+}
+
+// GetWebhookSubscription mengambil subscription berdasarkan id
+func GetWebhookSubscription(executor Executor, id int) (*models.WebhookSubscription, error) {
+	return scanWebhookSubscription(executor.QueryRow("SELECT "+webhookSubscriptionColumns+" FROM webhook_subscriptions WHERE id = $1", id))
+}
+
+// ListWebhookSubscriptionsByPartner mengambil semua subscription milik partner
+func ListWebhookSubscriptionsByPartner(executor Executor, partnerID int) ([]models.WebhookSubscription, error) {
+	rows, err := executor.Query("SELECT "+webhookSubscriptionColumns+" FROM webhook_subscriptions WHERE partner_id = $1 ORDER BY id", partnerID)
+	if err != nil {
+		return nil, err
+	}
+	return collectWebhookSubscriptions(rows)
+}
+
+// FindMatchingWebhookSubscriptions mengambil subscription aktif yang cocok dengan tipe event dan rekening.
+// Rekening harus masih terhubung dengan partner pemilik subscription, termasuk untuk subscription
+// dengan daftar rekening kosong.
+func FindMatchingWebhookSubscriptions(executor Executor, eventType, noRekening string) ([]models.WebhookSubscription, error) {
+	rows, err := executor.Query(`SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions s
+		WHERE aktif AND $1 = ANY(event_types) AND (cardinality(rekening) = 0 OR $2 = ANY(rekening))
+		AND EXISTS (SELECT 1 FROM partner_rekening pr WHERE pr.partner_id = s.partner_id AND pr.no_rekening = $2)`, eventType, noRekening)
+	if err != nil {
+		return nil, err
+	}
+	return collectWebhookSubscriptions(rows)
+}
+
+// RotateWebhookSecret mengganti secret; secret lama tetap dipakai menandatangani sampai previousUntil
+func RotateWebhookSecret(executor Executor, id int, secretEnc string, previousUntil time.Time) error {
+	_, err := executor.Exec(`UPDATE webhook_subscriptions
+		SET previous_secret_enc = secret_enc, previous_secret_until = $1, secret_enc = $2
+		WHERE id = $3`, previousUntil, secretEnc, id)
+	return err
+}
+
+// DeactivateWebhookSubscription menonaktifkan subscription milik partner
+func DeactivateWebhookSubscription(executor Executor, id, partnerID int) (bool, error) {
+	result, err := executor.Exec("UPDATE webhook_subscriptions SET aktif = FALSE WHERE id = $1 AND partner_id = $2", id, partnerID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// InsertWebhookDelivery menjadwalkan pengiriman; event yang sama tidak dijadwalkan dua kali
+func InsertWebhookDelivery(executor Executor, d *models.WebhookDelivery) error {
+	_, err := executor.Exec(`INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, trace_parent)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		d.SubscriptionID, d.EventID, d.EventType, []byte(d.Payload), nullString(d.TraceParent))
+	return err
+}
+
+// ClaimWebhookDeliveries mengambil pengiriman yang jatuh tempo dan menundanya selama lease,
+// sehingga worker lain tidak mengambilnya selama pengiriman berlangsung. Jika worker mati
+// di tengah pengiriman, pengiriman diambil ulang setelah lease habis.
+func ClaimWebhookDeliveries(tx *sql.Tx, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := tx.Query(`SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries d
+		WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+		ORDER BY d.next_attempt_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := collectWebhookDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range deliveries {
+		if _, err := tx.Exec("UPDATE webhook_deliveries SET next_attempt_at = $1 WHERE id = $2", now.Add(lease), deliveries[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDeliveryResult menyimpan status pengiriman setelah satu percobaan
+func UpdateWebhookDeliveryResult(executor Executor, d *models.WebhookDelivery) error {
+	_, err := executor.Exec(`UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, delivered_at = $6
+		WHERE id = $7`,
+		d.Status, d.Attempts, d.NextAttemptAt, d.LastStatusCode, nullString(d.LastError), d.DeliveredAt, d.ID)
+	return err
+}
+
+// InsertWebhookDeliveryAttempt menambahkan baris ke log pengiriman
+func InsertWebhookDeliveryAttempt(executor Executor, a *models.WebhookDeliveryAttempt) error {
+	return executor.QueryRow(`INSERT INTO webhook_delivery_attempts (delivery_id, attempted_at, status_code, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`, a.DeliveryID, a.AttemptedAt, a.StatusCode, nullString(a.Error), a.DurationMs).Scan(&a.ID)
+}
+
+// ListWebhookDeliveries mengambil pengiriman, opsional dibatasi milik partner (partnerID > 0) dan status
+func ListWebhookDeliveries(executor Executor, partnerID int, status string, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := executor.Query(`SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE ($1 = 0 OR s.partner_id = $1) AND ($2 = '' OR d.status = $2)
+		ORDER BY d.id DESC LIMIT $3`, partnerID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectWebhookDeliveries(rows)
+}
+
+// ListWebhookDeliveryAttempts mengambil log percobaan sebuah pengiriman
+func ListWebhookDeliveryAttempts(executor Executor, deliveryID int64) ([]models.WebhookDeliveryAttempt, error) {
+	rows, err := executor.Query(`SELECT id, delivery_id, attempted_at, status_code, COALESCE(error, ''), duration_ms
+		FROM webhook_delivery_attempts WHERE delivery_id = $1 ORDER BY id`, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []models.WebhookDeliveryAttempt{}
+	for rows.Next() {
+		var a models.WebhookDeliveryAttempt
+		var statusCode sql.NullInt64
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.AttemptedAt, &statusCode, &a.Error, &a.DurationMs); err != nil {
+			return nil, err
+		}
+		if statusCode.Valid {
+			code := int(statusCode.Int64)
+			a.StatusCode = &code
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+// RequeueWebhookDelivery menjadwalkan ulang pengiriman (termasuk dari dead-letter queue)
+// dengan jatah percobaan baru. partnerID > 0 membatasi ke pengiriman milik partner tersebut.
+func RequeueWebhookDelivery(executor Executor, id int64, partnerID int) (bool, error) {
+	result, err := executor.Exec(`UPDATE webhook_deliveries d
+		SET status = 'pending', attempts = 0, next_attempt_at = $1
+		FROM webhook_subscriptions s
+		WHERE d.id = $2 AND s.id = d.subscription_id AND ($3 = 0 OR s.partner_id = $3)`, time.Now(), id, partnerID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// WebhookDeliveryBelongsTo memeriksa apakah pengiriman milik partner tertentu
+func WebhookDeliveryBelongsTo(executor Executor, id int64, partnerID int) (bool, error) {
+	var exists bool
+	err := executor.QueryRow(`SELECT EXISTS (SELECT 1 FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.id = $1 AND s.partner_id = $2)`, id, partnerID).Scan(&exists)
+	return exists, err
+}
+
+func scanWebhookSubscription(row rowScanner) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	var previousUntil sql.NullTime
+	err := row.Scan(&sub.ID, &sub.PartnerID, &sub.URL, pq.Array(&sub.EventTypes), pq.Array(&sub.Rekening), &sub.SecretEnc,
+		&sub.PreviousSecretEnc, &previousUntil, &sub.Aktif, &sub.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if previousUntil.Valid {
+		sub.PreviousSecretUntil = &previousUntil.Time
+	}
+	return &sub, nil
+}
+
+func collectWebhookSubscriptions(rows *sql.Rows) ([]models.WebhookSubscription, error) {
+	defer rows.Close()
+
+	subs := []models.WebhookSubscription{}
+	for rows.Next() {
+		sub, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, *sub)
+	}
+	return subs, rows.Err()
+}
+
+func collectWebhookDeliveries(rows *sql.Rows) ([]models.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		var payload []byte
+		var statusCode sql.NullInt64
+		var deliveredAt sql.NullTime
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &statusCode, &d.LastError, &d.CreatedAt, &deliveredAt, &d.TraceParent); err != nil {
+			return nil, err
+		}
+		d.Payload = payload
+		if statusCode.Valid {
+			code := int(statusCode.Int64)
+			d.LastStatusCode = &code
+		}
+		if deliveredAt.Valid {
+			d.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
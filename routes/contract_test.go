@@ -0,0 +1,484 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/openapi"
+	"golang-echo-postgresql/utils"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const (
+	echoPath     = "github.com/labstack/echo/v4"
+	apierrorPath = "golang-echo-postgresql/apierror"
+
+	// rawBody menandai response selain JSON (HTML, CSV, schema yang ditulis apa adanya)
+	rawBody = "raw"
+
+	// anyJSON menandai reply JSON yang didokumentasikan dengan *openapi.Schema, misalnya
+	// dokumen OpenAPI sendiri; tipe body apa pun yang ditulis handler dianggap cocok
+	anyJSON = "any JSON"
+)
+
+// middlewareStatuses adalah status error yang dibalas middleware route sebelum handler
+// berjalan, jadi boleh didokumentasikan walaupun handler tidak menghasilkannya
+var middlewareStatuses = map[int]string{
+	http.StatusUnauthorized:       "Authenticate/VerifyPartner",
+	http.StatusForbidden:          "policy.Require/auth.RequireRole/auth.RequireRekeningAccess",
+	http.StatusTooManyRequests:    "RateLimit",
+	http.StatusServiceUnavailable: "EODGuard/breaker",
+}
+
+// TestHandlersMatchSpec membaca kode sumber handler setiap route dan membandingkannya
+// dengan tabel apiRoutes:
+//   - tipe yang di-Bind handler sama dengan Body yang didokumentasikan;
+//   - setiap c.JSON/c.HTML/WriteHeader handler didokumentasikan dengan status dan tipe
+//     body yang sama, dan setiap reply sukses atau reply dengan body benar-benar ditulis;
+//   - setiap reply error lain bisa dihasilkan handler lewat apierror atau oleh middleware.
+func TestHandlersMatchSpec(t *testing.T) {
+	if testing.Short() {
+		t.Skip("type-checks handler sources")
+	}
+	spec := buildSpec(t)
+
+	documented := map[string]openapi.Route{}
+	for _, r := range apiRoutes {
+		documented[r.Method+" "+r.Path] = r
+	}
+
+	handlerOf := map[string]handlerRef{}
+	pkgs := map[string]bool{}
+	for _, r := range stubRoutes(t, spec).Routes() {
+		if !apiRoute(r) {
+			continue
+		}
+		ref, ok := parseHandlerName(r.Name)
+		if !ok {
+			t.Errorf("%s %s: cannot resolve handler %s", r.Method, r.Path, r.Name)
+			continue
+		}
+		handlerOf[r.Method+" "+r.Path] = ref
+		pkgs[ref.pkg] = true
+	}
+
+	src := loadSources(t, keys(pkgs))
+
+	var problems []string
+	for route, ref := range handlerOf {
+		r, ok := documented[route]
+		if !ok {
+			continue // dilaporkan TestSpecMatchesRoutes
+		}
+		b, err := src.analyze(ref)
+		if err != nil {
+			problems = append(problems, route+": "+err.Error())
+			continue
+		}
+		for _, p := range compareRoute(r, b) {
+			problems = append(problems, route+": "+p)
+		}
+	}
+	report(t, problems)
+}
+
+// compareRoute membandingkan satu route terdokumentasi dengan perilaku handler-nya
+func compareRoute(r openapi.Route, b *behaviour) []string {
+	var problems []string
+
+	if r.Method != http.MethodGet {
+		want := ""
+		if r.Body != nil {
+			want = reflectKey(reflect.TypeOf(r.Body))
+		}
+		for _, bound := range b.binds {
+			if bound != want {
+				problems = append(problems, fmt.Sprintf("handler binds %s, documented body is %q", bound, want))
+			}
+		}
+		if want != "" && len(b.binds) == 0 {
+			problems = append(problems, "documented body "+want+" is never bound by the handler")
+		}
+	}
+
+	var docs []reply
+	for _, d := range r.Replies {
+		docs = append(docs, documentedReply(d))
+	}
+	for _, w := range b.writes {
+		if !slices.ContainsFunc(docs, w.matches) {
+			problems = append(problems, fmt.Sprintf("handler writes %d %s, not documented", w.status, w.body))
+		}
+	}
+	for i, d := range r.Replies {
+		dr := docs[i]
+		switch {
+		case slices.ContainsFunc(b.writes, dr.matches):
+		case d.Status < 400 || d.Body != nil:
+			problems = append(problems, fmt.Sprintf("documented %d %s is never written by the handler", dr.status, dr.body))
+		case b.codes[d.Status]:
+		case middlewareStatuses[d.Status] != "":
+		default:
+			problems = append(problems, fmt.Sprintf("documented %d is not returned by the handler or route middleware", d.Status))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// reply adalah satu status dan tipe body response
+type reply struct {
+	status int
+	body   string
+}
+
+// matches berlaku dua arah antara reply tertulis dan reply terdokumentasi
+func (r reply) matches(other reply) bool {
+	if r.status != other.status {
+		return false
+	}
+	if r.body == anyJSON || other.body == anyJSON {
+		return r.body != rawBody && other.body != rawBody
+	}
+	return r.body == other.body
+}
+
+func documentedReply(d openapi.Reply) reply {
+	switch body := d.Body.(type) {
+	case nil:
+		return reply{d.Status, reflectKey(reflect.TypeOf(utils.Response{}))}
+	case *openapi.Schema:
+		if d.ContentType == "" || d.ContentType == "application/json" {
+			return reply{d.Status, anyJSON}
+		}
+		return reply{d.Status, rawBody}
+	default:
+		if d.ContentType != "" && d.ContentType != "application/json" {
+			return reply{d.Status, rawBody}
+		}
+		return reply{d.Status, reflectKey(reflect.TypeOf(body))}
+	}
+}
+
+// behaviour adalah hasil analisis kode sumber satu handler beserta fungsi pembantunya
+type behaviour struct {
+	binds  []string
+	writes []reply
+	codes  map[int]bool // status dari kode apierror yang dikembalikan
+}
+
+// handlerRef menunjuk fungsi atau method di kode sumber, dari nama runtime handler Echo
+type handlerRef struct {
+	pkg string
+	fn  string // "Fungsi" atau "Receiver.Method"
+}
+
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+$`)
+
+// parseHandlerName mengurai nama seperti "mod/handlers.(*NasabahHandler).Tabung-fm" atau
+// "mod/openapi.SpecHandler.func1"; closure dianalisis sebagai fungsi pembuatnya
+func parseHandlerName(name string) (handlerRef, bool) {
+	name = closureSuffix.ReplaceAllString(strings.TrimSuffix(name, "-fm"), "")
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return handlerRef{}, false
+	}
+	pkg, sym := name[:slash+1+dot], name[slash+2+dot:]
+	sym = strings.NewReplacer("(*", "", "(", "", ")", "").Replace(sym)
+	return handlerRef{pkg: pkg, fn: sym}, true
+}
+
+// sources adalah package modul yang di-type-check dari kode sumber. Dependensinya dibaca
+// dari export data hasil go list -export.
+type sources struct {
+	fset *token.FileSet
+	pkgs map[string]*sourcePackage
+}
+
+type sourcePackage struct {
+	info  *types.Info
+	funcs map[string]*ast.FuncDecl
+}
+
+func loadSources(t *testing.T, paths []string) *sources {
+	t.Helper()
+	args := append([]string{"list", "-export", "-deps", "-json"}, paths...)
+	out, err := exec.Command("go", args...).Output()
+	if err != nil {
+		t.Fatalf("go list: %v", err)
+	}
+
+	type listed struct {
+		ImportPath string
+		Dir        string
+		GoFiles    []string
+		Export     string
+	}
+	listedPkgs := map[string]listed{}
+	exports := map[string]string{}
+	dec := json.NewDecoder(strings.NewReader(string(out)))
+	for {
+		var p listed
+		if err := dec.Decode(&p); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("go list output: %v", err)
+		}
+		listedPkgs[p.ImportPath] = p
+		exports[p.ImportPath] = p.Export
+	}
+
+	src := &sources{fset: token.NewFileSet(), pkgs: map[string]*sourcePackage{}}
+	imp := importer.ForCompiler(src.fset, "gc", func(path string) (io.ReadCloser, error) {
+		if exports[path] == "" {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+		return os.Open(exports[path])
+	})
+
+	for _, path := range paths {
+		p := listedPkgs[path]
+		var files []*ast.File
+		for _, name := range p.GoFiles {
+			f, err := parser.ParseFile(src.fset, filepath.Join(p.Dir, name), nil, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files = append(files, f)
+		}
+
+		info := &types.Info{
+			Types: map[ast.Expr]types.TypeAndValue{},
+			Uses:  map[*ast.Ident]types.Object{},
+			Defs:  map[*ast.Ident]types.Object{},
+		}
+		if _, err := (&types.Config{Importer: imp}).Check(path, src.fset, files, info); err != nil {
+			t.Fatalf("type-check %s: %v", path, err)
+		}
+
+		sp := &sourcePackage{info: info, funcs: map[string]*ast.FuncDecl{}}
+		for _, f := range files {
+			for _, decl := range f.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok {
+					sp.funcs[funcKey(fn)] = fn
+				}
+			}
+		}
+		src.pkgs[path] = sp
+	}
+	return src
+}
+
+func funcKey(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if id, ok := recv.(*ast.Ident); ok {
+		return id.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// analyze mengumpulkan Bind, response yang ditulis dan kode apierror dari handler serta
+// fungsi di package yang sama yang dipanggilnya
+func (s *sources) analyze(ref handlerRef) (*behaviour, error) {
+	p, ok := s.pkgs[ref.pkg]
+	if !ok || p.funcs[ref.fn] == nil {
+		return nil, fmt.Errorf("handler %s.%s not found in source", ref.pkg, ref.fn)
+	}
+	b := &behaviour{codes: map[int]bool{}}
+	s.walk(ref.pkg, p, p.funcs[ref.fn], b, map[string]bool{})
+	return b, nil
+}
+
+func (s *sources) walk(pkg string, p *sourcePackage, fn *ast.FuncDecl, b *behaviour, seen map[string]bool) {
+	if fn.Body == nil || seen[funcKey(fn)] {
+		return
+	}
+	seen[funcKey(fn)] = true
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		var ident *ast.Ident
+		var recv ast.Expr
+		switch f := call.Fun.(type) {
+		case *ast.Ident:
+			ident = f
+		case *ast.SelectorExpr:
+			ident, recv = f.Sel, f.X
+		default:
+			return true
+		}
+		obj, ok := p.info.Uses[ident].(*types.Func)
+		if !ok || obj.Pkg() == nil {
+			return true
+		}
+
+		switch {
+		case recv != nil && typeKey(p.info.TypeOf(recv)) == echoPath+".Context":
+			s.contextCall(p, ident.Name, call, b)
+		case recv != nil && typeKey(p.info.TypeOf(recv)) == echoPath+".Response" && ident.Name == "WriteHeader":
+			if status, ok := intConst(p, call.Args[0]); ok {
+				b.writes = append(b.writes, reply{status, rawBody})
+			}
+		case obj.Pkg().Path() == apierrorPath && (ident.Name == "New" || ident.Name == "Wrap"):
+			for _, code := range stringConsts(p, fn, call.Args[0]) {
+				b.codes[apierror.Lookup(apierror.Code(code)).Status] = true
+			}
+		case obj.Pkg().Path() == pkg:
+			key := obj.Name()
+			if sig, ok := obj.Type().(*types.Signature); ok && sig.Recv() != nil {
+				key = namedOf(sig.Recv().Type()) + "." + key
+			}
+			if callee := p.funcs[key]; callee != nil {
+				s.walk(pkg, p, callee, b, seen)
+			}
+		}
+		return true
+	})
+}
+
+// contextCall mencatat pemanggilan method echo.Context yang membaca body atau menulis response
+func (s *sources) contextCall(p *sourcePackage, method string, call *ast.CallExpr, b *behaviour) {
+	switch method {
+	case "Bind":
+		arg := call.Args[0]
+		if u, ok := arg.(*ast.UnaryExpr); ok && u.Op == token.AND {
+			arg = u.X
+		}
+		b.binds = append(b.binds, typeKey(p.info.TypeOf(arg)))
+	case "JSON", "JSONPretty":
+		if status, ok := intConst(p, call.Args[0]); ok {
+			b.writes = append(b.writes, reply{status, typeKey(p.info.TypeOf(call.Args[1]))})
+		}
+	case "HTML", "HTMLBlob", "String", "Blob", "Stream", "NoContent":
+		if status, ok := intConst(p, call.Args[0]); ok {
+			b.writes = append(b.writes, reply{status, rawBody})
+		}
+	}
+}
+
+func intConst(p *sourcePackage, e ast.Expr) (int, bool) {
+	v := p.info.Types[e].Value
+	if v == nil || v.Kind() != constant.Int {
+		return 0, false
+	}
+	n, ok := constant.Int64Val(v)
+	return int(n), ok
+}
+
+// stringConsts mengembalikan nilai konstanta string e. Jika e adalah variabel lokal,
+// semua konstanta yang pernah di-assign ke variabel itu di dalam fn dikembalikan.
+func stringConsts(p *sourcePackage, fn *ast.FuncDecl, e ast.Expr) []string {
+	if v := p.info.Types[e].Value; v != nil && v.Kind() == constant.String {
+		return []string{constant.StringVal(v)}
+	}
+	id, ok := e.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	variable := p.info.Uses[id]
+
+	var values []string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			lhsID, ok := lhs.(*ast.Ident)
+			if !ok || (p.info.Defs[lhsID] != variable && p.info.Uses[lhsID] != variable) {
+				continue
+			}
+			if v := p.info.Types[assign.Rhs[i]].Value; v != nil && v.Kind() == constant.String {
+				values = append(values, constant.StringVal(v))
+			}
+		}
+		return true
+	})
+	return values
+}
+
+func namedOf(t types.Type) string {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+// typeKey dan reflectKey menamai tipe dengan cara yang sama dari go/types dan reflect;
+// pointer diabaikan karena di-encode sama dengan nilainya
+func typeKey(t types.Type) string {
+	switch t := types.Unalias(t).(type) {
+	case *types.Pointer:
+		return typeKey(t.Elem())
+	case *types.Named:
+		if t.Obj().Pkg() == nil {
+			return t.Obj().Name()
+		}
+		return t.Obj().Pkg().Path() + "." + t.Obj().Name()
+	case *types.Slice:
+		return "[]" + typeKey(t.Elem())
+	case *types.Map:
+		return "map[" + typeKey(t.Key()) + "]" + typeKey(t.Elem())
+	case *types.Interface:
+		return "interface{}"
+	case nil:
+		return "<nil>"
+	default:
+		return t.String()
+	}
+}
+
+func reflectKey(t reflect.Type) string {
+	switch {
+	case t.Kind() == reflect.Pointer:
+		return reflectKey(t.Elem())
+	case t.Name() != "" && t.PkgPath() != "":
+		return t.PkgPath() + "." + t.Name()
+	case t.Kind() == reflect.Slice:
+		return "[]" + reflectKey(t.Elem())
+	case t.Kind() == reflect.Map:
+		return "map[" + reflectKey(t.Key()) + "]" + reflectKey(t.Elem())
+	case t.Kind() == reflect.Interface:
+		return "interface{}"
+	default:
+		return t.String()
+	}
+}
+
+func keys(m map[string]bool) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,576 @@
+package routes
+
+import (
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/audit"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/config"
+	"golang-echo-postgresql/db"
+	"golang-echo-postgresql/fraud"
+	"golang-echo-postgresql/gl"
+	"golang-echo-postgresql/handlers"
+	"golang-echo-postgresql/health"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/openapi"
+	"golang-echo-postgresql/partnersign"
+	"golang-echo-postgresql/transaction"
+	"golang-echo-postgresql/utils"
+	"net/http"
+)
+
+const (
+	bearer  = "bearerAuth"
+	partner = "partnerSignature"
+
+	// SpecPath dan DocsPath adalah lokasi dokumen OpenAPI dan Swagger UI
+	SpecPath = "/openapi.json"
+	DocsPath = "/docs"
+
+	// ErrorsPath menyajikan katalog kode error apierror
+	ErrorsPath = "/errors"
+
+	// LivePath dan ReadyPath adalah probe liveness dan readiness untuk orchestrator
+	LivePath  = "/healthz"
+	ReadyPath = "/readyz"
+)
+
+// Spec menyusun dokumen OpenAPI untuk semua route di RegisterRoutes. Setiap route baru
+// wajib ditambahkan ke tabel ini; go test ./routes gagal jika keduanya berbeda atau jika
+// status dan tipe body di tabel tidak sesuai dengan handler.
+func Spec() (*openapi.Document, error) {
+	info := openapi.Info{
+		Title:   "Bank Tabungan API",
+		Version: "1.0.0",
+		Description: "API tabungan nasabah, back-office, dan integrasi partner. Request yang tidak sesuai " +
+			"schema ditolak dengan 400, remark \"Invalid request payload\" dan satu pesan per field di errors. " +
+			"Request yang melewati rate limit ditolak dengan 429 (RATE_LIMITED) beserta header RateLimit-* dan Retry-After.",
+	}
+	schemes := map[string]*openapi.SecurityScheme{
+		bearer: {
+			Type:         "http",
+			Scheme:       "bearer",
+			BearerFormat: "JWT",
+			Description:  "Access token dari /auth/nasabah/login atau /auth/staff/login",
+		},
+		partner: {
+			Type: "apiKey",
+			In:   "header",
+			Name: partnersign.HeaderClientID,
+			Description: "Request partner bertanda tangan HMAC-SHA256. Kirim juga header " + partnersign.HeaderTimestamp +
+				" (detik Unix), " + partnersign.HeaderNonce + " dan " + partnersign.HeaderSignature + ".",
+		},
+	}
+	return openapi.Build(info, tags, schemes, utils.Response{}, apiRoutes)
+}
+
+var tags = []openapi.Tag{
+	{Name: "nasabah", Description: "Pendaftaran dan transaksi nasabah"},
+	{Name: "auth", Description: "Login, refresh token dan kunci penandatangan JWT"},
+	{Name: "ops", Description: "Health, konfigurasi, database, gRPC dan dokumentasi API"},
+	{Name: "backoffice", Description: "Operasi back-office dengan maker-checker"},
+	{Name: "partner", Description: "Kredensial partner"},
+	{Name: "webhook", Description: "Subscription dan pengiriman webhook"},
+	{Name: "audit", Description: "Log audit"},
+	{Name: "fraud", Description: "Review keputusan aturan fraud"},
+	{Name: "aml", Description: "Pelaporan PPATK dan kasus AML"},
+	{Name: "screening", Description: "Screening watchlist"},
+	{Name: "eod", Description: "Proses tutup hari"},
+	{Name: "reconciliation", Description: "Rekonsiliasi saldo"},
+	{Name: "gl", Description: "Jurnal buku besar dan neraca saldo"},
+}
+
+var (
+	tokenAuth   = []string{bearer}
+	partnerAuth = []string{partner}
+
+	idParam      = openapi.PathParam("id", openapi.Integer())
+	int64IDParam = openapi.PathParam("id", &openapi.Schema{Type: "integer", Format: "int64"})
+	tanggalQuery = openapi.Query("tanggal", openapi.Date(), "Tanggal bisnis YYYY-MM-DD, bawaan kemarin")
+	afterIDQuery = openapi.Query("after_id", &openapi.Schema{Type: "integer", Format: "int64"}, "Kursor: hanya data dengan id lebih besar")
+
+	forbidden       = openapi.Reply{Status: http.StatusForbidden, Description: "Role tidak memiliki izin"}
+	notFound        = openapi.Reply{Status: http.StatusNotFound, Description: "Data tidak ditemukan"}
+	conflict        = openapi.Reply{Status: http.StatusConflict, Description: "Status data tidak mengizinkan aksi ini"}
+	eodInProgress   = openapi.Reply{Status: http.StatusServiceUnavailable, Description: "EOD sedang berjalan, coba lagi nanti"}
+	waitingApproval = openapi.Reply{Status: http.StatusAccepted, Description: "Menunggu persetujuan checker", Body: handlers.ApprovalResponse{}}
+	fraudBlocked    = openapi.Reply{Status: http.StatusForbidden, Description: "Diblokir aturan fraud (FRAUD_BLOCKED), metadata.decision_id berisi id keputusan"}
+	stepUpRequired  = openapi.Reply{
+		Status:      http.StatusPreconditionRequired,
+		Description: "Kirim ulang dengan header " + fraud.HeaderStepUpPIN + " (STEP_UP_REQUIRED), metadata.decision_id berisi id keputusan",
+	}
+)
+
+func ok(body interface{}) openapi.Reply {
+	return openapi.Reply{Status: http.StatusOK, Body: body}
+}
+
+func statusQuery(description string) *openapi.Parameter {
+	return openapi.Query("status", openapi.String(), description)
+}
+
+// limitQuery adalah parameter ?limit= yang diterima semua endpoint daftar
+func limitQuery() *openapi.Parameter {
+	min, max := 1.0, 1000.0
+	return openapi.Query("limit", &openapi.Schema{Type: "integer", Minimum: &min, Maximum: &max}, "Jumlah data maksimum")
+}
+
+var apiRoutes = []openapi.Route{
+	// Nasabah
+	{
+		Method: http.MethodPost, Path: "/daftar", Tag: "nasabah", Summary: "Daftarkan nasabah baru",
+		Body: models.RegisterNasabahRequest{},
+		Replies: []openapi.Reply{
+			ok(handlers.RegisterResponse{}),
+			{Status: http.StatusAccepted, Description: "Rekening dibuat tetapi ditahan untuk review kepatuhan", Body: handlers.RegisterResponse{}},
+			{Status: http.StatusBadRequest, Description: "Payload tidak valid (INVALID_PAYLOAD, INVALID_IDENTITY, INVALID_PIN) atau NIK/No HP sudah dipakai (DUPLICATE)"},
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/tabung", Tag: "nasabah", Summary: "Setor dana",
+		Security: []string{bearer, partner},
+		Body:     handlers.TabungRequest{},
+		Replies:  []openapi.Reply{ok(handlers.TabungResponse{}), fraudBlocked, stepUpRequired, notFound, conflict, eodInProgress},
+	},
+	{
+		Method: http.MethodPost, Path: "/tarik", Tag: "nasabah", Summary: "Tarik dana",
+		Security: tokenAuth,
+		Body:     handlers.TabungRequest{},
+		Replies:  []openapi.Reply{ok(handlers.SaldoResponse{}), waitingApproval, fraudBlocked, stepUpRequired, notFound, conflict, eodInProgress},
+	},
+	{
+		Method: http.MethodPost, Path: "/transfer", Tag: "nasabah", Summary: "Transfer antarrekening",
+		Security: tokenAuth,
+		Body:     handlers.TransferRequest{},
+		Replies:  []openapi.Reply{ok(transaction.TransferResult{}), waitingApproval, fraudBlocked, stepUpRequired, notFound, conflict, eodInProgress},
+	},
+	{
+		Method: http.MethodGet, Path: "/saldo/:no_rekening", Tag: "nasabah", Summary: "Cek saldo rekening",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(handlers.SaldoResponse{}), forbidden, notFound},
+	},
+
+	// Autentikasi
+	{
+		Method: http.MethodPost, Path: "/auth/nasabah/login", Tag: "auth", Summary: "Login nasabah dengan PIN",
+		Body:    models.LoginNasabahRequest{},
+		Replies: []openapi.Reply{ok(models.TokenResponse{}), {Status: http.StatusUnauthorized, Description: "Kredensial salah"}},
+	},
+	{
+		Method: http.MethodPost, Path: "/auth/staff/login", Tag: "auth", Summary: "Login petugas",
+		Body:    models.LoginStaffRequest{},
+		Replies: []openapi.Reply{ok(models.TokenResponse{}), {Status: http.StatusUnauthorized, Description: "Kredensial salah"}},
+	},
+	{
+		Method: http.MethodPost, Path: "/auth/refresh", Tag: "auth", Summary: "Tukar refresh token dengan pasangan token baru",
+		Body:    models.RefreshTokenRequest{},
+		Replies: []openapi.Reply{ok(models.TokenResponse{}), {Status: http.StatusUnauthorized, Description: "Refresh token tidak valid"}},
+	},
+	{
+		Method: http.MethodPost, Path: "/auth/logout", Tag: "auth", Summary: "Cabut access token dan refresh token",
+		Security: tokenAuth,
+		Body:     models.RefreshTokenRequest{}, BodyOptional: true,
+		Replies: []openapi.Reply{ok(nil)},
+	},
+	{
+		Method: http.MethodGet, Path: "/.well-known/jwks.json", Tag: "auth", Summary: "Kunci publik penandatangan JWT",
+		Replies: []openapi.Reply{ok(auth.JWKS{})},
+	},
+	{
+		Method: http.MethodPost, Path: "/auth/keys/rotate", Tag: "auth", Summary: "Rotasi kunci penandatangan JWT",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(auth.JWKS{}), forbidden},
+	},
+
+	// Operasional
+	{
+		Method: http.MethodGet, Path: LivePath, Tag: "ops", Summary: "Liveness: proses masih melayani request",
+		Replies: []openapi.Reply{ok(health.Report{})},
+	},
+	{
+		Method: http.MethodGet, Path: ReadyPath, Tag: "ops", Summary: "Readiness: database, versi skema, worker dan status shutdown",
+		Replies: []openapi.Reply{
+			ok(health.Report{}),
+			{Status: http.StatusServiceUnavailable, Description: "Belum siap atau sedang shutdown", Body: health.Report{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/config", Tag: "ops", Summary: "Konfigurasi aktif, secret disamarkan",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok([]config.Setting{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/db/stats", Tag: "ops", Summary: "Circuit breaker dan pool koneksi database",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(db.PoolStats{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/grpc/stats", Tag: "ops", Summary: "Metrik RPC server gRPC",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(handlers.GRPCStatsResponse{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: SpecPath, Tag: "ops", Summary: "Dokumen OpenAPI ini",
+		Replies: []openapi.Reply{ok(&openapi.Schema{Type: "object"})},
+	},
+	{
+		Method: http.MethodGet, Path: DocsPath, Tag: "ops", Summary: "Swagger UI",
+		Replies: []openapi.Reply{{Status: http.StatusOK, ContentType: "text/html", Body: openapi.String()}},
+	},
+	{
+		Method: http.MethodGet, Path: ErrorsPath, Tag: "ops", Summary: "Katalog kode error, pesan mengikuti Accept-Language",
+		Replies: []openapi.Reply{ok([]apierror.Documented{})},
+	},
+
+	// Back-office
+	{
+		Method: http.MethodPost, Path: "/backoffice/adjustments", Tag: "backoffice", Summary: "Penyesuaian saldo",
+		Security: tokenAuth,
+		Body:     models.AdjustmentRequest{},
+		Replies:  []openapi.Reply{ok(handlers.ResultResponse{}), waitingApproval, forbidden, eodInProgress},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/reversals", Tag: "backoffice", Summary: "Pembalikan transaksi",
+		Security: tokenAuth,
+		Body:     models.ReversalRequest{},
+		Replies:  []openapi.Reply{ok(handlers.ResultResponse{}), waitingApproval, forbidden, eodInProgress},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/freezes", Tag: "backoffice", Summary: "Bekukan rekening",
+		Security: tokenAuth,
+		Body:     models.FreezeRequest{},
+		Replies:  []openapi.Reply{ok(handlers.ResultResponse{}), waitingApproval, forbidden},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/unfreezes", Tag: "backoffice", Summary: "Cairkan rekening beku",
+		Security: tokenAuth,
+		Body:     models.FreezeRequest{},
+		Replies:  []openapi.Reply{ok(handlers.ResultResponse{}), waitingApproval, forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/approvals", Tag: "backoffice", Summary: "Daftar operasi maker-checker",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{statusQuery("Status operasi, bawaan pending")},
+		Replies:  []openapi.Reply{ok([]models.PendingOperation{}), forbidden},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/approvals/:id/approve", Tag: "backoffice", Summary: "Setujui dan eksekusi operasi",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{idParam},
+		Body:     models.DecisionRequest{}, BodyOptional: true,
+		Replies: []openapi.Reply{ok(handlers.DecisionResponse{}), forbidden, notFound, conflict, eodInProgress},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/approvals/:id/reject", Tag: "backoffice", Summary: "Tolak operasi",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{idParam},
+		Body:     models.DecisionRequest{}, BodyOptional: true,
+		Replies: []openapi.Reply{ok(handlers.DecisionResponse{}), forbidden, notFound, conflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/staff", Tag: "backoffice", Summary: "Buat akun petugas",
+		Security: tokenAuth,
+		Body:     models.CreateStaffRequest{},
+		Replies:  []openapi.Reply{ok(models.Staff{}), forbidden},
+	},
+
+	// Partner
+	{
+		Method: http.MethodPost, Path: "/backoffice/partners", Tag: "partner", Summary: "Daftarkan partner dan terbitkan kredensial",
+		Security: tokenAuth,
+		Body:     models.CreatePartnerRequest{},
+		Replies:  []openapi.Reply{ok(handlers.CreatePartnerResponse{}), forbidden},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/partners/:client_id/secrets/rotate", Tag: "partner", Summary: "Rotasi secret partner",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(models.PartnerCredentialResponse{}), forbidden, notFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/partners/:client_id/rekening", Tag: "partner", Summary: "Rekening yang terhubung dengan partner",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(models.PartnerRekeningResponse{}), forbidden, notFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/partners/:client_id/rekening", Tag: "partner", Summary: "Hubungkan rekening dengan partner",
+		Security: tokenAuth,
+		Body:     models.PartnerRekeningRequest{},
+		Replies:  []openapi.Reply{ok(models.PartnerRekeningResponse{}), forbidden, notFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/partners/:client_id/rekening/:no_rekening/unlink", Tag: "partner", Summary: "Putus hubungan rekening dengan partner",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(models.PartnerRekeningResponse{}), forbidden, notFound},
+	},
+
+	// Webhook (back-office)
+	{
+		Method: http.MethodGet, Path: "/backoffice/webhooks/dead-letters", Tag: "webhook", Summary: "Pengiriman webhook yang gagal permanen",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{limitQuery()},
+		Replies:  []openapi.Reply{ok([]models.WebhookDelivery{}), forbidden},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/webhooks/deliveries/:id/redeliver", Tag: "webhook", Summary: "Jadwalkan ulang pengiriman webhook",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{int64IDParam},
+		Replies:  []openapi.Reply{{Status: http.StatusAccepted, Description: "Pengiriman dijadwalkan ulang"}, forbidden, notFound},
+	},
+
+	// Audit
+	{
+		Method: http.MethodGet, Path: "/backoffice/audit", Tag: "audit", Summary: "Cari event audit",
+		Security: tokenAuth,
+		Params: []*openapi.Parameter{
+			openapi.Query("no_rekening", openapi.String(), ""),
+			openapi.Query("actor", openapi.String(), "Pelaku dengan format tipe:id, misalnya staff:3"),
+			afterIDQuery,
+			limitQuery(),
+		},
+		Replies: []openapi.Reply{ok([]models.AuditEvent{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/audit/verify", Tag: "audit", Summary: "Verifikasi rantai hash log audit",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(audit.Report{}), forbidden},
+	},
+
+	// Fraud
+	{
+		Method: http.MethodGet, Path: "/backoffice/fraud/rules", Tag: "fraud", Summary: "Aturan fraud yang berlaku",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(fraud.RuleSet{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/fraud/decisions", Tag: "fraud", Summary: "Keputusan fraud yang ditandai",
+		Security: tokenAuth,
+		Params: []*openapi.Parameter{
+			statusQuery("Status review, bawaan open; all untuk semua"),
+			openapi.Query("no_rekening", openapi.String(), ""),
+			afterIDQuery,
+			limitQuery(),
+		},
+		Replies: []openapi.Reply{ok([]models.FraudDecision{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/fraud/decisions/:id", Tag: "fraud", Summary: "Detail keputusan fraud",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{int64IDParam},
+		Replies:  []openapi.Reply{ok(models.FraudDecision{}), forbidden, notFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/fraud/decisions/:id/review", Tag: "fraud", Summary: "Tutup review keputusan fraud",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{int64IDParam},
+		Body:     models.FraudReviewRequest{},
+		Replies:  []openapi.Reply{ok(models.FraudDecision{}), forbidden, notFound, conflict},
+	},
+
+	// AML
+	{
+		Method: http.MethodGet, Path: "/backoffice/aml/ltkt", Tag: "aml", Summary: "Laporan transaksi keuangan tunai (LTKT)",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{tanggalQuery},
+		Replies:  []openapi.Reply{ok([]models.LTKTReport{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/aml/exports", Tag: "aml", Summary: "Berkas ekspor PPATK",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{limitQuery()},
+		Replies:  []openapi.Reply{ok([]models.AMLExport{}), forbidden},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/aml/runs", Tag: "aml", Summary: "Jalankan ulang job AML harian",
+		Security: tokenAuth,
+		Body:     models.AMLRunRequest{},
+		Replies:  []openapi.Reply{ok(models.AMLRun{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/aml/cases", Tag: "aml", Summary: "Daftar kasus AML",
+		Security: tokenAuth,
+		Params: []*openapi.Parameter{
+			statusQuery("Status kasus"),
+			openapi.Query("no_rekening", openapi.String(), ""),
+			limitQuery(),
+		},
+		Replies: []openapi.Reply{ok([]models.AMLCase{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/aml/cases/:id", Tag: "aml", Summary: "Detail kasus AML",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{idParam},
+		Replies:  []openapi.Reply{ok(models.AMLCase{}), forbidden, notFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/aml/cases", Tag: "aml", Summary: "Buka kasus AML",
+		Security: tokenAuth,
+		Body:     models.OpenAMLCaseRequest{},
+		Replies:  []openapi.Reply{ok(models.AMLCase{}), forbidden, notFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/aml/cases/:id/transition", Tag: "aml", Summary: "Ubah status kasus AML",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{idParam},
+		Body:     models.AMLCaseTransitionRequest{},
+		Replies:  []openapi.Reply{ok(models.AMLCase{}), forbidden, notFound, conflict},
+	},
+
+	// Screening
+	{
+		Method: http.MethodGet, Path: "/backoffice/screening/hits", Tag: "screening", Summary: "Hit screening watchlist",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{statusQuery("Status hit, bawaan pending"), limitQuery()},
+		Replies:  []openapi.Reply{ok([]models.ScreeningHit{}), forbidden},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/screening/hits/:id/clear", Tag: "screening", Summary: "Nyatakan hit sebagai false positive",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{idParam},
+		Body:     models.ScreeningDecisionRequest{},
+		Replies:  []openapi.Reply{ok(models.ScreeningHit{}), forbidden, notFound, conflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/screening/hits/:id/confirm", Tag: "screening", Summary: "Konfirmasi hit dan bekukan rekening",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{idParam},
+		Body:     models.ScreeningDecisionRequest{},
+		Replies:  []openapi.Reply{ok(models.ScreeningHit{}), forbidden, notFound, conflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/screening/watchlists", Tag: "screening", Summary: "Daftar watchlist dan versi aktif",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok([]models.Watchlist{}), forbidden},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/screening/watchlists/:kode/import", Tag: "screening", Summary: "Impor versi baru watchlist",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{openapi.Query("format", openapi.Enum("csv", "xml"), "Format berkas, bawaan csv")},
+		RawBody:  []string{"text/csv", "application/xml"},
+		Replies: []openapi.Reply{
+			{Status: http.StatusCreated, Body: models.WatchlistVersion{}},
+			forbidden,
+			{Status: http.StatusRequestEntityTooLarge, Description: "Berkas watchlist terlalu besar"},
+		},
+	},
+
+	// EOD
+	{
+		Method: http.MethodGet, Path: "/backoffice/eod/status", Tag: "eod", Summary: "Tanggal bisnis dan progres EOD",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(models.EODStatus{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/eod/runs/:tanggal", Tag: "eod", Summary: "Run EOD untuk satu tanggal",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{openapi.PathParam("tanggal", openapi.Date())},
+		Replies:  []openapi.Reply{ok(models.EODRun{}), forbidden, notFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/eod/runs", Tag: "eod", Summary: "Jalankan EOD secara manual",
+		Security: tokenAuth,
+		Body:     models.EODRunRequest{}, BodyOptional: true,
+		Replies: []openapi.Reply{
+			{Status: http.StatusAccepted, Description: "EOD mulai berjalan", Body: handlers.EODStartedResponse{}},
+			forbidden,
+			conflict,
+		},
+	},
+
+	// Rekonsiliasi
+	{
+		Method: http.MethodGet, Path: "/backoffice/reconciliation/runs", Tag: "reconciliation", Summary: "Riwayat run rekonsiliasi",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{limitQuery()},
+		Replies:  []openapi.Reply{ok([]models.ReconciliationRun{}), forbidden},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/reconciliation/runs", Tag: "reconciliation", Summary: "Jalankan rekonsiliasi",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{{Status: http.StatusAccepted, Description: "Rekonsiliasi mulai berjalan"}, forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/reconciliation/mismatches", Tag: "reconciliation", Summary: "Daftar selisih",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{statusQuery("Status selisih"), limitQuery()},
+		Replies:  []openapi.Reply{ok([]models.ReconciliationMismatch{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/reconciliation/mismatches/:id", Tag: "reconciliation", Summary: "Detail selisih",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{idParam},
+		Replies:  []openapi.Reply{ok(models.ReconciliationMismatch{}), forbidden, notFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/backoffice/reconciliation/mismatches/:id/corrections", Tag: "reconciliation", Summary: "Ajukan koreksi selisih",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{idParam},
+		Body:     models.CorrectionRequest{},
+		Replies:  []openapi.Reply{waitingApproval, forbidden, notFound, conflict},
+	},
+
+	// Buku besar
+	{
+		Method: http.MethodGet, Path: "/backoffice/gl/mapping", Tag: "gl", Summary: "Pemetaan jenis transaksi ke akun GL",
+		Security: tokenAuth,
+		Replies:  []openapi.Reply{ok(gl.Mapping{}), forbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/gl/vouchers", Tag: "gl", Summary: "Jurnal harian",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{tanggalQuery, openapi.Query("format", openapi.Enum(gl.FormatJSON, gl.FormatCSV), "Bawaan json")},
+		Replies: []openapi.Reply{
+			ok([]models.GLVoucher{}),
+			{Status: http.StatusOK, ContentType: "text/csv", Body: openapi.String()},
+			forbidden,
+			conflict,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/backoffice/gl/trial-balance", Tag: "gl", Summary: "Neraca saldo",
+		Security: tokenAuth,
+		Params:   []*openapi.Parameter{tanggalQuery},
+		Replies:  []openapi.Reply{ok(models.TrialBalance{}), forbidden, conflict},
+	},
+
+	// Webhook milik partner
+	{
+		Method: http.MethodPost, Path: "/partner/webhooks", Tag: "webhook", Summary: "Daftarkan subscription webhook",
+		Security: partnerAuth,
+		Body:     models.WebhookSubscriptionRequest{},
+		Replies:  []openapi.Reply{ok(models.WebhookSecretResponse{}), {Status: http.StatusForbidden, Description: "Rekening tidak terhubung dengan partner"}},
+	},
+	{
+		Method: http.MethodGet, Path: "/partner/webhooks", Tag: "webhook", Summary: "Daftar subscription webhook",
+		Security: partnerAuth,
+		Replies:  []openapi.Reply{ok([]models.WebhookSubscription{})},
+	},
+	{
+		Method: http.MethodPost, Path: "/partner/webhooks/:id/deactivate", Tag: "webhook", Summary: "Nonaktifkan subscription",
+		Security: partnerAuth,
+		Params:   []*openapi.Parameter{idParam},
+		Replies:  []openapi.Reply{ok(nil), notFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/partner/webhooks/:id/secret/rotate", Tag: "webhook", Summary: "Rotasi secret subscription",
+		Security: partnerAuth,
+		Params:   []*openapi.Parameter{idParam},
+		Replies:  []openapi.Reply{ok(models.WebhookSecretResponse{}), notFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/partner/webhooks/deliveries", Tag: "webhook", Summary: "Riwayat pengiriman webhook",
+		Security: partnerAuth,
+		Params:   []*openapi.Parameter{statusQuery("Status pengiriman"), limitQuery()},
+		Replies:  []openapi.Reply{ok([]models.WebhookDelivery{})},
+	},
+	{
+		Method: http.MethodGet, Path: "/partner/webhooks/deliveries/:id/attempts", Tag: "webhook", Summary: "Percobaan pengiriman webhook",
+		Security: partnerAuth,
+		Params:   []*openapi.Parameter{int64IDParam},
+		Replies:  []openapi.Reply{ok([]models.WebhookDeliveryAttempt{}), notFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/partner/webhooks/deliveries/:id/redeliver", Tag: "webhook", Summary: "Kirim ulang webhook",
+		Security: partnerAuth,
+		Params:   []*openapi.Parameter{int64IDParam},
+		Replies:  []openapi.Reply{{Status: http.StatusAccepted, Description: "Pengiriman dijadwalkan ulang"}, notFound},
+	},
+}
@@ -0,0 +1,184 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"golang-echo-postgresql/handlers"
+	"golang-echo-postgresql/openapi"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// committedSpec adalah dokumen OpenAPI yang di-commit, relatif terhadap package ini
+const committedSpec = "../docs/openapi.json"
+
+var update = flag.Bool("update", false, "tulis ulang docs/openapi.json dari Spec()")
+
+// stubRoutes mendaftarkan semua route dengan handler kosong; hanya method, path dan nama
+// handler yang dipakai
+func stubRoutes(t *testing.T, spec *openapi.Document) *echo.Echo {
+	t.Helper()
+	noop := func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+
+	e := echo.New()
+	RegisterRoutes(e, Dependencies{
+		Nasabah:       &handlers.NasabahHandler{},
+		Auth:          &handlers.AuthHandler{},
+		Backoffice:    &handlers.BackofficeHandler{},
+		Partner:       &handlers.PartnerHandler{},
+		Audit:         &handlers.AuditHandler{},
+		Webhook:       &handlers.WebhookHandler{},
+		Fraud:         &handlers.FraudHandler{},
+		AML:           &handlers.AMLHandler{},
+		Screening:     &handlers.ScreeningHandler{},
+		EOD:           &handlers.EODHandler{},
+		Reconcile:     &handlers.ReconciliationHandler{},
+		GL:            &handlers.GLHandler{},
+		Config:        &handlers.ConfigHandler{},
+		DB:            &handlers.DBHandler{},
+		GRPC:          &handlers.GRPCHandler{},
+		Health:        &handlers.HealthHandler{},
+		Authenticate:  noop,
+		VerifyPartner: noop,
+		EODGuard:      noop,
+		RateLimit:     noop,
+		Spec:          spec,
+	})
+	return e
+}
+
+// apiRoute mengembalikan route Echo yang bukan method bawaan router (misalnya HEAD, OPTIONS)
+func apiRoute(r *echo.Route) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func buildSpec(t *testing.T) *openapi.Document {
+	t.Helper()
+	spec, err := Spec()
+	if err != nil {
+		t.Fatalf("Spec: %v", err)
+	}
+	return spec
+}
+
+// TestSpecMatchesRoutes membandingkan route Echo dengan operasi di dokumen, ke dua arah
+func TestSpecMatchesRoutes(t *testing.T) {
+	spec := buildSpec(t)
+
+	registered := map[string]bool{}
+	for _, r := range stubRoutes(t, spec).Routes() {
+		if apiRoute(r) {
+			registered[r.Method+" "+r.Path] = true
+		}
+	}
+
+	documented := map[string]bool{}
+	for p, item := range spec.Paths {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+openapi.EchoPath(p)] = true
+		}
+	}
+
+	var problems []string
+	for route := range registered {
+		if !documented[route] {
+			problems = append(problems, "route not documented: "+route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			problems = append(problems, "documented operation has no route: "+route)
+		}
+	}
+	report(t, problems)
+}
+
+// TestSpecRefsResolve memastikan setiap $ref menunjuk ke schema yang ada di components
+func TestSpecRefsResolve(t *testing.T) {
+	spec := buildSpec(t)
+
+	var problems []string
+	seen := map[*openapi.Schema]bool{}
+	var walk func(where string, s *openapi.Schema)
+	walk = func(where string, s *openapi.Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		if s.Ref != "" {
+			if _, ok := spec.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]; !ok {
+				problems = append(problems, where+": unresolved "+s.Ref)
+			}
+		}
+		walk(where, s.Items)
+		walk(where, s.AdditionalProperties)
+		for _, prop := range s.Properties {
+			walk(where, prop)
+		}
+	}
+
+	for name, s := range spec.Components.Schemas {
+		walk("components."+name, s)
+	}
+	for p, item := range spec.Paths {
+		for method, op := range item {
+			where := strings.ToUpper(method) + " " + p
+			for _, param := range op.Parameters {
+				walk(where, param.Schema)
+			}
+			if op.RequestBody != nil {
+				for _, media := range op.RequestBody.Content {
+					walk(where, media.Schema)
+				}
+			}
+			for _, res := range op.Responses {
+				for _, media := range res.Content {
+					walk(where, media.Schema)
+				}
+			}
+		}
+	}
+	report(t, problems)
+}
+
+// TestOpenAPIDocumentUpToDate memastikan docs/openapi.json sama dengan hasil Spec().
+// Perbarui dengan: go test ./routes -run TestOpenAPIDocumentUpToDate -update
+func TestOpenAPIDocumentUpToDate(t *testing.T) {
+	generated, err := json.MarshalIndent(buildSpec(t), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated = append(generated, '\n')
+
+	if *update {
+		if err := os.WriteFile(committedSpec, generated, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	committed, err := os.ReadFile(committedSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(committed, generated) {
+		t.Fatal("docs/openapi.json is out of date, run go test ./routes -run TestOpenAPIDocumentUpToDate -update")
+	}
+}
+
+func report(t *testing.T, problems []string) {
+	t.Helper()
+	sort.Strings(problems)
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
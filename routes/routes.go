@@ -1,16 +1,161 @@
 package routes
 
 import (
+	"golang-echo-postgresql/apierror"
+	"golang-echo-postgresql/auth"
 	"golang-echo-postgresql/handlers"
+	"golang-echo-postgresql/openapi"
+	"golang-echo-postgresql/policy"
 
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterRoutes(e *echo.Echo, nasabahHandler *handlers.NasabahHandler) {
+// Dependencies berisi handler dan middleware yang dibutuhkan untuk mendaftarkan route
+type Dependencies struct {
+	Nasabah    *handlers.NasabahHandler
+	Auth       *handlers.AuthHandler
+	Backoffice *handlers.BackofficeHandler
+	Partner    *handlers.PartnerHandler
+	Audit      *handlers.AuditHandler
+	Webhook    *handlers.WebhookHandler
+	Fraud      *handlers.FraudHandler
+	AML        *handlers.AMLHandler
+	Screening  *handlers.ScreeningHandler
+	EOD        *handlers.EODHandler
+	Reconcile  *handlers.ReconciliationHandler
+	GL         *handlers.GLHandler
+	Config     *handlers.ConfigHandler
+	DB         *handlers.DBHandler
+	GRPC       *handlers.GRPCHandler
+	Health     *handlers.HealthHandler
+
+	Authenticate  echo.MiddlewareFunc
+	VerifyPartner echo.MiddlewareFunc
+	EODGuard      echo.MiddlewareFunc // menahan atau menolak transaksi selama EOD
+	RateLimit     echo.MiddlewareFunc // batas per principal dan partner, setelah autentikasi
+
+	// Spec adalah dokumen hasil Spec(), disajikan di /openapi.json dan /docs
+	Spec *openapi.Document
+}
+
+func RegisterRoutes(e *echo.Echo, deps Dependencies) {
+	authenticate := then(deps.Authenticate, deps.RateLimit)
+	verifyPartner := then(deps.VerifyPartner, deps.RateLimit)
+	eodGuard := deps.EODGuard
+
 	// Register the route to register a new nasabah
-	e.POST("/daftar", nasabahHandler.RegisterNasabah)
-	e.POST("/tabung", handlers.Tabung)
-	e.POST("/tarik", nasabahHandler.TarikDana)
-	e.GET("/saldo/:no_rekening", nasabahHandler.GetSaldo)
+	e.POST("/daftar", deps.Nasabah.RegisterNasabah)
+	e.POST("/tabung", deps.Nasabah.Tabung, verifyPartner, authenticate, eodGuard)
+	e.POST("/tarik", deps.Nasabah.TarikDana, authenticate, eodGuard)
+	e.POST("/transfer", deps.Nasabah.Transfer, authenticate, eodGuard)
+	e.GET("/saldo/:no_rekening", deps.Nasabah.GetSaldo, authenticate, auth.RequireRekeningAccess("no_rekening"))
+
+	// Autentikasi nasabah dan petugas
+	e.POST("/auth/nasabah/login", deps.Auth.LoginNasabah)
+	e.POST("/auth/staff/login", deps.Auth.LoginStaff)
+	e.POST("/auth/refresh", deps.Auth.RefreshToken)
+	e.POST("/auth/logout", deps.Auth.Logout, authenticate)
+	e.GET("/.well-known/jwks.json", deps.Auth.JWKS)
+	e.POST("/auth/keys/rotate", deps.Auth.RotateKeys, authenticate, auth.RequireRole(auth.RoleAdmin))
+
+	// Probe liveness dan readiness untuk orchestrator
+	e.GET(LivePath, deps.Health.Live)
+	e.GET(ReadyPath, deps.Health.Ready)
+
+	// Konfigurasi aktif beserta asal setiap nilai, secret disamarkan
+	e.GET("/config", deps.Config.Dump, authenticate, auth.RequireRole(auth.RoleAdmin))
+
+	// Status circuit breaker dan statistik pool koneksi database
+	e.GET("/db/stats", deps.DB.Stats, authenticate, auth.RequireRole(auth.RoleAdmin))
+
+	// Metrik RPC server gRPC
+	e.GET("/grpc/stats", deps.GRPC.Stats, authenticate, auth.RequireRole(auth.RoleAdmin))
+
+	// Dokumentasi API
+	e.GET(SpecPath, openapi.SpecHandler(deps.Spec))
+	e.GET(DocsPath, openapi.UIHandler("Bank Tabungan API", SpecPath))
+	e.GET(ErrorsPath, apierror.CatalogueHandler())
+
+	// Operasi back-office dengan maker-checker
+	backoffice := e.Group("/backoffice", authenticate)
+	backoffice.POST("/adjustments", deps.Backoffice.Adjust, policy.Require(policy.PermAdjust), eodGuard)
+	backoffice.POST("/reversals", deps.Backoffice.Reverse, policy.Require(policy.PermReverse), eodGuard)
+	backoffice.POST("/freezes", deps.Backoffice.Freeze, policy.Require(policy.PermFreeze))
+	backoffice.POST("/unfreezes", deps.Backoffice.Unfreeze, policy.Require(policy.PermUnfreeze))
+	backoffice.GET("/approvals", deps.Backoffice.ListApprovals, policy.Require(policy.PermApprovalRead))
+	backoffice.POST("/approvals/:id/approve", deps.Backoffice.Approve, policy.Require(policy.PermApprove), eodGuard)
+	backoffice.POST("/approvals/:id/reject", deps.Backoffice.Reject, policy.Require(policy.PermApprove))
+	backoffice.POST("/staff", deps.Backoffice.CreateStaff, policy.Require(policy.PermManageStaff))
+
+	// Kredensial partner untuk request bertanda tangan HMAC
+	backoffice.POST("/partners", deps.Partner.CreatePartner, policy.Require(policy.PermManagePartners))
+	backoffice.POST("/partners/:client_id/secrets/rotate", deps.Partner.RotateSecret, policy.Require(policy.PermManagePartners))
+	backoffice.GET("/partners/:client_id/rekening", deps.Partner.ListRekening, policy.Require(policy.PermManagePartners))
+	backoffice.POST("/partners/:client_id/rekening", deps.Partner.LinkRekening, policy.Require(policy.PermManagePartners))
+	backoffice.POST("/partners/:client_id/rekening/:no_rekening/unlink", deps.Partner.UnlinkRekening, policy.Require(policy.PermManagePartners))
+
+	// Dead-letter queue webhook
+	backoffice.GET("/webhooks/dead-letters", deps.Webhook.ListDeadLetters, policy.Require(policy.PermManagePartners))
+	backoffice.POST("/webhooks/deliveries/:id/redeliver", deps.Webhook.RedeliverAny, policy.Require(policy.PermManagePartners))
+
+	// Log audit
+	backoffice.GET("/audit", deps.Audit.SearchEvents, policy.Require(policy.PermAuditRead))
+	backoffice.GET("/audit/verify", deps.Audit.VerifyChain, policy.Require(policy.PermAuditRead))
+
+	// Review analis atas transaksi yang ditandai aturan fraud
+	backoffice.GET("/fraud/rules", deps.Fraud.Rules, policy.Require(policy.PermFraudRead))
+	backoffice.GET("/fraud/decisions", deps.Fraud.ListFlagged, policy.Require(policy.PermFraudRead))
+	backoffice.GET("/fraud/decisions/:id", deps.Fraud.GetDecision, policy.Require(policy.PermFraudRead))
+	backoffice.POST("/fraud/decisions/:id/review", deps.Fraud.Review, policy.Require(policy.PermFraudReview))
+
+	// Pelaporan PPATK dan manajemen kasus AML
+	backoffice.GET("/aml/ltkt", deps.AML.ListLTKT, policy.Require(policy.PermAMLRead))
+	backoffice.GET("/aml/exports", deps.AML.ListExports, policy.Require(policy.PermAMLRead))
+	backoffice.POST("/aml/runs", deps.AML.Run, policy.Require(policy.PermAMLManage))
+	backoffice.GET("/aml/cases", deps.AML.ListCases, policy.Require(policy.PermAMLRead))
+	backoffice.GET("/aml/cases/:id", deps.AML.GetCase, policy.Require(policy.PermAMLRead))
+	backoffice.POST("/aml/cases", deps.AML.OpenCase, policy.Require(policy.PermAMLManage))
+	backoffice.POST("/aml/cases/:id/transition", deps.AML.TransitionCase, policy.Require(policy.PermAMLManage))
+
+	// Screening watchlist: review hit oleh compliance dan impor versi watchlist
+	backoffice.GET("/screening/hits", deps.Screening.ListHits, policy.Require(policy.PermScreeningRead))
+	backoffice.POST("/screening/hits/:id/clear", deps.Screening.Clear, policy.Require(policy.PermScreeningReview))
+	backoffice.POST("/screening/hits/:id/confirm", deps.Screening.Confirm, policy.Require(policy.PermScreeningReview))
+	backoffice.GET("/screening/watchlists", deps.Screening.ListWatchlists, policy.Require(policy.PermScreeningRead))
+	backoffice.POST("/screening/watchlists/:kode/import", deps.Screening.Import, policy.Require(policy.PermWatchlistImport))
+
+	// Proses tutup hari (EOD)
+	backoffice.GET("/eod/status", deps.EOD.Status, policy.Require(policy.PermEODRead))
+	backoffice.GET("/eod/runs/:tanggal", deps.EOD.GetRun, policy.Require(policy.PermEODRead))
+	backoffice.POST("/eod/runs", deps.EOD.Run, policy.Require(policy.PermEODRun))
+
+	// Rekonsiliasi saldo terhadap riwayat transaksi dan koreksi selisih lewat maker-checker
+	backoffice.GET("/reconciliation/runs", deps.Reconcile.ListRuns, policy.Require(policy.PermReconcileRead))
+	backoffice.POST("/reconciliation/runs", deps.Reconcile.Run, policy.Require(policy.PermReconcileRun))
+	backoffice.GET("/reconciliation/mismatches", deps.Reconcile.ListMismatches, policy.Require(policy.PermReconcileRead))
+	backoffice.GET("/reconciliation/mismatches/:id", deps.Reconcile.GetMismatch, policy.Require(policy.PermReconcileRead))
+	backoffice.POST("/reconciliation/mismatches/:id/corrections", deps.Reconcile.SubmitCorrection, policy.Require(policy.PermReconcileCorrect))
+
+	// Jurnal buku besar harian dan neraca saldo untuk akuntansi
+	backoffice.GET("/gl/mapping", deps.GL.Mapping, policy.Require(policy.PermGLRead))
+	backoffice.GET("/gl/vouchers", deps.GL.Vouchers, policy.Require(policy.PermGLRead))
+	backoffice.GET("/gl/trial-balance", deps.GL.TrialBalance, policy.Require(policy.PermGLRead))
+
+	// Subscription webhook milik partner (request bertanda tangan HMAC)
+	webhooks := e.Group("/partner/webhooks", verifyPartner, auth.RequireSubject(auth.SubjectPartner))
+	webhooks.POST("", deps.Webhook.Subscribe)
+	webhooks.GET("", deps.Webhook.ListSubscriptions)
+	webhooks.POST("/:id/deactivate", deps.Webhook.Deactivate)
+	webhooks.POST("/:id/secret/rotate", deps.Webhook.RotateSecret)
+	webhooks.GET("/deliveries", deps.Webhook.ListDeliveries)
+	webhooks.GET("/deliveries/:id/attempts", deps.Webhook.ListAttempts)
+	webhooks.POST("/deliveries/:id/redeliver", deps.Webhook.Redeliver)
+}
 
+// then menjalankan second tepat setelah first, misalnya rate limit per principal setelah
+// autentikasi menentukan principal-nya
+func then(first, second echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return first(second(next))
+	}
 }
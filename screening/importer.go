@@ -0,0 +1,125 @@
+package screening
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"io"
+	"strings"
+)
+
+// Format file watchlist yang didukung
+const (
+	FormatCSV = "csv"
+	FormatXML = "xml"
+)
+
+var ErrInvalidFile = errors.New("file watchlist tidak valid")
+
+// ParseCSV membaca watchlist CSV dengan baris header. Kolom yang dikenali: nama (wajib),
+// alias (dipisah ";"), nik, tanggal_lahir dan keterangan; kolom lain diabaikan.
+func ParseCSV(r io.Reader) ([]models.WatchlistEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: header tidak terbaca: %v", ErrInvalidFile, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["nama"]; !ok {
+		return nil, fmt.Errorf("%w: kolom nama tidak ada", ErrInvalidFile)
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []models.WatchlistEntry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: baris %d: %v", ErrInvalidFile, line, err)
+		}
+		entry := models.WatchlistEntry{
+			Nama:         field(record, "nama"),
+			Alias:        splitAlias(field(record, "alias")),
+			NIK:          field(record, "nik"),
+			TanggalLahir: field(record, "tanggal_lahir"),
+			Keterangan:   field(record, "keterangan"),
+		}
+		if entry.Nama == "" {
+			return nil, fmt.Errorf("%w: baris %d: nama kosong", ErrInvalidFile, line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+type xmlWatchlist struct {
+	XMLName xml.Name   `xml:"watchlist"`
+	Entries []xmlEntry `xml:"entry"`
+}
+
+type xmlEntry struct {
+	Nama         string   `xml:"nama"`
+	Alias        []string `xml:"alias"`
+	NIK          string   `xml:"nik"`
+	TanggalLahir string   `xml:"tanggal_lahir"`
+	Keterangan   string   `xml:"keterangan"`
+}
+
+// ParseXML membaca watchlist XML:
+//
+//	<watchlist>
+//	  <entry><nama>...</nama><alias>...</alias><nik>...</nik><tanggal_lahir>...</tanggal_lahir><keterangan>...</keterangan></entry>
+//	</watchlist>
+func ParseXML(r io.Reader) ([]models.WatchlistEntry, error) {
+	var doc xmlWatchlist
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	entries := make([]models.WatchlistEntry, 0, len(doc.Entries))
+	for i, e := range doc.Entries {
+		entry := models.WatchlistEntry{
+			Nama:         strings.TrimSpace(e.Nama),
+			NIK:          strings.TrimSpace(e.NIK),
+			TanggalLahir: strings.TrimSpace(e.TanggalLahir),
+			Keterangan:   strings.TrimSpace(e.Keterangan),
+		}
+		for _, alias := range e.Alias {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				entry.Alias = append(entry.Alias, alias)
+			}
+		}
+		if entry.Nama == "" {
+			return nil, fmt.Errorf("%w: entri %d: nama kosong", ErrInvalidFile, i+1)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func splitAlias(s string) []string {
+	var aliases []string
+	for _, alias := range strings.Split(s, ";") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
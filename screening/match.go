@@ -0,0 +1,108 @@
+package screening
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// honorifics adalah gelar yang sering ditulis berbeda-beda dan tidak membedakan orang
+var honorifics = map[string]bool{
+	"H": true, "HJ": true, "HAJI": true, "HAJJAH": true, "DR": true, "DRS": true, "DRA": true, "IR": true,
+	"PROF": true, "SH": true, "SE": true, "ST": true, "SPD": true, "MM": true, "MBA": true, "ALM": true,
+	"MR": true, "MRS": true, "MS": true,
+}
+
+// Normalize menyeragamkan nama untuk pencocokan: huruf besar, tanpa tanda baca,
+// tanpa gelar, dan spasi tunggal
+func Normalize(nama string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			return unicode.ToUpper(r)
+		case r == '\'' || r == '`':
+			return -1
+		}
+		return ' '
+	}, nama)
+
+	var tokens []string
+	for _, token := range strings.Fields(cleaned) {
+		if !honorifics[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// Similarity mengembalikan skor 0..1 antara dua nama yang sudah dinormalisasi. Skor
+// adalah nilai Jaro-Winkler tertinggi antara urutan asli dan urutan token yang
+// diurutkan, sehingga "AHMAD BIN SALIM" cocok dengan "SALIM AHMAD BIN".
+func Similarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+	return max(JaroWinkler(a, b), JaroWinkler(sortTokens(a), sortTokens(b)))
+}
+
+// JaroWinkler menghitung kemiripan Jaro-Winkler antara dua string
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+func sortTokens(s string) string {
+	tokens := strings.Fields(s)
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
@@ -0,0 +1,221 @@
+package screening
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/repositories"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrHitNotFound    = errors.New("hit screening tidak ditemukan")
+	ErrHitNotPending  = errors.New("hit screening sudah direview")
+	ErrNoteRequired   = errors.New("catatan review wajib diisi")
+	ErrInvalidFormat  = errors.New("format watchlist harus csv atau xml")
+	ErrInvalidKode    = errors.New("kode watchlist tidak valid")
+	ErrEmptyWatchlist = errors.New("watchlist tidak berisi entri")
+)
+
+var kodePattern = regexp.MustCompile(`^[a-z0-9_-]{2,32}$`)
+
+// Hits mengambil hit screening, opsional difilter status
+func (s *Service) Hits(status string, limit int) ([]models.ScreeningHit, error) {
+	return repositories.ListScreeningHits(s.DB, status, limit)
+}
+
+// Watchlists mengambil semua watchlist beserta riwayat versinya
+func (s *Service) Watchlists() ([]models.Watchlist, error) {
+	return repositories.ListWatchlists(s.DB)
+}
+
+// Clear menandai hit sebagai bukan orang yang sama. Jika tidak ada hit pending lain
+// untuk nasabah tersebut, rekening diaktifkan kembali.
+func (s *Service) Clear(reviewer *auth.Principal, id int, catatan string) (*models.ScreeningHit, error) {
+	return s.decide(reviewer, id, catatan, models.HitCleared)
+}
+
+// Confirm menandai hit sebagai kecocokan benar; rekening dibekukan dan tetap beku
+// sampai dibuka lewat prosedur unfreeze backoffice
+func (s *Service) Confirm(reviewer *auth.Principal, id int, catatan string) (*models.ScreeningHit, error) {
+	return s.decide(reviewer, id, catatan, models.HitConfirmed)
+}
+
+func (s *Service) decide(reviewer *auth.Principal, id int, catatan, status string) (*models.ScreeningHit, error) {
+	catatan = strings.TrimSpace(catatan)
+	if catatan == "" {
+		return nil, ErrNoteRequired
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	hit, err := repositories.GetScreeningHitForUpdate(tx, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrHitNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if hit.Status != models.HitPending {
+		return nil, ErrHitNotPending
+	}
+
+	now := time.Now()
+	hit.Status = status
+	hit.ReviewedBy = &reviewer.ID
+	hit.ReviewedAt = &now
+	hit.Catatan = catatan
+	if err := repositories.UpdateScreeningHitDecision(tx, hit); err != nil {
+		return nil, err
+	}
+
+	if status == models.HitConfirmed {
+		err = s.freeze(tx, hit)
+	} else {
+		err = s.release(tx, hit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"HitID":      hit.ID,
+		"NoRekening": hit.NoRekening,
+		"status":     status,
+		"ReviewerID": reviewer.ID,
+	}).Info("Screening hit reviewed")
+
+	return hit, nil
+}
+
+func (s *Service) freeze(tx *sql.Tx, hit *models.ScreeningHit) error {
+	if err := repositories.UpdateStatusRekening(tx, hit.NoRekening, models.StatusBeku); err != nil {
+		return err
+	}
+	return outbox.Enqueue(tx, outbox.AccountStatusChangedV1{
+		NoRekening: hit.NoRekening,
+		Status:     models.StatusBeku,
+		Alasan:     "Watchlist match confirmed: " + hit.Watchlist,
+		OccurredAt: time.Now(),
+	})
+}
+
+// release mengaktifkan rekening setelah hit pending terakhir di-clear. Nasabah baru
+// yang tertahan saat registrasi baru mendapat event AccountOpened di sini.
+func (s *Service) release(tx *sql.Tx, hit *models.ScreeningHit) error {
+	pending, err := repositories.CountPendingScreeningHits(tx, hit.NasabahID)
+	if err != nil || pending > 0 {
+		return err
+	}
+	activated, err := repositories.ActivatePendingNasabah(tx, hit.NasabahID)
+	if err != nil || !activated {
+		return err
+	}
+
+	if hit.Konteks == models.ScreeningOnboarding {
+		return outbox.Enqueue(tx, outbox.AccountOpenedV1{
+			NoRekening: hit.NoRekening,
+			Nama:       hit.NamaSubjek,
+			OpenedAt:   time.Now(),
+		})
+	}
+	return outbox.Enqueue(tx, outbox.AccountStatusChangedV1{
+		NoRekening: hit.NoRekening,
+		Status:     models.StatusAktif,
+		Alasan:     "Watchlist hits cleared",
+		OccurredAt: time.Now(),
+	})
+}
+
+// Import membaca file watchlist lalu menyimpannya sebagai versi baru yang langsung
+// aktif. Versi lama tetap disimpan sehingga hit lama masih bisa ditelusuri ke entri
+// yang memicunya. importer boleh nil untuk impor lewat command line.
+func (s *Service) Import(importer *auth.Principal, kode, format string, data []byte) (*models.WatchlistVersion, error) {
+	kode = strings.ToLower(strings.TrimSpace(kode))
+	if !kodePattern.MatchString(kode) {
+		return nil, ErrInvalidKode
+	}
+
+	var entries []models.WatchlistEntry
+	var err error
+	switch format {
+	case FormatCSV:
+		entries, err = ParseCSV(bytes.NewReader(data))
+	case FormatXML:
+		entries, err = ParseXML(bytes.NewReader(data))
+	default:
+		return nil, ErrInvalidFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrEmptyWatchlist
+	}
+
+	sum := sha256.Sum256(data)
+	version := &models.WatchlistVersion{
+		Format:      format,
+		Checksum:    hex.EncodeToString(sum[:]),
+		JumlahEntri: len(entries),
+		Aktif:       true,
+	}
+	if importer != nil {
+		version.ImportedBy = &importer.ID
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	watchlist, err := repositories.LockWatchlist(tx, kode)
+	if err != nil {
+		return nil, err
+	}
+	version.WatchlistID = watchlist.ID
+	if err := repositories.InsertWatchlistVersion(tx, version); err != nil {
+		return nil, err
+	}
+	if err := repositories.InsertWatchlistEntries(tx, version.ID, entries); err != nil {
+		return nil, err
+	}
+	if err := repositories.ActivateWatchlistVersion(tx, watchlist.ID, version.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"watchlist": kode,
+		"versi":     version.Versi,
+		"entries":   version.JumlahEntri,
+		"checksum":  version.Checksum,
+	}).Info("Watchlist imported")
+
+	if err := s.Refresh(); err != nil {
+		log.WithFields(log.Fields{
+			"error": err,
+		}).Error("Failed to reload watchlist entries after import")
+	}
+	return version, nil
+}
@@ -0,0 +1,193 @@
+package screening
+
+import (
+	"context"
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/repositories"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Match adalah entri watchlist yang cocok dengan nama yang di-screening
+type Match struct {
+	Entry models.WatchlistEntry `json:"entry"`
+	Skor  float64               `json:"skor"`
+}
+
+type indexedEntry struct {
+	entry models.WatchlistEntry
+	names []string
+}
+
+// Service mencocokkan nama nasabah dengan entri aktif semua watchlist. Entri disimpan
+// di memori dan dimuat ulang setiap kali versi aktif berubah, termasuk impor dari
+// instance lain yang terdeteksi oleh Run.
+type Service struct {
+	DB              *sql.DB
+	Threshold       float64
+	RefreshInterval time.Duration
+
+	mu       sync.RWMutex
+	entries  []indexedEntry
+	versions []int64
+}
+
+// NewService membuat Service dan memuat entri watchlist aktif
+func NewService(db *sql.DB, threshold float64, refreshInterval time.Duration) (*Service, error) {
+	s := &Service{DB: db, Threshold: threshold, RefreshInterval: refreshInterval}
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Refresh memuat ulang entri watchlist aktif dari database
+func (s *Service) Refresh() error {
+	versions, err := repositories.GetActiveWatchlistVersionIDs(s.DB)
+	if err != nil {
+		return err
+	}
+	entries, err := repositories.ListActiveWatchlistEntries(s.DB)
+	if err != nil {
+		return err
+	}
+
+	indexed := make([]indexedEntry, 0, len(entries))
+	for _, e := range entries {
+		ie := indexedEntry{entry: e}
+		for _, name := range append([]string{e.Nama}, e.Alias...) {
+			if n := Normalize(name); n != "" {
+				ie.names = append(ie.names, n)
+			}
+		}
+		indexed = append(indexed, ie)
+	}
+
+	s.mu.Lock()
+	s.entries = indexed
+	s.versions = versions
+	s.mu.Unlock()
+
+	log.WithFields(log.Fields{
+		"entries":  len(indexed),
+		"versions": versions,
+	}).Info("Watchlist entries loaded")
+	return nil
+}
+
+// Run memeriksa perubahan versi aktif secara berkala sampai ctx selesai
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			versions, err := repositories.GetActiveWatchlistVersionIDs(s.DB)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error": err,
+				}).Error("Failed to check watchlist versions")
+				continue
+			}
+			s.mu.RLock()
+			changed := !slices.Equal(versions, s.versions)
+			s.mu.RUnlock()
+			if !changed {
+				continue
+			}
+			if err := s.Refresh(); err != nil {
+				log.WithFields(log.Fields{
+					"error": err,
+				}).Error("Failed to reload watchlist entries, keeping previous entries")
+			}
+		}
+	}
+}
+
+// Screen mencari entri watchlist yang cocok dengan nama atau NIK. NIK yang sama
+// persis selalu dianggap cocok; nama dianggap cocok jika skor kemiripan nama atau
+// salah satu alias mencapai Threshold. Hasil diurutkan dari skor tertinggi.
+func (s *Service) Screen(nama, nik string) []Match {
+	normalized := Normalize(nama)
+	nik = strings.TrimSpace(nik)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matches []Match
+	for _, ie := range s.entries {
+		skor := 0.0
+		if nik != "" && ie.entry.NIK == nik {
+			skor = 1
+		} else {
+			for _, name := range ie.names {
+				skor = max(skor, Similarity(normalized, name))
+			}
+		}
+		if skor >= s.Threshold {
+			matches = append(matches, Match{Entry: ie.entry, Skor: skor})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Skor > matches[j].Skor })
+	return matches
+}
+
+// ScreenNasabah men-screening nasabah di dalam transaksi pemanggil. Setiap kecocokan
+// dicatat sebagai hit pending dan nasabah dipindahkan ke status pending_review
+// sampai compliance me-review semua hit-nya.
+func (s *Service) ScreenNasabah(tx *sql.Tx, nasabah *models.Nasabah, konteks string) ([]models.ScreeningHit, error) {
+	matches := s.Screen(nasabah.Nama, nasabah.NIK)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	hits := make([]models.ScreeningHit, 0, len(matches))
+	for _, m := range matches {
+		hit := models.ScreeningHit{
+			NasabahID:  nasabah.ID,
+			NoRekening: nasabah.NoRekening,
+			Konteks:    konteks,
+			NamaSubjek: nasabah.Nama,
+			EntryID:    m.Entry.ID,
+			Watchlist:  m.Entry.Watchlist,
+			Versi:      m.Entry.Versi,
+			NamaEntri:  m.Entry.Nama,
+			Skor:       m.Skor,
+		}
+		if err := repositories.InsertScreeningHit(tx, &hit); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+
+	if nasabah.Status != models.StatusBeku {
+		if err := repositories.UpdateStatusRekening(tx, nasabah.NoRekening, models.StatusPendingReview); err != nil {
+			return nil, err
+		}
+		nasabah.Status = models.StatusPendingReview
+	}
+
+	log.WithFields(log.Fields{
+		"NoRekening": nasabah.NoRekening,
+		"konteks":    konteks,
+		"hits":       len(hits),
+		"skor":       hits[0].Skor,
+	}).Warn("Watchlist screening hit, account held for review")
+
+	return hits, nil
+}
+
+// ScreenCounterparty men-screening lawan transaksi transfer. Hasilnya sama dengan
+// ScreenNasabah dengan konteks transfer; transfer harus ditolak jika ada hit.
+func (s *Service) ScreenCounterparty(tx *sql.Tx, counterparty *models.Nasabah) ([]models.ScreeningHit, error) {
+	return s.ScreenNasabah(tx, counterparty, models.ScreeningTransfer)
+}
@@ -0,0 +1,360 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"sync"
+	"time"
+)
+
+// defaultLockTimeout berperan seperti lock_timeout Postgres: unit of work yang saling
+// menunggu (deadlock) dibatalkan alih-alih menggantung selamanya
+const defaultLockTimeout = 5 * time.Second
+
+// Memory adalah Store in-memory yang aman dipakai bersamaan. Semantiknya mengikuti
+// implementasi Postgres: pembacaan melihat data yang sudah di-commit (read committed)
+// ditambah perubahan unit of work sendiri, Lock dan UpdateSaldo mengunci rekening
+// sampai unit of work selesai, dan perubahan baru terlihat setelah commit.
+type Memory struct {
+	LockTimeout time.Duration
+
+	mu       sync.Mutex
+	nextID   int
+	nextTxID int
+	nasabah  map[string]memNasabah // berdasarkan no_rekening
+	tabungan []models.Tabungan
+	events   []outbox.Message
+	rowLocks map[string]*rowLock
+	lockMu   sync.Mutex
+}
+
+// rowLock adalah kunci satu rekening. refs menghitung unit of work yang memegang atau
+// menunggu kunci; entri dihapus saat refs kembali nol agar rowLocks tidak terus tumbuh
+// oleh rekening yang pernah dicoba, termasuk yang tidak ada.
+type rowLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+type memNasabah struct {
+	models.Nasabah
+	pinHash string
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		LockTimeout: defaultLockTimeout,
+		nasabah:     map[string]memNasabah{},
+		rowLocks:    map[string]*rowLock{},
+	}
+}
+
+// Events mengembalikan event domain yang sudah di-commit, berurutan
+func (m *Memory) Events() []outbox.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]outbox.Message(nil), m.events...)
+}
+
+func (m *Memory) Do(ctx context.Context, fn func(uow UnitOfWork) error) error {
+	u := &memUnit{
+		m:       m,
+		ctx:     ctx,
+		held:    map[string]bool{},
+		staged:  map[string]memNasabah{},
+		created: map[string]bool{},
+	}
+	defer u.release()
+
+	if err := fn(u); err != nil {
+		return err
+	}
+	return u.commit()
+}
+
+// memUnit menampung perubahan satu unit of work sampai commit
+type memUnit struct {
+	m        *Memory
+	ctx      context.Context
+	held     map[string]bool
+	staged   map[string]memNasabah
+	created  map[string]bool
+	tabungan []models.Tabungan
+	events   []outbox.Message
+}
+
+func (u *memUnit) Customers() CustomerRepository       { return u }
+func (u *memUnit) Accounts() AccountRepository         { return u }
+func (u *memUnit) Transactions() TransactionRepository { return u }
+func (u *memUnit) SQL() *sql.Tx                        { return nil }
+func (u *memUnit) Context() context.Context            { return u.ctx }
+
+func (u *memUnit) Enqueue(msg outbox.Message) error {
+	u.events = append(u.events, msg)
+	return nil
+}
+
+// lock mengunci rekening untuk unit of work ini; kunci yang sama boleh diminta berulang
+func (u *memUnit) lock(noRekening string) error {
+	if u.held[noRekening] {
+		return nil
+	}
+
+	u.m.lockMu.Lock()
+	l, ok := u.m.rowLocks[noRekening]
+	if !ok {
+		l = &rowLock{ch: make(chan struct{}, 1)}
+		u.m.rowLocks[noRekening] = l
+	}
+	l.refs++
+	u.m.lockMu.Unlock()
+
+	timer := time.NewTimer(u.m.LockTimeout)
+	defer timer.Stop()
+	var err error
+	select {
+	case l.ch <- struct{}{}:
+		u.held[noRekening] = true
+		return nil
+	case <-u.ctx.Done():
+		err = u.ctx.Err()
+	case <-timer.C:
+		err = ErrLockTimeout
+	}
+
+	u.m.lockMu.Lock()
+	u.m.unref(noRekening, l)
+	u.m.lockMu.Unlock()
+	return err
+}
+
+func (u *memUnit) release() {
+	u.m.lockMu.Lock()
+	defer u.m.lockMu.Unlock()
+	for noRekening := range u.held {
+		l := u.m.rowLocks[noRekening]
+		<-l.ch
+		u.m.unref(noRekening, l)
+	}
+	u.held = map[string]bool{}
+}
+
+// unref melepas satu referensi kunci rekening; m.lockMu harus dipegang
+func (m *Memory) unref(noRekening string, l *rowLock) {
+	l.refs--
+	if l.refs == 0 {
+		delete(m.rowLocks, noRekening)
+	}
+}
+
+// current mengembalikan salinan nasabah dari perubahan unit of work ini atau data yang sudah di-commit
+func (u *memUnit) current(noRekening string) (memNasabah, bool) {
+	if n, ok := u.staged[noRekening]; ok {
+		return n, true
+	}
+	u.m.mu.Lock()
+	defer u.m.mu.Unlock()
+	n, ok := u.m.nasabah[noRekening]
+	return n, ok
+}
+
+func (u *memUnit) commit() error {
+	u.m.mu.Lock()
+	defer u.m.mu.Unlock()
+
+	// Pemeriksaan ulang seperti unique constraint: unit of work lain bisa saja
+	// mendaftarkan NIK atau No HP yang sama setelah CheckExisting
+	for noRekening := range u.created {
+		n := u.staged[noRekening]
+		if _, ok := u.m.nasabah[noRekening]; ok || len(u.m.duplicates(n.NIK, n.NoHP)) > 0 {
+			return ErrDuplicate
+		}
+	}
+
+	for noRekening, n := range u.staged {
+		u.m.nasabah[noRekening] = n
+	}
+	u.m.tabungan = append(u.m.tabungan, u.tabungan...)
+	u.m.events = append(u.m.events, u.events...)
+	return nil
+}
+
+// duplicates mencari NIK dan No HP di data yang sudah di-commit; m.mu harus dipegang
+func (m *Memory) duplicates(nik, noHP string) []string {
+	var fields []string
+	var nikUsed, noHPUsed bool
+	for _, n := range m.nasabah {
+		nikUsed = nikUsed || n.NIK == nik
+		noHPUsed = noHPUsed || n.NoHP == noHP
+	}
+	if nikUsed {
+		fields = append(fields, "NIK")
+	}
+	if noHPUsed {
+		fields = append(fields, "No HP")
+	}
+	return fields
+}
+
+func (u *memUnit) CheckExisting(nik, noHP string) ([]string, error) {
+	u.m.mu.Lock()
+	fields := u.m.duplicates(nik, noHP)
+	u.m.mu.Unlock()
+
+	for noRekening := range u.created {
+		n := u.staged[noRekening]
+		if n.NIK == nik && !contains(fields, "NIK") {
+			fields = append(fields, "NIK")
+		}
+		if n.NoHP == noHP && !contains(fields, "No HP") {
+			fields = append(fields, "No HP")
+		}
+	}
+	return fields, nil
+}
+
+func (u *memUnit) Create(nasabah *models.Nasabah, pinHash string) error {
+	if err := u.lock(nasabah.NoRekening); err != nil {
+		return err
+	}
+	if _, ok := u.current(nasabah.NoRekening); ok {
+		return ErrDuplicate
+	}
+	fields, _ := u.CheckExisting(nasabah.NIK, nasabah.NoHP)
+	if len(fields) > 0 {
+		return ErrDuplicate
+	}
+
+	u.m.mu.Lock()
+	u.m.nextID++
+	nasabah.ID = u.m.nextID
+	u.m.mu.Unlock()
+
+	n := memNasabah{Nasabah: *nasabah, pinHash: pinHash}
+	n.Saldo = 0
+	n.Status = models.StatusAktif
+	u.staged[nasabah.NoRekening] = n
+	u.created[nasabah.NoRekening] = true
+	return nil
+}
+
+func (u *memUnit) GetByNoRekening(noRekening string) (*models.Nasabah, error) {
+	n, ok := u.current(noRekening)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	nasabah := n.Nasabah
+	return &nasabah, nil
+}
+
+func (u *memUnit) UpdateStatus(noRekening, status string) error {
+	if err := u.lock(noRekening); err != nil {
+		return err
+	}
+	n, ok := u.current(noRekening)
+	if !ok {
+		return ErrNotFound
+	}
+	n.Status = status
+	u.staged[noRekening] = n
+	return nil
+}
+
+func (u *memUnit) GetCredentials(noRekening string) (int, string, error) {
+	n, ok := u.current(noRekening)
+	if !ok {
+		return 0, "", ErrNotFound
+	}
+	return n.ID, n.pinHash, nil
+}
+
+func (u *memUnit) GetNoRekening(nasabahID int) (string, error) {
+	for noRekening, n := range u.staged {
+		if n.ID == nasabahID {
+			return noRekening, nil
+		}
+	}
+	u.m.mu.Lock()
+	defer u.m.mu.Unlock()
+	for noRekening, n := range u.m.nasabah {
+		if n.ID == nasabahID {
+			return noRekening, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+func (u *memUnit) GetSaldo(noRekening string) (float64, error) {
+	n, ok := u.current(noRekening)
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return n.Saldo, nil
+}
+
+func (u *memUnit) Lock(noRekening string) (*models.Nasabah, error) {
+	if err := u.lock(noRekening); err != nil {
+		return nil, err
+	}
+	return u.GetByNoRekening(noRekening)
+}
+
+func (u *memUnit) UpdateSaldo(noRekening, jenisTransaksi string, nominal float64) error {
+	if err := u.lock(noRekening); err != nil {
+		return err
+	}
+	n, ok := u.current(noRekening)
+	if !ok {
+		return ErrNotFound
+	}
+	if models.IsKredit(jenisTransaksi) {
+		n.Saldo += nominal
+	} else {
+		if n.Saldo < nominal {
+			return ErrSaldoTidakCukup
+		}
+		n.Saldo -= nominal
+	}
+	u.staged[noRekening] = n
+	return nil
+}
+
+func (u *memUnit) Insert(t *models.Tabungan) error {
+	u.m.mu.Lock()
+	u.m.nextTxID++
+	t.ID = u.m.nextTxID
+	u.m.mu.Unlock()
+
+	t.CreatedAt = time.Now()
+	u.tabungan = append(u.tabungan, *t)
+	return nil
+}
+
+func (u *memUnit) ListByNasabah(nasabahID int) ([]models.Tabungan, error) {
+	var riwayat []models.Tabungan
+	u.m.mu.Lock()
+	for _, t := range u.m.tabungan {
+		if t.NasabahID == nasabahID {
+			riwayat = append(riwayat, t)
+		}
+	}
+	u.m.mu.Unlock()
+
+	for _, t := range u.tabungan {
+		if t.NasabahID == nasabahID {
+			riwayat = append(riwayat, t)
+		}
+	}
+	return riwayat, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
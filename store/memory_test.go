@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryRowLocksAreReleased(t *testing.T) {
+	m := NewMemory()
+	m.LockTimeout = 10 * time.Millisecond
+	ctx := context.Background()
+
+	// Rekening yang tidak ada tetap dikunci dulu seperti SELECT ... FOR UPDATE
+	for _, noRekening := range []string{"1000000001", "1000000002", "1000000003"} {
+		err := m.Do(ctx, func(uow UnitOfWork) error {
+			_, err := uow.Accounts().Lock(noRekening)
+			return err
+		})
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Lock(%s) = %v, want ErrNotFound", noRekening, err)
+		}
+	}
+
+	// Unit of work yang gagal menunggu kunci juga tidak meninggalkan entri
+	err := m.Do(ctx, func(holder UnitOfWork) error {
+		holder.Accounts().Lock("1000000001")
+		return m.Do(ctx, func(waiter UnitOfWork) error {
+			_, err := waiter.Accounts().Lock("1000000001")
+			return err
+		})
+	})
+	if !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("contended Lock = %v, want ErrLockTimeout", err)
+	}
+
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+	if len(m.rowLocks) != 0 {
+		t.Errorf("rowLocks has %d entries after all units finished, want 0", len(m.rowLocks))
+	}
+}
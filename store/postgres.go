@@ -0,0 +1,175 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/repositories"
+	"golang-echo-postgresql/tracing"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Postgres adalah Store di atas fungsi-fungsi package repositories
+type Postgres struct {
+	DB *sql.DB
+}
+
+func NewPostgres(db *sql.DB) *Postgres {
+	return &Postgres{DB: db}
+}
+
+func (p *Postgres) Do(ctx context.Context, fn func(uow UnitOfWork) error) (err error) {
+	// Span unit of work mencakup seluruh waktu rekening terkunci, sampai commit
+	ctx, span := tracing.Child(ctx, "store.UnitOfWork")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := p.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&pgUnit{ctx: ctx, tx: tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Tx membungkus transaksi yang sudah berjalan sebagai UnitOfWork, misalnya transaksi
+// milik antrean persetujuan. Commit dan rollback tetap menjadi tanggung jawab pemanggil.
+func Tx(tx *sql.Tx) UnitOfWork {
+	return &pgUnit{ctx: context.Background(), tx: tx}
+}
+
+// pgUnit mengimplementasikan ketiga repository sekaligus di atas satu *sql.Tx. Setiap
+// pemanggilan repository menjadi span dengan span SQL-nya di bawahnya.
+type pgUnit struct {
+	ctx context.Context
+	tx  *sql.Tx
+}
+
+// start membuka span repository dan mengembalikan executor yang meneruskan context span
+// itu ke setiap statement
+func (u *pgUnit) start(name string) (repositories.Executor, trace.Span) {
+	ctx, span := tracing.Child(u.ctx, "store."+name)
+	return ctxTx{ctx: ctx, tx: u.tx}, span
+}
+
+// ctxTx meneruskan context milik Do ke setiap statement, sehingga deadline atau
+// pembatalan request juga menghentikan query yang sedang berjalan
+type ctxTx struct {
+	ctx context.Context
+	tx  *sql.Tx
+}
+
+func (t ctxTx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.tx.QueryRowContext(t.ctx, query, args...)
+}
+
+func (t ctxTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.QueryContext(t.ctx, query, args...)
+}
+
+func (t ctxTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.ExecContext(t.ctx, query, args...)
+}
+
+func (u *pgUnit) Customers() CustomerRepository       { return u }
+func (u *pgUnit) Accounts() AccountRepository         { return u }
+func (u *pgUnit) Transactions() TransactionRepository { return u }
+func (u *pgUnit) SQL() *sql.Tx                        { return u.tx }
+func (u *pgUnit) Context() context.Context            { return u.ctx }
+
+func (u *pgUnit) Enqueue(msg outbox.Message) (err error) {
+	exec, span := u.start("Enqueue")
+	defer func() { tracing.End(span, err) }()
+	return outbox.EnqueueContext(u.ctx, exec, msg)
+}
+
+func (u *pgUnit) CheckExisting(nik, noHP string) (fields []string, err error) {
+	exec, span := u.start("Customers.CheckExisting")
+	defer func() { tracing.End(span, err) }()
+	_, fields, err = repositories.CheckExistingNasabah(exec, nik, noHP)
+	return fields, err
+}
+
+func (u *pgUnit) Create(nasabah *models.Nasabah, pinHash string) (err error) {
+	exec, span := u.start("Customers.Create")
+	defer func() { tracing.End(span, err) }()
+	err = repositories.CreateNasabah(exec, nasabah, pinHash)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (u *pgUnit) GetByNoRekening(noRekening string) (nasabah *models.Nasabah, err error) {
+	exec, span := u.start("Customers.GetByNoRekening")
+	defer func() { tracing.End(span, err) }()
+	nasabah, err = repositories.GetNasabahByNoRekening(exec, noRekening)
+	return nasabah, notFound(err)
+}
+
+func (u *pgUnit) UpdateStatus(noRekening, status string) (err error) {
+	exec, span := u.start("Customers.UpdateStatus")
+	defer func() { tracing.End(span, err) }()
+	return notFound(repositories.UpdateStatusRekening(exec, noRekening, status))
+}
+
+func (u *pgUnit) GetCredentials(noRekening string) (id int, pinHash string, err error) {
+	exec, span := u.start("Customers.GetCredentials")
+	defer func() { tracing.End(span, err) }()
+	id, pinHash, err = repositories.GetNasabahPINHash(exec, noRekening)
+	return id, pinHash, notFound(err)
+}
+
+func (u *pgUnit) GetNoRekening(nasabahID int) (noRekening string, err error) {
+	exec, span := u.start("Customers.GetNoRekening")
+	defer func() { tracing.End(span, err) }()
+	noRekening, err = repositories.GetNoRekeningByNasabahID(exec, nasabahID)
+	return noRekening, notFound(err)
+}
+
+func (u *pgUnit) GetSaldo(noRekening string) (saldo float64, err error) {
+	exec, span := u.start("Accounts.GetSaldo")
+	defer func() { tracing.End(span, err) }()
+	saldo, err = repositories.GetSaldo(exec, noRekening)
+	return saldo, notFound(err)
+}
+
+func (u *pgUnit) Lock(noRekening string) (nasabah *models.Nasabah, err error) {
+	exec, span := u.start("Accounts.Lock")
+	defer func() { tracing.End(span, err) }()
+	nasabah, err = repositories.GetNasabahByNoRekeningForUpdate(exec, noRekening)
+	return nasabah, notFound(err)
+}
+
+func (u *pgUnit) UpdateSaldo(noRekening, jenisTransaksi string, nominal float64) (err error) {
+	exec, span := u.start("Accounts.UpdateSaldo")
+	defer func() { tracing.End(span, err) }()
+	return notFound(repositories.UpdateSaldo(exec, noRekening, jenisTransaksi, nominal))
+}
+
+func (u *pgUnit) Insert(t *models.Tabungan) (err error) {
+	exec, span := u.start("Transactions.Insert")
+	defer func() { tracing.End(span, err) }()
+	return repositories.InsertTabunganDetail(exec, t)
+}
+
+func (u *pgUnit) ListByNasabah(nasabahID int) (riwayat []models.Tabungan, err error) {
+	exec, span := u.start("Transactions.ListByNasabah")
+	defer func() { tracing.End(span, err) }()
+	return repositories.GetRiwayatTransaksi(exec, nasabahID)
+}
+
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
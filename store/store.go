@@ -0,0 +1,96 @@
+// Package store mendefinisikan repository nasabah, rekening dan transaksi di balik
+// interface beserta unit of work, dengan implementasi Postgres dan in-memory. Handler
+// yang hanya memakai Store bisa dijalankan tanpa Postgres, misalnya untuk pengujian.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/repositories"
+)
+
+// Backend Store yang bisa dipilih lewat konfigurasi
+const (
+	BackendPostgres = "postgres"
+	BackendMemory   = "memory"
+)
+
+var (
+	ErrNotFound        = errors.New("data tidak ditemukan")
+	ErrDuplicate       = errors.New("data sudah ada")
+	ErrLockTimeout     = errors.New("gagal mengunci rekening dalam batas waktu")
+	ErrSaldoTidakCukup = repositories.ErrSaldoTidakCukup
+)
+
+// CustomerRepository mengelola data nasabah
+type CustomerRepository interface {
+	// CheckExisting mengembalikan field ("NIK", "No HP") yang sudah dipakai nasabah lain
+	CheckExisting(nik, noHP string) ([]string, error)
+	Create(nasabah *models.Nasabah, pinHash string) error
+	GetByNoRekening(noRekening string) (*models.Nasabah, error)
+	UpdateStatus(noRekening, status string) error
+
+	// GetCredentials mengembalikan id nasabah dan hash PIN untuk login
+	GetCredentials(noRekening string) (int, string, error)
+	// GetNoRekening mengembalikan nomor rekening nasabah berdasarkan id
+	GetNoRekening(nasabahID int) (string, error)
+}
+
+// AccountRepository mengelola saldo rekening. Lock dan UpdateSaldo mengunci rekening
+// sampai unit of work selesai, sehingga transaksi lain pada rekening yang sama menunggu.
+type AccountRepository interface {
+	GetSaldo(noRekening string) (float64, error)
+	Lock(noRekening string) (*models.Nasabah, error)
+	UpdateSaldo(noRekening, jenisTransaksi string, nominal float64) error
+}
+
+// TransactionRepository mencatat mutasi rekening
+type TransactionRepository interface {
+	Insert(t *models.Tabungan) error
+	ListByNasabah(nasabahID int) ([]models.Tabungan, error)
+}
+
+// UnitOfWork adalah satu transaksi: semua perubahan dan event di dalamnya disimpan
+// bersama-sama atau tidak sama sekali
+type UnitOfWork interface {
+	Customers() CustomerRepository
+	Accounts() AccountRepository
+	Transactions() TransactionRepository
+
+	// Enqueue mencatat event domain yang dikirim setelah unit of work di-commit
+	Enqueue(msg outbox.Message) error
+
+	// SQL mengembalikan transaksi Postgres untuk komponen yang belum memakai Store
+	// (aturan fraud, screening); nil pada implementasi in-memory
+	SQL() *sql.Tx
+
+	// Context mengembalikan context unit of work, misalnya untuk membuka span tracing
+	// bagi komponen yang memakai SQL
+	Context() context.Context
+}
+
+// Store menjalankan unit of work
+type Store interface {
+	// Do menjalankan fn dalam satu unit of work; di-commit jika fn tidak mengembalikan
+	// error dan di-rollback jika sebaliknya
+	Do(ctx context.Context, fn func(uow UnitOfWork) error) error
+}
+
+// NoRekeningLookup mencari nomor rekening nasabah di st untuk auth.TokenService;
+// nasabah yang tidak ada dilaporkan sebagai sql.ErrNoRows
+func NoRekeningLookup(st Store) func(nasabahID int) (string, error) {
+	return func(nasabahID int) (string, error) {
+		var noRekening string
+		err := st.Do(context.Background(), func(uow UnitOfWork) (err error) {
+			noRekening, err = uow.Customers().GetNoRekening(nasabahID)
+			return err
+		})
+		if errors.Is(err, ErrNotFound) {
+			return "", sql.ErrNoRows
+		}
+		return noRekening, err
+	}
+}
@@ -0,0 +1,98 @@
+// Package tracing menyiapkan tracing OpenTelemetry: span untuk request HTTP dan gRPC,
+// service transaksi, repository Store dan statement SQL, propagasi W3C trace context
+// dari header masuk ke webhook partner, serta trace_id pada log.
+//
+// Tanpa endpoint OTLP tracer provider tetap no-op, tetapi trace context dari header masuk
+// tetap diteruskan sehingga trace_id pemanggil muncul di log dan di webhook keluar.
+// Seperti label metrik, atribut span tidak pernah berisi nomor rekening, NIK atau No HP.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "golang-echo-postgresql"
+
+var tracer = otel.Tracer(instrumentationName)
+
+// Options mengatur exporter OTLP. Endpoint kosong berarti span tidak diekspor.
+type Options struct {
+	Endpoint    string // URL collector OTLP/gRPC, misalnya http://otel-collector:4317
+	ServiceName string
+	SampleRatio float64 // porsi trace baru yang direkam; trace dari pemanggil mengikuti keputusan pemanggil
+}
+
+// Setup memasang propagator W3C trace context dan, jika Endpoint diisi, tracer provider
+// dengan exporter OTLP. Fungsi yang dikembalikan mengirim span yang tersisa saat shutdown.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(opts.Endpoint))
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(opts.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start membuka span baru, sebagai root jika ctx belum punya trace
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, opts...)
+}
+
+// Child membuka span hanya jika ctx sudah berada di dalam trace. Pekerjaan latar belakang
+// (EOD, relay outbox) tidak menghasilkan trace tanpa induk untuk setiap query.
+func Child(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return tracer.Start(ctx, name, opts...)
+}
+
+// End menutup span dan menandainya gagal jika err tidak nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceParent mengembalikan header traceparent untuk span di ctx, atau string kosong
+// jika ctx tidak berada di dalam trace. Dipakai untuk menyimpan trace context bersama
+// event outbox.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// WithTraceParent mengembalikan ctx dengan trace context dari header traceparent yang
+// disimpan TraceParent
+func WithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
+}
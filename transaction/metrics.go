@@ -0,0 +1,61 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"golang-echo-postgresql/metrics"
+)
+
+// Reason mengembalikan alasan penolakan transaksi yang stabil untuk label metrik.
+// Error yang tidak dikenal menjadi "error".
+func Reason(err error) string {
+	var fraudErr *FraudError
+	switch {
+	case errors.As(err, &fraudErr):
+		if fraudErr.Decision.NeedsStepUp() {
+			return "step_up_required"
+		}
+		return "fraud_blocked"
+	case errors.Is(err, ErrInvalidAmount):
+		return "invalid_amount"
+	case errors.Is(err, ErrInvalidRequest):
+		return "invalid_request"
+	case errors.Is(err, ErrSameRekening):
+		return "same_rekening"
+	case errors.Is(err, ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, ErrRekeningNotLinked):
+		return "rekening_not_linked"
+	case errors.Is(err, ErrRekeningNotFound):
+		return "rekening_not_found"
+	case errors.Is(err, ErrTujuanNotFound):
+		return "destination_not_found"
+	case errors.Is(err, ErrRekeningBeku):
+		return "rekening_frozen"
+	case errors.Is(err, ErrRekeningReview):
+		return "rekening_under_review"
+	case errors.Is(err, ErrTujuanTidakAktif):
+		return "destination_inactive"
+	case errors.Is(err, ErrTujuanDitahan):
+		return "destination_held"
+	case errors.Is(err, ErrSaldoTidakCukup):
+		return "insufficient_balance"
+	case errors.Is(err, ErrApprovalUnavailable):
+		return "approval_unavailable"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	}
+	return "error"
+}
+
+// observe mencatat hasil satu perintah transaksi di metrik bisnis
+func observe(kind, channel string, nominal float64, res *Result, err error) {
+	switch {
+	case err != nil:
+		metrics.TransactionRejected(kind, channel, Reason(err))
+	case res != nil && res.Status == StatusPendingApproval:
+		metrics.TransactionPending(kind, channel)
+	case res != nil:
+		metrics.TransactionCompleted(kind, channel, nominal)
+	}
+}
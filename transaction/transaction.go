@@ -0,0 +1,639 @@
+// Package transaction adalah satu-satunya jalur setor, tarik dan transfer antarrekening.
+// HTTP, gRPC dan proses batch mengirim perintah bertipe ke Service sehingga validasi,
+// otorisasi, screening fraud/watchlist dan pembukuan identik di semua channel.
+package transaction
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/approval"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/backoffice"
+	"golang-echo-postgresql/fraud"
+	"golang-echo-postgresql/metrics"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/repositories"
+	"golang-echo-postgresql/screening"
+	"golang-echo-postgresql/store"
+	"golang-echo-postgresql/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Error yang sama dengan operasi back-office memakai sentinel backoffice agar hasil
+// eksekusi antrean persetujuan diterjemahkan dengan cara yang sama
+var (
+	ErrInvalidRequest      = backoffice.ErrInvalidRequest
+	ErrRekeningNotFound    = backoffice.ErrRekeningNotFound
+	ErrRekeningBeku        = backoffice.ErrRekeningBeku
+	ErrRekeningReview      = backoffice.ErrRekeningReview
+	ErrSaldoTidakCukup     = store.ErrSaldoTidakCukup
+	ErrInvalidAmount       = errors.New("nominal harus lebih dari 0")
+	ErrSameRekening        = errors.New("rekening asal dan tujuan tidak boleh sama")
+	ErrForbidden           = errors.New("tidak berhak bertransaksi atas rekening")
+	ErrRekeningNotLinked   = errors.New("rekening tidak terhubung dengan partner")
+	ErrTujuanNotFound      = errors.New("rekening tujuan tidak ditemukan")
+	ErrTujuanTidakAktif    = errors.New("rekening tujuan dibekukan atau menunggu review compliance")
+	ErrTujuanDitahan       = errors.New("rekening tujuan ditahan screening watchlist")
+	ErrApprovalUnavailable = errors.New("antrean persetujuan tidak tersedia")
+)
+
+// FraudError dikembalikan jika aturan fraud memblokir transaksi atau meminta step-up PIN
+type FraudError struct {
+	Decision *fraud.Decision
+}
+
+func (e *FraudError) Error() string {
+	if e.Decision.NeedsStepUp() {
+		return "transaksi membutuhkan verifikasi PIN"
+	}
+	return "transaksi diblokir aturan fraud"
+}
+
+// Status hasil transaksi
+const (
+	StatusCompleted       = "completed"
+	StatusPendingApproval = "pending_approval"
+	StatusHeld            = "held" // rekening tujuan tertahan screening watchlist, tidak ada dana berpindah
+)
+
+// maxReferensi adalah panjang kolom tabungan.referensi
+const maxReferensi = 64
+
+// Meta adalah metadata channel dan referensi yang dibawa setiap perintah. Channel kosong
+// diturunkan dari Principal; Principal nil hanya diterima untuk channel sistem (proses batch).
+type Meta struct {
+	Channel    string          `json:"channel,omitempty"`
+	Referensi  string          `json:"referensi,omitempty"`
+	Keterangan string          `json:"keterangan,omitempty"`
+	Principal  *auth.Principal `json:"-"`
+	StepUpPIN  string          `json:"-"`
+}
+
+// Deposit adalah perintah setor ke rekening
+type Deposit struct {
+	NoRekening string  `json:"no_rekening"`
+	Nominal    float64 `json:"nominal"`
+	Meta
+}
+
+// Withdraw adalah perintah tarik dari rekening
+type Withdraw struct {
+	NoRekening string  `json:"no_rekening"`
+	Nominal    float64 `json:"nominal"`
+	Meta
+}
+
+// Transfer adalah perintah pemindahan dana antarrekening
+type Transfer struct {
+	DariRekening string  `json:"dari_rekening"`
+	KeRekening   string  `json:"ke_rekening"`
+	Nominal      float64 `json:"nominal"`
+	Meta
+}
+
+// Result adalah hasil setor atau tarik; untuk transfer berisi sisi rekening asal.
+// Operation hanya terisi jika transaksi menunggu persetujuan.
+type Result struct {
+	Status     string                   `json:"status"`
+	TabunganID int                      `json:"tabungan_id,omitempty"`
+	NoRekening string                   `json:"no_rekening"`
+	Nominal    float64                  `json:"nominal"`
+	SaldoAwal  float64                  `json:"saldo_awal"`
+	SaldoAkhir float64                  `json:"saldo_akhir"`
+	Channel    string                   `json:"channel"`
+	Referensi  string                   `json:"referensi,omitempty"`
+	Operation  *models.PendingOperation `json:"operation,omitempty"`
+}
+
+// TransferResult adalah hasil transfer. Saldo rekening tujuan tidak ikut dikembalikan.
+type TransferResult struct {
+	Result
+	KeRekening       string `json:"ke_rekening"`
+	KreditTabunganID int    `json:"kredit_tabungan_id,omitempty"`
+}
+
+// Service menjalankan perintah transaksi di atas Store. Approvals, Fraud, Screening dan
+// PartnerLinks membutuhkan Postgres; biarkan nil saat memakai store in-memory.
+type Service struct {
+	Store     store.Store
+	Policy    *policy.Policy
+	Approvals *approval.Queue
+	Fraud     *fraud.Engine
+	Screening *screening.Service
+
+	// PartnerLinks memeriksa partner_rekening sebelum setoran partner. Tanpa pemeriksa
+	// tidak ada rekening yang dianggap terhubung, jadi setoran partner selalu ditolak.
+	PartnerLinks PartnerLinks
+}
+
+// PartnerLinks melaporkan apakah rekening sudah dihubungkan petugas dengan partner
+type PartnerLinks func(partnerID int, noRekening string) (bool, error)
+
+// NewPartnerLinks memeriksa hubungan partner dan rekening di tabel partner_rekening,
+// pemeriksaan yang sama dengan pendaftaran webhook partner
+func NewPartnerLinks(executor repositories.Executor) PartnerLinks {
+	return func(partnerID int, noRekening string) (bool, error) {
+		unlinked, err := repositories.FindUnlinkedPartnerRekening(executor, partnerID, []string{noRekening})
+		if err != nil {
+			return false, err
+		}
+		return len(unlinked) == 0, nil
+	}
+}
+
+func NewService(st store.Store, pol *policy.Policy, approvals *approval.Queue, fraudEngine *fraud.Engine, screener *screening.Service) *Service {
+	return &Service{Store: st, Policy: pol, Approvals: approvals, Fraud: fraudEngine, Screening: screener}
+}
+
+// Register mendaftarkan eksekusi penarikan dan transfer yang sudah disetujui checker.
+// Payload penarikan yang diantrekan sebelumnya ({no_rekening, jenis_transaksi, nominal})
+// tetap bisa dibaca karena field-nya sama. Transfer yang rekening tujuannya tertahan
+// screening dikembalikan dengan status held tanpa error, supaya transaksi persetujuan
+// tetap di-commit bersama hit dan status pending_review, dan checker melihat penahanannya.
+func (s *Service) Register(q *approval.Queue) {
+	q.Register(policy.ActionWithdrawal, func(tx *sql.Tx, payload json.RawMessage) (interface{}, error) {
+		var cmd Withdraw
+		if err := decode(payload, &cmd); err != nil {
+			return nil, err
+		}
+		res, err := s.withdraw(store.Tx(tx), cmd, false)
+		observe(metrics.TransactionWithdraw, cmd.Channel, cmd.Nominal, res, err)
+		return res, err
+	})
+	q.Register(policy.ActionTransfer, func(tx *sql.Tx, payload json.RawMessage) (interface{}, error) {
+		var cmd Transfer
+		if err := decode(payload, &cmd); err != nil {
+			return nil, err
+		}
+		res, held, err := s.transfer(store.Tx(tx), cmd, false)
+		if held {
+			observe(metrics.TransactionTransfer, cmd.Channel, cmd.Nominal, nil, ErrTujuanDitahan)
+			return &TransferResult{Result: Result{
+				Status:     StatusHeld,
+				NoRekening: cmd.DariRekening,
+				Nominal:    cmd.Nominal,
+				Channel:    cmd.Channel,
+				Referensi:  cmd.Referensi,
+			}, KeRekening: cmd.KeRekening}, nil
+		}
+		var r *Result
+		if res != nil {
+			r = &res.Result
+		}
+		observe(metrics.TransactionTransfer, cmd.Channel, cmd.Nominal, r, err)
+		return res, err
+	})
+}
+
+// decode membaca payload antrean persetujuan; channel kosong berarti dibuat lewat teller
+func decode(payload json.RawMessage, cmd interface{ meta() *Meta }) error {
+	if err := json.Unmarshal(payload, cmd); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	if m := cmd.meta(); m.Channel == "" {
+		m.Channel = models.ChannelTeller
+	}
+	return nil
+}
+
+func (m *Meta) meta() *Meta { return m }
+
+// Deposit menyetor dana ke rekening aktif. Nasabah hanya boleh menyetor ke rekeningnya
+// sendiri; petugas membutuhkan permission setor.
+func (s *Service) Deposit(ctx context.Context, cmd Deposit) (res *Result, err error) {
+	ctx, span := tracing.Child(ctx, "transaction.Deposit")
+	defer func() {
+		observe(metrics.TransactionDeposit, cmd.Channel, cmd.Nominal, res, err)
+		tracing.End(span, err)
+	}()
+
+	if err := validate(cmd.NoRekening, cmd.Nominal, &cmd.Meta); err != nil {
+		return nil, err
+	}
+	if _, err := s.authorize(policy.ActionDeposit, cmd.NoRekening, cmd.Nominal, cmd.Meta); err != nil {
+		return nil, err
+	}
+
+	err = s.Store.Do(ctx, func(uow store.UnitOfWork) error {
+		nasabah, err := lock(uow, cmd.NoRekening, ErrRekeningNotFound)
+		if err != nil {
+			return err
+		}
+		if err := checkRekening(nasabah); err != nil {
+			return err
+		}
+		if err := s.screenFraud(uow, nasabah, models.JenisSetor, cmd.Nominal, cmd.Meta); err != nil {
+			return err
+		}
+
+		res, err = book(uow, nasabah, models.JenisSetor, cmd.Nominal, cmd.Meta)
+		if err != nil {
+			return err
+		}
+		return uow.Enqueue(outbox.FundsDepositedV1{NoRekening: nasabah.NoRekening, Nominal: cmd.Nominal, SaldoAkhir: res.SaldoAkhir, OccurredAt: time.Now()})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Withdraw menarik dana dari rekening aktif. Penarikan yang menurut policy harus
+// disetujui dimasukkan ke antrean dan dikembalikan dengan status pending_approval.
+func (s *Service) Withdraw(ctx context.Context, cmd Withdraw) (res *Result, err error) {
+	ctx, span := tracing.Child(ctx, "transaction.Withdraw")
+	defer func() {
+		observe(metrics.TransactionWithdraw, cmd.Channel, cmd.Nominal, res, err)
+		tracing.End(span, err)
+	}()
+
+	if err := validate(cmd.NoRekening, cmd.Nominal, &cmd.Meta); err != nil {
+		return nil, err
+	}
+	outcome, err := s.authorize(policy.ActionWithdrawal, cmd.NoRekening, cmd.Nominal, cmd.Meta)
+	if err != nil {
+		return nil, err
+	}
+	if outcome == policy.RequireApproval {
+		op, err := s.Approvals.Submit(cmd.Principal, policy.ActionWithdrawal, cmd.NoRekening, cmd.Nominal, "Penarikan besar", cmd)
+		if err != nil {
+			return nil, err
+		}
+		return pending(cmd.NoRekening, cmd.Nominal, cmd.Meta, op), nil
+	}
+
+	err = s.Store.Do(ctx, func(uow store.UnitOfWork) error {
+		var err error
+		res, err = s.withdraw(uow, cmd, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// withdraw membukukan penarikan di dalam unit of work; screen false untuk penarikan
+// yang sudah disetujui checker
+func (s *Service) withdraw(uow store.UnitOfWork, cmd Withdraw, screen bool) (*Result, error) {
+	if err := validate(cmd.NoRekening, cmd.Nominal, &cmd.Meta); err != nil {
+		return nil, err
+	}
+	nasabah, err := lock(uow, cmd.NoRekening, ErrRekeningNotFound)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRekening(nasabah); err != nil {
+		return nil, err
+	}
+	if nasabah.Saldo < cmd.Nominal {
+		return nil, ErrSaldoTidakCukup
+	}
+	if screen {
+		if err := s.screenFraud(uow, nasabah, models.JenisTarik, cmd.Nominal, cmd.Meta); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := book(uow, nasabah, models.JenisTarik, cmd.Nominal, cmd.Meta)
+	if err != nil {
+		return nil, err
+	}
+	err = uow.Enqueue(outbox.FundsWithdrawnV1{NoRekening: nasabah.NoRekening, Nominal: cmd.Nominal, SaldoAkhir: res.SaldoAkhir, OccurredAt: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Transfer memindahkan dana antarrekening. Rekening tujuan di-screening terhadap
+// watchlist; jika cocok, hit dan status pending_review tetap disimpan tetapi tidak ada
+// dana yang berpindah dan ErrTujuanDitahan dikembalikan.
+func (s *Service) Transfer(ctx context.Context, cmd Transfer) (res *TransferResult, err error) {
+	ctx, span := tracing.Child(ctx, "transaction.Transfer")
+	defer func() {
+		var r *Result
+		if res != nil {
+			r = &res.Result
+		}
+		observe(metrics.TransactionTransfer, cmd.Channel, cmd.Nominal, r, err)
+		tracing.End(span, err)
+	}()
+
+	if err := validate(cmd.DariRekening, cmd.Nominal, &cmd.Meta); err != nil {
+		return nil, err
+	}
+	if cmd.KeRekening == "" {
+		return nil, ErrInvalidRequest
+	}
+	if cmd.KeRekening == cmd.DariRekening {
+		return nil, ErrSameRekening
+	}
+	if cmd.Referensi == "" {
+		if cmd.Referensi, err = newReferensi(); err != nil {
+			return nil, err
+		}
+	}
+	outcome, err := s.authorize(policy.ActionTransfer, cmd.DariRekening, cmd.Nominal, cmd.Meta)
+	if err != nil {
+		return nil, err
+	}
+	if outcome == policy.RequireApproval {
+		op, err := s.Approvals.Submit(cmd.Principal, policy.ActionTransfer, cmd.DariRekening, cmd.Nominal, "Transfer besar", cmd)
+		if err != nil {
+			return nil, err
+		}
+		return &TransferResult{Result: *pending(cmd.DariRekening, cmd.Nominal, cmd.Meta, op), KeRekening: cmd.KeRekening}, nil
+	}
+
+	var held bool
+	err = s.Store.Do(ctx, func(uow store.UnitOfWork) error {
+		var err error
+		res, held, err = s.transfer(uow, cmd, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if held {
+		return nil, ErrTujuanDitahan
+	}
+	return res, nil
+}
+
+// transfer membukukan kedua sisi transfer di dalam unit of work. held bernilai true jika
+// rekening tujuan tertahan screening; unit of work tetap di-commit agar hit tersimpan.
+func (s *Service) transfer(uow store.UnitOfWork, cmd Transfer, screen bool) (*TransferResult, bool, error) {
+	if err := validate(cmd.DariRekening, cmd.Nominal, &cmd.Meta); err != nil {
+		return nil, false, err
+	}
+	if cmd.KeRekening == "" {
+		return nil, false, ErrInvalidRequest
+	}
+	if cmd.KeRekening == cmd.DariRekening {
+		return nil, false, ErrSameRekening
+	}
+
+	// Kunci kedua rekening dengan urutan tetap agar transfer berlawanan arah tidak deadlock
+	locked := map[string]*models.Nasabah{}
+	for _, noRekening := range lockOrder(cmd.DariRekening, cmd.KeRekening) {
+		missing := ErrRekeningNotFound
+		if noRekening == cmd.KeRekening {
+			missing = ErrTujuanNotFound
+		}
+		nasabah, err := lock(uow, noRekening, missing)
+		if err != nil {
+			return nil, false, err
+		}
+		locked[noRekening] = nasabah
+	}
+	dari, ke := locked[cmd.DariRekening], locked[cmd.KeRekening]
+
+	if err := checkRekening(dari); err != nil {
+		return nil, false, err
+	}
+	if checkRekening(ke) != nil {
+		return nil, false, ErrTujuanTidakAktif
+	}
+	if dari.Saldo < cmd.Nominal {
+		return nil, false, ErrSaldoTidakCukup
+	}
+
+	if s.Screening != nil {
+		_, span := tracing.Child(uow.Context(), "screening.ScreenCounterparty")
+		hits, err := s.Screening.ScreenCounterparty(uow.SQL(), ke)
+		span.SetAttributes(attribute.Int("screening.hits", len(hits)))
+		tracing.End(span, err)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(hits) > 0 {
+			return nil, true, nil
+		}
+	}
+	if screen {
+		if err := s.screenFraud(uow, dari, models.JenisTransferKeluar, cmd.Nominal, cmd.Meta); err != nil {
+			return nil, false, err
+		}
+	}
+
+	debit, err := book(uow, dari, models.JenisTransferKeluar, cmd.Nominal, cmd.Meta)
+	if err != nil {
+		return nil, false, err
+	}
+	kredit, err := book(uow, ke, models.JenisTransferMasuk, cmd.Nominal, cmd.Meta)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	err = uow.Enqueue(outbox.FundsTransferredV1{
+		NoRekening:    dari.NoRekening,
+		Arah:          outbox.ArahKeluar,
+		LawanRekening: ke.NoRekening,
+		Nominal:       cmd.Nominal,
+		SaldoAkhir:    debit.SaldoAkhir,
+		Referensi:     cmd.Referensi,
+		OccurredAt:    now,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	err = uow.Enqueue(outbox.FundsTransferredV1{
+		NoRekening:    ke.NoRekening,
+		Arah:          outbox.ArahMasuk,
+		LawanRekening: dari.NoRekening,
+		Nominal:       cmd.Nominal,
+		SaldoAkhir:    kredit.SaldoAkhir,
+		Referensi:     cmd.Referensi,
+		OccurredAt:    now,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &TransferResult{Result: *debit, KeRekening: ke.NoRekening, KreditTabunganID: kredit.TabunganID}, false, nil
+}
+
+// authorize memeriksa hak principal atas rekening yang didebit atau dikredit dan menilai
+// policy. Partner tidak memiliki rekening; setoran partner hanya boleh ke rekening yang
+// terhubung dengan partner tersebut, dan policy hanya mengizinkan partner menyetor.
+func (s *Service) authorize(action policy.Action, noRekening string, nominal float64, meta Meta) (policy.Outcome, error) {
+	if meta.Principal == nil {
+		if meta.Channel == models.ChannelSistem {
+			return policy.Allow, nil
+		}
+		return policy.Deny, ErrForbidden
+	}
+	if action == policy.ActionDeposit && meta.Principal.Type == auth.SubjectPartner {
+		if err := s.checkPartnerLink(meta.Principal.ID, noRekening); err != nil {
+			return policy.Deny, err
+		}
+	} else if !auth.CanAccessRekening(meta.Principal, noRekening) {
+		return policy.Deny, ErrForbidden
+	}
+
+	outcome := s.Policy.Evaluate(meta.Principal, action, nominal)
+	switch {
+	case outcome == policy.Deny:
+		return outcome, ErrForbidden
+	case outcome == policy.RequireApproval && s.Approvals == nil:
+		return outcome, ErrApprovalUnavailable
+	}
+	return outcome, nil
+}
+
+// checkPartnerLink menolak setoran partner ke rekening yang tidak terhubung dengannya
+func (s *Service) checkPartnerLink(partnerID int, noRekening string) error {
+	if s.PartnerLinks == nil {
+		return ErrRekeningNotLinked
+	}
+	linked, err := s.PartnerLinks(partnerID, noRekening)
+	if err != nil {
+		return err
+	}
+	if !linked {
+		return ErrRekeningNotLinked
+	}
+	return nil
+}
+
+// screenFraud menilai transaksi dengan aturan fraud di dalam unit of work yang sama
+// dengan perubahan saldo. Tanpa mesin fraud (store in-memory) transaksi selalu lolos.
+func (s *Service) screenFraud(uow store.UnitOfWork, nasabah *models.Nasabah, jenis string, nominal float64, meta Meta) error {
+	if s.Fraud == nil {
+		return nil
+	}
+	// Aturan fraud membaca riwayat lewat SQL tanpa context, jadi waktunya dicatat di satu span
+	_, span := tracing.Child(uow.Context(), "fraud.Screen")
+	decision, err := s.Fraud.Screen(uow.SQL(), fraud.Transaction{
+		NasabahID:  nasabah.ID,
+		NoRekening: nasabah.NoRekening,
+		Jenis:      jenis,
+		Nominal:    nominal,
+		Principal:  meta.Principal,
+	}, meta.StepUpPIN)
+	if decision != nil {
+		span.SetAttributes(attribute.String("fraud.outcome", decision.Outcome))
+	}
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+	if decision.Blocked() || decision.NeedsStepUp() {
+		return &FraudError{Decision: decision}
+	}
+	return nil
+}
+
+// validate memeriksa field bersama dan melengkapi channel dari principal
+func validate(noRekening string, nominal float64, meta *Meta) error {
+	if noRekening == "" || len(meta.Referensi) > maxReferensi {
+		return ErrInvalidRequest
+	}
+	if !(nominal > 0) {
+		return ErrInvalidAmount
+	}
+
+	if meta.Channel == "" {
+		if meta.Principal == nil {
+			return ErrForbidden
+		}
+		meta.Channel = fraud.ChannelOf(meta.Principal)
+	}
+	switch meta.Channel {
+	case models.ChannelApp, models.ChannelTeller, models.ChannelPartner, models.ChannelSistem:
+		return nil
+	}
+	return ErrInvalidRequest
+}
+
+// lock mengunci rekening sampai unit of work selesai; missing dikembalikan jika rekening tidak ada
+func lock(uow store.UnitOfWork, noRekening string, missing error) (*models.Nasabah, error) {
+	nasabah, err := uow.Accounts().Lock(noRekening)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, missing
+	}
+	return nasabah, err
+}
+
+func lockOrder(a, b string) []string {
+	if b < a {
+		return []string{b, a}
+	}
+	return []string{a, b}
+}
+
+// checkRekening menolak transaksi pada rekening yang dibekukan atau menunggu review compliance
+func checkRekening(nasabah *models.Nasabah) error {
+	switch nasabah.Status {
+	case models.StatusBeku:
+		return ErrRekeningBeku
+	case models.StatusPendingReview:
+		return ErrRekeningReview
+	}
+	return nil
+}
+
+// book mengubah saldo rekening yang sudah dikunci dan mencatat mutasinya
+func book(uow store.UnitOfWork, nasabah *models.Nasabah, jenis string, nominal float64, meta Meta) (*Result, error) {
+	if err := uow.Accounts().UpdateSaldo(nasabah.NoRekening, jenis, nominal); err != nil {
+		return nil, err
+	}
+	saldoAwal := nasabah.Saldo
+	if models.IsKredit(jenis) {
+		nasabah.Saldo += nominal
+	} else {
+		nasabah.Saldo -= nominal
+	}
+
+	mutasi := &models.Tabungan{
+		NasabahID:      nasabah.ID,
+		JenisTransaksi: jenis,
+		Nominal:        nominal,
+		Keterangan:     meta.Keterangan,
+		Channel:        meta.Channel,
+		Referensi:      meta.Referensi,
+	}
+	if err := uow.Transactions().Insert(mutasi); err != nil {
+		return nil, err
+	}
+	return &Result{
+		Status:     StatusCompleted,
+		TabunganID: mutasi.ID,
+		NoRekening: nasabah.NoRekening,
+		Nominal:    nominal,
+		SaldoAwal:  saldoAwal,
+		SaldoAkhir: nasabah.Saldo,
+		Channel:    meta.Channel,
+		Referensi:  meta.Referensi,
+	}, nil
+}
+
+func pending(noRekening string, nominal float64, meta Meta, op *models.PendingOperation) *Result {
+	return &Result{
+		Status:     StatusPendingApproval,
+		NoRekening: noRekening,
+		Nominal:    nominal,
+		Channel:    meta.Channel,
+		Referensi:  meta.Referensi,
+		Operation:  op,
+	}
+}
+
+// newReferensi membuat referensi transfer jika pemanggil tidak mengirimkannya
+func newReferensi() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "TRF" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,225 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golang-echo-postgresql/approval"
+	"golang-echo-postgresql/auth"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/policy"
+	"golang-echo-postgresql/screening"
+	"golang-echo-postgresql/store"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const (
+	rekeningA = "1000000001"
+	rekeningB = "1000000002"
+)
+
+// newTestService membuat Service di atas store in-memory dengan dua rekening bersaldo
+func newTestService(t *testing.T, saldo float64) *Service {
+	t.Helper()
+	st := store.NewMemory()
+	err := st.Do(context.Background(), func(uow store.UnitOfWork) error {
+		for i, noRekening := range []string{rekeningA, rekeningB} {
+			n := &models.Nasabah{Nama: "Nasabah", NIK: noRekening + "000000", NoHP: "08" + noRekening, NoRekening: noRekening}
+			if err := uow.Customers().Create(n, "hash"); err != nil {
+				return err
+			}
+			if saldo > 0 {
+				if err := uow.Accounts().UpdateSaldo(noRekening, models.JenisSetor, saldo+float64(i)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(st, policy.New(10_000_000), nil, nil, nil)
+}
+
+func nasabah(noRekening string) *auth.Principal {
+	return &auth.Principal{Type: auth.SubjectNasabah, ID: 1, NoRekening: noRekening}
+}
+
+func staff(role string) *auth.Principal {
+	return &auth.Principal{Type: auth.SubjectStaff, ID: 2, Role: role}
+}
+
+func partnerPrincipal() *auth.Principal {
+	return &auth.Principal{Type: auth.SubjectPartner, ID: 3, ClientID: "pt_test"}
+}
+
+func TestDepositAuthorization(t *testing.T) {
+	tests := []struct {
+		name string
+		meta Meta
+		want error
+	}{
+		{"own rekening", Meta{Principal: nasabah(rekeningA)}, nil},
+		{"other nasabah rekening", Meta{Principal: nasabah(rekeningB)}, ErrForbidden},
+		{"teller", Meta{Principal: staff(auth.RoleTeller)}, nil},
+		{"auditor lacks permission", Meta{Principal: staff(auth.RoleAuditor)}, ErrForbidden},
+		{"partner linked rekening", Meta{Principal: partnerPrincipal()}, nil},
+		{"other partner", Meta{Principal: &auth.Principal{Type: auth.SubjectPartner, ID: 4, ClientID: "pt_other"}}, ErrRekeningNotLinked},
+		{"anonymous", Meta{Channel: models.ChannelApp}, ErrForbidden},
+		{"system batch", Meta{Channel: models.ChannelSistem}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, 0)
+			s.PartnerLinks = func(partnerID int, noRekening string) (bool, error) {
+				return partnerID == partnerPrincipal().ID && noRekening == rekeningA, nil
+			}
+			res, err := s.Deposit(context.Background(), Deposit{NoRekening: rekeningA, Nominal: 5000, Meta: tt.meta})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && res.SaldoAkhir != 5000 {
+				t.Errorf("saldo akhir = %v, want 5000", res.SaldoAkhir)
+			}
+		})
+	}
+}
+
+func TestPartnerDepositChecksPartnerRekening(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("FROM unnest").
+		WithArgs(3, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"r"}).AddRow(rekeningB))
+
+	s := newTestService(t, 0)
+	s.PartnerLinks = NewPartnerLinks(db)
+	if _, err := s.Deposit(context.Background(), Deposit{NoRekening: rekeningB, Nominal: 5000, Meta: Meta{Principal: partnerPrincipal()}}); !errors.Is(err, ErrRekeningNotLinked) {
+		t.Errorf("err = %v, want %v", err, ErrRekeningNotLinked)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	// Tanpa pemeriksa (store in-memory) setoran partner selalu ditolak
+	s.PartnerLinks = nil
+	if _, err := s.Deposit(context.Background(), Deposit{NoRekening: rekeningA, Nominal: 5000, Meta: Meta{Principal: partnerPrincipal()}}); !errors.Is(err, ErrRekeningNotLinked) {
+		t.Errorf("without PartnerLinks err = %v, want %v", err, ErrRekeningNotLinked)
+	}
+}
+
+func TestWithdrawAuthorization(t *testing.T) {
+	tests := []struct {
+		name    string
+		nominal float64
+		meta    Meta
+		want    error
+	}{
+		{"own rekening", 5000, Meta{Principal: nasabah(rekeningA)}, nil},
+		{"other nasabah rekening", 5000, Meta{Principal: nasabah(rekeningB)}, ErrForbidden},
+		{"teller assisted", 5000, Meta{Principal: staff(auth.RoleTeller)}, nil},
+		{"compliance lacks permission", 5000, Meta{Principal: staff(auth.RoleCompliance)}, ErrForbidden},
+		{"partner cannot withdraw", 5000, Meta{Principal: partnerPrincipal()}, ErrForbidden},
+		{"anonymous", 5000, Meta{Channel: models.ChannelApp}, ErrForbidden},
+		{"large withdrawal needs approval queue", 10_000_000, Meta{Principal: nasabah(rekeningA)}, ErrApprovalUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, 20_000_000)
+			res, err := s.Withdraw(context.Background(), Withdraw{NoRekening: rekeningA, Nominal: tt.nominal, Meta: tt.meta})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && res.SaldoAkhir != 20_000_000-tt.nominal {
+				t.Errorf("saldo akhir = %v, want %v", res.SaldoAkhir, 20_000_000-tt.nominal)
+			}
+		})
+	}
+}
+
+func TestTransferAuthorization(t *testing.T) {
+	tests := []struct {
+		name    string
+		nominal float64
+		meta    Meta
+		want    error
+	}{
+		{"own rekening", 5000, Meta{Principal: nasabah(rekeningA)}, nil},
+		{"from other nasabah rekening", 5000, Meta{Principal: nasabah(rekeningB)}, ErrForbidden},
+		{"supervisor assisted", 5000, Meta{Principal: staff(auth.RoleSupervisor)}, nil},
+		{"auditor lacks permission", 5000, Meta{Principal: staff(auth.RoleAuditor)}, ErrForbidden},
+		{"partner cannot transfer", 5000, Meta{Principal: partnerPrincipal()}, ErrForbidden},
+		{"anonymous", 5000, Meta{Channel: models.ChannelApp}, ErrForbidden},
+		{"large transfer needs approval queue", 10_000_000, Meta{Principal: nasabah(rekeningA)}, ErrApprovalUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, 20_000_000)
+			res, err := s.Transfer(context.Background(), Transfer{DariRekening: rekeningA, KeRekening: rekeningB, Nominal: tt.nominal, Meta: tt.meta})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (res.SaldoAkhir != 20_000_000-tt.nominal || res.Referensi == "") {
+				t.Errorf("result = %+v, want saldo %v and generated referensi", res, 20_000_000-tt.nominal)
+			}
+		})
+	}
+}
+
+func TestApprovedTransferHeldByScreeningCommits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("FROM watchlist_versions").WillReturnRows(sqlmock.NewRows([]string{"ids"}).AddRow("{1}"))
+	mock.ExpectQuery("FROM watchlist_entries").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version_id", "kode", "versi", "nama", "alias", "nik", "tanggal_lahir", "keterangan"}).
+			AddRow(5, 1, "DTTOT", 3, "Terduga Teroris", "{}", "", "", ""))
+	screener, err := screening.NewService(db, 0.9, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := approval.NewQueue(db, time.Hour)
+	s := NewService(store.NewPostgres(db), policy.New(10_000_000), q, nil, screener)
+	s.Register(q)
+
+	payload, _ := json.Marshal(Transfer{DariRekening: rekeningA, KeRekening: rekeningB, Nominal: 20_000_000, Meta: Meta{Channel: models.ChannelTeller}})
+	nasabahColumns := []string{"id", "nik", "nama", "no_hp", "no_rekening", "saldo", "status"}
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM pending_operations WHERE id = \\$1 FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "action", "no_rekening", "nominal", "payload", "status", "maker_type", "maker_id",
+			"alasan", "checker_id", "catatan_checker", "error", "created_at", "expires_at", "decided_at"}).
+			AddRow(1, string(policy.ActionTransfer), rekeningA, 20_000_000, payload, models.OperasiPending, auth.SubjectStaff, 10,
+				"Transfer besar", nil, "", "", time.Now(), time.Now().Add(time.Hour), nil))
+	mock.ExpectQuery("FROM nasabah WHERE no_rekening = \\$1 FOR UPDATE").WithArgs(rekeningA).
+		WillReturnRows(sqlmock.NewRows(nasabahColumns).AddRow(1, "3171010000000001", "Budi", "081200000001", rekeningA, 50_000_000, models.StatusAktif))
+	mock.ExpectQuery("FROM nasabah WHERE no_rekening = \\$1 FOR UPDATE").WithArgs(rekeningB).
+		WillReturnRows(sqlmock.NewRows(nasabahColumns).AddRow(2, "3171010000000002", "Terduga Teroris", "081200000002", rekeningB, 0, models.StatusAktif))
+	mock.ExpectQuery("INSERT INTO screening_hits").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(1, models.HitPending, time.Now()))
+	mock.ExpectExec("UPDATE nasabah SET status").WithArgs(models.StatusPendingReview, rekeningB).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE pending_operations").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	op, result, err := q.Approve(staff(auth.RoleSupervisor), 1, "ok")
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	res, ok := result.(*TransferResult)
+	if !ok || res.Status != StatusHeld || op.Status != models.OperasiApproved {
+		t.Errorf("result = %+v, operation %s; want held transfer in approved operation", result, op.Status)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
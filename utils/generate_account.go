@@ -30,3 +30,8 @@ func ValidateNoHP(noHP string) bool {
 	re := regexp.MustCompile(`^\d{10,15}$`)
 	return re.MatchString(noHP)
 }
+
+func ValidatePIN(pin string) bool {
+	re := regexp.MustCompile(`^\d{6}$`)
+	return re.MatchString(pin)
+}
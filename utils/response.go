@@ -2,6 +2,10 @@
 package utils
 
 type Response struct {
-	Remark string   `json:"remark"`
-	Errors []string `json:"errors,omitempty"` // Tambahkan field Errors sebagai slice of strings
+	Code      string            `json:"code,omitempty"` // kode stabil dari katalog apierror, hanya pada error
+	Remark    string            `json:"remark"`
+	Errors    []string          `json:"errors,omitempty"` // Tambahkan field Errors sebagai slice of strings
+	Retryable bool              `json:"retryable,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
 }
@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress dikembalikan jika URL webhook mengarah ke alamat internal
+var ErrBlockedAddress = errors.New("alamat webhook mengarah ke jaringan internal atau reserved")
+
+// lookupTimeout membatasi resolusi DNS host webhook saat pendaftaran
+const lookupTimeout = 5 * time.Second
+
+// reservedPrefixes adalah rentang yang tidak tercakup helper net/netip tetapi tetap
+// tidak boleh dituju webhook: shared address space, dokumentasi, benchmark, NAT64
+// dan rentang yang dicadangkan IANA
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/23"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// blockedAddr melaporkan apakah alamat termasuk loopback, private, link-local atau reserved
+func blockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupHost me-resolve host webhook lewat resolver sistem
+func lookupHost(ctx context.Context, host string) ([]netip.Addr, error) {
+	return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+}
+
+// checkHost menolak host yang berupa atau ter-resolve ke alamat internal. Pemeriksaan
+// ini hanya saat pendaftaran; DNS bisa berubah, jadi Deliverer memeriksa ulang alamat
+// yang benar-benar dihubungi.
+func (s *Service) checkHost(host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if blockedAddr(addr) {
+			return fmt.Errorf("%w: %w: %s", ErrInvalidSubscription, ErrBlockedAddress, host)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	addrs, err := s.LookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: host %s tidak dapat di-resolve", ErrInvalidSubscription, host)
+	}
+	for _, addr := range addrs {
+		if blockedAddr(addr) {
+			return fmt.Errorf("%w: %w: %s (%s)", ErrInvalidSubscription, ErrBlockedAddress, host, addr)
+		}
+	}
+	return nil
+}
+
+// safeClient membuat HTTP client yang menolak koneksi ke alamat internal. Alamat
+// diperiksa di Control dialer, setelah DNS di-resolve dan untuk setiap redirect, sehingga
+// DNS rebinding tidak bisa melewati pemeriksaan saat pendaftaran. Proxy dari environment
+// tidak dipakai karena alamat yang dihubungi kemudian adalah proxy, bukan partner.
+func safeClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+			}
+			if blockedAddr(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestSubscribeRejectsInternalAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"loopback", "https://127.0.0.1/hook"},
+		{"loopback v6", "https://[::1]/hook"},
+		{"cloud metadata", "https://169.254.169.254/latest/meta-data"},
+		{"private", "https://10.1.2.3/hook"},
+		{"v4-mapped private", "https://[::ffff:192.168.1.1]/hook"},
+		{"shared address space", "https://100.64.0.1/hook"},
+		{"unspecified", "https://0.0.0.0/hook"},
+		{"hostname resolving to private", "https://internal.partner.example/hook"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newTestService(t)
+			s.LookupHost = func(ctx context.Context, host string) ([]netip.Addr, error) {
+				return []netip.Addr{netip.MustParseAddr("93.184.216.34"), netip.MustParseAddr("172.16.0.10")}, nil
+			}
+			_, err := s.Subscribe(7, models.WebhookSubscriptionRequest{URL: tt.url, EventTypes: []string{outbox.TypeFundsDeposited}})
+			if !errors.Is(err, ErrBlockedAddress) || !errors.Is(err, ErrInvalidSubscription) {
+				t.Fatalf("err = %v, want ErrBlockedAddress", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestSubscribeRejectsUnresolvableHost(t *testing.T) {
+	s, _ := newTestService(t)
+	s.LookupHost = func(ctx context.Context, host string) ([]netip.Addr, error) {
+		return nil, errors.New("no such host")
+	}
+	_, err := s.Subscribe(7, models.WebhookSubscriptionRequest{URL: "https://missing.example/hook", EventTypes: []string{outbox.TypeFundsDeposited}})
+	if !errors.Is(err, ErrInvalidSubscription) {
+		t.Fatalf("err = %v, want ErrInvalidSubscription", err)
+	}
+}
+
+func TestSafeClientRefusesInternalAddress(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	// Alamat diperiksa saat dial, jadi host yang lolos pendaftaran lalu berubah ke
+	// loopback tetap tidak dihubungi
+	_, err := safeClient(time.Second).Post(srv.URL, "application/json", nil)
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("err = %v, want ErrBlockedAddress", err)
+	}
+	if called {
+		t.Error("internal endpoint was called")
+	}
+}
+
+func TestBlockedAddr(t *testing.T) {
+	for _, addr := range []string{"8.8.8.8", "93.184.216.34", "2606:4700:4700::1111"} {
+		if blockedAddr(netip.MustParseAddr(addr)) {
+			t.Errorf("blockedAddr(%s) = true, want public address allowed", addr)
+		}
+	}
+	for _, addr := range []string{"127.0.0.53", "192.168.0.1", "fd00::1", "fe80::1", "224.0.0.1", "198.51.100.7", "240.0.0.1", "64:ff9b::a00:1"} {
+		if !blockedAddr(netip.MustParseAddr(addr)) {
+			t.Errorf("blockedAddr(%s) = false, want blocked", addr)
+		}
+	}
+}
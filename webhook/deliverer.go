@@ -0,0 +1,259 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/partner"
+	"golang-echo-postgresql/repositories"
+	"golang-echo-postgresql/tracing"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	baseBackoff = 10 * time.Second
+	maxBackoff  = 6 * time.Hour
+)
+
+// Deliverer mengirim webhook yang sudah dijadwalkan ke URL partner. Pengiriman yang
+// gagal dicoba ulang dengan exponential backoff dan jitter; setelah MaxAttempts
+// pengiriman masuk dead-letter queue (status "dead") sampai dikirim ulang secara manual.
+type Deliverer struct {
+	DB           *sql.DB
+	Box          *partner.SecretBox
+	Client       *http.Client
+	MaxAttempts  int
+	BatchSize    int
+	PollInterval time.Duration
+}
+
+// NewDeliverer membuat Deliverer baru. Kecuali allowInsecure, client menolak koneksi ke
+// alamat loopback, private, link-local dan reserved.
+func NewDeliverer(db *sql.DB, box *partner.SecretBox, timeout time.Duration, maxAttempts, batchSize int, pollInterval time.Duration, allowInsecure bool) *Deliverer {
+	client := safeClient(timeout)
+	if allowInsecure {
+		client = &http.Client{Timeout: timeout}
+	}
+	return &Deliverer{
+		DB:           db,
+		Box:          box,
+		Client:       client,
+		MaxAttempts:  maxAttempts,
+		BatchSize:    batchSize,
+		PollInterval: pollInterval,
+	}
+}
+
+// Run menjalankan Deliverer sampai ctx dibatalkan
+func (d *Deliverer) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		sent, err := d.DeliverOnce(ctx)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err,
+			}).Error("Webhook delivery failed")
+		}
+
+		if sent > 0 {
+			timer.Reset(0)
+		} else {
+			timer.Reset(d.PollInterval)
+		}
+	}
+}
+
+// DeliverOnce mengirim satu batch webhook secara paralel dan mengembalikan jumlah
+// pengiriman yang dicoba
+func (d *Deliverer) DeliverOnce(ctx context.Context) (int, error) {
+	// Lease mencegah worker lain mengambil pengiriman yang sedang berjalan
+	lease := 2*d.Client.Timeout + time.Minute
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	deliveries, err := repositories.ClaimWebhookDeliveries(tx, time.Now(), lease, d.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	subs := map[int]*models.WebhookSubscription{}
+	for _, delivery := range deliveries {
+		if _, ok := subs[delivery.SubscriptionID]; ok {
+			continue
+		}
+		sub, err := repositories.GetWebhookSubscription(d.DB, delivery.SubscriptionID)
+		if err != nil {
+			return 0, err
+		}
+		subs[delivery.SubscriptionID] = sub
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *models.WebhookDelivery) {
+			defer wg.Done()
+			d.deliver(ctx, subs[delivery.SubscriptionID], delivery)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *Deliverer) deliver(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery) {
+	fields := log.Fields{
+		"DeliveryID":     delivery.ID,
+		"SubscriptionID": sub.ID,
+		"EventID":        delivery.EventID,
+		"type":           delivery.EventType,
+	}
+
+	started := time.Now()
+	statusCode, sendErr := d.send(ctx, sub, delivery, started)
+	if ctx.Err() != nil {
+		// Shutdown: biarkan lease habis dan pengiriman diambil ulang saat start berikutnya
+		return
+	}
+
+	attempt := &models.WebhookDeliveryAttempt{
+		DeliveryID:  delivery.ID,
+		AttemptedAt: started,
+		DurationMs:  int(time.Since(started).Milliseconds()),
+	}
+	delivery.Attempts++
+	delivery.LastError = ""
+	delivery.LastStatusCode = nil
+	if statusCode != 0 {
+		attempt.StatusCode = &statusCode
+		delivery.LastStatusCode = &statusCode
+	}
+
+	switch {
+	case sendErr == nil:
+		now := time.Now()
+		delivery.Status = models.WebhookDelivered
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= d.MaxAttempts:
+		attempt.Error = sendErr.Error()
+		delivery.Status = models.WebhookDead
+		delivery.LastError = sendErr.Error()
+		log.WithFields(fields).WithField("error", sendErr).Error("Webhook moved to dead-letter queue")
+	default:
+		attempt.Error = sendErr.Error()
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = time.Now().Add(backoff(delivery.Attempts))
+		log.WithFields(fields).WithFields(log.Fields{
+			"error":       sendErr,
+			"attempts":    delivery.Attempts,
+			"NextAttempt": delivery.NextAttemptAt,
+		}).Warn("Webhook delivery failed, will retry")
+	}
+
+	if err := repositories.InsertWebhookDeliveryAttempt(d.DB, attempt); err != nil {
+		log.WithFields(fields).WithField("error", err).Error("Failed to record webhook attempt")
+	}
+	if err := repositories.UpdateWebhookDeliveryResult(d.DB, delivery); err != nil {
+		log.WithFields(fields).WithField("error", err).Error("Failed to update webhook delivery")
+	}
+}
+
+// send mengirim satu percobaan. Span pengiriman berada di bawah trace request yang membuat
+// event, dan trace context-nya dikirim ke partner lewat header traceparent.
+func (d *Deliverer) send(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery, now time.Time) (statusCode int, err error) {
+	ctx, span := tracing.Child(tracing.WithTraceParent(ctx, delivery.TraceParent), "webhook.Deliver "+delivery.EventType,
+		trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if statusCode != 0 {
+			span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+		}
+		tracing.End(span, err)
+	}()
+
+	if !sub.Aktif {
+		return 0, fmt.Errorf("subscription nonaktif")
+	}
+
+	secrets, err := d.secrets(sub, now)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, strconv.FormatInt(delivery.EventID, 10))
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderSignature, SignatureHeader(now.Unix(), delivery.Payload, secrets...))
+	tracing.Inject(req)
+	span.SetAttributes(semconv.HTTPRequestMethodKey.String(http.MethodPost), semconv.ServerAddress(req.URL.Hostname()))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint membalas %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Deliverer) secrets(sub *models.WebhookSubscription, now time.Time) ([]string, error) {
+	if d.Box == nil {
+		return nil, ErrNotConfigured
+	}
+	current, err := d.Box.Open(sub.SecretEnc)
+	if err != nil {
+		return nil, err
+	}
+	secrets := []string{current}
+	if sub.PreviousSecretEnc != "" && sub.PreviousSecretUntil != nil && now.Before(*sub.PreviousSecretUntil) {
+		previous, err := d.Box.Open(sub.PreviousSecretEnc)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, previous)
+	}
+	return secrets, nil
+}
+
+// backoff menghasilkan jeda 10s, 20s, 40s, ... maksimal 6 jam, ditambah jitter hingga 20%
+// agar endpoint yang baru pulih tidak dibanjiri percobaan ulang secara bersamaan
+func backoff(attempts int) time.Duration {
+	d := baseBackoff << uint(min(attempts-1, 16))
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Received adalah satu webhook yang diterima Receiver
+type Received struct {
+	EventID    string    `json:"event_id"`
+	Duplicate  bool      `json:"duplicate"`
+	Envelope   Envelope  `json:"envelope"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// Receiver adalah penerima webhook sederhana untuk pengujian lokal. Receiver
+// memverifikasi signature, mengenali event duplikat berdasarkan X-Webhook-Id, dan bisa
+// diatur untuk gagal pada sejumlah request pertama guna menguji retry.
+type Receiver struct {
+	Secret    string
+	Tolerance time.Duration
+	FailFirst int // jumlah request pertama yang dijawab 503
+
+	mu       sync.Mutex
+	requests int
+	seen     map[string]bool
+	received []Received
+}
+
+// NewReceiver membuat Receiver dengan secret subscription
+func NewReceiver(secret string, failFirst int) *Receiver {
+	return &Receiver{Secret: secret, Tolerance: 5 * time.Minute, FailFirst: failFirst, seen: map[string]bool{}}
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	if err := VerifyHeader(req.Header.Get(HeaderSignature), body, r.Secret, r.Tolerance, time.Now()); err != nil {
+		log.WithFields(log.Fields{
+			"error": err,
+		}).Warn("Rejected webhook")
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests++
+	if r.requests <= r.FailFirst {
+		http.Error(w, "simulated failure", http.StatusServiceUnavailable)
+		return
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	eventID := req.Header.Get(HeaderEventID)
+	received := Received{EventID: eventID, Duplicate: r.seen[eventID], Envelope: envelope, ReceivedAt: time.Now()}
+	r.seen[eventID] = true
+	r.received = append(r.received, received)
+
+	log.WithFields(log.Fields{
+		"EventID":    eventID,
+		"type":       envelope.Type,
+		"NoRekening": envelope.NoRekening,
+		"duplicate":  received.Duplicate,
+	}).Info("Webhook received")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Received mengembalikan salinan semua webhook yang sudah diterima
+func (r *Receiver) Received() []Received {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Received{}, r.received...)
+}
@@ -0,0 +1,284 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/partner"
+	"golang-echo-postgresql/repositories"
+	"golang-echo-postgresql/tracing"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("subscription webhook tidak ditemukan")
+	ErrDeliveryNotFound     = errors.New("pengiriman webhook tidak ditemukan")
+	ErrNotConfigured        = errors.New("penandatanganan webhook belum dikonfigurasi")
+	ErrInvalidSubscription  = errors.New("subscription webhook tidak valid")
+	ErrRekeningNotLinked    = errors.New("rekening tidak terhubung dengan partner")
+)
+
+// EventTypes adalah tipe event yang bisa dilanggan partner
+var EventTypes = []string{
+	outbox.TypeFundsDeposited,
+	outbox.TypeFundsWithdrawn,
+	outbox.TypeFundsTransferred,
+	outbox.TypeBalanceAdjusted,
+	outbox.TypeTransactionReversed,
+	outbox.TypeAccountStatusChanged,
+}
+
+// Envelope adalah body JSON yang dikirim ke URL webhook
+type Envelope struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	NoRekening string          `json:"no_rekening"`
+	Sequence   int64           `json:"sequence"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Service mengelola subscription webhook milik partner dan menjadwalkan pengiriman
+// event dari outbox. Secret webhook dienkripsi dengan SecretBox yang sama dengan
+// secret partner.
+type Service struct {
+	DB            *sql.DB
+	Box           *partner.SecretBox // nil jika PARTNER_SECRET_KEY belum diatur
+	SecretOverlap time.Duration
+	AllowInsecure bool // izinkan URL http:// dan alamat internal untuk pengembangan lokal
+
+	// LookupHost me-resolve host URL saat pendaftaran; bawaannya resolver sistem
+	LookupHost func(ctx context.Context, host string) ([]netip.Addr, error)
+}
+
+// NewService membuat Service webhook
+func NewService(db *sql.DB, box *partner.SecretBox, secretOverlap time.Duration, allowInsecure bool) *Service {
+	return &Service{DB: db, Box: box, SecretOverlap: secretOverlap, AllowInsecure: allowInsecure, LookupHost: lookupHost}
+}
+
+// Subscribe mendaftarkan URL webhook baru dan mengembalikan secret penandatangannya.
+// Setiap rekening yang disebut harus terhubung dengan partner.
+func (s *Service) Subscribe(partnerID int, req models.WebhookSubscriptionRequest) (*models.WebhookSecretResponse, error) {
+	if s.Box == nil {
+		return nil, ErrNotConfigured
+	}
+	if err := s.validate(req); err != nil {
+		return nil, err
+	}
+	if len(req.Rekening) > 0 {
+		unlinked, err := repositories.FindUnlinkedPartnerRekening(s.DB, partnerID, req.Rekening)
+		if err != nil {
+			return nil, err
+		}
+		if len(unlinked) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrRekeningNotLinked, strings.Join(unlinked, ", "))
+		}
+	}
+
+	secret, sealed, err := s.newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	rekening := req.Rekening
+	if rekening == nil {
+		rekening = []string{}
+	}
+	sub := &models.WebhookSubscription{
+		PartnerID:  partnerID,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Rekening:   rekening,
+		SecretEnc:  sealed,
+	}
+	if err := repositories.CreateWebhookSubscription(s.DB, sub); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"SubscriptionID": sub.ID,
+		"PartnerID":      partnerID,
+		"EventTypes":     sub.EventTypes,
+	}).Info("Webhook subscription created")
+
+	return &models.WebhookSecretResponse{Subscription: sub, Secret: secret}, nil
+}
+
+// List mengambil semua subscription milik partner
+func (s *Service) List(partnerID int) ([]models.WebhookSubscription, error) {
+	return repositories.ListWebhookSubscriptionsByPartner(s.DB, partnerID)
+}
+
+// Deactivate menghentikan pengiriman event baru ke subscription
+func (s *Service) Deactivate(partnerID, id int) error {
+	ok, err := repositories.DeactivateWebhookSubscription(s.DB, id, partnerID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// RotateSecret menerbitkan secret baru. Selama SecretOverlap setiap webhook ditandatangani
+// dengan secret lama dan baru sekaligus agar partner bisa mengganti secret tanpa gangguan.
+func (s *Service) RotateSecret(partnerID, id int) (*models.WebhookSecretResponse, error) {
+	if s.Box == nil {
+		return nil, ErrNotConfigured
+	}
+
+	sub, err := s.owned(partnerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, sealed, err := s.newSecret()
+	if err != nil {
+		return nil, err
+	}
+	previousUntil := time.Now().Add(s.SecretOverlap)
+	if err := repositories.RotateWebhookSecret(s.DB, sub.ID, sealed, previousUntil); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"SubscriptionID": sub.ID,
+		"PreviousUntil":  previousUntil,
+	}).Info("Webhook secret rotated")
+
+	sub.PreviousSecretUntil = &previousUntil
+	return &models.WebhookSecretResponse{Subscription: sub, Secret: secret, PreviousSecretUntil: &previousUntil}, nil
+}
+
+// Deliveries mengambil log pengiriman. partnerID 0 berarti semua partner.
+func (s *Service) Deliveries(partnerID int, status string, limit int) ([]models.WebhookDelivery, error) {
+	return repositories.ListWebhookDeliveries(s.DB, partnerID, status, limit)
+}
+
+// Attempts mengambil log percobaan sebuah pengiriman milik partner
+func (s *Service) Attempts(partnerID int, deliveryID int64) ([]models.WebhookDeliveryAttempt, error) {
+	ok, err := repositories.WebhookDeliveryBelongsTo(s.DB, deliveryID, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDeliveryNotFound
+	}
+	return repositories.ListWebhookDeliveryAttempts(s.DB, deliveryID)
+}
+
+// Redeliver menjadwalkan ulang pengiriman, termasuk yang sudah masuk dead-letter queue.
+// partnerID 0 dipakai petugas untuk pengiriman milik partner mana pun.
+func (s *Service) Redeliver(partnerID int, deliveryID int64) error {
+	ok, err := repositories.RequeueWebhookDelivery(s.DB, deliveryID, partnerID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrDeliveryNotFound
+	}
+
+	log.WithFields(log.Fields{
+		"DeliveryID": deliveryID,
+		"PartnerID":  partnerID,
+	}).Info("Webhook delivery requeued")
+	return nil
+}
+
+// Dispatch adalah handler outbox yang menjadwalkan satu pengiriman untuk setiap
+// subscription yang cocok. Pengiriman HTTP dilakukan Deliverer secara terpisah,
+// jadi request yang memicu event tidak pernah menunggu endpoint partner.
+func (s *Service) Dispatch(ctx context.Context, event models.OutboxEvent) error {
+	subs, err := repositories.FindMatchingWebhookSubscriptions(s.DB, event.Type, event.AggregateID)
+	if err != nil || len(subs) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(Envelope{
+		ID:         event.ID,
+		Type:       event.Type,
+		Version:    event.Version,
+		NoRekening: event.AggregateID,
+		Sequence:   event.Sequence,
+		CreatedAt:  event.CreatedAt,
+		Data:       event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		err := repositories.InsertWebhookDelivery(s.DB, &models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			TraceParent:    tracing.TraceParent(ctx),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) owned(partnerID, id int) (*models.WebhookSubscription, error) {
+	sub, err := repositories.GetWebhookSubscription(s.DB, id)
+	if err == sql.ErrNoRows || (err == nil && sub.PartnerID != partnerID) {
+		return nil, ErrSubscriptionNotFound
+	}
+	return sub, err
+}
+
+func (s *Service) validate(req models.WebhookSubscriptionRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && !(s.AllowInsecure && u.Scheme == "http")) {
+		return fmt.Errorf("%w: url harus https", ErrInvalidSubscription)
+	}
+	if !s.AllowInsecure {
+		if err := s.checkHost(u.Hostname()); err != nil {
+			return err
+		}
+	}
+	if len(req.EventTypes) == 0 {
+		return fmt.Errorf("%w: event_types wajib diisi", ErrInvalidSubscription)
+	}
+	for _, eventType := range req.EventTypes {
+		if !knownEventType(eventType) {
+			return fmt.Errorf("%w: event type %q tidak dikenal", ErrInvalidSubscription, eventType)
+		}
+	}
+	return nil
+}
+
+func (s *Service) newSecret() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	secret := "whsec_" + hex.EncodeToString(b)
+	sealed, err := s.Box.Seal(secret)
+	return secret, sealed, err
+}
+
+func knownEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"golang-echo-postgresql/models"
+	"golang-echo-postgresql/outbox"
+	"golang-echo-postgresql/partner"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newTestService(t *testing.T) (*Service, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	box, err := partner.NewSecretBox(strings.Repeat("ab", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(db, box, time.Hour, false)
+	s.LookupHost = func(ctx context.Context, host string) ([]netip.Addr, error) {
+		return []netip.Addr{netip.MustParseAddr("93.184.216.34")}, nil
+	}
+	return s, mock
+}
+
+func TestSubscribeRejectsForeignRekening(t *testing.T) {
+	s, mock := newTestService(t)
+	mock.ExpectQuery("FROM unnest").
+		WithArgs(7, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"r"}).AddRow("9990001"))
+
+	_, err := s.Subscribe(7, models.WebhookSubscriptionRequest{
+		URL:        "https://partner.example/hook",
+		EventTypes: []string{outbox.TypeFundsDeposited},
+		Rekening:   []string{"1230001", "9990001"},
+	})
+	if !errors.Is(err, ErrRekeningNotLinked) {
+		t.Fatalf("err = %v, want ErrRekeningNotLinked", err)
+	}
+	if !strings.Contains(err.Error(), "9990001") || strings.Contains(err.Error(), "1230001") {
+		t.Errorf("err = %q, want only the foreign rekening listed", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSubscribeAcceptsLinkedRekening(t *testing.T) {
+	s, mock := newTestService(t)
+	mock.ExpectQuery("FROM unnest").
+		WithArgs(7, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"r"}))
+	mock.ExpectQuery("INSERT INTO webhook_subscriptions").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "aktif", "created_at"}).AddRow(1, true, time.Now()))
+
+	resp, err := s.Subscribe(7, models.WebhookSubscriptionRequest{
+		URL:        "https://partner.example/hook",
+		EventTypes: []string{outbox.TypeFundsDeposited},
+		Rekening:   []string{"1230001"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp.Secret, "whsec_") {
+		t.Errorf("secret = %q, want whsec_ prefix", resp.Secret)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDispatchOnlyMatchesLinkedRekening(t *testing.T) {
+	s, mock := newTestService(t)
+	mock.ExpectQuery("EXISTS \\(SELECT 1 FROM partner_rekening").
+		WithArgs(outbox.TypeFundsDeposited, "9990001").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := s.Dispatch(context.Background(), models.OutboxEvent{Type: outbox.TypeFundsDeposited, AggregateID: "9990001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
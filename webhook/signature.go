@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header yang dikirim bersama setiap webhook
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEventID   = "X-Webhook-Id"
+	HeaderEventType = "X-Webhook-Event"
+)
+
+var (
+	ErrInvalidSignatureHeader = errors.New("header signature webhook tidak valid")
+	ErrSignatureExpired       = errors.New("timestamp signature webhook di luar toleransi")
+	ErrSignatureMismatch      = errors.New("signature webhook tidak cocok")
+)
+
+// Sign menghitung HMAC-SHA256 (hex) atas "<timestamp>.<body>"
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader menyusun nilai header X-Webhook-Signature, misalnya
+// "t=1700000000,v1=ab12...". Selama masa rotasi secret, satu signature v1 dikirim
+// untuk setiap secret yang masih berlaku sehingga penerima cukup mencocokkan salah satunya.
+func SignatureHeader(timestamp int64, body []byte, secrets ...string) string {
+	parts := []string{"t=" + strconv.FormatInt(timestamp, 10)}
+	for _, secret := range secrets {
+		parts = append(parts, "v1="+Sign(secret, timestamp, body))
+	}
+	return strings.Join(parts, ",")
+}
+
+// VerifyHeader dipakai penerima webhook untuk memeriksa signature dengan secret miliknya
+func VerifyHeader(header string, body []byte, secret string, tolerance time.Duration, now time.Time) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return ErrInvalidSignatureHeader
+		}
+		switch key {
+		case "t":
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidSignatureHeader
+			}
+			timestamp = ts
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrInvalidSignatureHeader
+	}
+
+	signedAt := time.Unix(timestamp, 0)
+	if tolerance > 0 && (signedAt.Before(now.Add(-tolerance)) || signedAt.After(now.Add(tolerance))) {
+		return ErrSignatureExpired
+	}
+
+	expected := Sign(secret, timestamp, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
+}